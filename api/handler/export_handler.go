@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"trade/api/response"
+	"trade/export"
+	"trade/utils"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// ExportKlines K线导出接口，按批次读取并以流的形式返回文件
+// 参数：symbol、interval、start/end（格式同 as_of）、format(csv/jsonl/parquet)、columns(逗号分隔)、gzip(true/false)
+func ExportKlines(ctx context.Context, c *app.RequestContext) {
+	opts := export.Options{
+		Symbol:   c.Query("symbol"),
+		Interval: c.Query("interval"),
+		Format:   c.DefaultQuery("format", export.FormatCSV),
+		Gzip:     c.Query("gzip") == "true" || c.Query("gzip") == "1",
+	}
+	if columns := c.Query("columns"); columns != "" {
+		opts.Columns = strings.Split(columns, ",")
+	}
+
+	var bounds [2]time.Time
+	for i, name := range []string{"start", "end"} {
+		value, err := utils.ParseAsOf(c.Query(name))
+		if err != nil {
+			response.ParamError(c, fmt.Sprintf("参数错误：%s%v", name, err))
+			return
+		}
+		bounds[i] = value
+	}
+	opts.Start, opts.End = bounds[0], bounds[1]
+	if err := opts.Validate(); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	// 响应头发出后无法再返回错误码，导出失败时中断流，客户端会收到不完整的响应
+	reader, writer := io.Pipe()
+	go func() {
+		count, err := export.Export(writer, opts)
+		if err != nil {
+			log.Printf("导出 %s %s 失败（已导出 %d 根）: %v", opts.Symbol, opts.Interval, count, err)
+		}
+		writer.CloseWithError(err)
+	}()
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.FileName(opts)))
+	c.SetContentType(export.ContentType(opts))
+	c.SetBodyStream(reader, -1)
+}
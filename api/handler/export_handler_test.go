@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"trade/api/response"
+	"trade/internal/fakebinance"
+
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/route"
+)
+
+func TestExportKlines(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine, _ := newTestEngine(t, func(engine *route.Engine) {
+		engine.GET("/api/v1/klines/export", ExportKlines)
+	}, fakebinance.GenerateKlines("BTCUSDT", "1h", start, 6, 42000)...)
+
+	resp := ut.PerformRequest(engine, "GET", "/api/v1/klines/export?symbol=BTCUSDT&interval=1h&start=2024-01-01T01:00:00Z&columns=open_time,close", nil).Result()
+	lines := strings.Split(strings.TrimSpace(string(resp.Body())), "\n")
+	if len(lines) != 6 || lines[0] != "open_time,close" || !strings.HasPrefix(lines[1], "2024-01-01T01:00:00Z,") {
+		t.Fatalf("导出内容错误: %q", lines)
+	}
+	if disposition := string(resp.Header.Peek("Content-Disposition")); !strings.Contains(disposition, "BTCUSDT-1h.csv") {
+		t.Errorf("文件名错误: %s", disposition)
+	}
+
+	for _, bad := range []string{
+		"/api/v1/klines/export?symbol=BTCUSDT",
+		"/api/v1/klines/export?symbol=BTCUSDT&interval=1h&format=xlsx",
+		"/api/v1/klines/export?symbol=BTCUSDT&interval=1h&columns=vwap",
+		"/api/v1/klines/export?symbol=BTCUSDT&interval=1h&end=bad",
+	} {
+		if base := performAnalyze(t, engine, bad, nil); base.Code != response.CodeParamError {
+			t.Errorf("%s 应返回参数错误，实际 %d", bad, base.Code)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"trade/api/response"
+	"trade/model"
+	"trade/paper"
+	"trade/store"
+	"trade/utils"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// defaultPaperOrderLimit 订单列表默认返回条数
+const defaultPaperOrderLimit = 100
+
+// PaperAccountResponse 模拟盘账户概览
+type PaperAccountResponse struct {
+	Account       *model.PaperAccount   `json:"account"`        // 账户
+	Equity        float64               `json:"equity"`         // 当前权益 = 钱包余额 + 未实现盈亏
+	UnrealizedPnL float64               `json:"unrealized_pnl"` // 未实现盈亏
+	UsedMargin    float64               `json:"used_margin"`    // 占用保证金
+	Positions     []model.PaperPosition `json:"positions"`      // 当前持仓
+}
+
+// PaperOrderResponse 模拟盘订单及其成交
+type PaperOrderResponse struct {
+	model.PaperOrder
+	Fills []model.PaperFill `json:"fills"` // 成交记录
+}
+
+// paperAccount 加载默认模拟盘账户，不存在时写入错误响应并返回 nil
+func paperAccount(c *app.RequestContext) *model.PaperAccount {
+	account, err := store.Default().GetPaperAccount(paper.DefaultAccountName)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("加载模拟盘账户失败：%v", err))
+		return nil
+	}
+	if account == nil {
+		response.DataNotFound(c, "模拟盘账户不存在，请先以 -mode paper 运行")
+		return nil
+	}
+	return account
+}
+
+// GetPaperAccount 模拟盘账户概览接口
+func GetPaperAccount(ctx context.Context, c *app.RequestContext) {
+	account := paperAccount(c)
+	if account == nil {
+		return
+	}
+
+	positions, err := store.Default().ListPaperPositions(store.PaperPositionQuery{
+		AccountID: account.ID,
+		Status:    model.PositionStatusOpen,
+	})
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("加载模拟盘持仓失败：%v", err))
+		return
+	}
+
+	data := &PaperAccountResponse{Account: account, Positions: positions}
+	for _, p := range positions {
+		data.UnrealizedPnL += p.UnrealizedPnL
+		data.UsedMargin += p.Margin
+	}
+	data.Equity = account.Balance + data.UnrealizedPnL
+	response.Success(c, data)
+}
+
+// ListPaperPositions 模拟盘持仓接口，status 默认 OPEN，传 ALL 返回全部
+func ListPaperPositions(ctx context.Context, c *app.RequestContext) {
+	account := paperAccount(c)
+	if account == nil {
+		return
+	}
+
+	status := strings.ToUpper(c.DefaultQuery("status", model.PositionStatusOpen))
+	switch status {
+	case model.PositionStatusOpen, model.PositionStatusClosed:
+	case "ALL":
+		status = ""
+	default:
+		response.ParamError(c, "参数错误：status只支持OPEN、CLOSED或ALL")
+		return
+	}
+
+	positions, err := store.Default().ListPaperPositions(store.PaperPositionQuery{
+		AccountID: account.ID,
+		Symbol:    c.Query("symbol"),
+		Status:    status,
+	})
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("加载模拟盘持仓失败：%v", err))
+		return
+	}
+	response.Success(c, positions)
+}
+
+// ListPaperOrders 模拟盘订单接口（按下单时间倒序）
+func ListPaperOrders(ctx context.Context, c *app.RequestContext) {
+	account := paperAccount(c)
+	if account == nil {
+		return
+	}
+
+	limit := defaultPaperOrderLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			response.ParamError(c, "参数错误：limit必须是正整数")
+			return
+		}
+		limit = parsed
+	}
+
+	orders, err := store.Default().ListPaperOrders(account.ID, c.Query("symbol"), limit)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("加载模拟盘订单失败：%v", err))
+		return
+	}
+
+	data := make([]*PaperOrderResponse, 0, len(orders))
+	for _, order := range orders {
+		fills, err := store.Default().ListPaperFills(order.ID)
+		if err != nil {
+			response.InternalError(c, fmt.Sprintf("加载模拟盘成交失败：%v", err))
+			return
+		}
+		data = append(data, &PaperOrderResponse{PaperOrder: order, Fills: fills})
+	}
+	response.Success(c, data)
+}
+
+// ListPaperEquity 模拟盘权益曲线接口，start/end 格式同 as_of
+func ListPaperEquity(ctx context.Context, c *app.RequestContext) {
+	account := paperAccount(c)
+	if account == nil {
+		return
+	}
+
+	var bounds [2]time.Time
+	for i, name := range []string{"start", "end"} {
+		value, err := utils.ParseAsOf(c.Query(name))
+		if err != nil {
+			response.ParamError(c, fmt.Sprintf("参数错误：%s%v", name, err))
+			return
+		}
+		bounds[i] = value
+	}
+
+	points, err := store.Default().ListPaperEquity(account.ID, bounds[0], bounds[1])
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("加载模拟盘权益失败：%v", err))
+		return
+	}
+	response.Success(c, points)
+}
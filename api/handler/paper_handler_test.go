@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"trade/api/response"
+	"trade/model"
+	"trade/paper"
+
+	"github.com/cloudwego/hertz/pkg/route"
+)
+
+// paperRoutes 注册模拟盘路由
+func paperRoutes(engine *route.Engine) {
+	engine.GET("/api/v1/paper/account", GetPaperAccount)
+	engine.GET("/api/v1/paper/positions", ListPaperPositions)
+	engine.GET("/api/v1/paper/equity", ListPaperEquity)
+}
+
+func TestPaperAccountAndEquity(t *testing.T) {
+	engine, memory := newTestEngine(t, paperRoutes)
+
+	if base := performAnalyze(t, engine, "/api/v1/paper/account", nil); base.Code != response.CodeDataNotFound {
+		t.Fatalf("账户不存在时应返回 CodeDataNotFound，实际 %d", base.Code)
+	}
+
+	account := &model.PaperAccount{Name: paper.DefaultAccountName, InitialBalance: 1000, Balance: 990}
+	memory.SavePaperAccount(account)
+	memory.SavePaperPosition(&model.PaperPosition{AccountID: account.ID, Symbol: "BTCUSDT", Status: model.PositionStatusOpen, Margin: 5, UnrealizedPnL: 2})
+	memory.SavePaperPosition(&model.PaperPosition{AccountID: account.ID, Symbol: "ETHUSDT", Status: model.PositionStatusClosed, Margin: 5})
+	for day := 1; day <= 3; day++ {
+		memory.SavePaperEquity(&model.PaperEquity{AccountID: account.ID, Time: time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC), Equity: float64(1000 + day)})
+	}
+
+	var summary PaperAccountResponse
+	if base := performAnalyze(t, engine, "/api/v1/paper/account", &summary); base.Code != response.CodeSuccess {
+		t.Fatalf("请求失败: %+v", base)
+	}
+	if summary.Equity != 992 || summary.UsedMargin != 5 || len(summary.Positions) != 1 {
+		t.Errorf("账户概览错误: %+v", summary)
+	}
+
+	var positions []model.PaperPosition
+	performAnalyze(t, engine, "/api/v1/paper/positions?status=all", &positions)
+	if len(positions) != 2 {
+		t.Errorf("status=all 应返回全部持仓，实际 %d", len(positions))
+	}
+	if base := performAnalyze(t, engine, "/api/v1/paper/positions?status=bad", nil); base.Code != response.CodeParamError {
+		t.Errorf("非法 status 应返回参数错误，实际 %d", base.Code)
+	}
+
+	var points []model.PaperEquity
+	performAnalyze(t, engine, "/api/v1/paper/equity?start=2024-01-02", &points)
+	if len(points) != 2 || points[0].Equity != 1002 {
+		t.Errorf("权益曲线应从 2024-01-02 开始: %+v", points)
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trade/api/response"
+	"trade/positioning"
+	"trade/utils"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// PositioningKlinesResponse K线与持仓统计对齐结果
+type PositioningKlinesResponse struct {
+	Symbol   string                     `json:"symbol"`   // 交易对
+	Interval string                     `json:"interval"` // K线周期，同时也是持仓统计周期
+	Start    time.Time                  `json:"start"`    // 开盘时间下界（含）
+	End      time.Time                  `json:"end"`      // 开盘时间上界（含）
+	Coverage positioning.Coverage       `json:"coverage"` // 各类统计覆盖的K线数量
+	Rows     []positioning.AlignedKline `json:"rows"`     // 按开盘时间升序的K线及统计
+}
+
+// GetPositioningKlines 持仓量、大户多空比和主动买卖量按K线开盘时间对齐的接口
+// start/end 格式同 as_of，默认返回交易所保留期（最近 30 天）内的数据
+func GetPositioningKlines(ctx context.Context, c *app.RequestContext) {
+	symbol, interval := c.Query("symbol"), c.Query("interval")
+	if symbol == "" || interval == "" {
+		response.ParamError(c, "参数错误：symbol和interval不能为空")
+		return
+	}
+	if !positioning.SupportsInterval(interval) {
+		response.ParamError(c, fmt.Sprintf("参数错误：interval %s 没有持仓统计数据", interval))
+		return
+	}
+
+	var bounds [2]time.Time
+	for i, name := range []string{"start", "end"} {
+		value, err := utils.ParseAsOf(c.Query(name))
+		if err != nil {
+			response.ParamError(c, fmt.Sprintf("参数错误：%s%v", name, err))
+			return
+		}
+		bounds[i] = value
+	}
+	if bounds[1].IsZero() {
+		bounds[1] = time.Now().UTC()
+	}
+	if bounds[0].IsZero() {
+		bounds[0] = bounds[1].Add(-positioning.Retention)
+	}
+
+	rows, coverage, err := positioning.Align(symbol, interval, bounds[0], bounds[1])
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("加载持仓统计失败：%v", err))
+		return
+	}
+	response.Success(c, &PositioningKlinesResponse{
+		Symbol:   symbol,
+		Interval: interval,
+		Start:    bounds[0],
+		End:      bounds[1],
+		Coverage: coverage,
+		Rows:     rows,
+	})
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"trade/api/response"
+	"trade/internal/fakebinance"
+	"trade/model"
+
+	"github.com/cloudwego/hertz/pkg/route"
+)
+
+func TestGetPositioningKlines(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine, memory := newTestEngine(t, func(engine *route.Engine) {
+		engine.GET("/api/v1/positioning/klines", GetPositioningKlines)
+	}, fakebinance.GenerateKlines("BTCUSDT", "1h", start, 3, 42000)...)
+	memory.UpsertLongShortRatios([]model.LongShortRatio{
+		{Symbol: "BTCUSDT", Period: "1h", Kind: model.LongShortKindTopAccount, Time: start.Add(time.Hour), LongShortRatio: 1.5},
+	})
+
+	var data PositioningKlinesResponse
+	url := "/api/v1/positioning/klines?symbol=BTCUSDT&interval=1h&start=2024-01-01&end=2024-01-01T02:00:00Z"
+	if base := performAnalyze(t, engine, url, &data); base.Code != response.CodeSuccess {
+		t.Fatalf("请求失败: %+v", base)
+	}
+	if len(data.Rows) != 3 || data.Coverage.TopAccount != 1 || data.Rows[0].TopAccountRatio != nil {
+		t.Fatalf("对齐结果错误: %+v", data)
+	}
+	if ratio := data.Rows[1].TopAccountRatio; ratio == nil || *ratio != 1.5 {
+		t.Errorf("多空比应对齐到第二根K线: %+v", data.Rows[1])
+	}
+
+	for _, bad := range []string{
+		"/api/v1/positioning/klines?interval=1h",
+		"/api/v1/positioning/klines?symbol=BTCUSDT&interval=1w",
+		"/api/v1/positioning/klines?symbol=BTCUSDT&interval=1h&start=bad",
+	} {
+		if base := performAnalyze(t, engine, bad, nil); base.Code != response.CodeParamError {
+			t.Errorf("%s 应返回参数错误，实际 %d", bad, base.Code)
+		}
+	}
+}
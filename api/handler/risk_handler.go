@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"trade/api/response"
+	"trade/model"
+	"trade/risk"
+	"trade/store"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// defaultRiskRejectionLimit 拒绝记录默认返回条数
+const defaultRiskRejectionLimit = 100
+
+// RiskStatusResponse 风控状态
+type RiskStatusResponse struct {
+	KillSwitch *model.KillSwitch `json:"kill_switch"` // 紧急停止开关，从未设置时为 null
+	States     []model.RiskState `json:"states"`      // 各账户的当日盈亏和连续亏损次数
+}
+
+// KillSwitchRequest 紧急停止开关请求
+type KillSwitchRequest struct {
+	Active *bool  `json:"active"` // 是否开启
+	Reason string `json:"reason"` // 原因
+}
+
+// RiskResetRequest 风控状态重置请求
+type RiskResetRequest struct {
+	Name string `json:"name"` // 账户名称(paper/dry_run/testnet/live)
+}
+
+// GetRiskStatus 风控状态接口
+func GetRiskStatus(ctx context.Context, c *app.RequestContext) {
+	killSwitch, err := store.Default().GetKillSwitch()
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("加载紧急停止开关失败：%v", err))
+		return
+	}
+	states, err := store.Default().ListRiskStates()
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("加载风控状态失败：%v", err))
+		return
+	}
+	response.Success(c, &RiskStatusResponse{KillSwitch: killSwitch, States: states})
+}
+
+// SetKillSwitch 紧急停止开关接口，开启后模拟盘和下单进程停止开新仓
+func SetKillSwitch(ctx context.Context, c *app.RequestContext) {
+	var req KillSwitchRequest
+	if err := c.Bind(&req); err != nil {
+		response.ParamError(c, fmt.Sprintf("参数错误：%v", err))
+		return
+	}
+	if req.Active == nil {
+		response.ParamError(c, "参数错误：缺少active参数")
+		return
+	}
+
+	killSwitch, err := risk.SetKillSwitch(*req.Active, req.Reason)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+	response.Success(c, killSwitch)
+}
+
+// ResetRisk 清零账户的连续亏损次数和当日已实现盈亏
+func ResetRisk(ctx context.Context, c *app.RequestContext) {
+	var req RiskResetRequest
+	if err := c.Bind(&req); err != nil {
+		response.ParamError(c, fmt.Sprintf("参数错误：%v", err))
+		return
+	}
+	if req.Name == "" {
+		response.ParamError(c, "参数错误：缺少name参数")
+		return
+	}
+
+	state, err := risk.Reset(req.Name)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+	if state == nil {
+		response.DataNotFound(c, fmt.Sprintf("账户 %s 没有风控状态", req.Name))
+		return
+	}
+	response.Success(c, state)
+}
+
+// ListRiskRejections 风控拒绝记录接口（按时间倒序）
+func ListRiskRejections(ctx context.Context, c *app.RequestContext) {
+	limit := defaultRiskRejectionLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			response.ParamError(c, "参数错误：limit必须是正整数")
+			return
+		}
+		limit = parsed
+	}
+
+	rejections, err := store.Default().ListRiskRejections(c.Query("symbol"), limit)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("加载风控拒绝记录失败：%v", err))
+		return
+	}
+	response.Success(c, rejections)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"bytes"
+	"testing"
+
+	"trade/api/response"
+	"trade/model"
+
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/route"
+)
+
+// riskRoutes 注册风控路由
+func riskRoutes(engine *route.Engine) {
+	engine.GET("/api/v1/risk/status", GetRiskStatus)
+	engine.POST("/api/v1/risk/kill-switch", SetKillSwitch)
+	engine.POST("/api/v1/risk/reset", ResetRisk)
+	engine.GET("/api/v1/risk/rejections", ListRiskRejections)
+}
+
+// performPost 发送 JSON 请求体的 POST 请求并解析统一响应
+func performPost(t *testing.T, engine *route.Engine, url, body string, out interface{}) *response.BaseResponse {
+	t.Helper()
+	w := ut.PerformRequest(engine, "POST", url, &ut.Body{Body: bytes.NewBufferString(body), Len: len(body)},
+		ut.Header{Key: "Content-Type", Value: "application/json"})
+	return decodeResponse(t, w.Result().Body(), out)
+}
+
+func TestRiskKillSwitchAndReset(t *testing.T) {
+	engine, memory := newTestEngine(t, riskRoutes)
+
+	if base := performPost(t, engine, "/api/v1/risk/kill-switch", `{"reason": "缺少开关"}`, nil); base.Code != response.CodeParamError {
+		t.Errorf("缺少 active 应返回参数错误，实际 %d", base.Code)
+	}
+
+	var killSwitch model.KillSwitch
+	if base := performPost(t, engine, "/api/v1/risk/kill-switch", `{"active": true, "reason": "行情异常"}`, &killSwitch); base.Code != response.CodeSuccess {
+		t.Fatalf("开启紧急停止失败: %+v", base)
+	}
+	if !killSwitch.Active || killSwitch.Reason != "行情异常" {
+		t.Errorf("紧急停止开关错误: %+v", killSwitch)
+	}
+
+	memory.SaveRiskState(&model.RiskState{Name: "live", Day: "2024-01-01", DailyRealizedPnL: -30, ConsecutiveLosses: 5})
+	var status RiskStatusResponse
+	performAnalyze(t, engine, "/api/v1/risk/status", &status)
+	if status.KillSwitch == nil || !status.KillSwitch.Active || len(status.States) != 1 {
+		t.Errorf("风控状态错误: %+v", status)
+	}
+
+	if base := performPost(t, engine, "/api/v1/risk/reset", `{"name": "paper"}`, nil); base.Code != response.CodeDataNotFound {
+		t.Errorf("不存在的账户应返回 CodeDataNotFound，实际 %d", base.Code)
+	}
+	var state model.RiskState
+	performPost(t, engine, "/api/v1/risk/reset", `{"name": "live"}`, &state)
+	if state.ConsecutiveLosses != 0 || state.DailyRealizedPnL != 0 {
+		t.Errorf("重置后连续亏损和当日盈亏应为0: %+v", state)
+	}
+}
+
+func TestListRiskRejections(t *testing.T) {
+	engine, memory := newTestEngine(t, riskRoutes)
+	for _, symbol := range []string{"BTCUSDT", "ETHUSDT", "BTCUSDT"} {
+		memory.SaveRiskRejection(&model.RiskRejection{Account: "paper", Symbol: symbol, Rule: model.RiskRuleMaxDailyLoss})
+	}
+
+	var rejections []model.RiskRejection
+	performAnalyze(t, engine, "/api/v1/risk/rejections?symbol=BTCUSDT", &rejections)
+	if len(rejections) != 2 {
+		t.Errorf("应返回2条 BTCUSDT 拒绝记录，实际 %d", len(rejections))
+	}
+	if base := performAnalyze(t, engine, "/api/v1/risk/rejections?limit=0", nil); base.Code != response.CodeParamError {
+		t.Errorf("limit=0 应返回参数错误，实际 %d", base.Code)
+	}
+}
@@ -2,25 +2,37 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"trade/api/response"
-	"trade/db"
 	"trade/model"
 	"trade/strategy"
+	"trade/utils"
 
 	"github.com/cloudwego/hertz/pkg/app"
 )
 
 // AnalyzeRequest 分析请求参数
 type AnalyzeRequest struct {
-	StrategyType string `json:"strategy_type" query:"strategy_type"` // 策略类型: strategy_1, strategy_2
+	StrategyType string `json:"strategy_type" query:"strategy_type"` // 策略类型: 见 /api/v1/strategy/list
 	Symbol       string `json:"symbol" query:"symbol"`                // 交易对
 	Interval     string `json:"interval" query:"interval"`            // K线周期
 	Date         string `json:"date,omitempty" query:"date"`          // 日期(策略一使用，格式：2024-10-30)
-	Hour         *int   `json:"hour,omitempty" query:"hour"`          // 小时(策略二使用，0-23)
+	Hour         *int   `json:"hour,omitempty" query:"hour"`          // 小时(策略二、三使用，0-23)
+	Weekday      *int   `json:"weekday,omitempty" query:"weekday"`    // 星期(策略三使用，0-6，0=周日)
+	AsOf         string `json:"as_of,omitempty" query:"as_of"`        // 数据截止时点(UTC)，只使用该时刻之前已收盘的K线
+}
+
+// StrategyMeta 策略元信息
+type StrategyMeta struct {
+	StrategyType string           `json:"strategy_type"` // 策略类型
+	Description  string           `json:"description"`   // 策略描述
+	Parameters   []strategy.Param `json:"parameters"`    // 策略参数
 }
 
 // AnalyzeStrategy 策略分析接口
@@ -61,321 +73,143 @@ func AnalyzeStrategy(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
-	// 根据策略类型调用不同的处理函数
-	switch req.StrategyType {
-	case "strategy_1":
-		handleStrategy1(ctx, c, &req)
-	case "strategy_2":
-		handleStrategy2(ctx, c, &req)
-	default:
-		response.ParamError(c, "参数错误：strategy_type只支持strategy_1或strategy_2")
+	// 从策略注册表查找策略
+	s, ok := strategy.Get(req.StrategyType)
+	if !ok {
+		response.ParamError(c, fmt.Sprintf("参数错误：strategy_type只支持%s", strings.Join(strategy.Names(), "或")))
+		return
 	}
-}
 
-// handleStrategy1 处理策略一
-func handleStrategy1(ctx context.Context, c *app.RequestContext, req *AnalyzeRequest) {
-	// 解析日期
-	var targetDate time.Time
-	var month, day int
+	// 时点分析：只使用 as_of 之前已收盘的K线，日期和小时默认取 as_of
+	asOf, err := utils.ParseAsOf(req.AsOf)
+	if err != nil {
+		response.ParamError(c, fmt.Sprintf("参数错误：as_of%v", err))
+		return
+	}
 
+	// 解析分析时间：日期默认今天，小时默认当前小时
+	targetTime := time.Now()
+	if !asOf.IsZero() {
+		targetTime = asOf
+	}
 	if req.Date != "" {
-		var err error
-		targetDate, err = time.Parse("2006-01-02", req.Date)
+		date, err := time.Parse("2006-01-02", req.Date)
 		if err != nil {
-			response.ParamError(c, fmt.Sprintf("参数错误：日期格式错误，应为YYYY-MM-DD，例如：2024-10-30"))
+			response.ParamError(c, "参数错误：日期格式错误，应为YYYY-MM-DD，例如：2024-10-30")
 			return
 		}
-		month = int(targetDate.Month())
-		day = targetDate.Day()
-	} else {
-		// 默认使用今天
-		now := time.Now()
-		month = int(now.Month())
-		day = now.Day()
-		targetDate = now
-	}
-
-	// 检查日期是否有效
-	if !isValidDate(month, day) {
-		response.ParamError(c, fmt.Sprintf("参数错误：日期不存在(%d月没有%d日)", month, day))
-		return
+		targetTime = time.Date(date.Year(), date.Month(), date.Day(),
+			targetTime.Hour(), 0, 0, 0, targetTime.Location())
 	}
-
-	// 分析当前日期
-	dateStr := fmt.Sprintf("%02d-%02d", month, day)
-	currentDayStats := analyzeSingleDay(req.Symbol, req.Interval, month, day)
-
-	// 如果没有数据
-	if currentDayStats.TotalCount == 0 {
-		response.DataNotFound(c, fmt.Sprintf("未找到%s在%s的历史数据", req.Symbol, dateStr))
-		return
-	}
-
-	// 分析所有年份同一日期
-	allYearRecords := analyzeAllYearsSameDate(req.Symbol, req.Interval, month, day)
-
-	// 分析所有月份相同日期
-	allMonthStats := analyzeAllMonthsSameDay(req.Symbol, req.Interval, day)
-
-	// 构建响应数据
-	resp := buildStrategy1Response(req, currentDayStats, allYearRecords, allMonthStats, month, day)
-
-	// 检查样本量
-	if currentDayStats.TotalCount < 5 {
-		response.SampleTooLow(c, "样本量不足，统计结果可能不可靠", resp)
-		return
-	}
-
-	response.Success(c, resp)
-}
-
-// handleStrategy2 处理策略二
-func handleStrategy2(ctx context.Context, c *app.RequestContext, req *AnalyzeRequest) {
-	// 解析小时
-	var targetHour int
 	if req.Hour != nil {
-		targetHour = *req.Hour
-		if targetHour < 0 || targetHour > 23 {
+		if *req.Hour < 0 || *req.Hour > 23 {
 			response.ParamError(c, "参数错误：hour参数必须在0-23之间")
 			return
 		}
-	} else {
-		// 默认使用当前小时
-		targetHour = time.Now().Hour()
+		targetTime = time.Date(targetTime.Year(), targetTime.Month(), targetTime.Day(),
+			*req.Hour, 0, 0, 0, targetTime.Location())
 	}
 
-	// 分析当前小时
-	currentHourStats := analyzeSpecificHour(req.Symbol, req.Interval, targetHour)
-
-	// 如果没有数据
-	if currentHourStats.TotalCount == 0 {
-		response.DataNotFound(c, fmt.Sprintf("未找到%s在%02d:00的历史数据", req.Symbol, targetHour))
+	if req.Weekday != nil && (*req.Weekday < 0 || *req.Weekday > 6) {
+		response.ParamError(c, "参数错误：weekday参数必须在0-6之间")
 		return
 	}
 
-	// 分析24小时
-	allHourStats := analyzeAll24Hours(req.Symbol, req.Interval)
-
-	// 构建响应数据
-	resp := buildStrategy2Response(req, currentHourStats, allHourStats, targetHour)
-
-	// 检查样本量
-	if currentHourStats.TotalCount < 10 {
-		response.SampleTooLow(c, "样本量不足，统计结果可能不可靠", resp)
-		return
+	// 其余query参数原样传给策略，body中的hour/weekday也一并传入
+	params := make(map[string]string)
+	c.QueryArgs().VisitAll(func(key, value []byte) {
+		params[string(key)] = string(value)
+	})
+	if req.Hour != nil {
+		params["hour"] = strconv.Itoa(*req.Hour)
 	}
-
-	response.Success(c, resp)
-}
-
-// analyzeSingleDay 分析单个日期的统计数据
-func analyzeSingleDay(symbol, interval string, month, day int) *strategy.DayStats {
-	dateStr := fmt.Sprintf("%02d-%02d", month, day)
-
-	var klines []model.Kline
-	err := db.Pog.Where("symbol = ? AND interval = ? AND day = ?", symbol, interval, dateStr).
-		Order("open_time ASC").
-		Find(&klines).Error
-
-	if err != nil || len(klines) == 0 {
-		return &strategy.DayStats{
-			Day:        dateStr,
-			Month:      month,
-			TotalCount: 0,
-			Records:    []strategy.KlineRecord{},
-		}
+	if req.Weekday != nil {
+		params["weekday"] = strconv.Itoa(*req.Weekday)
 	}
 
-	return calculateStats(dateStr, month, klines)
-}
-
-// analyzeAllYearsSameDate 分析所有年份同一日期的数据
-func analyzeAllYearsSameDate(symbol, interval string, month, day int) []strategy.KlineRecord {
-	dateStr := fmt.Sprintf("%02d-%02d", month, day)
-
-	var klines []model.Kline
-	err := db.Pog.Where("symbol = ? AND interval = ? AND day = ?", symbol, interval, dateStr).
-		Order("open_time ASC").
-		Find(&klines).Error
-
-	if err != nil || len(klines) == 0 {
-		return []strategy.KlineRecord{}
+	result, err := s.Analyze(ctx, &strategy.Input{
+		Symbol:   req.Symbol,
+		Interval: req.Interval,
+		Time:     targetTime,
+		AsOf:     asOf,
+		Params:   params,
+	})
+	if errors.Is(err, strategy.ErrInvalidParam) {
+		response.ParamError(c, err.Error())
+		return
 	}
-
-	records := make([]strategy.KlineRecord, 0, len(klines))
-	for _, kline := range klines {
-		priceDiff := kline.Close - kline.Open
-		isUp := kline.Close > kline.Open
-
-		record := strategy.KlineRecord{
-			Year:       kline.Date,
-			OpenPrice:  kline.Open,
-			ClosePrice: kline.Close,
-			PriceDiff:  priceDiff,
-			IsUp:       isUp,
-		}
-		records = append(records, record)
+	if errors.Is(err, strategy.ErrNoData) {
+		response.DataNotFound(c, fmt.Sprintf("未找到%s的历史数据(%v)", req.Symbol, err))
+		return
 	}
-
-	// 按年份排序
-	sort.Slice(records, func(i, j int) bool {
-		return records[i].Year < records[j].Year
-	})
-
-	return records
-}
-
-// analyzeAllMonthsSameDay 分析所有月份相同日期的数据
-func analyzeAllMonthsSameDay(symbol, interval string, day int) []*strategy.DayStats {
-	stats := make([]*strategy.DayStats, 0, 12)
-
-	for month := 1; month <= 12; month++ {
-		// 检查该月是否有这一天
-		if !isValidDate(month, day) {
-			continue
-		}
-
-		stat := analyzeSingleDay(symbol, interval, month, day)
-		if stat.TotalCount > 0 {
-			stats = append(stats, stat)
-		}
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("策略分析失败：%v", err))
+		return
 	}
 
-	return stats
-}
-
-// analyzeSpecificHour 分析特定小时的统计数据
-func analyzeSpecificHour(symbol, interval string, hour int) *strategy.HourStats {
-	hourStr := fmt.Sprintf("%d", hour)
-
-	var klines []model.Kline
-	err := db.Pog.Where("symbol = ? AND interval = ? AND hour = ?", symbol, interval, hourStr).
-		Order("open_time ASC").
-		Find(&klines).Error
-
-	if err != nil || len(klines) == 0 {
-		return &strategy.HourStats{
-			Hour:       hour,
-			TotalCount: 0,
-			Records:    []strategy.KlineRecord{},
-		}
+	// 没有专用响应格式的策略直接返回结构化结果
+	builder, ok := responseBuilders[s.Name()]
+	if !ok {
+		response.Success(c, result)
+		return
 	}
 
-	return calculateHourStats(hour, klines)
-}
-
-// analyzeAll24Hours 分析所有24小时的数据
-func analyzeAll24Hours(symbol, interval string) []*strategy.HourStats {
-	stats := make([]*strategy.HourStats, 0, 24)
-
-	for hour := 0; hour < 24; hour++ {
-		stat := analyzeSpecificHour(symbol, interval, hour)
-		if stat.TotalCount > 0 {
-			stats = append(stats, stat)
-		}
+	resp, sampleTooLow := builder(&req, result)
+	if sampleTooLow {
+		response.SampleTooLow(c, "样本量不足，统计结果可能不可靠", resp)
+		return
 	}
 
-	return stats
+	response.Success(c, resp)
 }
 
-// calculateStats 计算统计数据
-func calculateStats(dateStr string, month int, klines []model.Kline) *strategy.DayStats {
-	stats := &strategy.DayStats{
-		Day:        dateStr,
-		Month:      month,
-		TotalCount: len(klines),
-		Records:    make([]strategy.KlineRecord, 0, len(klines)),
-	}
-
-	for _, kline := range klines {
-		priceDiff := kline.Close - kline.Open
-		isUp := kline.Close > kline.Open
-
-		// 记录每条K线数据
-		record := strategy.KlineRecord{
-			Year:       kline.Date,
-			OpenPrice:  kline.Open,
-			ClosePrice: kline.Close,
-			PriceDiff:  priceDiff,
-			IsUp:       isUp,
-		}
-		stats.Records = append(stats.Records, record)
-
-		// 统计涨跌次数
-		if kline.Close > kline.Open {
-			stats.UpCount++
-		} else if kline.Close < kline.Open {
-			stats.DownCount++
-		} else {
-			stats.FlatCount++
-		}
-	}
-
-	// 计算上涨概率
-	stats.UpRate = float64(stats.UpCount) / float64(stats.TotalCount) * 100
-
-	return stats
+// ListStrategies 策略列表接口
+func ListStrategies(ctx context.Context, c *app.RequestContext) {
+	list := strategy.List()
+	strategies := make([]*StrategyMeta, 0, len(list))
+	for _, s := range list {
+		strategies = append(strategies, &StrategyMeta{
+			StrategyType: s.Name(),
+			Description:  s.Description(),
+			Parameters:   s.Parameters(),
+		})
+	}
+	response.Success(c, strategies)
 }
 
-// calculateHourStats 计算小时统计数据
-func calculateHourStats(hour int, klines []model.Kline) *strategy.HourStats {
-	stats := &strategy.HourStats{
-		Hour:       hour,
-		TotalCount: len(klines),
-		Records:    make([]strategy.KlineRecord, 0, len(klines)),
-	}
-
-	for _, kline := range klines {
-		priceDiff := kline.Close - kline.Open
-		isUp := kline.Close > kline.Open
-
-		// 记录每条K线数据
-		record := strategy.KlineRecord{
-			Year:       kline.Date,
-			OpenPrice:  kline.Open,
-			ClosePrice: kline.Close,
-			PriceDiff:  priceDiff,
-			IsUp:       isUp,
-		}
-		stats.Records = append(stats.Records, record)
-
-		// 统计涨跌次数
-		if kline.Close > kline.Open {
-			stats.UpCount++
-		} else if kline.Close < kline.Open {
-			stats.DownCount++
-		} else {
-			stats.FlatCount++
-		}
-	}
+// responseBuilder 将策略结果转换为接口响应数据，并返回样本量是否不足
+type responseBuilder func(req *AnalyzeRequest, result *strategy.Result) (interface{}, bool)
 
-	// 计算上涨概率
-	if stats.TotalCount > 0 {
-		stats.UpRate = float64(stats.UpCount) / float64(stats.TotalCount) * 100
-	}
+// responseBuilders 各策略的专用响应格式
+var responseBuilders = map[string]responseBuilder{
+	"strategy_1": buildStrategy1Result,
+	"strategy_2": buildStrategy2Result,
+}
 
-	return stats
+// buildStrategy1Result 构建策略一响应
+func buildStrategy1Result(req *AnalyzeRequest, result *strategy.Result) (interface{}, bool) {
+	analysis := result.Data.(*strategy.Strategy1Analysis)
+	resp := buildStrategy1Response(req, analysis.Current, analysis.AllYears, analysis.AllMonths, analysis.Month, analysis.Day, result.AsOf)
+	return resp, analysis.Current.TotalCount < 5
 }
 
-// isValidDate 检查日期是否有效
-func isValidDate(month, day int) bool {
-	daysInMonth := []int{0, 31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
-	if month < 1 || month > 12 {
-		return false
-	}
-	return day >= 1 && day <= daysInMonth[month]
+// buildStrategy2Result 构建策略二响应
+func buildStrategy2Result(req *AnalyzeRequest, result *strategy.Result) (interface{}, bool) {
+	analysis := result.Data.(*strategy.Strategy2Analysis)
+	resp := buildStrategy2Response(req, analysis.Current, analysis.AllHours, analysis.Hour, result.AsOf)
+	return resp, analysis.Current.TotalCount < 10
 }
 
 // buildStrategy1Response 构建策略一响应
 func buildStrategy1Response(req *AnalyzeRequest, currentStats *strategy.DayStats,
 	allYearRecords []strategy.KlineRecord, allMonthStats []*strategy.DayStats,
-	month, day int) *response.Strategy1Response {
+	month, day int, asOf *time.Time) *response.Strategy1Response {
 
 	dateStr := fmt.Sprintf("%02d-%02d", month, day)
 	analysisDate := fmt.Sprintf("2024-%02d-%02d", month, day)
 
 	// 计算可靠性
-	reliability, reliabilityNote := getReliability(currentStats.TotalCount)
+	reliability, reliabilityNote := getReliability(currentStats.UpRate, currentStats.Significance)
 
 	// 计算跨年分析
 	crossYearAnalysis := buildCrossYearAnalysis(allYearRecords, month, day)
@@ -401,12 +235,13 @@ func buildStrategy1Response(req *AnalyzeRequest, currentStats *strategy.DayStats
 			Interval:     req.Interval,
 			AnalysisDate: analysisDate,
 			TargetPeriod: dateStr,
+			AsOf:         formatAsOf(asOf),
 		},
 		DataStatistics: &response.DataStatistics{
 			DataSource: "币安合约历史K线数据",
 			DateRange: response.DateRange{
 				StartDate: "2018-01-01",
-				EndDate:   time.Now().Format("2006-01-02"),
+				EndDate:   dataEndTime(asOf).Format("2006-01-02"),
 			},
 			TotalRecordsUsed: currentStats.TotalCount,
 			QueryMethod:      "按symbol、interval和day字段查询数据库Kline表",
@@ -419,6 +254,8 @@ func buildStrategy1Response(req *AnalyzeRequest, currentStats *strategy.DayStats
 			FlatCount:       currentStats.FlatCount,
 			UpRate:          currentStats.UpRate,
 			DownRate:        float64(currentStats.DownCount) / float64(currentStats.TotalCount) * 100,
+			ReturnStats:     &currentStats.ReturnStats,
+			Significance:    &currentStats.Significance,
 			Reliability:     reliability,
 			ReliabilityNote: reliabilityNote,
 		},
@@ -431,12 +268,12 @@ func buildStrategy1Response(req *AnalyzeRequest, currentStats *strategy.DayStats
 
 // buildStrategy2Response 构建策略二响应
 func buildStrategy2Response(req *AnalyzeRequest, currentHourStats *strategy.HourStats,
-	allHourStats []*strategy.HourStats, targetHour int) *response.Strategy2Response {
+	allHourStats []*strategy.HourStats, targetHour int, asOf *time.Time) *response.Strategy2Response {
 
-	analysisDatetime := time.Now().Format("2006-01-02 15:04:05")
+	analysisDatetime := dataEndTime(asOf).Format("2006-01-02 15:04:05")
 
 	// 计算可靠性
-	reliability, reliabilityNote := getHourReliability(currentHourStats.TotalCount)
+	reliability, reliabilityNote := getReliability(currentHourStats.UpRate, currentHourStats.Significance)
 
 	// 构建小时对比
 	hourlyComparison := buildHourlyComparison(allHourStats, targetHour)
@@ -468,12 +305,13 @@ func buildStrategy2Response(req *AnalyzeRequest, currentHourStats *strategy.Hour
 			Interval:         req.Interval,
 			AnalysisDatetime: analysisDatetime,
 			TargetHour:       targetHour,
+			AsOf:             formatAsOf(asOf),
 		},
 		DataStatistics: &response.DataStatistics{
 			DataSource: "币安合约历史K线数据",
 			DateRange: response.DateRange{
 				StartDate: "2018-01-01",
-				EndDate:   time.Now().Format("2006-01-02"),
+				EndDate:   dataEndTime(asOf).Format("2006-01-02"),
 			},
 			TotalRecordsUsed: currentHourStats.TotalCount,
 			QueryMethod:      "按symbol、interval和hour字段查询数据库Kline表",
@@ -486,6 +324,8 @@ func buildStrategy2Response(req *AnalyzeRequest, currentHourStats *strategy.Hour
 			FlatCount:       currentHourStats.FlatCount,
 			UpRate:          currentHourStats.UpRate,
 			DownRate:        float64(currentHourStats.DownCount) / float64(currentHourStats.TotalCount) * 100,
+			ReturnStats:     &currentHourStats.ReturnStats,
+			Significance:    &currentHourStats.Significance,
 			Reliability:     reliability,
 			ReliabilityNote: reliabilityNote,
 		},
@@ -498,24 +338,35 @@ func buildStrategy2Response(req *AnalyzeRequest, currentHourStats *strategy.Hour
 	}
 }
 
-// getReliability 获取可靠性等级
-func getReliability(sampleCount int) (string, string) {
-	if sampleCount >= 10 {
-		return "high", "样本数量充足，统计结果可靠性高"
-	} else if sampleCount >= 5 {
-		return "medium", "样本数量适中，统计结果具有一定参考价值"
+// dataEndTime 数据截止时间：时点分析取 as_of，否则取当前时间
+func dataEndTime(asOf *time.Time) time.Time {
+	if asOf == nil {
+		return time.Now()
 	}
-	return "low", fmt.Sprintf("样本数量过少(仅%d条)，统计结果不可靠", sampleCount)
+	return *asOf
 }
 
-// getHourReliability 获取小时可靠性等级
-func getHourReliability(sampleCount int) (string, string) {
-	if sampleCount >= 100 {
-		return "high", "样本数量充足(>100条)，统计结果可靠性高"
-	} else if sampleCount >= 10 {
-		return "medium", "样本数量适中，统计结果具有一定参考价值"
+// formatAsOf 格式化响应中的数据截止时点，未指定时为空
+func formatAsOf(asOf *time.Time) string {
+	if asOf == nil {
+		return ""
 	}
-	return "low", fmt.Sprintf("样本数量较少(少于10条)，统计结果可能不可靠", sampleCount)
+	return asOf.UTC().Format(time.RFC3339)
+}
+
+// getReliability 根据上涨率相对基准、经多重比较校正(BH)后的p值获取可靠性等级
+// p<0.01 为 high，p<0.05 为 medium，否则与基准的差异可能只是随机波动
+func getReliability(upRate float64, sig model.Significance) (string, string) {
+	interval := fmt.Sprintf("95%%置信区间[%.2f%%, %.2f%%]", sig.CILower, sig.CIUpper)
+	if sig.PValueBH < 0.01 {
+		return "high", fmt.Sprintf("上涨率%.2f%%与基准%.2f%%差异高度显著(BH校正p=%.4f)，%s",
+			upRate, sig.Baseline, sig.PValueBH, interval)
+	} else if sig.PValueBH < 0.05 {
+		return "medium", fmt.Sprintf("上涨率%.2f%%与基准%.2f%%差异显著(BH校正p=%.4f)，%s",
+			upRate, sig.Baseline, sig.PValueBH, interval)
+	}
+	return "low", fmt.Sprintf("上涨率%.2f%%与基准%.2f%%差异不显著(BH校正p=%.4f)，%s，当前样本量只能识别±%.2f个百分点以上的优势",
+		upRate, sig.Baseline, sig.PValueBH, interval, sig.MinDetectableEdge)
 }
 
 // buildCrossYearAnalysis 构建跨年分析
@@ -580,16 +431,8 @@ func buildCrossMonthAnalysis(allStats []*strategy.DayStats, currentMonth, day in
 		return nil
 	}
 
-	// 找出最佳和最差月份
-	var best, worst *strategy.DayStats
-	for _, stat := range allStats {
-		if best == nil || stat.UpRate > best.UpRate {
-			best = stat
-		}
-		if worst == nil || stat.UpRate < worst.UpRate {
-			worst = stat
-		}
-	}
+	// 找出校正后显著的最佳和最差月份，没有时为空
+	best, worst := strategy.BestWorstMonth(allStats)
 
 	// 当前月份排名
 	var currentStat *strategy.DayStats
@@ -606,22 +449,24 @@ func buildCrossMonthAnalysis(allStats []*strategy.DayStats, currentMonth, day in
 	var bestPerf, worstPerf *response.Performance
 	if best != nil {
 		bestPerf = &response.Performance{
-			Month:      best.Month,
-			MonthLabel: fmt.Sprintf("%02d月%02d日", best.Month, day),
-			UpRate:     best.UpRate,
-			SampleCount: best.TotalCount,
-			UpCount:    best.UpCount,
-			DownCount:  best.DownCount,
+			Month:        best.Month,
+			MonthLabel:   fmt.Sprintf("%02d月%02d日", best.Month, day),
+			UpRate:       best.UpRate,
+			SampleCount:  best.TotalCount,
+			UpCount:      best.UpCount,
+			DownCount:    best.DownCount,
+			Significance: &best.Significance,
 		}
 	}
 	if worst != nil {
 		worstPerf = &response.Performance{
-			Month:      worst.Month,
-			MonthLabel: fmt.Sprintf("%02d月%02d日", worst.Month, day),
-			UpRate:     worst.UpRate,
-			SampleCount: worst.TotalCount,
-			UpCount:    worst.UpCount,
-			DownCount:  worst.DownCount,
+			Month:        worst.Month,
+			MonthLabel:   fmt.Sprintf("%02d月%02d日", worst.Month, day),
+			UpRate:       worst.UpRate,
+			SampleCount:  worst.TotalCount,
+			UpCount:      worst.UpCount,
+			DownCount:    worst.DownCount,
+			Significance: &worst.Significance,
 		}
 	}
 
@@ -629,7 +474,9 @@ func buildCrossMonthAnalysis(allStats []*strategy.DayStats, currentMonth, day in
 	if currentStat != nil {
 		perfLevel := "medium"
 		perfNote := "当前月份表现中等"
-		if rank <= len(allStats)/3 {
+		if !currentStat.SurvivesCorrection {
+			perfNote = "当前月份与基准的差异未通过多重比较校正，排名可能只是随机波动"
+		} else if rank <= len(allStats)/3 {
 			perfLevel = "excellent"
 			perfNote = "当前月份表现优秀，历史上涨概率较高"
 		} else if rank >= len(allStats)*2/3 {
@@ -648,7 +495,7 @@ func buildCrossMonthAnalysis(allStats []*strategy.DayStats, currentMonth, day in
 
 	return &response.CrossMonthAnalysis{
 		Title:               "跨月对比",
-		Description:         fmt.Sprintf("对比所有月份的%d号，找出历史表现最好和最差的月份", day),
+		Description:         fmt.Sprintf("对比所有月份的%d号，找出上涨率经多重比较校正后显著高于/低于基准的最好和最差月份", day),
 		MonthsAnalyzed:      len(allStats),
 		BestMonth:           bestPerf,
 		WorstMonth:          worstPerf,
@@ -669,19 +516,8 @@ func buildHourlyComparison(allStats []*strategy.HourStats, currentHour int) *res
 	}
 	avgUpRate := totalUpRate / float64(len(allStats))
 
-	// 找出最佳和最差时段(样本数>=5)
-	var best, worst *strategy.HourStats
-	for _, stat := range allStats {
-		if stat.TotalCount < 5 {
-			continue
-		}
-		if best == nil || stat.UpRate > best.UpRate {
-			best = stat
-		}
-		if worst == nil || stat.UpRate < worst.UpRate {
-			worst = stat
-		}
-	}
+	// 找出校正后显著的最佳和最差时段(样本数>=5)，没有时为空
+	best, worst := strategy.BestWorstHour(allStats, 5)
 
 	// 当前小时排名
 	var currentStat *strategy.HourStats
@@ -708,7 +544,8 @@ func buildHourlyComparison(allStats []*strategy.HourStats, currentHour int) *res
 			SampleCount:     best.TotalCount,
 			UpCount:         best.UpCount,
 			DownCount:       best.DownCount,
-			PerformanceNote: "历史表现最佳时段",
+			PerformanceNote: "上涨率经多重比较校正后显著高于基准的最佳时段",
+			Significance:    &best.Significance,
 		}
 	}
 	if worst != nil {
@@ -719,7 +556,8 @@ func buildHourlyComparison(allStats []*strategy.HourStats, currentHour int) *res
 			SampleCount:     worst.TotalCount,
 			UpCount:         worst.UpCount,
 			DownCount:       worst.DownCount,
-			PerformanceNote: "历史表现最差时段",
+			PerformanceNote: "上涨率经多重比较校正后显著低于基准的最差时段",
+			Significance:    &worst.Significance,
 		}
 	}
 
@@ -727,7 +565,9 @@ func buildHourlyComparison(allStats []*strategy.HourStats, currentHour int) *res
 	if currentStat != nil && currentStat.TotalCount >= 5 {
 		perfLevel := "medium"
 		perfNote := "当前时段表现中等"
-		if rank <= validCount/3 {
+		if !currentStat.SurvivesCorrection {
+			perfNote = "当前时段与基准的差异未通过多重比较校正，排名可能只是随机波动"
+		} else if rank <= validCount/3 {
 			perfLevel = "excellent"
 			perfNote = "当前时段历史表现优秀，排名前10%"
 		} else if rank >= validCount*2/3 {
@@ -759,12 +599,13 @@ func buildHighWinHours(allStats []*strategy.HourStats) *response.HighWinHours {
 	hours := make([]*response.Performance, 0)
 
 	for _, stat := range allStats {
-		if stat.TotalCount >= 10 && stat.UpRate >= 60 {
+		if stat.TotalCount >= 10 && stat.UpRate >= 60 && stat.SurvivesCorrection {
 			hours = append(hours, &response.Performance{
-				Hour:        stat.Hour,
-				HourLabel:   fmt.Sprintf("%02d:00", stat.Hour),
-				UpRate:      stat.UpRate,
-				SampleCount: stat.TotalCount,
+				Hour:         stat.Hour,
+				HourLabel:    fmt.Sprintf("%02d:00", stat.Hour),
+				UpRate:       stat.UpRate,
+				SampleCount:  stat.TotalCount,
+				Significance: &stat.Significance,
 			})
 		}
 	}
@@ -781,7 +622,7 @@ func buildHighWinHours(allStats []*strategy.HourStats) *response.HighWinHours {
 
 	return &response.HighWinHours{
 		Title:       "高胜率时段推荐",
-		Description: "上涨率≥60%且样本数≥10的时段",
+		Description: "上涨率≥60%、样本数≥10且24小时多重比较校正(BH)后仍显著的时段",
 		Count:       len(hours),
 		Hours:       hours,
 	}
@@ -792,12 +633,13 @@ func buildLowWinHours(allStats []*strategy.HourStats) *response.LowWinHours {
 	hours := make([]*response.Performance, 0)
 
 	for _, stat := range allStats {
-		if stat.UpRate < 45 {
+		if stat.UpRate < 45 && stat.SurvivesCorrection {
 			hours = append(hours, &response.Performance{
-				Hour:        stat.Hour,
-				HourLabel:   fmt.Sprintf("%02d:00", stat.Hour),
-				UpRate:      stat.UpRate,
-				SampleCount: stat.TotalCount,
+				Hour:         stat.Hour,
+				HourLabel:    fmt.Sprintf("%02d:00", stat.Hour),
+				UpRate:       stat.UpRate,
+				SampleCount:  stat.TotalCount,
+				Significance: &stat.Significance,
 			})
 		}
 	}
@@ -814,7 +656,7 @@ func buildLowWinHours(allStats []*strategy.HourStats) *response.LowWinHours {
 
 	return &response.LowWinHours{
 		Title:       "低胜率时段警示",
-		Description: "上涨率<45%的时段，建议谨慎交易",
+		Description: "上涨率<45%且多重比较校正(BH)后仍显著的时段，建议谨慎交易",
 		Count:       len(hours),
 		Hours:       hours,
 	}
@@ -873,29 +715,46 @@ func buildTimeZoneAnalysis(allStats []*strategy.HourStats) *response.TimeZoneAna
 	}
 }
 
+// returnFactors 涨跌幅相关的支持因素和风险因素
+func returnFactors(upRate float64, returns model.ReturnStats) (supporting, risks []string) {
+	supporting = []string{
+		fmt.Sprintf("历史期望收益为%+.4f%%（平均涨幅%+.4f%%，平均跌幅%+.4f%%）", returns.Expectancy, returns.AvgWin, returns.AvgLoss),
+	}
+	if returns.ProfitFactor > 0 {
+		supporting = append(supporting, fmt.Sprintf("盈亏比为%.2f", returns.ProfitFactor))
+	}
+	if upRate > 50 && returns.Expectancy < 0 {
+		risks = append(risks, "上涨概率虽高但期望收益为负，下跌幅度大于上涨幅度")
+	}
+	if upRate < 50 && returns.Expectancy > 0 {
+		risks = append(risks, "上涨概率偏低，期望为正主要依赖少数大涨")
+	}
+	return supporting, risks
+}
+
 // buildTradingRecommendation 构建交易建议
 func buildTradingRecommendation(stats *strategy.DayStats, reliability string) *response.TradingRecommendation {
-	signal := "neutral"
-	if stats.UpRate > 55 {
-		signal = "bullish"
-	} else if stats.UpRate < 45 {
-		signal = "bearish"
-	}
+	signal := strategy.TradeSignal(stats.ReturnStats, stats.Significance, stats.TotalCount)
 
 	confidenceLevel := reliability
 
+	returnSupporting, returnRisks := returnFactors(stats.UpRate, stats.ReturnStats)
 	supportingFactors := []string{
 		fmt.Sprintf("历史数据显示上涨概率为%.2f%%", stats.UpRate),
 		fmt.Sprintf("样本数量为%d条", stats.TotalCount),
 	}
+	supportingFactors = append(supportingFactors, returnSupporting...)
 
-	riskFactors := []string{}
+	riskFactors := returnRisks
+	if !stats.SurvivesCorrection {
+		riskFactors = append(riskFactors, fmt.Sprintf("12个月多重比较校正后不显著(BH p=%.4f)，可能只是随机波动", stats.PValueBH))
+	}
 	if stats.TotalCount < 10 {
 		riskFactors = append(riskFactors, fmt.Sprintf("样本量不足%d条，可能存在统计偏差", stats.TotalCount))
 	}
 	riskFactors = append(riskFactors, "历史表现不代表未来走势")
 
-	mainReason := fmt.Sprintf("历史数据显示上涨概率为%.2f%%", stats.UpRate)
+	mainReason := fmt.Sprintf("历史期望收益为%+.4f%%，上涨概率为%.2f%%", stats.Expectancy, stats.UpRate)
 
 	return &response.TradingRecommendation{
 		Signal:            signal,
@@ -911,19 +770,17 @@ func buildTradingRecommendation(stats *strategy.DayStats, reliability string) *r
 func buildHourTradingRecommendation(currentStats *strategy.HourStats, allStats []*strategy.HourStats,
 	targetHour int, reliability string) *response.TradingRecommendation {
 
-	signal := "neutral"
-	if currentStats.UpRate > 55 {
-		signal = "bullish"
-	} else if currentStats.UpRate < 45 {
-		signal = "bearish"
-	}
+	signal := strategy.TradeSignal(currentStats.ReturnStats, currentStats.Significance, currentStats.TotalCount)
 
-	// 找出最佳时段
+	// 找出最佳时段（上涨率高、期望为正且通过多重比较校正）
 	optimalHours := []string{}
 	avoidHours := []string{}
 
 	for _, stat := range allStats {
-		if stat.TotalCount >= 10 && stat.UpRate >= 60 {
+		if !stat.SurvivesCorrection {
+			continue
+		}
+		if stat.TotalCount >= 10 && stat.UpRate >= 60 && stat.Expectancy > 0 {
 			optimalHours = append(optimalHours, fmt.Sprintf("%02d:00", stat.Hour))
 		}
 		if stat.UpRate < 45 {
@@ -943,15 +800,20 @@ func buildHourTradingRecommendation(currentStats *strategy.HourStats, allStats [
 		}
 	}
 
+	returnSupporting, returnRisks := returnFactors(currentStats.UpRate, currentStats.ReturnStats)
 	supportingFactors := []string{
-		fmt.Sprintf("样本数量充足(%d条)，统计结果可靠", currentStats.TotalCount),
+		fmt.Sprintf("样本数量为%d条", currentStats.TotalCount),
 		fmt.Sprintf("当前时段历史上涨概率为%.2f%%", currentStats.UpRate),
 	}
-	if rank <= validCount/3 {
+	supportingFactors = append(supportingFactors, returnSupporting...)
+	if rank <= validCount/3 && currentStats.SurvivesCorrection {
 		supportingFactors = append(supportingFactors, "当前时段属于高胜率时段")
 	}
 
-	riskFactors := []string{}
+	riskFactors := returnRisks
+	if !currentStats.SurvivesCorrection {
+		riskFactors = append(riskFactors, fmt.Sprintf("24小时多重比较校正后不显著(BH p=%.4f)，可能只是随机波动", currentStats.PValueBH))
+	}
 	if currentStats.TotalCount < 10 {
 		riskFactors = append(riskFactors, "样本量较少，统计结果可能不稳定")
 	}
@@ -960,7 +822,7 @@ func buildHourTradingRecommendation(currentStats *strategy.HourStats, allStats [
 		Signal:              signal,
 		ConfidenceLevel:     reliability,
 		ConfidenceScore:     currentStats.UpRate,
-		MainReason:          fmt.Sprintf("当前时段(%02d:00)历史上涨概率为%.2f%%，在24小时中排名第%d位", targetHour, currentStats.UpRate, rank),
+		MainReason:          fmt.Sprintf("当前时段(%02d:00)历史期望收益为%+.4f%%，上涨概率为%.2f%%，在24小时中排名第%d位", targetHour, currentStats.Expectancy, currentStats.UpRate, rank),
 		SupportingFactors:   supportingFactors,
 		RiskFactors:         riskFactors,
 		OptimalTradingHours: optimalHours,
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"trade/api/response"
+	"trade/model"
+	"trade/store"
+
+	"github.com/cloudwego/hertz/pkg/common/config"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/route"
+)
+
+// newTestEngine 使用内存存储创建测试引擎，routes 注册被测路由，klines 预先写入存储
+func newTestEngine(t *testing.T, routes func(engine *route.Engine), klines ...model.Kline) (*route.Engine, *store.MemoryStore) {
+	t.Helper()
+	memory := store.NewMemoryStore()
+	for i := range klines {
+		klines[i].FillTimeFields()
+	}
+	if _, err := memory.UpsertKlines(klines); err != nil {
+		t.Fatalf("写入测试K线失败: %v", err)
+	}
+	store.SetDefault(memory)
+	t.Cleanup(func() { store.SetDefault(nil) })
+
+	engine := route.NewEngine(config.NewOptions(nil))
+	routes(engine)
+	return engine, memory
+}
+
+// strategyRoutes 注册策略分析路由
+func strategyRoutes(engine *route.Engine) {
+	engine.GET("/api/v1/strategy/analyze", AnalyzeStrategy)
+}
+
+// performAnalyze 发送 GET 请求并解析统一响应，data 解析到 out
+func performAnalyze(t *testing.T, engine *route.Engine, url string, out interface{}) *response.BaseResponse {
+	t.Helper()
+	return decodeResponse(t, ut.PerformRequest(engine, "GET", url, nil).Result().Body(), out)
+}
+
+// decodeResponse 解析统一响应，out 不为空时 data 解析到 out
+func decodeResponse(t *testing.T, body []byte, out interface{}) *response.BaseResponse {
+	t.Helper()
+	base := &response.BaseResponse{}
+	if out != nil {
+		base.Data = out
+	}
+	if err := json.Unmarshal(body, base); err != nil {
+		t.Fatalf("解析响应失败: %v, body=%s", err, body)
+	}
+	return base
+}
+
+func TestAnalyzeStrategy1Offline(t *testing.T) {
+	klines := make([]model.Kline, 0)
+	for year := 2018; year <= 2024; year++ {
+		close := 110.0
+		if year%3 == 0 {
+			close = 90
+		}
+		klines = append(klines, model.Kline{
+			Symbol:   "BTCUSDT",
+			Interval: "1d",
+			OpenTime: time.Date(year, 10, 30, 0, 0, 0, 0, time.UTC),
+			Open:     100,
+			Close:    close,
+		})
+	}
+	engine, _ := newTestEngine(t, strategyRoutes, klines...)
+
+	var data response.Strategy1Response
+	base := performAnalyze(t, engine, "/api/v1/strategy/analyze?strategy_type=strategy_1&symbol=BTCUSDT&interval=1d&date=2024-10-30", &data)
+	if base.Code != response.CodeSuccess {
+		t.Fatalf("期望成功，实际 code=%d message=%s", base.Code, base.Message)
+	}
+
+	result := data.CurrentPeriodResult
+	if result == nil || result.SampleCount != 7 || result.UpCount != 5 || result.DownCount != 2 {
+		t.Fatalf("当前周期结果错误: %+v", result)
+	}
+	if data.CrossYearAnalysis == nil || data.CrossYearAnalysis.YearsAnalyzed != 7 {
+		t.Errorf("跨年分析错误: %+v", data.CrossYearAnalysis)
+	}
+
+	// 只有这一个日期的数据时上涨率等于基准，不应被判为可靠
+	sig := result.Significance
+	if sig == nil || sig.PValue < 0.99 || sig.CILower >= result.UpRate || sig.CIUpper <= result.UpRate {
+		t.Errorf("显著性错误: %+v", sig)
+	}
+	if result.Reliability != "low" {
+		t.Errorf("期望可靠性 low，实际 %s", result.Reliability)
+	}
+}
+
+func TestAnalyzeStrategy1AsOf(t *testing.T) {
+	klines := make([]model.Kline, 0)
+	for year := 2018; year <= 2024; year++ {
+		openTime := time.Date(year, 10, 30, 0, 0, 0, 0, time.UTC)
+		klines = append(klines, model.Kline{
+			Symbol:    "BTCUSDT",
+			Interval:  "1d",
+			OpenTime:  openTime,
+			CloseTime: openTime.Add(24*time.Hour - time.Millisecond),
+			Open:      100,
+			Close:     110,
+		})
+	}
+	engine, _ := newTestEngine(t, strategyRoutes, klines...)
+
+	// 2022-10-30 当天的K线在 as_of 时还没收盘，只能用到 2018-2021 四年
+	var data response.Strategy1Response
+	base := performAnalyze(t, engine, "/api/v1/strategy/analyze?strategy_type=strategy_1&symbol=BTCUSDT&interval=1d&as_of=2022-10-30", &data)
+	if base.Code != response.CodeSampleTooLow {
+		t.Fatalf("4个样本应提示样本量不足，实际 code=%d message=%s", base.Code, base.Message)
+	}
+	if data.CurrentPeriodResult.SampleCount != 4 || data.CrossYearAnalysis.YearsAnalyzed != 4 {
+		t.Errorf("as_of 之后的K线不应参与统计: %+v", data.CurrentPeriodResult)
+	}
+	if data.AnalysisTarget.AsOf != "2022-10-30T00:00:00Z" || data.DataStatistics.DateRange.EndDate != "2022-10-30" {
+		t.Errorf("响应中的数据截止时点错误: %+v %+v", data.AnalysisTarget, data.DataStatistics.DateRange)
+	}
+
+	base = performAnalyze(t, engine, "/api/v1/strategy/analyze?strategy_type=strategy_1&symbol=BTCUSDT&interval=1d&as_of=2022/10/30", nil)
+	if base.Code != response.CodeParamError {
+		t.Errorf("as_of 格式错误应返回参数错误，实际 code=%d", base.Code)
+	}
+}
+
+func TestAnalyzeStrategy2Significance(t *testing.T) {
+	// 08:00 连续60天全部上涨，其余时段涨跌各半
+	klines := make([]model.Kline, 0)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for day := 0; day < 60; day++ {
+		for hour := 0; hour < 24; hour++ {
+			close := 101.0
+			if hour != 8 && (day+hour)%2 == 0 {
+				close = 99
+			}
+			klines = append(klines, model.Kline{
+				Symbol:   "BTCUSDT",
+				Interval: "1h",
+				OpenTime: start.AddDate(0, 0, day).Add(time.Duration(hour) * time.Hour),
+				Open:     100,
+				Close:    close,
+			})
+		}
+	}
+	engine, _ := newTestEngine(t, strategyRoutes, klines...)
+
+	var data response.Strategy2Response
+	base := performAnalyze(t, engine, "/api/v1/strategy/analyze?strategy_type=strategy_2&symbol=BTCUSDT&interval=1h&hour=8", &data)
+	if base.Code != response.CodeSuccess {
+		t.Fatalf("期望成功，实际 code=%d message=%s", base.Code, base.Message)
+	}
+
+	result := data.CurrentHourResult
+	if result.Significance == nil || result.Significance.PValue >= 0.01 {
+		t.Fatalf("08:00 应显著高于基准: %+v", result.Significance)
+	}
+	if result.Reliability != "high" {
+		t.Errorf("期望可靠性 high，实际 %s", result.Reliability)
+	}
+
+	// 只有08:00通过24小时多重比较校正，其余涨跌各半的时段不应被推荐
+	if !result.Significance.SurvivesCorrection {
+		t.Errorf("08:00 应通过多重比较校正: %+v", result.Significance)
+	}
+	if high := data.HighWinHours; high.Count != 1 || high.Hours[0].Hour != 8 {
+		t.Errorf("高胜率时段应只有08:00: %+v", high.Hours)
+	}
+	if low := data.LowWinHours; low.Count != 0 {
+		t.Errorf("不应有低胜率时段: %+v", low.Hours)
+	}
+	// 最佳/最差时段只在校正后显著的时段中选出
+	if comparison := data.HourlyComparison; comparison == nil || comparison.BestHour == nil || comparison.BestHour.Hour != 8 || comparison.WorstHour != nil {
+		t.Errorf("最佳时段应为08:00且没有最差时段: %+v", comparison)
+	}
+}
+
+func TestAnalyzeStrategyNoData(t *testing.T) {
+	engine, _ := newTestEngine(t, strategyRoutes)
+
+	base := performAnalyze(t, engine, "/api/v1/strategy/analyze?strategy_type=strategy_2&symbol=BTCUSDT&interval=1h&hour=8", nil)
+	if base.Code != response.CodeDataNotFound {
+		t.Errorf("期望 code=%d，实际 code=%d message=%s", response.CodeDataNotFound, base.Code, base.Message)
+	}
+}
+
+func TestAnalyzeStrategy1NegativeExpectancy(t *testing.T) {
+	// 小涨多次、大跌一次：上涨率高但期望为负，不应给出看涨信号
+	klines := make([]model.Kline, 0)
+	for year := 2018; year <= 2024; year++ {
+		close := 101.0
+		if year == 2020 {
+			close = 80
+		}
+		klines = append(klines, model.Kline{
+			Symbol:   "BTCUSDT",
+			Interval: "1d",
+			OpenTime: time.Date(year, 10, 30, 0, 0, 0, 0, time.UTC),
+			Open:     100,
+			Close:    close,
+		})
+	}
+	engine, _ := newTestEngine(t, strategyRoutes, klines...)
+
+	var data response.Strategy1Response
+	base := performAnalyze(t, engine, "/api/v1/strategy/analyze?strategy_type=strategy_1&symbol=BTCUSDT&interval=1d&date=2024-10-30", &data)
+	if base.Code != response.CodeSuccess {
+		t.Fatalf("期望成功，实际 code=%d message=%s", base.Code, base.Message)
+	}
+
+	returns := data.CurrentPeriodResult.ReturnStats
+	if returns == nil || returns.Expectancy >= 0 || returns.AvgLoss != -20 {
+		t.Fatalf("涨跌幅统计错误: %+v", returns)
+	}
+	if signal := data.TradingRecommendation.Signal; signal == "bullish" {
+		t.Errorf("期望为负时不应看涨，上涨率 %.2f%%", data.CurrentPeriodResult.UpRate)
+	}
+}
@@ -3,6 +3,8 @@ package response
 import (
 	"time"
 
+	"trade/model"
+
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/protocol/consts"
 )
@@ -54,6 +56,7 @@ type AnalysisTarget struct {
 	AnalysisDatetime string `json:"analysis_datetime,omitempty"` // 分析时间(策略二)
 	TargetPeriod     string `json:"target_period,omitempty"`     // 目标周期(策略一)
 	TargetHour       int    `json:"target_hour,omitempty"`       // 目标小时(策略二)
+	AsOf             string `json:"as_of,omitempty"`             // 数据截止时点(只使用之前已收盘的K线)
 }
 
 // PeriodResult 周期结果
@@ -66,6 +69,8 @@ type PeriodResult struct {
 	FlatCount        int     `json:"flat_count"`        // 平盘次数
 	UpRate           float64 `json:"up_rate"`           // 上涨概率
 	DownRate         float64 `json:"down_rate"`         // 下跌概率
+	ReturnStats      *model.ReturnStats `json:"return_stats,omitempty"` // 涨跌幅统计
+	Significance     *model.Significance `json:"significance,omitempty"` // 相对基准上涨率的显著性
 	Reliability      string  `json:"reliability"`       // 可靠性等级
 	ReliabilityNote  string  `json:"reliability_note"`  // 可靠性说明
 }
@@ -84,6 +89,7 @@ type Performance struct {
 	Result           string  `json:"result,omitempty"`      // 结果(策略一)
 	Rate             float64 `json:"rate,omitempty"`        // 概率(策略一)
 	PerformanceNote  string  `json:"performance_note,omitempty"` // 表现说明
+	Significance     *model.Significance `json:"significance,omitempty"` // 显著性(含多重比较校正)
 }
 
 // CrossYearAnalysis 跨年对比分析(策略一)
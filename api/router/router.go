@@ -22,6 +22,55 @@ func Register(h *server.Hertz) {
 
 		// GET /api/v1/strategy/analyze - 支持GET请求(通过query参数)
 		strategy.GET("/analyze", handler.AnalyzeStrategy)
+
+		// GET /api/v1/strategy/list - 已注册的策略列表
+		strategy.GET("/list", handler.ListStrategies)
+	}
+
+	// 模拟盘路由
+	paper := v1.Group("/paper")
+	{
+		// GET /api/v1/paper/account - 账户概览及当前持仓
+		paper.GET("/account", handler.GetPaperAccount)
+
+		// GET /api/v1/paper/positions - 持仓列表
+		paper.GET("/positions", handler.ListPaperPositions)
+
+		// GET /api/v1/paper/orders - 订单及成交
+		paper.GET("/orders", handler.ListPaperOrders)
+
+		// GET /api/v1/paper/equity - 权益曲线
+		paper.GET("/equity", handler.ListPaperEquity)
+	}
+
+	// K线路由
+	klines := v1.Group("/klines")
+	{
+		// GET /api/v1/klines/export - 导出K线（CSV/JSONL/Parquet，流式返回）
+		klines.GET("/export", handler.ExportKlines)
+	}
+
+	// 持仓统计路由
+	positioning := v1.Group("/positioning")
+	{
+		// GET /api/v1/positioning/klines - K线与持仓量、多空比、主动买卖量对齐
+		positioning.GET("/klines", handler.GetPositioningKlines)
+	}
+
+	// 风控路由
+	risk := v1.Group("/risk")
+	{
+		// GET /api/v1/risk/status - 紧急停止开关及各账户风控状态
+		risk.GET("/status", handler.GetRiskStatus)
+
+		// POST /api/v1/risk/kill-switch - 开启或关闭紧急停止
+		risk.POST("/kill-switch", handler.SetKillSwitch)
+
+		// POST /api/v1/risk/reset - 清零连续亏损次数和当日已实现盈亏
+		risk.POST("/reset", handler.ResetRisk)
+
+		// GET /api/v1/risk/rejections - 被风控拒绝的开仓信号
+		risk.GET("/rejections", handler.ListRiskRejections)
 	}
 
 	// 健康检查
@@ -40,6 +89,17 @@ func Register(h *server.Hertz) {
 				"GET  /health",
 				"GET  /api/v1/strategy/analyze",
 				"POST /api/v1/strategy/analyze",
+				"GET  /api/v1/strategy/list",
+				"GET  /api/v1/paper/account",
+				"GET  /api/v1/paper/positions",
+				"GET  /api/v1/paper/orders",
+				"GET  /api/v1/paper/equity",
+				"GET  /api/v1/klines/export",
+				"GET  /api/v1/positioning/klines",
+				"GET  /api/v1/risk/status",
+				"POST /api/v1/risk/kill-switch",
+				"POST /api/v1/risk/reset",
+				"GET  /api/v1/risk/rejections",
 			},
 		})
 	})
@@ -0,0 +1,116 @@
+package backtest
+
+import (
+	"errors"
+	"time"
+
+	"trade/model"
+)
+
+// ErrNoData 回测区间内没有K线数据
+var ErrNoData = errors.New("回测区间内没有K线数据")
+
+// Signal 策略给出的持仓方向
+type Signal int
+
+const (
+	SignalNone  Signal = iota // 空仓
+	SignalLong                // 做多
+	SignalShort               // 做空
+)
+
+func (s Signal) String() string {
+	switch s {
+	case SignalLong:
+		return "long"
+	case SignalShort:
+		return "short"
+	default:
+		return "none"
+	}
+}
+
+// Strategy 回测策略
+// 引擎逐根回放K线：先在开盘时调用 Signal 询问持仓方向，收盘后再调用 Observe 喂入这根K线，
+// 所以策略在任何时刻只能看到已经收盘的数据，不会用到未来信息
+type Strategy interface {
+	// Name 策略名称
+	Name() string
+	// Signal 返回在 openTime 开盘时应持有的方向
+	Signal(openTime time.Time) Signal
+	// Observe 喂入一根已收盘的K线
+	Observe(kline model.Kline)
+}
+
+// Config 回测参数
+type Config struct {
+	Symbol         string    // 交易对
+	Interval       string    // K线周期
+	Start          time.Time // 回测开始时间，之前的K线只用于策略预热
+	End            time.Time // 回测结束时间，为空表示到最新K线
+	InitialCapital float64   // 初始资金(USDT)
+	FeeRate        float64   // 手续费率，按成交额收取，例如 0.0004
+	SlippageRate   float64   // 滑点，按成交价的比例，例如 0.0005
+	Leverage       float64   // 杠杆倍数
+	PositionSize   float64   // 每次开仓占用的保证金占权益的比例 (0-1]
+	AllowShort     bool      // 是否允许做空，不允许时看跌信号只平仓
+}
+
+// DefaultConfig 返回默认回测参数（币安U本位合约 taker 手续费、1倍杠杆、全仓位）
+func DefaultConfig() Config {
+	return Config{
+		InitialCapital: 10000,
+		FeeRate:        0.0004,
+		SlippageRate:   0.0005,
+		Leverage:       1,
+		PositionSize:   1,
+		AllowShort:     true,
+	}
+}
+
+// Trade 一笔已平仓的交易
+type Trade struct {
+	Side       Signal    `json:"side"`        // 方向
+	EntryTime  time.Time `json:"entry_time"`  // 开仓时间
+	EntryPrice float64   `json:"entry_price"` // 开仓成交价（含滑点）
+	ExitTime   time.Time `json:"exit_time"`   // 平仓时间
+	ExitPrice  float64   `json:"exit_price"`  // 平仓成交价（含滑点）
+	Quantity   float64   `json:"quantity"`    // 数量
+	Margin     float64   `json:"margin"`      // 占用保证金
+	PnL        float64   `json:"pnl"`         // 净盈亏（已扣开平仓手续费）
+	ReturnPct  float64   `json:"return_pct"`  // 净盈亏占保证金的比例(%)
+	Fees       float64   `json:"fees"`        // 开平仓手续费合计
+	Bars       int       `json:"bars"`        // 持仓K线数
+	Liquidated bool      `json:"liquidated"`  // 是否被强平
+}
+
+// EquityPoint 权益曲线上的一个点（K线收盘时按收盘价估值）
+type EquityPoint struct {
+	Time   time.Time `json:"time"`
+	Equity float64   `json:"equity"`
+}
+
+// Metrics 回测绩效指标，百分比字段单位为 %
+type Metrics struct {
+	InitialCapital float64 `json:"initial_capital"` // 初始资金
+	FinalEquity    float64 `json:"final_equity"`    // 最终权益
+	TotalReturn    float64 `json:"total_return"`    // 总收益率(%)
+	CAGR           float64 `json:"cagr"`            // 年化复合收益率(%)
+	Sharpe         float64 `json:"sharpe"`          // 年化夏普比率（无风险利率按0）
+	Sortino        float64 `json:"sortino"`         // 年化索提诺比率
+	MaxDrawdown    float64 `json:"max_drawdown"`    // 最大回撤(%)
+	WinRate        float64 `json:"win_rate"`        // 胜率(%)
+	ProfitFactor   float64 `json:"profit_factor"`   // 盈亏比（总盈利/总亏损，没有亏损时为0）
+	TradeCount     int     `json:"trade_count"`     // 交易笔数
+	Liquidations   int     `json:"liquidations"`    // 强平次数
+	TotalFees      float64 `json:"total_fees"`      // 手续费合计
+}
+
+// Report 回测结果
+type Report struct {
+	Strategy string        `json:"strategy"`
+	Config   Config        `json:"config"`
+	Equity   []EquityPoint `json:"equity"`
+	Trades   []Trade       `json:"trades"`
+	Metrics  Metrics       `json:"metrics"`
+}
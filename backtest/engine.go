@@ -0,0 +1,234 @@
+package backtest
+
+import (
+	"fmt"
+	"time"
+
+	"trade/model"
+	"trade/store"
+	"trade/utils"
+)
+
+// position 当前持仓
+type position struct {
+	side       Signal
+	entryTime  time.Time
+	entryPrice float64
+	quantity   float64
+	margin     float64
+	entryFee   float64
+	bars       int
+}
+
+// direction 做多为1，做空为-1
+func (p *position) direction() float64 {
+	if p.side == SignalShort {
+		return -1
+	}
+	return 1
+}
+
+// unrealized 按 price 估算的浮动盈亏
+func (p *position) unrealized(price float64) float64 {
+	return p.direction() * p.quantity * (price - p.entryPrice)
+}
+
+// liquidationPrice 强平价：亏损吃掉全部保证金的价格（不考虑维持保证金率）
+func (p *position) liquidationPrice(leverage float64) float64 {
+	return p.entryPrice * (1 - p.direction()/leverage)
+}
+
+// engine 单次回测的运行状态
+type engine struct {
+	config Config
+	cash   float64 // 不含持仓浮动盈亏的权益
+	pos    *position
+	report *Report
+}
+
+// Run 回放 config 指定交易对+周期的历史K线，按策略信号模拟开平仓
+// Start 之前的K线只喂给策略预热，不交易
+func Run(config Config, strategy Strategy) (*Report, error) {
+	if err := validate(config); err != nil {
+		return nil, err
+	}
+
+	klines, err := store.Default().FindKlines(store.KlineQuery{
+		Symbol:   config.Symbol,
+		Interval: config.Interval,
+		End:      config.End,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("加载K线失败: %w", err)
+	}
+
+	e := &engine{
+		config: config,
+		cash:   config.InitialCapital,
+		report: &Report{
+			Strategy: strategy.Name(),
+			Config:   config,
+			Equity:   []EquityPoint{},
+			Trades:   []Trade{},
+		},
+	}
+
+	var last *model.Kline
+	for i := range klines {
+		kline := klines[i]
+		if kline.OpenTime.Before(config.Start) {
+			strategy.Observe(kline)
+			continue
+		}
+		if len(e.report.Equity) == 0 {
+			e.report.Equity = append(e.report.Equity, EquityPoint{Time: kline.OpenTime, Equity: e.cash})
+		}
+
+		e.rebalance(strategy.Signal(kline.OpenTime), kline)
+		e.checkLiquidation(kline)
+		if e.pos != nil {
+			e.pos.bars++
+		}
+
+		strategy.Observe(kline)
+		e.report.Equity = append(e.report.Equity, EquityPoint{Time: utils.NextOpenTime(kline.OpenTime, kline.Interval), Equity: e.equity(kline.Close)})
+		last = &klines[i]
+
+		// 爆仓后无法继续交易
+		if e.cash <= 0 && e.pos == nil {
+			break
+		}
+	}
+
+	if last == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoData, config.Symbol, config.Interval)
+	}
+
+	// 回测结束时按最后一根K线收盘价平仓
+	if e.pos != nil {
+		e.close(utils.NextOpenTime(last.OpenTime, last.Interval), last.Close, false)
+		e.report.Equity[len(e.report.Equity)-1].Equity = e.cash
+	}
+
+	e.report.Metrics = calculateMetrics(config, e.report.Equity, e.report.Trades)
+	return e.report, nil
+}
+
+// validate 检查回测参数
+func validate(config Config) error {
+	if config.Symbol == "" || config.Interval == "" {
+		return fmt.Errorf("交易对和周期不能为空")
+	}
+	if config.InitialCapital <= 0 {
+		return fmt.Errorf("初始资金必须大于0")
+	}
+	if config.Leverage < 1 {
+		return fmt.Errorf("杠杆倍数不能小于1")
+	}
+	if config.PositionSize <= 0 || config.PositionSize > 1 {
+		return fmt.Errorf("仓位比例必须在(0, 1]之间")
+	}
+	if config.FeeRate < 0 || config.SlippageRate < 0 {
+		return fmt.Errorf("手续费率和滑点不能为负")
+	}
+	if !config.End.IsZero() && config.End.Before(config.Start) {
+		return fmt.Errorf("结束时间不能早于开始时间")
+	}
+	return nil
+}
+
+// rebalance 在K线开盘时按信号调整持仓：方向变化先平仓，再按新方向开仓
+func (e *engine) rebalance(signal Signal, kline model.Kline) {
+	if signal == SignalShort && !e.config.AllowShort {
+		signal = SignalNone
+	}
+	if e.pos != nil && e.pos.side != signal {
+		e.close(kline.OpenTime, kline.Open, false)
+	}
+	if e.pos == nil && signal != SignalNone {
+		e.open(signal, kline.OpenTime, kline.Open)
+	}
+}
+
+// open 以 price 开仓，成交价计入滑点，手续费从权益中扣除
+func (e *engine) open(side Signal, at time.Time, price float64) {
+	margin := e.cash * e.config.PositionSize
+	if margin <= 0 || price <= 0 {
+		return
+	}
+
+	fill := e.fillPrice(side, price, true)
+	quantity := margin * e.config.Leverage / fill
+	fee := quantity * fill * e.config.FeeRate
+	e.cash -= fee
+
+	e.pos = &position{
+		side:       side,
+		entryTime:  at,
+		entryPrice: fill,
+		quantity:   quantity,
+		margin:     margin,
+		entryFee:   fee,
+	}
+}
+
+// close 以 price 平仓并记录交易，强平时成交价即强平价，不再计滑点
+func (e *engine) close(at time.Time, price float64, liquidated bool) {
+	p := e.pos
+	fill := price
+	if !liquidated {
+		fill = e.fillPrice(p.side, price, false)
+	}
+
+	fee := p.quantity * fill * e.config.FeeRate
+	gross := p.unrealized(fill)
+	e.cash += gross - fee
+
+	pnl := gross - p.entryFee - fee
+	e.report.Trades = append(e.report.Trades, Trade{
+		Side:       p.side,
+		EntryTime:  p.entryTime,
+		EntryPrice: p.entryPrice,
+		ExitTime:   at,
+		ExitPrice:  fill,
+		Quantity:   p.quantity,
+		Margin:     p.margin,
+		PnL:        pnl,
+		ReturnPct:  pnl / p.margin * 100,
+		Fees:       p.entryFee + fee,
+		Bars:       p.bars,
+		Liquidated: liquidated,
+	})
+	e.pos = nil
+}
+
+// checkLiquidation 用K线最高/最低价检查持仓是否触及强平价
+func (e *engine) checkLiquidation(kline model.Kline) {
+	if e.pos == nil {
+		return
+	}
+	liquidation := e.pos.liquidationPrice(e.config.Leverage)
+	if e.pos.side == SignalLong && kline.Low <= liquidation ||
+		e.pos.side == SignalShort && kline.High >= liquidation {
+		e.pos.bars++
+		e.close(utils.NextOpenTime(kline.OpenTime, kline.Interval), liquidation, true)
+	}
+}
+
+// fillPrice 计入滑点后的成交价：买入向上滑、卖出向下滑
+func (e *engine) fillPrice(side Signal, price float64, opening bool) float64 {
+	// 开多、平空是买入；开空、平多是卖出
+	buy := (side == SignalLong) == opening
+	if buy {
+		return price * (1 + e.config.SlippageRate)
+	}
+	return price * (1 - e.config.SlippageRate)
+}
+
+// equity 按 price 估值的总权益
+func (e *engine) equity(price float64) float64 {
+	if e.pos == nil {
+		return e.cash
+	}
+	return e.cash + e.pos.unrealized(price)
+}
@@ -0,0 +1,289 @@
+package backtest
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"trade/model"
+	"trade/store"
+	"trade/strategy"
+)
+
+// fixedStrategy 始终返回同一方向，并检查引擎没有提前喂入未收盘的K线
+type fixedStrategy struct {
+	t        *testing.T
+	signal   Signal
+	observed []time.Time
+}
+
+func (s *fixedStrategy) Name() string { return "fixed" }
+
+func (s *fixedStrategy) Signal(openTime time.Time) Signal {
+	for _, seen := range s.observed {
+		if !seen.Before(openTime) {
+			s.t.Fatalf("在 %s 开盘时已经看到了 %s 的K线", openTime, seen)
+		}
+	}
+	return s.signal
+}
+
+func (s *fixedStrategy) Observe(kline model.Kline) {
+	s.observed = append(s.observed, kline.OpenTime)
+}
+
+func day(d int) time.Time {
+	return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+func dailyKline(d int, open, high, low, close float64) model.Kline {
+	k := model.Kline{
+		Symbol:    "BTCUSDT",
+		Interval:  "1d",
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+		OpenTime:  day(d),
+		CloseTime: day(d + 1).Add(-time.Second), // 入库的收盘时间截断到秒
+	}
+	k.FillTimeFields()
+	return k
+}
+
+func useKlines(t *testing.T, klines ...model.Kline) {
+	t.Helper()
+	memory := store.NewMemoryStore()
+	if _, err := memory.UpsertKlines(klines); err != nil {
+		t.Fatalf("写入测试K线失败: %v", err)
+	}
+	previous := store.Default()
+	store.SetDefault(memory)
+	t.Cleanup(func() { store.SetDefault(previous) })
+}
+
+func testConfig() Config {
+	config := DefaultConfig()
+	config.Symbol = "BTCUSDT"
+	config.Interval = "1d"
+	config.InitialCapital = 1000
+	config.FeeRate = 0
+	config.SlippageRate = 0
+	return config
+}
+
+func TestRunLongWithFees(t *testing.T) {
+	useKlines(t,
+		dailyKline(1, 100, 110, 100, 110),
+		dailyKline(2, 110, 121, 110, 121),
+	)
+
+	// 无手续费：持有两天 +21%
+	report, err := Run(testConfig(), &fixedStrategy{t: t, signal: SignalLong})
+	if err != nil {
+		t.Fatalf("回测失败: %v", err)
+	}
+	if math.Abs(report.Metrics.FinalEquity-1210) > 1e-9 || len(report.Trades) != 1 {
+		t.Fatalf("期望权益 1210、1笔交易，实际 %+v", report.Metrics)
+	}
+	if len(report.Equity) != 3 || report.Equity[1].Equity != 1100 {
+		t.Errorf("权益曲线错误: %+v", report.Equity)
+	}
+	// 权益点和回测结束平仓的时间都是下一根K线的开盘时间
+	if !report.Equity[1].Time.Equal(day(2)) || !report.Trades[0].ExitTime.Equal(day(3)) {
+		t.Errorf("K线结束时间错误: %v %v", report.Equity[1].Time, report.Trades[0].ExitTime)
+	}
+
+	// 手续费 0.1%：开仓 100*10*0.001=1，平仓 121*10*0.001=1.21
+	config := testConfig()
+	config.FeeRate = 0.001
+	report, _ = Run(config, &fixedStrategy{t: t, signal: SignalLong})
+	trade := report.Trades[0]
+	if math.Abs(trade.PnL-207.79) > 1e-9 || math.Abs(trade.Fees-2.21) > 1e-9 || trade.Bars != 2 {
+		t.Errorf("交易明细错误: %+v", trade)
+	}
+	if math.Abs(report.Metrics.FinalEquity-1207.79) > 1e-9 || report.Metrics.WinRate != 100 {
+		t.Errorf("绩效指标错误: %+v", report.Metrics)
+	}
+
+	// 滑点：买入价向上滑
+	config = testConfig()
+	config.SlippageRate = 0.01
+	report, _ = Run(config, &fixedStrategy{t: t, signal: SignalLong})
+	if trade := report.Trades[0]; trade.EntryPrice != 101 || math.Abs(trade.ExitPrice-119.79) > 1e-9 {
+		t.Errorf("滑点成交价错误: %+v", trade)
+	}
+}
+
+func TestRunShortAndLiquidation(t *testing.T) {
+	useKlines(t,
+		dailyKline(1, 100, 101, 89, 95),
+		dailyKline(2, 95, 96, 90, 90),
+	)
+
+	// 不允许做空时看跌信号不开仓
+	config := testConfig()
+	config.AllowShort = false
+	report, _ := Run(config, &fixedStrategy{t: t, signal: SignalShort})
+	if len(report.Trades) != 0 || report.Metrics.FinalEquity != 1000 {
+		t.Errorf("不允许做空时不应交易: %+v", report.Metrics)
+	}
+
+	// 做空盈利 10%
+	report, _ = Run(testConfig(), &fixedStrategy{t: t, signal: SignalShort})
+	if math.Abs(report.Metrics.FinalEquity-1100) > 1e-9 {
+		t.Errorf("做空最终权益期望 1100，实际 %.4f", report.Metrics.FinalEquity)
+	}
+
+	// 10倍杠杆做多，最低价 89 跌破强平价 90，损失全部保证金
+	config = testConfig()
+	config.Leverage = 10
+	report, _ = Run(config, &fixedStrategy{t: t, signal: SignalLong})
+	if len(report.Trades) != 1 || !report.Trades[0].Liquidated || report.Trades[0].ExitPrice != 90 {
+		t.Fatalf("应被强平: %+v", report.Trades)
+	}
+	if !report.Trades[0].ExitTime.Equal(day(2)) {
+		t.Errorf("强平时间应为K线结束时间，实际 %v", report.Trades[0].ExitTime)
+	}
+	if report.Metrics.FinalEquity > 1e-9 || report.Metrics.Liquidations != 1 || report.Metrics.MaxDrawdown < 99.999 {
+		t.Errorf("强平后权益应归零: %+v", report.Metrics)
+	}
+}
+
+func TestRunWarmupAndErrors(t *testing.T) {
+	useKlines(t,
+		dailyKline(1, 100, 110, 100, 110),
+		dailyKline(2, 110, 121, 110, 121),
+		dailyKline(3, 121, 121, 100, 100),
+	)
+
+	// Start 之前的K线只喂给策略，不交易；End 之后的K线不参与
+	config := testConfig()
+	config.Start = day(2)
+	config.End = day(2)
+	strategy := &fixedStrategy{t: t, signal: SignalLong}
+	report, err := Run(config, strategy)
+	if err != nil {
+		t.Fatalf("回测失败: %v", err)
+	}
+	if len(strategy.observed) != 2 || report.Trades[0].EntryTime != day(2) || report.Trades[0].ExitPrice != 121 {
+		t.Errorf("预热或区间处理错误: observed=%v trades=%+v", strategy.observed, report.Trades)
+	}
+
+	config.Start = day(10)
+	config.End = time.Time{}
+	if _, err := Run(config, strategy); !errors.Is(err, ErrNoData) {
+		t.Errorf("区间内无数据应返回 ErrNoData，实际 %v", err)
+	}
+
+	config = testConfig()
+	config.PositionSize = 1.5
+	if _, err := Run(config, strategy); err == nil {
+		t.Error("仓位比例超过1应报错")
+	}
+}
+
+// countingStore 统计K线查询次数的内存存储
+type countingStore struct {
+	*store.MemoryStore
+	finds int
+}
+
+func (s *countingStore) FindKlines(query store.KlineQuery) ([]model.Kline, error) {
+	s.finds++
+	return s.MemoryStore.FindKlines(query)
+}
+
+func TestModelStrategy(t *testing.T) {
+	memory := &countingStore{MemoryStore: store.NewMemoryStore()}
+	store.SetDefault(memory)
+	t.Cleanup(func() { store.SetDefault(nil) })
+
+	// 60 天小时K线：08:00 持续上涨、09:00 持续下跌，其余小时小幅波动
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var klines []model.Kline
+	for d := 0; d < 60; d++ {
+		for h := 0; h < 24; h++ {
+			closePrice := 100 + float64((d*7+h*13)%11-5)*0.1
+			switch h {
+			case 8:
+				closePrice = 102 + float64(d%3)*0.1
+			case 9:
+				closePrice = 98 - float64(d%3)*0.1
+			}
+			openTime := start.Add(time.Duration(d*24+h) * time.Hour)
+			k := model.Kline{
+				Symbol: "BTCUSDT", Interval: "1h", OpenTime: openTime, CloseTime: openTime.Add(time.Hour - time.Second),
+				Open: 100, High: 103, Low: 97, Close: closePrice,
+			}
+			k.FillTimeFields()
+			klines = append(klines, k)
+		}
+	}
+	memory.UpsertKlines(klines)
+
+	// 回测只在开始时加载一次K线，信号由逐根喂入的K线在内存中计算
+	s, err := NewStrategy("strategy_2", "1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := testConfig()
+	config.Interval = "1h"
+	config.Start = start.AddDate(0, 0, 30)
+	report, err := Run(config, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if memory.finds != 1 {
+		t.Errorf("回测期间不应逐根查询存储，实际查询 %d 次", memory.finds)
+	}
+	if len(report.Trades) == 0 {
+		t.Error("持续上涨/下跌的小时应产生交易")
+	}
+
+	// 预热完全部K线后，信号与策略按同一时点查询的结果一致
+	provider, _ := strategy.GetSignalProvider("strategy_2")
+	day := start.AddDate(0, 0, 60)
+	for h := 0; h < 24; h++ {
+		openTime := day.Add(time.Duration(h) * time.Hour)
+		want, err := provider.Signal("BTCUSDT", "1h", openTime, openTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := s.Signal(openTime)
+		if (want == strategy.SignalBullish) != (got == SignalLong) || (want == strategy.SignalBearish) != (got == SignalShort) {
+			t.Errorf("%02d:00 回测信号 %s 与策略信号 %s 不一致", h, got, want)
+		}
+	}
+	if got := s.Signal(day.Add(8 * time.Hour)); got != SignalLong {
+		t.Errorf("08:00 持续上涨应做多，实际 %s", got)
+	}
+	if got := s.Signal(day.Add(9 * time.Hour)); got != SignalShort {
+		t.Errorf("09:00 持续下跌应做空，实际 %s", got)
+	}
+
+	// 没有历史数据时空仓
+	fresh, _ := NewStrategy("strategy_2", "1h")
+	if got := fresh.Signal(start); got != SignalNone {
+		t.Errorf("没有历史数据应空仓，实际 %s", got)
+	}
+
+	if _, err := NewStrategy("strategy_9", "1h"); err == nil {
+		t.Error("未知策略应报错")
+	}
+	if _, err := NewStrategy("strategy_2", "1d"); err == nil {
+		t.Error("策略不适用的周期应报错")
+	}
+}
+
+func TestRiskAdjusted(t *testing.T) {
+	sharpe, sortino := riskAdjusted([]float64{0.01, -0.01, 0.02, 0.0}, 365)
+	// 均值 0.005，样本标准差 0.0129099，下行偏差 sqrt(0.0001/4)=0.005
+	if math.Abs(sharpe-0.005/0.012909944*math.Sqrt(365)) > 1e-6 {
+		t.Errorf("夏普比率错误: %.6f", sharpe)
+	}
+	if math.Abs(sortino-math.Sqrt(365)) > 1e-6 {
+		t.Errorf("索提诺比率错误: %.6f", sortino)
+	}
+}
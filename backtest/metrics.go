@@ -0,0 +1,115 @@
+package backtest
+
+import (
+	"math"
+	"time"
+
+	"trade/utils"
+)
+
+// yearDuration 一年的时长（加密货币 7×24 小时交易）
+const yearDuration = 365 * 24 * time.Hour
+
+// calculateMetrics 根据权益曲线和交易列表计算绩效指标
+func calculateMetrics(config Config, equity []EquityPoint, trades []Trade) Metrics {
+	metrics := Metrics{
+		InitialCapital: config.InitialCapital,
+		FinalEquity:    config.InitialCapital,
+		TradeCount:     len(trades),
+	}
+	if len(equity) == 0 {
+		return metrics
+	}
+
+	final := equity[len(equity)-1]
+	metrics.FinalEquity = final.Equity
+	metrics.TotalReturn = (final.Equity/config.InitialCapital - 1) * 100
+
+	// 年化复合收益率
+	years := float64(final.Time.Sub(equity[0].Time)) / float64(yearDuration)
+	if years > 0 && final.Equity > 0 {
+		metrics.CAGR = (math.Pow(final.Equity/config.InitialCapital, 1/years) - 1) * 100
+	} else if final.Equity <= 0 {
+		metrics.CAGR = -100
+	}
+
+	// 夏普/索提诺：按每根K线的权益收益率计算后年化
+	returns := make([]float64, 0, len(equity)-1)
+	for i := 1; i < len(equity); i++ {
+		if equity[i-1].Equity > 0 {
+			returns = append(returns, equity[i].Equity/equity[i-1].Equity-1)
+		}
+	}
+	barsPerYear := float64(yearDuration) / float64(utils.IntervalDuration(config.Interval))
+	metrics.Sharpe, metrics.Sortino = riskAdjusted(returns, barsPerYear)
+
+	// 最大回撤
+	peak := equity[0].Equity
+	for _, point := range equity {
+		if point.Equity > peak {
+			peak = point.Equity
+		}
+		if peak > 0 {
+			if drawdown := (peak - point.Equity) / peak * 100; drawdown > metrics.MaxDrawdown {
+				metrics.MaxDrawdown = drawdown
+			}
+		}
+	}
+
+	// 胜率、盈亏比、手续费
+	var wins int
+	var grossWin, grossLoss float64
+	for _, trade := range trades {
+		if trade.PnL > 0 {
+			wins++
+			grossWin += trade.PnL
+		} else if trade.PnL < 0 {
+			grossLoss -= trade.PnL
+		}
+		if trade.Liquidated {
+			metrics.Liquidations++
+		}
+		metrics.TotalFees += trade.Fees
+	}
+	if len(trades) > 0 {
+		metrics.WinRate = float64(wins) / float64(len(trades)) * 100
+	}
+	if grossLoss > 0 {
+		metrics.ProfitFactor = grossWin / grossLoss
+	}
+
+	return metrics
+}
+
+// riskAdjusted 计算年化夏普和索提诺比率（无风险利率按0）
+func riskAdjusted(returns []float64, periodsPerYear float64) (sharpe, sortino float64) {
+	n := len(returns)
+	if n < 2 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, r := range returns {
+		sum += r
+	}
+	mean := sum / float64(n)
+
+	var variance, downside float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+		if r < 0 {
+			downside += r * r
+		}
+	}
+	stdDev := math.Sqrt(variance / float64(n-1))
+	downsideDev := math.Sqrt(downside / float64(n))
+
+	annualize := math.Sqrt(periodsPerYear)
+	if stdDev > 0 {
+		sharpe = mean / stdDev * annualize
+	}
+	if downsideDev > 0 {
+		sortino = mean / downsideDev * annualize
+	}
+	return sharpe, sortino
+}
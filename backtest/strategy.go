@@ -0,0 +1,49 @@
+package backtest
+
+import (
+	"time"
+
+	"trade/model"
+	"trade/strategy"
+)
+
+// ModelStrategy 用已注册策略的内存信号模型回测，与模拟盘和实盘下单使用同一条规则（含多重比较校正）
+// 引擎逐根喂入已收盘的K线，每根K线开盘时只用之前的统计给出信号，不再查询数据库
+type ModelStrategy struct {
+	name  string
+	model *strategy.SignalModel
+}
+
+// NewStrategy 按策略名称创建回测策略，策略必须支持自动交易且适用于该周期
+func NewStrategy(name, interval string) (*ModelStrategy, error) {
+	m, err := strategy.NewSignalModel(name, interval)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelStrategy{name: name, model: m}, nil
+}
+
+func (s *ModelStrategy) Name() string {
+	return s.name
+}
+
+func (s *ModelStrategy) Signal(openTime time.Time) Signal {
+	// 分组没有历史数据时空仓
+	signal, err := s.model.Signal(openTime)
+	if err != nil {
+		return SignalNone
+	}
+
+	switch signal {
+	case strategy.SignalBullish:
+		return SignalLong
+	case strategy.SignalBearish:
+		return SignalShort
+	default:
+		return SignalNone
+	}
+}
+
+func (s *ModelStrategy) Observe(kline model.Kline) {
+	s.model.Observe(kline)
+}
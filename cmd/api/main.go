@@ -1,26 +1,39 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"log"
 
 	"trade/api/router"
 	"trade/db"
+	"trade/utils"
 
 	"github.com/cloudwego/hertz/pkg/app/server"
 )
 
 func main() {
+	configPath := flag.String("config", utils.DefaultConfigPath(), "配置文件路径")
+	flag.Parse()
+
+	// 加载配置文件
+	config, err := utils.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("加载配置文件失败: %v", err)
+	}
+
 	// 初始化数据库连接
 	fmt.Println("正在初始化数据库连接...")
-	db.InitPostgreSql()
+	db.InitPostgreSql(&config.Database)
 	fmt.Println("✓ 数据库连接成功")
 
-	// 初始化币安客户端(如果需要API密钥，请在这里填写)
-	db.InitBinance("", "")
+	// 初始化币安客户端(API密钥在配置文件或环境变量中设置)
+	db.InitBinance(&config.Binance)
 
 	// 创建Hertz服务器
+	port := config.API.Port
 	h := server.Default(
-		server.WithHostPorts(":8080"),
+		server.WithHostPorts(config.API.Addr()),
 		server.WithMaxRequestBodySize(4*1024*1024), // 4MB
 	)
 
@@ -31,17 +44,17 @@ func main() {
 	fmt.Println("\n╔═══════════════════════════════════════════════════════════════╗")
 	fmt.Println("║           Trade Strategy API Server                          ║")
 	fmt.Println("╚═══════════════════════════════════════════════════════════════╝")
-	fmt.Println("\n服务已启动，监听端口: 8080")
+	fmt.Printf("\n服务已启动，监听端口: %d\n", port)
 	fmt.Println("\n可用接口:")
-	fmt.Println("  - GET  http://localhost:8080/")
-	fmt.Println("  - GET  http://localhost:8080/health")
-	fmt.Println("  - GET  http://localhost:8080/api/v1/strategy/analyze")
-	fmt.Println("  - POST http://localhost:8080/api/v1/strategy/analyze")
+	fmt.Printf("  - GET  http://localhost:%d/\n", port)
+	fmt.Printf("  - GET  http://localhost:%d/health\n", port)
+	fmt.Printf("  - GET  http://localhost:%d/api/v1/strategy/analyze\n", port)
+	fmt.Printf("  - POST http://localhost:%d/api/v1/strategy/analyze\n", port)
 	fmt.Println("\n示例请求:")
 	fmt.Println("  # 策略一 - 历史同期涨跌分析")
-	fmt.Println("  curl 'http://localhost:8080/api/v1/strategy/analyze?strategy_type=strategy_1&symbol=BTCUSDT&interval=1d&date=2024-10-30'")
+	fmt.Printf("  curl 'http://localhost:%d/api/v1/strategy/analyze?strategy_type=strategy_1&symbol=BTCUSDT&interval=1d&date=2024-10-30'\n", port)
 	fmt.Println("\n  # 策略二 - 小时级别涨跌分析")
-	fmt.Println("  curl 'http://localhost:8080/api/v1/strategy/analyze?strategy_type=strategy_2&symbol=BTCUSDT&interval=1h&hour=14'")
+	fmt.Printf("  curl 'http://localhost:%d/api/v1/strategy/analyze?strategy_type=strategy_2&symbol=BTCUSDT&interval=1h&hour=14'\n", port)
 	fmt.Println("\n按 Ctrl+C 停止服务")
 	fmt.Println("═══════════════════════════════════════════════════════════════\n")
 
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"trade/db"
+	"trade/kline"
+	"trade/utils"
+)
+
+func main() {
+	// 命令行参数
+	symbol := flag.String("symbol", "", "只回填指定交易对（默认回填配置文件中的全部交易对）")
+	interval := flag.String("interval", "", "只回填指定时间周期（默认回填配置文件中的全部周期）")
+	configPath := flag.String("config", utils.DefaultConfigPath(), "配置文件路径")
+	flag.Parse()
+
+	// 加载配置文件
+	config, err := utils.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Printf("❌ 加载配置文件失败: %v\n", err)
+		return
+	}
+
+	// 初始化币安客户端
+	db.InitBinance(&config.Binance)
+
+	// 初始化数据库连接（自动迁移会添加成交量字段）
+	db.InitPostgreSql(&config.Database)
+
+	fmt.Println("========== 开始回填K线成交量数据 ==========")
+
+	for _, symbolConfig := range config.Symbols {
+		if *symbol != "" && symbolConfig.Symbol != *symbol {
+			continue
+		}
+
+		for _, iv := range symbolConfig.Intervals {
+			if *interval != "" && iv != *interval {
+				continue
+			}
+
+			remaining, err := kline.BackfillVolume(symbolConfig.Symbol, iv)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			if remaining > 0 {
+				fmt.Printf("⚠️  %s %s 仍有 %d 根K线成交笔数为 0（可能确实无成交）\n", symbolConfig.Symbol, iv, remaining)
+			}
+		}
+	}
+
+	fmt.Println("\n========== 成交量回填完成 ==========")
+}
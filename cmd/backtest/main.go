@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"trade/backtest"
+	"trade/db"
+	"trade/utils"
+)
+
+func main() {
+	defaults := backtest.DefaultConfig()
+
+	// 命令行参数
+	symbol := flag.String("symbol", "BTCUSDT", "交易对")
+	interval := flag.String("interval", "1d", "K线周期")
+	strategyName := flag.String("strategy", "strategy_1", "回测策略: strategy_1(历史同期) / strategy_2(小时) / strategy_3(星期)")
+	start := flag.String("start", "", "回测开始日期(UTC)，之前的数据只用于策略预热，格式：2022-01-01")
+	end := flag.String("end", "", "回测结束日期(UTC)，默认到最新K线，格式：2024-12-31")
+	capital := flag.Float64("capital", defaults.InitialCapital, "初始资金(USDT)")
+	fee := flag.Float64("fee", defaults.FeeRate, "手续费率，按成交额收取")
+	slippage := flag.Float64("slippage", defaults.SlippageRate, "滑点，按成交价的比例")
+	leverage := flag.Float64("leverage", defaults.Leverage, "杠杆倍数")
+	size := flag.Float64("size", defaults.PositionSize, "每次开仓占用保证金占权益的比例 (0-1]")
+	allowShort := flag.Bool("short", defaults.AllowShort, "是否允许做空")
+	showTrades := flag.Bool("trades", false, "输出每笔交易明细")
+	configPath := flag.String("config", utils.DefaultConfigPath(), "配置文件路径")
+	flag.Parse()
+
+	config := backtest.Config{
+		Symbol:         *symbol,
+		Interval:       *interval,
+		InitialCapital: *capital,
+		FeeRate:        *fee,
+		SlippageRate:   *slippage,
+		Leverage:       *leverage,
+		PositionSize:   *size,
+		AllowShort:     *allowShort,
+	}
+
+	var err error
+	if config.Start, err = parseDate(*start); err != nil {
+		fmt.Printf("❌ 开始日期格式错误: %v\n", err)
+		return
+	}
+	if config.End, err = parseDate(*end); err != nil {
+		fmt.Printf("❌ 结束日期格式错误: %v\n", err)
+		return
+	}
+
+	strategy, err := backtest.NewStrategy(*strategyName, *interval)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	// 加载配置文件
+	appConfig, err := utils.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Printf("❌ 加载配置文件失败: %v\n", err)
+		return
+	}
+
+	// 初始化数据库连接
+	db.InitPostgreSql(&appConfig.Database)
+
+	fmt.Printf("========== 回测 %s %s %s ==========\n", strategy.Name(), config.Symbol, config.Interval)
+	report, err := backtest.Run(config, strategy)
+	if err != nil {
+		fmt.Printf("❌ 回测失败: %v\n", err)
+		return
+	}
+
+	printReport(report, *showTrades)
+}
+
+// parseDate 解析 UTC 日期，空字符串返回零值
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.UTC)
+}
+
+// printReport 输出回测绩效和交易明细
+func printReport(report *backtest.Report, showTrades bool) {
+	m := report.Metrics
+	if len(report.Equity) > 0 {
+		fmt.Printf("回测区间: %s ~ %s\n",
+			report.Equity[0].Time.Format("2006-01-02 15:04"),
+			report.Equity[len(report.Equity)-1].Time.Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("手续费率: %.4f%%  滑点: %.4f%%  杠杆: %.1fx  仓位: %.0f%%\n",
+		report.Config.FeeRate*100, report.Config.SlippageRate*100, report.Config.Leverage, report.Config.PositionSize*100)
+
+	fmt.Println("\n---------- 绩效指标 ----------")
+	fmt.Printf("初始资金:   %.2f\n", m.InitialCapital)
+	fmt.Printf("最终权益:   %.2f\n", m.FinalEquity)
+	fmt.Printf("总收益率:   %.2f%%\n", m.TotalReturn)
+	fmt.Printf("年化收益:   %.2f%%\n", m.CAGR)
+	fmt.Printf("夏普比率:   %.2f\n", m.Sharpe)
+	fmt.Printf("索提诺比率: %.2f\n", m.Sortino)
+	fmt.Printf("最大回撤:   %.2f%%\n", m.MaxDrawdown)
+	fmt.Printf("交易笔数:   %d (强平 %d)\n", m.TradeCount, m.Liquidations)
+	fmt.Printf("胜率:       %.2f%%\n", m.WinRate)
+	fmt.Printf("盈亏比:     %.2f\n", m.ProfitFactor)
+	fmt.Printf("手续费合计: %.2f\n", m.TotalFees)
+
+	if !showTrades {
+		return
+	}
+
+	fmt.Println("\n---------- 交易明细 ----------")
+	for i, trade := range report.Trades {
+		mark := ""
+		if trade.Liquidated {
+			mark = " ⚠️ 强平"
+		}
+		fmt.Printf("%4d. %-5s %s @ %.4f -> %s @ %.4f  盈亏 %+.2f (%+.2f%%)  %d根%s\n",
+			i+1, trade.Side,
+			trade.EntryTime.Format("2006-01-02 15:04"), trade.EntryPrice,
+			trade.ExitTime.Format("2006-01-02 15:04"), trade.ExitPrice,
+			trade.PnL, trade.ReturnPct, trade.Bars, mark)
+	}
+}
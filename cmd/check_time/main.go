@@ -1,15 +1,28 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"log"
 	"time"
 
 	"trade/db"
 	"trade/model"
+	"trade/utils"
 )
 
 func main() {
-	db.InitPostgreSql()
+	configPath := flag.String("config", utils.DefaultConfigPath(), "配置文件路径")
+	flag.Parse()
+
+	// 加载配置文件
+	config, err := utils.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("加载配置文件失败: %v", err)
+	}
+
+	// 初始化数据库
+	db.InitPostgreSql(&config.Database)
 
 	// 查询策略一的详细记录，看看 close_time
 	var records []model.Strategy1DetailRecord
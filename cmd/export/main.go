@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"trade/db"
+	"trade/export"
+	"trade/utils"
+)
+
+func main() {
+	// 命令行参数
+	symbol := flag.String("symbol", "", "交易对，如 BTCUSDT")
+	interval := flag.String("interval", "", "时间周期，如 1h")
+	start := flag.String("start", "", "开盘时间下界（含，UTC），格式：2024-01-01 或 2024-01-01T08:00:00Z，默认不限制")
+	end := flag.String("end", "", "开盘时间上界（含，UTC），格式同 -start，默认不限制")
+	format := flag.String("format", export.FormatCSV, "导出格式：csv、jsonl 或 parquet")
+	columns := flag.String("columns", "", "导出的列（逗号分隔），默认全部："+strings.Join(export.ColumnNames(), ","))
+	gzip := flag.Bool("gzip", false, "gzip 压缩（parquet 格式改用 GZIP 列压缩）")
+	output := flag.String("o", "", "输出文件，默认写到标准输出")
+	configPath := flag.String("config", utils.DefaultConfigPath(), "配置文件路径")
+	flag.Parse()
+
+	opts := export.Options{Symbol: *symbol, Interval: *interval, Format: *format, Gzip: *gzip}
+	if *columns != "" {
+		opts.Columns = strings.Split(*columns, ",")
+	}
+	var err error
+	if opts.Start, err = utils.ParseAsOf(*start); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ -start %v\n", err)
+		os.Exit(2)
+	}
+	if opts.End, err = utils.ParseAsOf(*end); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ -end %v\n", err)
+		os.Exit(2)
+	}
+	if err := opts.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(2)
+	}
+
+	// 加载配置文件
+	config, err := utils.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ 加载配置文件失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 初始化数据库连接（日志输出到标准错误，避免混入导出数据）
+	db.InitPostgreSql(&config.Database)
+
+	out := os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ 创建输出文件失败: %v\n", err)
+			os.Exit(1)
+		}
+		defer out.Close()
+	}
+	w := bufio.NewWriter(out)
+
+	count, err := export.Export(w, opts)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ 导出失败（已导出 %d 根）: %v\n", count, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "✅ 导出 %s %s 共 %d 根K线\n", opts.Symbol, opts.Interval, count)
+}
@@ -1,12 +1,14 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"time"
 
 	"trade/db"
 	"trade/model"
+	"trade/utils"
 )
 
 func main() {
@@ -15,8 +17,17 @@ func main() {
 	fmt.Println("╚════════════════════════════════════════════════════════════════╝")
 	fmt.Println()
 
+	configPath := flag.String("config", utils.DefaultConfigPath(), "配置文件路径")
+	flag.Parse()
+
+	// 加载配置文件
+	config, err := utils.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("加载配置文件失败: %v", err)
+	}
+
 	// 初始化数据库
-	db.InitPostgreSql()
+	db.InitPostgreSql(&config.Database)
 
 	// 修复策略一的详细记录
 	fixStrategy1DetailRecords()
@@ -1,16 +1,28 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"log"
 	"time"
 
 	"trade/db"
 	"trade/model"
+	"trade/utils"
 )
 
 func main() {
+	configPath := flag.String("config", utils.DefaultConfigPath(), "配置文件路径")
+	flag.Parse()
+
+	// 加载配置文件
+	config, err := utils.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("加载配置文件失败: %v", err)
+	}
+
 	// 初始化数据库
-	db.InitPostgreSql()
+	db.InitPostgreSql(&config.Database)
 
 	fmt.Println("\n╔═══════════════════════════════════════════╗")
 	fmt.Println("║       旧数据修复工具                      ║")
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"trade/db"
+	"trade/kline"
+	"trade/utils"
+)
+
+func main() {
+	// 命令行参数
+	dir := flag.String("dir", "", "币安公共数据K线文件所在目录（递归查找 ZIP/CSV），如 data/futures/um/monthly/klines")
+	symbol := flag.String("symbol", "", "只导入指定交易对（默认导入目录中的全部交易对）")
+	interval := flag.String("interval", "", "只导入指定时间周期（默认导入目录中的全部周期）")
+	overwrite := flag.Bool("overwrite", false, "用文件中的数据覆盖数据库中已存在的K线（默认只写入不存在的K线）")
+	dryRun := flag.Bool("dry-run", false, "只校验文件并报告与数据库的重叠，不写入")
+	configPath := flag.String("config", utils.DefaultConfigPath(), "配置文件路径")
+	flag.Parse()
+
+	if *dir == "" {
+		fmt.Println("❌ 请通过 -dir 指定归档文件目录")
+		os.Exit(2)
+	}
+
+	// 加载配置文件
+	config, err := utils.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Printf("❌ 加载配置文件失败: %v\n", err)
+		return
+	}
+
+	// 初始化数据库连接
+	db.InitPostgreSql(&config.Database)
+
+	files, err := kline.ScanArchives(*dir, *symbol, *interval)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	fmt.Printf("========== 导入币安公共数据K线：%d 个文件 ==========\n", len(files))
+
+	var failed, rows, overlap, conflicts int
+	var written int64
+	for _, file := range files {
+		result, err := kline.ImportArchive(file, *overwrite, *dryRun)
+		if err != nil {
+			failed++
+			fmt.Printf("\n❌ %v\n", err)
+			continue
+		}
+		printImport(result)
+
+		rows += result.Rows
+		overlap += result.Overlap
+		conflicts += len(result.Conflicts)
+		written += result.Written
+	}
+
+	fmt.Println("\n========== 导入完成 ==========")
+	fmt.Printf("文件: %d 个（失败 %d 个）\n", len(files), failed)
+	fmt.Printf("K线: %d 根 | 已存在: %d 根 | 不一致: %d 根 | 写入: %d 根\n", rows, overlap, conflicts, written)
+	if *dryRun {
+		fmt.Println("dry-run 模式，未写入数据库")
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// printImport 打印单个文件的导入结果
+func printImport(result *kline.ArchiveImport) {
+	fmt.Printf("\n--- %s (%s %s) ---\n", filepath.Base(result.File.Path), result.File.Symbol, result.File.Interval)
+	fmt.Printf("  范围: %s ~ %s | %d 根\n",
+		result.FirstOpen.Format("2006-01-02 15:04:05"), result.LastOpen.Format("2006-01-02 15:04:05"), result.Rows)
+	fmt.Printf("  已存在: %d 根 | 写入: %d 根\n", result.Overlap, result.Written)
+
+	for _, gap := range result.Gaps {
+		fmt.Printf("  ⚠️  文件缺少: %s ~ %s (%d 根)\n",
+			gap.Start.Format("2006-01-02 15:04:05"), gap.End.Format("2006-01-02 15:04:05"), gap.Missing)
+	}
+	for i, t := range result.Conflicts {
+		if i == 5 {
+			fmt.Printf("  ⚠️  ... 共 %d 根与数据库不一致\n", len(result.Conflicts))
+			break
+		}
+		fmt.Printf("  ⚠️  与数据库不一致: %s\n", t.Format("2006-01-02 15:04:05"))
+	}
+}
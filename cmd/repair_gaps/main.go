@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"trade/db"
+	"trade/kline"
+	"trade/utils"
+)
+
+func main() {
+	// 命令行参数
+	symbol := flag.String("symbol", "", "只检查指定交易对（默认检查配置文件中的全部交易对）")
+	interval := flag.String("interval", "", "只检查指定时间周期（默认检查配置文件中的全部周期）")
+	dryRun := flag.Bool("dry-run", false, "只检查缺口，不从币安重新拉取")
+	configPath := flag.String("config", utils.DefaultConfigPath(), "配置文件路径")
+	flag.Parse()
+
+	// 加载配置文件
+	config, err := utils.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Printf("❌ 加载配置文件失败: %v\n", err)
+		return
+	}
+
+	// 初始化币安客户端
+	db.InitBinance(&config.Binance)
+
+	// 初始化数据库连接
+	db.InitPostgreSql(&config.Database)
+
+	fmt.Println("========== K线连续性检查 ==========")
+
+	totalMissing := 0
+	totalRepaired := 0
+	for _, symbolConfig := range config.Symbols {
+		if *symbol != "" && symbolConfig.Symbol != *symbol {
+			continue
+		}
+
+		for _, iv := range symbolConfig.Intervals {
+			if *interval != "" && iv != *interval {
+				continue
+			}
+
+			report, err := kline.ScanGaps(symbolConfig.Symbol, iv)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			printReport(report)
+
+			missing := report.MissingCount()
+			totalMissing += missing
+			if missing == 0 || *dryRun {
+				continue
+			}
+
+			// 修复后重新检查，统计实际补齐的数量
+			kline.RepairGaps(report)
+			after, err := kline.ScanGaps(symbolConfig.Symbol, iv)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			repaired := missing - after.MissingCount()
+			totalRepaired += repaired
+
+			fmt.Printf("🔧 修复结果 %s %s: 补齐 %d 根，仍缺失 %d 根\n",
+				symbolConfig.Symbol, iv, repaired, after.MissingCount())
+			for _, gap := range after.Gaps {
+				fmt.Printf("   ⚠️  无法补齐（交易所可能无数据）: %s ~ %s (%d 根)\n",
+					gap.Start.Format("2006-01-02 15:04:05"), gap.End.Format("2006-01-02 15:04:05"), gap.Missing)
+			}
+		}
+	}
+
+	fmt.Println("\n========== 检查完成 ==========")
+	fmt.Printf("缺失K线总数: %d\n", totalMissing)
+	if !*dryRun {
+		fmt.Printf("已补齐K线数: %d\n", totalRepaired)
+	}
+}
+
+// printReport 打印单个交易对+时间周期的检查结果
+func printReport(report *kline.GapReport) {
+	fmt.Printf("\n--- %s %s ---\n", report.Symbol, report.Interval)
+	if report.Actual == 0 {
+		fmt.Println("  0 条数据 ❌")
+		return
+	}
+
+	fmt.Printf("  范围: %s ~ %s\n",
+		report.FirstOpen.Format("2006-01-02 15:04:05"), report.LastOpen.Format("2006-01-02 15:04:05"))
+	fmt.Printf("  实际: %d 根 | 应有: %d 根 | 缺失: %d 根 | 重复: %d 根\n",
+		report.Actual, report.Expected, report.MissingCount(), len(report.Duplicates))
+
+	for _, gap := range report.Gaps {
+		fmt.Printf("  ❌ 缺口: %s ~ %s (%d 根)\n",
+			gap.Start.Format("2006-01-02 15:04:05"), gap.End.Format("2006-01-02 15:04:05"), gap.Missing)
+	}
+	for _, t := range report.Duplicates {
+		fmt.Printf("  ⚠️  重复: %s\n", t.Format("2006-01-02 15:04:05"))
+	}
+	if len(report.Gaps) == 0 && len(report.Duplicates) == 0 {
+		fmt.Println("  ✅ 数据连续")
+	}
+}
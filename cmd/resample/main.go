@@ -0,0 +1,131 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"trade/db"
+	"trade/resample"
+	"trade/utils"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+func main() {
+	// 命令行参数
+	symbol := flag.String("symbol", "", "交易对（默认使用配置文件中的全部交易对）")
+	base := flag.String("base", "1h", "基础周期，如 1m、1h")
+	targets := flag.String("target", "2h,4h,8h,12h,1d,1w", "合成的周期标签(逗号分隔)，支持 1d@America/New_York（按时区切日）、1d+8h（偏移）")
+	startStr := flag.String("start", "", "从该时间所在的K线开始合成(UTC)，默认从最早的基础K线开始，格式：2024-10-30 或 RFC3339")
+	endStr := flag.String("end", "", "合成到该时间之前开盘的K线(UTC)，默认到最新已收盘的K线")
+	check := flag.Bool("check", false, "只与交易所拉取的同周期K线比对一致性，不写入")
+	tolerance := flag.Float64("tolerance", resample.DefaultTolerance, "一致性比对的相对误差")
+	dryRun := flag.Bool("dry-run", false, "只合成并统计，不写入数据库")
+	configPath := flag.String("config", utils.DefaultConfigPath(), "配置文件路径")
+	flag.Parse()
+
+	start, err := utils.ParseAsOf(*startStr)
+	if err != nil {
+		fmt.Printf("❌ -start %v\n", err)
+		os.Exit(2)
+	}
+	end, err := utils.ParseAsOf(*endStr)
+	if err != nil {
+		fmt.Printf("❌ -end %v\n", err)
+		os.Exit(2)
+	}
+
+	// 加载配置文件
+	config, err := utils.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Printf("❌ 加载配置文件失败: %v\n", err)
+		return
+	}
+
+	symbols := []string{*symbol}
+	if *symbol == "" {
+		symbols = symbols[:0]
+		for _, symbolConfig := range config.Symbols {
+			symbols = append(symbols, symbolConfig.Symbol)
+		}
+	}
+
+	var specs []resample.Spec
+	for _, s := range symbols {
+		for _, label := range strings.Split(*targets, ",") {
+			spec, err := resample.ParseSpec(s, *base, strings.TrimSpace(label))
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(2)
+			}
+			specs = append(specs, spec)
+		}
+	}
+
+	// 初始化数据库连接
+	db.InitPostgreSql(&config.Database)
+
+	failed := 0
+	for _, spec := range specs {
+		fmt.Printf("\n--- %s %s（由 %s 合成）---\n", spec.Symbol, spec.Label(), spec.Base)
+		if *check {
+			report, err := resample.Check(spec, start, end, *tolerance)
+			if err != nil {
+				failed++
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			printReport(report)
+			if len(report.Mismatches) > 0 {
+				failed++
+			}
+			continue
+		}
+
+		result, err := resample.Resample(spec, start, end, *dryRun)
+		if err != nil {
+			failed++
+			fmt.Printf("❌ %v\n", err)
+			continue
+		}
+		fmt.Printf("  合成: %d 根 | 交易所已有: %d 根 | 写入: %d 根\n", result.Built, result.Exchange, result.Written)
+		printIncomplete(result.Incomplete)
+	}
+
+	if *dryRun && !*check {
+		fmt.Println("\ndry-run 模式，未写入数据库")
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// printReport 打印一致性比对结果
+func printReport(report *resample.CheckReport) {
+	fmt.Printf("  比对: %d 根 | 一致: %d 根 | 不一致: %d 根 | 交易所缺少: %d 根\n",
+		report.Compared, report.Matched, len(report.Mismatches), report.NoExchange)
+	for i, m := range report.Mismatches {
+		if i == 10 {
+			fmt.Printf("  ⚠️  ... 共 %d 根不一致\n", len(report.Mismatches))
+			break
+		}
+		fmt.Printf("  ⚠️  %s 不一致字段: %s（合成 O=%v H=%v L=%v C=%v V=%v，交易所 O=%v H=%v L=%v C=%v V=%v）\n",
+			m.OpenTime.Format(timeLayout), strings.Join(m.Fields, ","),
+			m.Derived.Open, m.Derived.High, m.Derived.Low, m.Derived.Close, m.Derived.Volume,
+			m.Exchange.Open, m.Exchange.High, m.Exchange.Low, m.Exchange.Close, m.Exchange.Volume)
+	}
+	printIncomplete(report.Incomplete)
+}
+
+// printIncomplete 打印缺少基础K线的K线
+func printIncomplete(incomplete []resample.Incomplete) {
+	for i, k := range incomplete {
+		if i == 5 {
+			fmt.Printf("  ⚠️  ... 共 %d 根缺少基础K线，可先补齐基础周期后重新合成\n", len(incomplete))
+			break
+		}
+		fmt.Printf("  ⚠️  %s 缺少基础K线（%d/%d）\n", k.OpenTime.Format(timeLayout), k.Have, k.Want)
+	}
+}
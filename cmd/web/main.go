@@ -3,30 +3,42 @@ package main
 import (
 	"flag"
 	"fmt"
+	"log"
 
 	"trade/db"
+	"trade/utils"
 	"trade/web"
 )
 
 func main() {
 	// 定义命令行参数
-	port := flag.Int("port", 8080, "Web服务器端口")
+	configPath := flag.String("config", utils.DefaultConfigPath(), "配置文件路径")
+	port := flag.Int("port", 0, "Web服务器端口（默认使用配置文件中的 web.port）")
 	flag.Parse()
 
+	// 加载配置文件
+	config, err := utils.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("加载配置文件失败: %v", err)
+	}
+	if *port > 0 {
+		config.Web.Port = *port
+	}
+
 	// 初始化数据库连接
-	db.InitPostgreSql()
+	db.InitPostgreSql(&config.Database)
 
 	fmt.Println("╔════════════════════════════════════════════════════════════════╗")
 	fmt.Println("║          交易策略分析 - Web服务器                              ║")
 	fmt.Println("╚════════════════════════════════════════════════════════════════╝")
 	fmt.Printf("\n")
-	fmt.Printf("🌐 访问地址: http://localhost:%d\n", *port)
-	fmt.Printf("📊 查看策略一: http://localhost:%d/api/strategy1\n", *port)
-	fmt.Printf("🕐 查看策略二: http://localhost:%d/api/strategy2\n", *port)
+	fmt.Printf("🌐 访问地址: http://localhost:%d\n", config.Web.Port)
+	fmt.Printf("📊 查看策略一: http://localhost:%d/api/strategy1\n", config.Web.Port)
+	fmt.Printf("🕐 查看策略二: http://localhost:%d/api/strategy2\n", config.Web.Port)
 	fmt.Printf("\n")
 	fmt.Println("按 Ctrl+C 停止服务器")
 	fmt.Println("════════════════════════════════════════════════════════════════")
 
 	// 启动Web服务器
-	web.StartServer(*port)
+	web.StartServer(config.Web.Addr())
 }
@@ -1,13 +1,25 @@
 package db
 
 import (
+	"strings"
+
+	"trade/model"
+
 	"github.com/adshao/go-binance/v2/futures"
 )
 
 // BinanceClient 全局币安合约客户端
 var BinanceClient *futures.Client
 
-// InitBinance 初始化币安客户端
-func InitBinance(apiKey, secretKey string) {
-	BinanceClient = futures.NewClient(apiKey, secretKey)
+// InitBinance 初始化币安客户端，配置了 data_url/stream_url 时行情接口和 WebSocket 指向该地址（例如本地模拟服务）
+func InitBinance(config *model.BinanceConfig) {
+	BinanceClient = futures.NewClient(config.APIKey, config.SecretKey)
+	if config.DataURL != "" {
+		BinanceClient.BaseURL = strings.TrimRight(config.DataURL, "/")
+	}
+	if config.StreamURL != "" {
+		streamURL := strings.TrimRight(config.StreamURL, "/")
+		futures.BaseWsMainUrl = streamURL + "/ws"
+		futures.BaseCombinedMainURL = streamURL + "/stream?streams="
+	}
 }
@@ -14,39 +14,31 @@ import (
 var Pog *gorm.DB
 
 // InitPostgreSql 初始化PostgreSQL数据库连接
-func InitPostgreSql() *gorm.DB {
-	var err error
-	Pog, err = gorm.Open(postgres.New(postgres.Config{
-		DSN:                  "host=pgm-bp140jpn9wct9u0two.pg.rds.aliyuncs.com user=wws password=Wws5201314 dbname=trade port=5432 sslmode=disable TimeZone=UTC",
-		PreferSimpleProtocol: true, // disables implicit prepared statement usage
-	}), &gorm.Config{})
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// 测试连接
-
-	sqlDB, err := Pog.DB()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// SetMaxIdleConns 设置空闲连接池中连接的最大数量。
-	sqlDB.SetMaxIdleConns(10)
-
-	// SetMaxOpenConns 设置打开数据库连接的最大数量。
-	sqlDB.SetMaxOpenConns(100)
-
-	// SetConnMaxLifetime 设置了可以重新使用连接的最大时间。
-	sqlDB.SetConnMaxLifetime(time.Hour)
+func InitPostgreSql(config *model.DatabaseConfig) *gorm.DB {
+	openPostgreSql(config)
 
 	// 自动迁移表结构
-	err = Pog.AutoMigrate(
+	err := Pog.AutoMigrate(
 		&model.Kline{},
+		&model.KlineCheckpoint{},
+		&model.FundingRate{},
+		&model.OpenInterest{},
+		&model.LongShortRatio{},
+		&model.TakerVolume{},
 		&model.Strategy1Result{},
 		&model.Strategy1DetailRecord{},
 		&model.Strategy2Result{},
 		&model.Strategy2DetailRecord{},
+		&model.Strategy3Result{},
+		&model.PaperAccount{},
+		&model.PaperPosition{},
+		&model.PaperOrder{},
+		&model.PaperFill{},
+		&model.PaperEquity{},
+		&model.ExecutionOrder{},
+		&model.KillSwitch{},
+		&model.RiskState{},
+		&model.RiskRejection{},
 	)
 	if err != nil {
 		log.Printf("自动迁移失败: %v", err)
@@ -62,48 +54,53 @@ func InitPostgreSql() *gorm.DB {
 	return Pog
 }
 
-// InitPostgreSql 初始化PostgreSQL数据库连接
-func InitPostgreSqlWs() *gorm.DB {
+// InitPostgreSqlWs 初始化PostgreSQL数据库连接（实时K线表）
+func InitPostgreSqlWs(config *model.DatabaseConfig) *gorm.DB {
+	openPostgreSql(config)
+
+	// 自动迁移表结构
+	err := Pog.AutoMigrate(
+		&model.KlineWs{},
+	)
+	if err != nil {
+		log.Printf("自动迁移失败: %v", err)
+	} else {
+		log.Println("数据库表结构迁移成功")
+	}
+
+	// 显式创建唯一索引
+	createUniqueIndexesWs()
+
+	log.Println("数据库连接成功")
+	return Pog
+}
+
+// openPostgreSql 按配置建立连接并设置连接池
+func openPostgreSql(config *model.DatabaseConfig) {
 	var err error
 	Pog, err = gorm.Open(postgres.New(postgres.Config{
-		DSN:                  "host=pgm-bp140jpn9wct9u0two.pg.rds.aliyuncs.com user=wws password=Wws5201314 dbname=trade port=5432 sslmode=disable TimeZone=UTC",
+		DSN:                  config.DSN(),
 		PreferSimpleProtocol: true, // disables implicit prepared statement usage
 	}), &gorm.Config{})
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// 测试连接
-
 	sqlDB, err := Pog.DB()
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	// SetMaxIdleConns 设置空闲连接池中连接的最大数量。
-	sqlDB.SetMaxIdleConns(10)
+	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
 
 	// SetMaxOpenConns 设置打开数据库连接的最大数量。
-	sqlDB.SetMaxOpenConns(100)
+	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
 
 	// SetConnMaxLifetime 设置了可以重新使用连接的最大时间。
-	sqlDB.SetConnMaxLifetime(time.Hour)
-
-	// 自动迁移表结构
-	err = Pog.AutoMigrate(
-		&model.KlineWs{},
-	)
-	if err != nil {
-		log.Printf("自动迁移失败: %v", err)
-	} else {
-		log.Println("数据库表结构迁移成功")
+	if lifetime, err := time.ParseDuration(config.ConnMaxLifetime); err == nil {
+		sqlDB.SetConnMaxLifetime(lifetime)
 	}
-
-	// 显式创建唯一索引
-	createUniqueIndexesWs()
-
-	log.Println("数据库连接成功")
-	return Pog
 }
 
 // createUniqueIndexes 为 Kline 表创建唯一索引
@@ -0,0 +1,519 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"trade/model"
+	"trade/risk"
+	"trade/store"
+	"trade/strategy"
+	"trade/utils"
+
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+// errCodeNoNeedToChangeMarginType 保证金模式已是目标模式时交易所返回的错误码
+const errCodeNoNeedToChangeMarginType = -4046
+
+// requestTimeout 单次交易所请求的超时时间
+const requestTimeout = 10 * time.Second
+
+// 止盈止损平仓时记录在订单上的信号
+const (
+	SignalStopLoss   = "stop_loss"
+	SignalTakeProfit = "take_profit"
+)
+
+// Executor 将策略信号转换为币安U本位合约市价单（单向持仓模式）
+// 每个交易对的目标仓位为：做多/做空按交易对配置的名义价值，或空仓
+type Executor struct {
+	mu sync.Mutex
+
+	client    *futures.Client
+	config    model.TradingConfig
+	symbols   map[string]model.SymbolTrading // 交易对生效的交易参数
+	providers map[string]strategy.SignalProvider
+	risk      *risk.Manager
+
+	rules     map[string]*SymbolRules // 交易对下单规则缓存
+	prepared  map[string]bool         // 已设置杠杆和保证金模式的交易对
+	positions map[string]float64      // dry_run 模式的本地仓位，正数为多、负数为空
+	entries   map[string]entry        // 当前持仓，用于止盈止损、风控敞口和已实现盈亏
+	lastOpen  map[string]time.Time    // symbol -> 最后处理的K线开盘时间
+	sequence  int                     // 客户端订单ID序号
+}
+
+// entry 持仓方向、数量和开仓价
+type entry struct {
+	direction float64 // 多头为1，空头为-1
+	quantity  float64 // 持仓数量
+	price     float64 // 开仓均价
+}
+
+// NewClient 按下单模式创建合约客户端：binance.base_url 优先，其次测试网，否则正式网
+func NewClient(config *model.Config) *futures.Client {
+	client := futures.NewClient(config.Binance.APIKey, config.Binance.SecretKey)
+	switch {
+	case config.Binance.BaseURL != "":
+		client.BaseURL = config.Binance.BaseURL
+	case config.Trading.ExecutionMode == model.ExecutionModeTestnet:
+		client.BaseURL = futures.BaseApiTestnetUrl
+	default:
+		client.BaseURL = futures.BaseApiMainUrl
+	}
+	return client
+}
+
+// NewExecutor 按 config.Trading 创建下单执行器
+func NewExecutor(config *model.Config) (*Executor, error) {
+	if config == nil {
+		return nil, fmt.Errorf("配置不能为空")
+	}
+
+	symbols := make(map[string]model.SymbolTrading, len(config.Symbols))
+	providers := make(map[string]strategy.SignalProvider)
+	tradings := []model.SymbolTrading{config.Trading.ForSymbol(model.SymbolConfig{})}
+	for _, symbolConfig := range config.Symbols {
+		t := config.Trading.ForSymbol(symbolConfig)
+		symbols[t.Symbol] = t
+		tradings = append(tradings, t)
+	}
+	for _, t := range tradings {
+		for _, name := range t.Strategies {
+			if providers[name] != nil {
+				continue
+			}
+			provider, err := strategy.GetSignalProvider(name)
+			if err != nil {
+				return nil, err
+			}
+			providers[name] = provider
+		}
+	}
+
+	return &Executor{
+		client:    NewClient(config),
+		config:    config.Trading,
+		symbols:   symbols,
+		providers: providers,
+		risk:      risk.NewManager(config.Trading.ExecutionMode, config.Risk),
+		rules:     make(map[string]*SymbolRules),
+		prepared:  make(map[string]bool),
+		positions: make(map[string]float64),
+		entries:   make(map[string]entry),
+		lastOpen:  make(map[string]time.Time),
+	}, nil
+}
+
+// trading 返回交易对生效的交易参数，未配置的交易对使用全局默认值
+func (e *Executor) trading(symbol string) model.SymbolTrading {
+	if t, ok := e.symbols[symbol]; ok {
+		return t
+	}
+	return e.config.ForSymbol(model.SymbolConfig{Symbol: symbol})
+}
+
+// Mode 返回下单模式
+func (e *Executor) Mode() string {
+	return e.config.ExecutionMode
+}
+
+// OnKline 处理已收盘K线：任意周期的K线触及止盈止损价时平仓；
+// trading.interval 周期的K线汇总交易对启用的策略对下一根K线的信号并调整仓位，
+// 各策略方向一致时按该方向持仓，存在分歧或全部中性时平仓；
+// 触发止盈止损的K线上不再按信号重新开仓，避免刚止损的仓位在同一根K线上按收盘价重新开回，下一根K线再按信号调仓
+func (e *Executor) OnKline(kline model.Kline) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	exited := false
+	if signal, price := e.protectiveExit(kline); signal != "" {
+		if _, err := e.Execute(ctx, kline.Symbol, kline.Interval, signal, price); err != nil {
+			return err
+		}
+		exited = true
+	}
+	if kline.Interval != e.config.Interval {
+		return nil
+	}
+
+	e.mu.Lock()
+	if last, ok := e.lastOpen[kline.Symbol]; ok && !kline.OpenTime.After(last) {
+		e.mu.Unlock()
+		return nil
+	}
+	e.lastOpen[kline.Symbol] = kline.OpenTime
+	e.mu.Unlock()
+	if exited {
+		return nil
+	}
+
+	barEnd := utils.NextOpenTime(kline.OpenTime, kline.Interval)
+
+	trading := e.trading(kline.Symbol)
+	signals := make([]string, 0, len(trading.Strategies))
+	for _, name := range trading.Strategies {
+		provider := e.providers[name]
+		if !provider.Supports(kline.Interval) {
+			continue
+		}
+		s, err := provider.Signal(kline.Symbol, kline.Interval, barEnd, barEnd)
+		if err != nil {
+			if errors.Is(err, strategy.ErrNoData) {
+				continue
+			}
+			return fmt.Errorf("%s 生成信号失败: %w", provider.Name(), err)
+		}
+		signals = append(signals, s)
+	}
+
+	_, err := e.Execute(ctx, kline.Symbol, kline.Interval, consensusSignal(signals), kline.Close)
+	return err
+}
+
+// protectiveExit K线最高价/最低价是否触及当前持仓的止损价或止盈价，返回平仓信号和触发价格
+// 同一根K线同时触及两者时按止损处理
+func (e *Executor) protectiveExit(kline model.Kline) (string, float64) {
+	e.mu.Lock()
+	held, ok := e.entries[kline.Symbol]
+	e.mu.Unlock()
+	if !ok {
+		return "", 0
+	}
+
+	trading := e.trading(kline.Symbol)
+	if stop := trading.StopLossPrice(held.price, held.direction); stop > 0 {
+		if (held.direction > 0 && kline.Low <= stop) || (held.direction < 0 && kline.High >= stop) {
+			return SignalStopLoss, stop
+		}
+	}
+	if target := trading.TakeProfitPrice(held.price, held.direction); target > 0 {
+		if (held.direction > 0 && kline.High >= target) || (held.direction < 0 && kline.Low <= target) {
+			return SignalTakeProfit, target
+		}
+	}
+	return "", 0
+}
+
+// consensusSignal 合并多个策略信号：中性不影响结果，存在方向相反的信号时结果为中性
+func consensusSignal(signals []string) string {
+	result := strategy.SignalNeutral
+	for _, signal := range signals {
+		if signal != strategy.SignalBullish && signal != strategy.SignalBearish {
+			continue
+		}
+		if result != strategy.SignalNeutral && result != signal {
+			return strategy.SignalNeutral
+		}
+		result = signal
+	}
+	return result
+}
+
+// Execute 按信号把 symbol 的仓位调整到目标方向，price 为参考价格，返回本次提交的订单
+// 方向相反时先只减仓平掉旧仓位，再经风控检查后按名义价值开新仓；止盈止损信号只平仓
+// 被风控拒绝时不返回错误，拒绝原因已保存
+func (e *Executor) Execute(ctx context.Context, symbol, interval, signal string, price float64) ([]*model.ExecutionOrder, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rules, err := e.symbolRules(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	trading := e.trading(symbol)
+	if err := e.prepare(ctx, trading); err != nil {
+		return nil, err
+	}
+	current, err := e.positionAmount(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	target := signalDirection(signal)
+	holding := sign(current)
+	orders := make([]*model.ExecutionOrder, 0, 2)
+	newOrder := func(side string, reduceOnly bool, quantity float64) *model.ExecutionOrder {
+		return &model.ExecutionOrder{
+			Mode:           e.config.ExecutionMode,
+			Symbol:         symbol,
+			Interval:       interval,
+			Signal:         signal,
+			Side:           side,
+			Type:           string(futures.OrderTypeMarket),
+			ReduceOnly:     reduceOnly,
+			Quantity:       quantity,
+			ReferencePrice: rules.RoundPrice(price),
+			CreatedAt:      time.Now(),
+		}
+	}
+
+	// 平掉与目标方向不一致的仓位
+	if holding != 0 && holding != target {
+		order := newOrder(orderSide(-holding), true, rules.RoundQuantity(math.Abs(current)))
+		orders = append(orders, order)
+		if err := e.submit(ctx, rules, order); err != nil {
+			return orders, err
+		}
+		if held, ok := e.entries[symbol]; ok {
+			// 与模拟盘一致，计入开仓和平仓两次手续费（按费率估算）
+			fill := fillPrice(order, price)
+			fees := order.Quantity * (held.price + fill) * e.config.FeeRate
+			pnl := held.direction*order.Quantity*(fill-held.price) - fees
+			if err := e.risk.RecordClose(pnl, order.CreatedAt); err != nil {
+				return orders, err
+			}
+		}
+		delete(e.entries, symbol)
+	}
+
+	// 按名义价值开新仓
+	if target != 0 && holding != target {
+		symbolExposure, totalExposure := e.exposure(symbol)
+		err := e.risk.Check(risk.Request{
+			Symbol:         symbol,
+			Interval:       interval,
+			Side:           positionSide(target),
+			Notional:       trading.Notional,
+			SymbolExposure: symbolExposure,
+			TotalExposure:  totalExposure,
+			MaxPosition:    trading.MaxPosition,
+		}, time.Now())
+		if errors.Is(err, risk.ErrRejected) {
+			return orders, nil
+		}
+		if err != nil {
+			return orders, err
+		}
+
+		quantity, err := rules.OpenQuantity(trading.Notional, price)
+		if err != nil {
+			order := newOrder(orderSide(target), false, rules.RoundQuantity(trading.Notional/price))
+			e.reject(order, err)
+			return append(orders, order), err
+		}
+		order := newOrder(orderSide(target), false, quantity)
+		orders = append(orders, order)
+		if err := e.submit(ctx, rules, order); err != nil {
+			return orders, err
+		}
+		e.entries[symbol] = entry{direction: target, quantity: order.Quantity, price: fillPrice(order, price)}
+	}
+
+	return orders, nil
+}
+
+// exposure 返回 symbol 和全部持仓按开仓价计算的名义价值合计
+func (e *Executor) exposure(symbol string) (symbolExposure, totalExposure float64) {
+	for s, held := range e.entries {
+		notional := held.quantity * held.price
+		if s == symbol {
+			symbolExposure += notional
+		}
+		totalExposure += notional
+	}
+	return symbolExposure, totalExposure
+}
+
+// fillPrice 订单成交均价，交易所未返回时使用参考价格
+func fillPrice(order *model.ExecutionOrder, price float64) float64 {
+	if order.AvgPrice > 0 {
+		return order.AvgPrice
+	}
+	return price
+}
+
+// symbolRules 返回交易对下单规则，首次调用时加载全部交易对的 exchangeInfo
+func (e *Executor) symbolRules(ctx context.Context, symbol string) (*SymbolRules, error) {
+	if rules, ok := e.rules[symbol]; ok {
+		return rules, nil
+	}
+
+	info, err := e.client.NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取交易规则失败: %w", err)
+	}
+	for _, s := range info.Symbols {
+		rules, err := NewSymbolRules(s)
+		if err != nil {
+			continue
+		}
+		e.rules[s.Symbol] = rules
+	}
+
+	rules, ok := e.rules[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol)
+	}
+	return rules, nil
+}
+
+// prepare 首次交易某个交易对前设置保证金模式和杠杆，dry_run 模式不发送请求
+func (e *Executor) prepare(ctx context.Context, trading model.SymbolTrading) error {
+	symbol := trading.Symbol
+	if e.prepared[symbol] || e.config.ExecutionMode == model.ExecutionModeDryRun {
+		return nil
+	}
+
+	err := e.client.NewChangeMarginTypeService().
+		Symbol(symbol).
+		MarginType(futures.MarginType(trading.MarginType)).
+		Do(ctx)
+	var apiErr *common.APIError
+	if err != nil && !(errors.As(err, &apiErr) && apiErr.Code == errCodeNoNeedToChangeMarginType) {
+		return fmt.Errorf("设置 %s 保证金模式失败: %w", symbol, err)
+	}
+
+	if _, err := e.client.NewChangeLeverageService().Symbol(symbol).Leverage(trading.Leverage).Do(ctx); err != nil {
+		return fmt.Errorf("设置 %s 杠杆失败: %w", symbol, err)
+	}
+
+	fmt.Printf("⚙️  %s 已设置为 %s %dx\n", symbol, trading.MarginType, trading.Leverage)
+	e.prepared[symbol] = true
+	return nil
+}
+
+// positionAmount 返回当前仓位数量（正数为多、负数为空），dry_run 模式使用本地记录
+// 交易所返回的开仓价会同步到本地，重启后仍能按已有仓位止盈止损
+func (e *Executor) positionAmount(ctx context.Context, symbol string) (float64, error) {
+	if e.config.ExecutionMode == model.ExecutionModeDryRun {
+		return e.positions[symbol], nil
+	}
+
+	risks, err := e.client.NewGetPositionRiskService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("查询 %s 仓位失败: %w", symbol, err)
+	}
+	amount := 0.0
+	for _, risk := range risks {
+		if risk.Symbol != symbol {
+			continue
+		}
+		value, err := strconv.ParseFloat(risk.PositionAmt, 64)
+		if err != nil {
+			return 0, fmt.Errorf("解析 %s 仓位失败: %w", symbol, err)
+		}
+		amount += value
+		if price, _ := strconv.ParseFloat(risk.EntryPrice, 64); value != 0 && price > 0 {
+			e.entries[symbol] = entry{direction: sign(value), quantity: math.Abs(value), price: price}
+		}
+	}
+	if amount == 0 {
+		delete(e.entries, symbol)
+	}
+	return amount, nil
+}
+
+// submit 提交市价单并保存订单记录，dry_run 模式按参考价格视为全部成交
+func (e *Executor) submit(ctx context.Context, rules *SymbolRules, order *model.ExecutionOrder) error {
+	e.sequence++
+	order.ClientOrderID = fmt.Sprintf("trade_%d_%d", order.CreatedAt.UnixMilli(), e.sequence)
+
+	if e.config.ExecutionMode == model.ExecutionModeDryRun {
+		order.Status = model.ExecutionStatusDryRun
+		order.ExecutedQty = order.Quantity
+		order.AvgPrice = order.ReferencePrice
+		if order.Side == model.OrderSideBuy {
+			e.positions[order.Symbol] += order.Quantity
+		} else {
+			e.positions[order.Symbol] -= order.Quantity
+		}
+		e.positions[order.Symbol] = rules.RoundQuantity(math.Abs(e.positions[order.Symbol])) * sign(e.positions[order.Symbol])
+		return e.save(order)
+	}
+
+	service := e.client.NewCreateOrderService().
+		Symbol(order.Symbol).
+		Side(futures.SideType(order.Side)).
+		Type(futures.OrderTypeMarket).
+		Quantity(rules.FormatQuantity(order.Quantity)).
+		NewClientOrderID(order.ClientOrderID).
+		NewOrderResponseType(futures.NewOrderRespTypeRESULT)
+	if order.ReduceOnly {
+		service = service.ReduceOnly(true)
+	}
+
+	resp, err := service.Do(ctx)
+	if err != nil {
+		e.reject(order, err)
+		return fmt.Errorf("%s 下单失败: %w", order.Symbol, err)
+	}
+
+	order.ExchangeOrderID = resp.OrderID
+	order.Status = string(resp.Status)
+	order.ExecutedQty, _ = strconv.ParseFloat(resp.ExecutedQuantity, 64)
+	order.AvgPrice, _ = strconv.ParseFloat(resp.AvgPrice, 64)
+	return e.save(order)
+}
+
+// reject 记录被拒绝的订单
+func (e *Executor) reject(order *model.ExecutionOrder, reason error) {
+	order.Status = model.ExecutionStatusRejected
+	order.Error = reason.Error()
+	if err := e.save(order); err != nil {
+		fmt.Printf("保存被拒订单失败: %v\n", err)
+	}
+}
+
+// save 保存订单并输出日志
+func (e *Executor) save(order *model.ExecutionOrder) error {
+	fmt.Printf("🧾 [%s] %s %s %s 数量 %v 参考价 %v 状态 %s\n",
+		order.Mode, order.Symbol, order.Side, reduceOnlyText(order.ReduceOnly), order.Quantity, order.ReferencePrice, order.Status)
+	if err := store.Default().SaveExecutionOrder(order); err != nil {
+		return fmt.Errorf("保存订单失败: %w", err)
+	}
+	return nil
+}
+
+// signalDirection 信号对应的目标方向：做多1、做空-1、空仓0
+func signalDirection(signal string) float64 {
+	switch signal {
+	case strategy.SignalBullish:
+		return 1
+	case strategy.SignalBearish:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// positionSide 方向为正时为多头，否则为空头
+func positionSide(direction float64) string {
+	if direction > 0 {
+		return model.PositionSideLong
+	}
+	return model.PositionSideShort
+}
+
+// orderSide 方向为正时买入，否则卖出
+func orderSide(direction float64) string {
+	if direction > 0 {
+		return model.OrderSideBuy
+	}
+	return model.OrderSideSell
+}
+
+func sign(value float64) float64 {
+	switch {
+	case value > 0:
+		return 1
+	case value < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func reduceOnlyText(reduceOnly bool) string {
+	if reduceOnly {
+		return "平仓"
+	}
+	return "开仓"
+}
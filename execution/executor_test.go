@@ -0,0 +1,438 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"trade/model"
+	"trade/risk"
+	"trade/store"
+	"trade/strategy"
+)
+
+// scriptedStrategy 按下一根K线开盘时间返回预设信号的测试策略
+type scriptedStrategy struct {
+	signals map[time.Time]string
+}
+
+var scripted = &scriptedStrategy{signals: make(map[time.Time]string)}
+
+func init() {
+	strategy.Register(scripted)
+}
+
+func (s *scriptedStrategy) Name() string                  { return "execution_scripted" }
+func (s *scriptedStrategy) Description() string           { return "测试用预设信号" }
+func (s *scriptedStrategy) Parameters() []strategy.Param  { return nil }
+func (s *scriptedStrategy) Run(*model.Config, time.Time)  {}
+func (s *scriptedStrategy) Supports(interval string) bool { return interval == "1h" }
+
+func (s *scriptedStrategy) Analyze(context.Context, *strategy.Input) (*strategy.Result, error) {
+	return nil, strategy.ErrNoData
+}
+
+func (s *scriptedStrategy) Signal(symbol, interval string, openTime, asOf time.Time) (string, error) {
+	if signal, ok := s.signals[openTime]; ok {
+		return signal, nil
+	}
+	return strategy.SignalNeutral, nil
+}
+
+// fakeExchange 本地模拟的币安合约接口，只实现执行器用到的几个端点
+type fakeExchange struct {
+	mu         sync.Mutex
+	server     *httptest.Server
+	positions  map[string]float64
+	leverage   map[string]int
+	marginType map[string]string
+	orders     []map[string]string
+	requests   map[string]int
+}
+
+func newFakeExchange(t *testing.T) *fakeExchange {
+	t.Helper()
+	f := &fakeExchange{
+		positions:  make(map[string]float64),
+		leverage:   make(map[string]int),
+		marginType: make(map[string]string),
+		requests:   make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/fapi/v1/exchangeInfo", func(w http.ResponseWriter, r *http.Request) {
+		f.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"symbols": []map[string]interface{}{
+				fakeSymbol("ETHUSDT", "0.01", "0.001", "20"),
+				fakeSymbol("BTCUSDT", "0.10", "0.001", "100"),
+			},
+		})
+	})
+	mux.HandleFunc("/fapi/v1/marginType", func(w http.ResponseWriter, r *http.Request) {
+		symbol, marginType := r.FormValue("symbol"), r.FormValue("marginType")
+		if f.marginType[symbol] == marginType {
+			f.writeJSON(w, http.StatusBadRequest, map[string]interface{}{"code": -4046, "msg": "No need to change margin type."})
+			return
+		}
+		f.marginType[symbol] = marginType
+		f.writeJSON(w, http.StatusOK, map[string]interface{}{"code": 200, "msg": "success"})
+	})
+	mux.HandleFunc("/fapi/v1/leverage", func(w http.ResponseWriter, r *http.Request) {
+		leverage, _ := strconv.Atoi(r.FormValue("leverage"))
+		f.leverage[r.FormValue("symbol")] = leverage
+		f.writeJSON(w, http.StatusOK, map[string]interface{}{"symbol": r.FormValue("symbol"), "leverage": leverage, "maxNotionalValue": "1000000"})
+	})
+	mux.HandleFunc("/fapi/v2/positionRisk", func(w http.ResponseWriter, r *http.Request) {
+		symbol := r.URL.Query().Get("symbol")
+		f.writeJSON(w, http.StatusOK, []map[string]string{{
+			"symbol":       symbol,
+			"positionAmt":  strconv.FormatFloat(f.positions[symbol], 'f', -1, 64),
+			"positionSide": "BOTH",
+		}})
+	})
+	mux.HandleFunc("/fapi/v1/order", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		order := map[string]string{}
+		for key := range r.PostForm {
+			order[key] = r.PostForm.Get(key)
+		}
+		f.orders = append(f.orders, order)
+
+		quantity, _ := strconv.ParseFloat(order["quantity"], 64)
+		if order["side"] == "SELL" {
+			quantity = -quantity
+		}
+		f.positions[order["symbol"]] += quantity
+		f.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"symbol":        order["symbol"],
+			"orderId":       len(f.orders),
+			"clientOrderId": order["newClientOrderId"],
+			"status":        "FILLED",
+			"origQty":       order["quantity"],
+			"executedQty":   order["quantity"],
+			"avgPrice":      "2000.5",
+			"side":          order["side"],
+			"type":          order["type"],
+		})
+	})
+
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.requests[r.URL.Path]++
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func fakeSymbol(symbol, tickSize, stepSize, minNotional string) map[string]interface{} {
+	return map[string]interface{}{
+		"symbol": symbol,
+		"status": "TRADING",
+		"filters": []map[string]string{
+			{"filterType": "PRICE_FILTER", "tickSize": tickSize, "minPrice": tickSize, "maxPrice": "1000000"},
+			{"filterType": "LOT_SIZE", "stepSize": stepSize, "minQty": stepSize, "maxQty": "10000"},
+			{"filterType": "MARKET_LOT_SIZE", "stepSize": stepSize, "minQty": stepSize, "maxQty": "1000"},
+			{"filterType": "MIN_NOTIONAL", "notional": minNotional},
+		},
+	}
+}
+
+func (f *fakeExchange) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// newTestExecutor 使用内存存储和本地模拟交易所创建执行器，默认 5U×10倍
+func newTestExecutor(t *testing.T, f *fakeExchange, mode string, symbols ...model.SymbolConfig) *Executor {
+	t.Helper()
+	store.SetDefault(store.NewMemoryStore())
+	t.Cleanup(func() { store.SetDefault(nil) })
+
+	executor, err := NewExecutor(&model.Config{
+		Binance: model.BinanceConfig{APIKey: "key", SecretKey: "secret", BaseURL: f.server.URL},
+		Trading: model.TradingConfig{
+			Margin:        5,
+			Leverage:      10,
+			ExecutionMode: mode,
+			Interval:      "1h",
+			MarginType:    model.MarginTypeIsolated,
+		},
+		Symbols: symbols,
+	})
+	if err != nil {
+		t.Fatalf("创建执行器失败: %v", err)
+	}
+	return executor
+}
+
+func TestExecutorTestnetFlow(t *testing.T) {
+	f := newFakeExchange(t)
+	executor := newTestExecutor(t, f, model.ExecutionModeTestnet)
+	ctx := context.Background()
+
+	// 50U / 2000 = 0.025 个
+	if _, err := executor.Execute(ctx, "ETHUSDT", "1h", strategy.SignalBullish, 2000); err != nil {
+		t.Fatalf("开多失败: %v", err)
+	}
+	if f.marginType["ETHUSDT"] != "ISOLATED" || f.leverage["ETHUSDT"] != 10 {
+		t.Errorf("应设置逐仓10倍: %v %v", f.marginType, f.leverage)
+	}
+	if len(f.orders) != 1 || f.orders[0]["side"] != "BUY" || f.orders[0]["quantity"] != "0.025" {
+		t.Fatalf("开多订单错误: %v", f.orders)
+	}
+
+	// 反手：先只减仓平多，再按 50U / 2600 = 0.019230 -> 0.019 开空
+	orders, err := executor.Execute(ctx, "ETHUSDT", "1h", strategy.SignalBearish, 2600)
+	if err != nil || len(orders) != 2 {
+		t.Fatalf("反手失败: %v %+v", err, orders)
+	}
+	if f.orders[1]["reduceOnly"] != "true" || f.orders[1]["quantity"] != "0.025" || f.orders[1]["side"] != "SELL" {
+		t.Errorf("平仓订单错误: %v", f.orders[1])
+	}
+	if f.orders[2]["quantity"] != "0.019" || f.orders[2]["side"] != "SELL" {
+		t.Errorf("开空订单错误: %v", f.orders[2])
+	}
+
+	// 中性信号平仓，杠杆只设置一次
+	if _, err := executor.Execute(ctx, "ETHUSDT", "1h", strategy.SignalNeutral, 2500); err != nil {
+		t.Fatal(err)
+	}
+	if f.positions["ETHUSDT"] != 0 || f.requests["/fapi/v1/leverage"] != 1 || f.requests["/fapi/v1/exchangeInfo"] != 1 {
+		t.Errorf("仓位应归零且杠杆、交易规则只请求一次: %v %v", f.positions, f.requests)
+	}
+
+	saved, _ := store.Default().ListExecutionOrders("ETHUSDT", 0)
+	if len(saved) != 4 {
+		t.Fatalf("应保存4笔订单，实际 %d", len(saved))
+	}
+	for _, order := range saved {
+		if order.Status != "FILLED" || order.ExchangeOrderID == 0 || order.AvgPrice != 2000.5 {
+			t.Errorf("订单记录错误: %+v", order)
+		}
+	}
+}
+
+func TestExecutorMinNotional(t *testing.T) {
+	f := newFakeExchange(t)
+	executor := newTestExecutor(t, f, model.ExecutionModeLive)
+
+	// BTCUSDT 最小名义价值 100U，5U×10倍不足
+	_, err := executor.Execute(context.Background(), "BTCUSDT", "1h", strategy.SignalBullish, 60000)
+	if !errors.Is(err, ErrBelowMinNotional) {
+		t.Fatalf("应返回 ErrBelowMinNotional，实际 %v", err)
+	}
+	if len(f.orders) != 0 {
+		t.Errorf("不应向交易所下单: %v", f.orders)
+	}
+	saved, _ := store.Default().ListExecutionOrders("", 0)
+	if len(saved) != 1 || saved[0].Status != model.ExecutionStatusRejected || saved[0].Error == "" {
+		t.Errorf("被拒订单应记录原因: %+v", saved)
+	}
+
+	if _, err := executor.Execute(context.Background(), "DOGEUSDT", "1h", strategy.SignalBullish, 1); !errors.Is(err, ErrUnknownSymbol) {
+		t.Errorf("未知交易对应返回 ErrUnknownSymbol，实际 %v", err)
+	}
+}
+
+func TestExecutorDryRun(t *testing.T) {
+	f := newFakeExchange(t)
+	executor := newTestExecutor(t, f, model.ExecutionModeDryRun)
+	ctx := context.Background()
+
+	for i, signal := range []string{strategy.SignalBullish, strategy.SignalBullish, strategy.SignalBearish} {
+		if _, err := executor.Execute(ctx, "ETHUSDT", "1h", signal, 2000); err != nil {
+			t.Fatalf("第%d次执行失败: %v", i, err)
+		}
+	}
+
+	if len(f.orders) != 0 || f.requests["/fapi/v1/leverage"] != 0 || f.requests["/fapi/v2/positionRisk"] != 0 {
+		t.Errorf("dry_run 只应请求交易规则: %v", f.requests)
+	}
+	if executor.positions["ETHUSDT"] != -0.025 {
+		t.Errorf("本地仓位应为 -0.025，实际 %v", executor.positions["ETHUSDT"])
+	}
+
+	saved, _ := store.Default().ListExecutionOrders("ETHUSDT", 0)
+	// 开多、(重复看多不下单)、平多、开空
+	if len(saved) != 3 {
+		t.Fatalf("应记录3笔订单，实际 %d", len(saved))
+	}
+	for _, order := range saved {
+		if order.Status != model.ExecutionStatusDryRun || order.Mode != model.ExecutionModeDryRun {
+			t.Errorf("dry_run 订单状态错误: %+v", order)
+		}
+	}
+}
+
+func TestExecutorSymbolTrading(t *testing.T) {
+	f := newFakeExchange(t)
+	executor := newTestExecutor(t, f, model.ExecutionModeTestnet,
+		model.SymbolConfig{Symbol: "ETHUSDT", Leverage: 20, MarginType: model.MarginTypeCrossed, Notional: 100, StopLossPct: 1})
+	ctx := context.Background()
+
+	// 100U / 2000 = 0.05 个，成交均价 2000.5
+	if _, err := executor.Execute(ctx, "ETHUSDT", "1h", strategy.SignalBullish, 2000); err != nil {
+		t.Fatal(err)
+	}
+	if f.marginType["ETHUSDT"] != "CROSSED" || f.leverage["ETHUSDT"] != 20 || f.orders[0]["quantity"] != "0.050" {
+		t.Errorf("应按交易对配置设置全仓20倍并下单 0.05: %v %v %v", f.marginType, f.leverage, f.orders)
+	}
+
+	// 1分钟K线最低价未触及止损价 2000.5×0.99=1980.495
+	kline := model.Kline{Symbol: "ETHUSDT", Interval: "1m", OpenTime: time.Unix(0, 0), High: 2010, Low: 1990, Close: 2000}
+	if err := executor.OnKline(kline); err != nil || len(f.orders) != 1 {
+		t.Fatalf("未触及止损不应下单: %v %v", err, f.orders)
+	}
+
+	kline.Low = 1980
+	if err := executor.OnKline(kline); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.orders) != 2 || f.orders[1]["reduceOnly"] != "true" || f.positions["ETHUSDT"] != 0 {
+		t.Fatalf("触及止损应只减仓平多: %v", f.orders)
+	}
+	saved, _ := store.Default().ListExecutionOrders("ETHUSDT", 1)
+	if len(saved) != 1 || saved[0].Signal != SignalStopLoss {
+		t.Errorf("止损订单应记录信号 stop_loss: %+v", saved)
+	}
+}
+
+func TestExecutorKillSwitch(t *testing.T) {
+	f := newFakeExchange(t)
+	executor := newTestExecutor(t, f, model.ExecutionModeTestnet)
+	ctx := context.Background()
+
+	if _, err := executor.Execute(ctx, "ETHUSDT", "1h", strategy.SignalBullish, 2000); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := risk.SetKillSwitch(true, "手动停止"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 紧急停止后反手：只平多，不开空
+	orders, err := executor.Execute(ctx, "ETHUSDT", "1h", strategy.SignalBearish, 2100)
+	if err != nil || len(orders) != 1 || !orders[0].ReduceOnly || f.positions["ETHUSDT"] != 0 {
+		t.Fatalf("紧急停止后应只平仓: %v %+v", err, orders)
+	}
+
+	rejections, _ := store.Default().ListRiskRejections("ETHUSDT", 0)
+	if len(rejections) != 1 || rejections[0].Rule != model.RiskRuleKillSwitch || rejections[0].Account != model.ExecutionModeTestnet {
+		t.Errorf("应记录 kill_switch 拒绝: %+v", rejections)
+	}
+	state, _ := store.Default().GetRiskState(model.ExecutionModeTestnet)
+	// 成交均价 2000.5 -> 2000.5，盈亏为0
+	if state == nil || state.ConsecutiveLosses != 0 {
+		t.Errorf("平仓应记录风控状态: %+v", state)
+	}
+}
+
+func TestExecutorSignalTimeWithStoredCloseTime(t *testing.T) {
+	f := newFakeExchange(t)
+	executor := newTestExecutor(t, f, model.ExecutionModeDryRun,
+		model.SymbolConfig{Symbol: "ETHUSDT", Strategies: []string{scripted.Name()}})
+
+	// 入库的收盘时间截断到秒(05:59:59)，信号应针对下一根K线的开盘时间 06:00
+	openTime := time.Date(2024, 1, 1, 5, 0, 0, 0, time.UTC)
+	scripted.signals = map[time.Time]string{openTime.Add(time.Hour): strategy.SignalBullish}
+	t.Cleanup(func() { scripted.signals = make(map[time.Time]string) })
+
+	kline := model.Kline{Symbol: "ETHUSDT", Interval: "1h", OpenTime: openTime,
+		CloseTime: openTime.Add(time.Hour - time.Second), High: 2010, Low: 1990, Close: 2000}
+	if err := executor.OnKline(kline); err != nil {
+		t.Fatal(err)
+	}
+	if executor.positions["ETHUSDT"] != 0.025 {
+		t.Errorf("应按 06:00 的信号开多 0.025，实际 %v", executor.positions["ETHUSDT"])
+	}
+}
+
+func TestExecutorNoReentryAfterStopLoss(t *testing.T) {
+	f := newFakeExchange(t)
+	executor := newTestExecutor(t, f, model.ExecutionModeTestnet,
+		model.SymbolConfig{Symbol: "ETHUSDT", Notional: 50, StopLossPct: 1, Strategies: []string{scripted.Name()}})
+
+	// 每根K线之后信号都看多
+	openTime := time.Date(2024, 1, 1, 5, 0, 0, 0, time.UTC)
+	scripted.signals = map[time.Time]string{}
+	for n := 1; n <= 3; n++ {
+		scripted.signals[openTime.Add(time.Duration(n)*time.Hour)] = strategy.SignalBullish
+	}
+	t.Cleanup(func() { scripted.signals = make(map[time.Time]string) })
+	bar := func(n int, low float64) model.Kline {
+		return model.Kline{Symbol: "ETHUSDT", Interval: "1h", OpenTime: openTime.Add(time.Duration(n) * time.Hour), High: 2010, Low: low, Close: 2000}
+	}
+
+	if err := executor.OnKline(bar(0, 1990)); err != nil || len(f.orders) != 1 {
+		t.Fatalf("应按看多信号开仓: %v %v", err, f.orders)
+	}
+	// 最低价触及止损价 2000.5×0.99，只平仓，同一根K线不按信号重新开多
+	if err := executor.OnKline(bar(1, 1980)); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.orders) != 2 || f.orders[1]["reduceOnly"] != "true" || f.positions["ETHUSDT"] != 0 {
+		t.Fatalf("止损的K线上不应重新开仓: %v", f.orders)
+	}
+	// 下一根K线再按信号开仓
+	if err := executor.OnKline(bar(2, 1990)); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.orders) != 3 || f.orders[2]["side"] != "BUY" || f.positions["ETHUSDT"] <= 0 {
+		t.Errorf("下一根K线应按信号重新开多: %v", f.orders)
+	}
+}
+
+func TestExecutorRiskAccounting(t *testing.T) {
+	f := newFakeExchange(t)
+	executor := newTestExecutor(t, f, model.ExecutionModeDryRun, model.SymbolConfig{Symbol: "ETHUSDT", MaxPosition: 60})
+	executor.config.FeeRate = 0.001
+	ctx := context.Background()
+
+	// 平价平仓：扣除开平仓手续费 0.025×2000×0.001×2 = 0.1 后记为亏损
+	if _, err := executor.Execute(ctx, "ETHUSDT", "1h", strategy.SignalBullish, 2000); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := executor.Execute(ctx, "ETHUSDT", "1h", strategy.SignalNeutral, 2000); err != nil {
+		t.Fatal(err)
+	}
+	state, _ := store.Default().GetRiskState(model.ExecutionModeDryRun)
+	if state == nil || state.ConsecutiveLosses != 1 || math.Abs(state.DailyRealizedPnL+0.1) > 1e-9 {
+		t.Errorf("平仓盈亏应扣除手续费: %+v", state)
+	}
+
+	// 交易对已有 20U 持仓时再开 50U 超过 max_position(60)
+	executor.entries["ETHUSDT"] = entry{direction: 1, quantity: 0.01, price: 2000}
+	if orders, err := executor.Execute(ctx, "ETHUSDT", "1h", strategy.SignalBullish, 2000); err != nil || len(orders) != 0 {
+		t.Fatalf("应被风控拒绝: %v %+v", err, orders)
+	}
+	rejections, _ := store.Default().ListRiskRejections("ETHUSDT", 0)
+	if len(rejections) != 1 || rejections[0].Rule != model.RiskRuleMaxPosition {
+		t.Errorf("应记录 max_position 拒绝: %+v", rejections)
+	}
+}
+
+func TestConsensusSignal(t *testing.T) {
+	cases := []struct {
+		signals []string
+		want    string
+	}{
+		{[]string{strategy.SignalBullish, strategy.SignalNeutral}, strategy.SignalBullish},
+		{[]string{strategy.SignalBearish, strategy.SignalBearish}, strategy.SignalBearish},
+		{[]string{strategy.SignalBullish, strategy.SignalBearish, strategy.SignalBullish}, strategy.SignalNeutral},
+		{nil, strategy.SignalNeutral},
+	}
+	for _, c := range cases {
+		if got := consensusSignal(c.signals); got != c.want {
+			t.Errorf("%v 合并结果应为 %s，实际 %s", c.signals, c.want, got)
+		}
+	}
+}
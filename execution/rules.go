@@ -0,0 +1,123 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+var (
+	// ErrUnknownSymbol 交易所不支持该交易对
+	ErrUnknownSymbol = errors.New("交易所不支持该交易对")
+	// ErrBelowMinNotional 按步长取整后的下单金额低于交易所最小名义价值或最小数量
+	ErrBelowMinNotional = errors.New("下单金额低于交易所最小限制")
+)
+
+// SymbolRules 交易对下单规则，来自 exchangeInfo 的 PRICE_FILTER、LOT_SIZE、MARKET_LOT_SIZE 和 MIN_NOTIONAL
+type SymbolRules struct {
+	Symbol      string  `json:"symbol"`       // 交易对
+	TickSize    float64 `json:"tick_size"`    // 价格步长
+	StepSize    float64 `json:"step_size"`    // 数量步长
+	MinQty      float64 `json:"min_qty"`      // 市价单最小数量
+	MaxQty      float64 `json:"max_qty"`      // 市价单最大数量，0 表示不限制
+	MinNotional float64 `json:"min_notional"` // 最小名义价值(USDT)
+
+	priceDecimals    int // 价格小数位数，由 tickSize 推出
+	quantityDecimals int // 数量小数位数，由 stepSize 推出
+}
+
+// NewSymbolRules 从 exchangeInfo 的交易对信息解析下单规则
+func NewSymbolRules(symbol futures.Symbol) (*SymbolRules, error) {
+	rules := &SymbolRules{Symbol: symbol.Symbol}
+
+	tickSize := filterValue(symbol, futures.SymbolFilterTypePrice, "tickSize")
+	stepSize := filterValue(symbol, futures.SymbolFilterTypeLotSize, "stepSize")
+	if tickSize == "" || stepSize == "" {
+		return nil, fmt.Errorf("%s 缺少 PRICE_FILTER 或 LOT_SIZE", symbol.Symbol)
+	}
+	rules.TickSize, rules.priceDecimals = parseStep(tickSize)
+	rules.StepSize, rules.quantityDecimals = parseStep(stepSize)
+	if rules.TickSize <= 0 || rules.StepSize <= 0 {
+		return nil, fmt.Errorf("%s 价格步长或数量步长非法: %s/%s", symbol.Symbol, tickSize, stepSize)
+	}
+
+	// 市价单数量限制优先取 MARKET_LOT_SIZE
+	filterType := futures.SymbolFilterTypeMarketLotSize
+	if filterValue(symbol, filterType, "minQty") == "" {
+		filterType = futures.SymbolFilterTypeLotSize
+	}
+	rules.MinQty, _ = strconv.ParseFloat(filterValue(symbol, filterType, "minQty"), 64)
+	rules.MaxQty, _ = strconv.ParseFloat(filterValue(symbol, filterType, "maxQty"), 64)
+	rules.MinNotional, _ = strconv.ParseFloat(filterValue(symbol, futures.SymbolFilterTypeMinNotional, "notional"), 64)
+	return rules, nil
+}
+
+// filterValue 返回交易对某个过滤器的字段值，不存在时返回空字符串
+func filterValue(symbol futures.Symbol, filterType futures.SymbolFilterType, key string) string {
+	for _, filter := range symbol.Filters {
+		if t, _ := filter["filterType"].(string); t != string(filterType) {
+			continue
+		}
+		value, _ := filter[key].(string)
+		return value
+	}
+	return ""
+}
+
+// parseStep 解析步长并返回其小数位数，例如 "0.0010" -> 0.001, 3
+func parseStep(step string) (float64, int) {
+	value, err := strconv.ParseFloat(step, 64)
+	if err != nil {
+		return 0, 0
+	}
+	decimals := 0
+	if i := strings.IndexByte(step, '.'); i >= 0 {
+		decimals = len(strings.TrimRight(step[i+1:], "0"))
+	}
+	return value, decimals
+}
+
+// RoundQuantity 数量按步长向下取整
+func (r *SymbolRules) RoundQuantity(quantity float64) float64 {
+	steps := math.Floor(quantity/r.StepSize + 1e-9)
+	return roundDecimals(steps*r.StepSize, r.quantityDecimals)
+}
+
+// RoundPrice 价格按价格步长四舍五入
+func (r *SymbolRules) RoundPrice(price float64) float64 {
+	ticks := math.Round(price / r.TickSize)
+	return roundDecimals(ticks*r.TickSize, r.priceDecimals)
+}
+
+// FormatQuantity 按数量精度格式化，用于下单参数
+func (r *SymbolRules) FormatQuantity(quantity float64) string {
+	return strconv.FormatFloat(quantity, 'f', r.quantityDecimals, 64)
+}
+
+// OpenQuantity 按名义价值和参考价格计算开仓数量：按步长向下取整、不超过最大数量，并检查最小数量和最小名义价值
+func (r *SymbolRules) OpenQuantity(notional, price float64) (float64, error) {
+	if price <= 0 {
+		return 0, fmt.Errorf("%s 参考价格非法: %v", r.Symbol, price)
+	}
+	quantity := r.RoundQuantity(notional / price)
+	if r.MaxQty > 0 && quantity > r.MaxQty {
+		quantity = r.RoundQuantity(r.MaxQty)
+	}
+	if quantity <= 0 || quantity < r.MinQty {
+		return 0, fmt.Errorf("%w: %s 数量 %s 小于最小数量 %v", ErrBelowMinNotional, r.Symbol, r.FormatQuantity(quantity), r.MinQty)
+	}
+	if value := quantity * price; value < r.MinNotional {
+		return 0, fmt.Errorf("%w: %s 名义价值 %.4f 小于 %.4f", ErrBelowMinNotional, r.Symbol, value, r.MinNotional)
+	}
+	return quantity, nil
+}
+
+// roundDecimals 保留 decimals 位小数，消除浮点误差
+func roundDecimals(value float64, decimals int) float64 {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'f', decimals, 64), 64)
+	return rounded
+}
@@ -0,0 +1,223 @@
+// Package export K线导出为 CSV、JSON Lines 或 Parquet，按批次从存储读取并流式写出
+package export
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"trade/model"
+	"trade/store"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// 支持的导出格式
+const (
+	FormatCSV     = "csv"
+	FormatJSONL   = "jsonl"
+	FormatParquet = "parquet"
+)
+
+// defaultBatchSize 每批从存储读取的K线数量，导出时内存中最多保留一批
+const defaultBatchSize = 5000
+
+// ErrInvalidOptions 导出参数错误
+var ErrInvalidOptions = errors.New("导出参数错误")
+
+// Options 导出参数
+type Options struct {
+	Symbol    string    // 交易对
+	Interval  string    // 时间周期
+	Start     time.Time // 开盘时间下界（含），零值表示不限制
+	End       time.Time // 开盘时间上界（含），零值表示不限制
+	Format    string    // csv、jsonl 或 parquet
+	Columns   []string  // 导出的列（按给定顺序），为空时导出全部列
+	Gzip      bool      // CSV/JSONL 整体 gzip 压缩；Parquet 改用 GZIP 列压缩（默认 SNAPPY）
+	BatchSize int       // 每批读取的K线数量，0 使用默认值
+}
+
+// column 一个可导出的列
+type column struct {
+	name    string
+	parquet func() parquet.Node // Parquet 列类型
+	value   func(k *model.Kline) interface{}
+}
+
+// allColumns 全部可导出的列，默认按此顺序导出
+var allColumns = []column{
+	{"open_time", timestampNode, func(k *model.Kline) interface{} { return k.OpenTime }},
+	{"close_time", timestampNode, func(k *model.Kline) interface{} { return k.CloseTime }},
+	{"symbol", parquet.String, func(k *model.Kline) interface{} { return k.Symbol }},
+	{"interval", parquet.String, func(k *model.Kline) interface{} { return k.Interval }},
+	{"open", doubleNode, func(k *model.Kline) interface{} { return k.Open }},
+	{"high", doubleNode, func(k *model.Kline) interface{} { return k.High }},
+	{"low", doubleNode, func(k *model.Kline) interface{} { return k.Low }},
+	{"close", doubleNode, func(k *model.Kline) interface{} { return k.Close }},
+	{"volume", doubleNode, func(k *model.Kline) interface{} { return k.Volume }},
+	{"quote_volume", doubleNode, func(k *model.Kline) interface{} { return k.QuoteVolume }},
+	{"trade_num", int64Node, func(k *model.Kline) interface{} { return k.TradeNum }},
+	{"taker_buy_volume", doubleNode, func(k *model.Kline) interface{} { return k.TakerBuyVolume }},
+	{"taker_buy_quote_volume", doubleNode, func(k *model.Kline) interface{} { return k.TakerBuyQuoteVolume }},
+	{"source", parquet.String, func(k *model.Kline) interface{} { return k.Source }},
+}
+
+func timestampNode() parquet.Node { return parquet.Timestamp(parquet.Millisecond) }
+func int64Node() parquet.Node     { return parquet.Int(64) }
+func doubleNode() parquet.Node    { return parquet.Leaf(parquet.DoubleType) }
+
+// ColumnNames 返回全部可导出的列名
+func ColumnNames() []string {
+	names := make([]string, 0, len(allColumns))
+	for _, c := range allColumns {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// rowWriter 按格式逐行写出K线
+type rowWriter interface {
+	write(k *model.Kline) error
+	// close 写出剩余内容（不关闭底层 io.Writer）
+	close() error
+}
+
+// Validate 检查导出参数并补全默认值
+func (o *Options) Validate() error {
+	if o.Symbol == "" || o.Interval == "" {
+		return fmt.Errorf("%w：symbol和interval不能为空", ErrInvalidOptions)
+	}
+	o.Format = strings.ToLower(o.Format)
+	if o.Format == "" {
+		o.Format = FormatCSV
+	}
+	switch o.Format {
+	case FormatCSV, FormatJSONL, FormatParquet:
+	default:
+		return fmt.Errorf("%w：format只支持csv、jsonl或parquet", ErrInvalidOptions)
+	}
+	if !o.Start.IsZero() && !o.End.IsZero() && o.End.Before(o.Start) {
+		return fmt.Errorf("%w：end不能早于start", ErrInvalidOptions)
+	}
+	if _, err := selectColumns(o.Columns); err != nil {
+		return err
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = defaultBatchSize
+	}
+	return nil
+}
+
+// selectColumns 按列名选择列，为空时返回全部列
+func selectColumns(names []string) ([]column, error) {
+	if len(names) == 0 {
+		return allColumns, nil
+	}
+	selected := make([]column, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if seen[name] {
+			return nil, fmt.Errorf("%w：列 %s 重复", ErrInvalidOptions, name)
+		}
+		found := false
+		for _, c := range allColumns {
+			if c.name == name {
+				selected = append(selected, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w：不支持的列 %s，可选 %s", ErrInvalidOptions, name, strings.Join(ColumnNames(), ","))
+		}
+		seen[name] = true
+	}
+	return selected, nil
+}
+
+// ContentType 导出格式对应的 HTTP Content-Type
+func ContentType(opts Options) string {
+	if opts.Gzip && opts.Format != FormatParquet {
+		return "application/gzip"
+	}
+	switch opts.Format {
+	case FormatJSONL:
+		return "application/x-ndjson"
+	case FormatParquet:
+		return "application/vnd.apache.parquet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// FileName 导出文件名，如 BTCUSDT-1h.csv.gz
+func FileName(opts Options) string {
+	name := fmt.Sprintf("%s-%s.%s", opts.Symbol, opts.Interval, opts.Format)
+	if opts.Gzip && opts.Format != FormatParquet {
+		name += ".gz"
+	}
+	return name
+}
+
+// Export 按开盘时间升序分批读取K线并写入 w，返回导出的K线数量
+// 出错时 w 中可能已写入部分数据
+func Export(w io.Writer, opts Options) (int, error) {
+	if err := opts.Validate(); err != nil {
+		return 0, err
+	}
+	columns, _ := selectColumns(opts.Columns)
+
+	out := w
+	var zw *gzip.Writer
+	if opts.Gzip && opts.Format != FormatParquet {
+		zw = gzip.NewWriter(w)
+		out = zw
+	}
+
+	var rw rowWriter
+	var err error
+	switch opts.Format {
+	case FormatJSONL:
+		rw = newJSONLWriter(out, columns)
+	case FormatParquet:
+		rw = newParquetWriter(out, columns, opts.Gzip)
+	default:
+		rw, err = newCSVWriter(out, columns)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	query := store.KlineQuery{Symbol: opts.Symbol, Interval: opts.Interval, Start: opts.Start, End: opts.End, Limit: opts.BatchSize}
+	for {
+		klines, err := store.Default().FindKlines(query)
+		if err != nil {
+			return count, fmt.Errorf("查询K线失败: %v", err)
+		}
+		for i := range klines {
+			if err := rw.write(&klines[i]); err != nil {
+				return count, err
+			}
+		}
+		count += len(klines)
+		if len(klines) < opts.BatchSize {
+			break
+		}
+		query.Start = klines[len(klines)-1].OpenTime.Add(time.Millisecond)
+	}
+
+	if err := rw.close(); err != nil {
+		return count, err
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,131 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"trade/internal/fakebinance"
+	"trade/store"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// seedKlines 在内存存储中写入 10 根 1h K线
+func seedKlines(t *testing.T) time.Time {
+	t.Helper()
+	memory := store.NewMemoryStore()
+	store.SetDefault(memory)
+	t.Cleanup(func() { store.SetDefault(nil) })
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	memory.UpsertKlines(fakebinance.GenerateKlines("BTCUSDT", "1h", start, 10, 42000))
+	memory.UpsertKlines(fakebinance.GenerateKlines("ETHUSDT", "1h", start, 10, 2200))
+	return start
+}
+
+func TestExportCSVBatches(t *testing.T) {
+	start := seedKlines(t)
+
+	var buf bytes.Buffer
+	count, err := Export(&buf, Options{
+		Symbol:    "BTCUSDT",
+		Interval:  "1h",
+		Start:     start.Add(2 * time.Hour),
+		End:       start.Add(8 * time.Hour),
+		Columns:   []string{"open_time", "close", "trade_num"},
+		BatchSize: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if count != 7 || len(lines) != 8 {
+		t.Fatalf("应分批导出 7 根K线，实际 %d: %q", count, lines)
+	}
+	if lines[0] != "open_time,close,trade_num" || !strings.HasPrefix(lines[1], "2024-01-01T02:00:00Z,") {
+		t.Errorf("CSV 表头或首行错误: %q", lines[:2])
+	}
+	if !strings.HasPrefix(lines[7], "2024-01-01T08:00:00Z,") {
+		t.Errorf("结束时间应包含在内: %q", lines[7])
+	}
+}
+
+func TestExportJSONLGzip(t *testing.T) {
+	seedKlines(t)
+
+	var buf bytes.Buffer
+	count, err := Export(&buf, Options{Symbol: "ETHUSDT", Interval: "1h", Format: "JSONL", Gzip: true})
+	if err != nil || count != 10 {
+		t.Fatalf("导出失败: %v %d", err, count)
+	}
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(zr)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 10 || !strings.HasPrefix(lines[0], `{"open_time":"2024-01-01T00:00:00Z","close_time":`) {
+		t.Fatalf("JSONL 内容错误: %q", lines[0])
+	}
+	var row map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[9]), &row); err != nil || row["symbol"] != "ETHUSDT" || len(row) != len(allColumns) {
+		t.Errorf("JSONL 行解析错误: %v %v", err, row)
+	}
+}
+
+func TestExportParquet(t *testing.T) {
+	start := seedKlines(t)
+
+	var buf bytes.Buffer
+	count, err := Export(&buf, Options{
+		Symbol:    "BTCUSDT",
+		Interval:  "1h",
+		Format:    FormatParquet,
+		Columns:   []string{"symbol", "open_time", "close", "trade_num"},
+		BatchSize: 4,
+	})
+	if err != nil || count != 10 {
+		t.Fatalf("导出失败: %v %d", err, count)
+	}
+
+	type row struct {
+		Symbol   string    `parquet:"symbol"`
+		OpenTime time.Time `parquet:"open_time,timestamp(millisecond)"`
+		Close    float64   `parquet:"close"`
+		TradeNum int64     `parquet:"trade_num"`
+	}
+	rows, err := parquet.Read[row](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	klines, _ := store.Default().FindKlines(store.KlineQuery{Symbol: "BTCUSDT", Interval: "1h"})
+	if len(rows) != 10 || rows[0].Symbol != "BTCUSDT" || !rows[0].OpenTime.Equal(start) {
+		t.Fatalf("Parquet 内容错误: %+v", rows)
+	}
+	if rows[9].Close != klines[9].Close || rows[9].TradeNum != klines[9].TradeNum {
+		t.Errorf("Parquet 数值错误: %+v", rows[9])
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	for _, opts := range []Options{
+		{Interval: "1h"},
+		{Symbol: "BTCUSDT", Interval: "1h", Format: "xlsx"},
+		{Symbol: "BTCUSDT", Interval: "1h", Columns: []string{"open", "vwap"}},
+		{Symbol: "BTCUSDT", Interval: "1h", Columns: []string{"open", "open"}},
+		{Symbol: "BTCUSDT", Interval: "1h", Start: time.Now(), End: time.Now().Add(-time.Hour)},
+	} {
+		if err := opts.Validate(); !errors.Is(err, ErrInvalidOptions) {
+			t.Errorf("%+v 应返回参数错误，实际 %v", opts, err)
+		}
+	}
+	if name := FileName(Options{Symbol: "BTCUSDT", Interval: "1h", Format: FormatCSV, Gzip: true}); name != "BTCUSDT-1h.csv.gz" {
+		t.Errorf("文件名错误: %s", name)
+	}
+}
@@ -0,0 +1,157 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"trade/model"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+)
+
+// parquetRowGroupRows Parquet 每个行组的行数，写满一个行组后输出，限制内存占用
+const parquetRowGroupRows = 50000
+
+// formatValue 把列值格式化为文本：时间使用 RFC3339(UTC)，数字使用最短表示
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// csvWriter 第一行为列名
+type csvWriter struct {
+	w       *csv.Writer
+	columns []column
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, columns []column) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w), columns: columns, record: make([]string, len(columns))}
+	for i, c := range columns {
+		cw.record[i] = c.name
+	}
+	return cw, cw.w.Write(cw.record)
+}
+
+func (cw *csvWriter) write(k *model.Kline) error {
+	for i, c := range cw.columns {
+		cw.record[i] = formatValue(c.value(k))
+	}
+	return cw.w.Write(cw.record)
+}
+
+func (cw *csvWriter) close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// jsonlWriter 每行一个 JSON 对象，字段顺序与列顺序一致
+type jsonlWriter struct {
+	w       *bufio.Writer
+	columns []column
+}
+
+func newJSONLWriter(w io.Writer, columns []column) *jsonlWriter {
+	return &jsonlWriter{w: bufio.NewWriter(w), columns: columns}
+}
+
+func (jw *jsonlWriter) write(k *model.Kline) error {
+	jw.w.WriteByte('{')
+	for i, c := range jw.columns {
+		if i > 0 {
+			jw.w.WriteByte(',')
+		}
+		value := c.value(k)
+		if t, ok := value.(time.Time); ok {
+			value = formatValue(t)
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		jw.w.WriteString(strconv.Quote(c.name))
+		jw.w.WriteByte(':')
+		jw.w.Write(data)
+	}
+	jw.w.WriteString("}\n")
+	return nil
+}
+
+func (jw *jsonlWriter) close() error {
+	return jw.w.Flush()
+}
+
+// parquetWriter 时间列为 TIMESTAMP(MILLIS)，价格和成交量为 DOUBLE
+// Parquet 的列按列名字母顺序排列，读取时按列名访问即可
+type parquetWriter struct {
+	w       *parquet.Writer
+	columns []column
+	index   []int // columns[i] 在 Parquet schema 中的列序号
+}
+
+func newParquetWriter(w io.Writer, columns []column, gzip bool) *parquetWriter {
+	group := make(parquet.Group, len(columns))
+	for _, c := range columns {
+		group[c.name] = c.parquet()
+	}
+	schema := parquet.NewSchema("kline", group)
+
+	position := make(map[string]int, len(columns))
+	for i, field := range schema.Fields() {
+		position[field.Name()] = i
+	}
+	index := make([]int, len(columns))
+	for i, c := range columns {
+		index[i] = position[c.name]
+	}
+
+	var codec compress.Codec = &parquet.Snappy
+	if gzip {
+		codec = &parquet.Gzip
+	}
+	pw := parquet.NewWriter(w, schema, parquet.Compression(codec), parquet.MaxRowsPerRowGroup(parquetRowGroupRows))
+	return &parquetWriter{w: pw, columns: columns, index: index}
+}
+
+func (pw *parquetWriter) write(k *model.Kline) error {
+	row := make(parquet.Row, len(pw.columns))
+	for i, c := range pw.columns {
+		var value parquet.Value
+		switch v := c.value(k).(type) {
+		case time.Time:
+			value = parquet.Int64Value(v.UnixMilli())
+		case float64:
+			value = parquet.DoubleValue(v)
+		case int64:
+			value = parquet.Int64Value(v)
+		case string:
+			value = parquet.ByteArrayValue([]byte(v))
+		}
+		row[pw.index[i]] = value.Level(0, 0, pw.index[i])
+	}
+	_, err := pw.w.WriteRows([]parquet.Row{row})
+	return err
+}
+
+func (pw *parquetWriter) close() error {
+	if err := pw.w.Close(); err != nil {
+		return fmt.Errorf("写入 Parquet 文件尾失败: %v", err)
+	}
+	return nil
+}
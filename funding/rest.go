@@ -0,0 +1,93 @@
+// Package funding 资金费率历史的拉取与入库
+package funding
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trade/db"
+	"trade/model"
+	"trade/store"
+	"trade/utils"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+// 每页最多 1000 条（约 333 天）
+const pageLimit = 1000
+
+// defaultStartTime 没有历史数据时的起始时间，币安最早的U本位永续合约在 2019-09 上线
+var defaultStartTime = time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC)
+
+// UpdateFundingRate 增量更新资金费率，从已入库的最新结算时间之后继续
+func UpdateFundingRate(symbol string) {
+	startTime := defaultStartTime
+	latest, err := store.Default().LatestFundingRate(symbol)
+	if err != nil {
+		fmt.Printf("查询 %s 最新资金费率失败: %v\n", symbol, err)
+		return
+	}
+	if latest != nil {
+		startTime = latest.FundingTime.Add(time.Millisecond)
+		fmt.Printf("将从 %s 之后获取 %s 的资金费率\n", latest.FundingTime.Format("2006-01-02 15:04:05"), symbol)
+	} else {
+		fmt.Printf("未找到 %s 的资金费率,将从 %s 开始获取\n", symbol, startTime.Format("2006-01-02"))
+	}
+
+	updateFundingRates(db.BinanceClient, symbol, startTime)
+}
+
+// updateFundingRates 从 startTime 开始分页拉取资金费率直到最新
+// 每页写入成功后再请求下一页，中途失败时已写入的数据保留，下次从最新一条继续
+func updateFundingRates(api *futures.Client, symbol string, startTime time.Time) {
+	totalCount := 0
+	for {
+		rates, err := api.NewFundingRateService().
+			Symbol(symbol).
+			StartTime(startTime.UnixMilli()).
+			Limit(pageLimit).
+			Do(context.Background())
+		if err != nil {
+			fmt.Printf("获取 %s 资金费率失败: %v\n", symbol, err)
+			return
+		}
+		if len(rates) == 0 {
+			break
+		}
+
+		models := make([]model.FundingRate, 0, len(rates))
+		for _, rate := range rates {
+			models = append(models, buildFundingRateModel(rate))
+		}
+		if _, err := store.Default().UpsertFundingRates(models); err != nil {
+			fmt.Printf("写入 %s 资金费率失败: %v\n", symbol, err)
+			return
+		}
+		totalCount += len(models)
+
+		startTime = models[len(models)-1].FundingTime.Add(time.Millisecond)
+		if len(rates) < pageLimit {
+			break
+		}
+
+		// 添加延迟避免API限制
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	fmt.Printf("%s 资金费率更新完成，共写入 %d 条\n", symbol, totalCount)
+}
+
+// buildFundingRateModel 根据币安返回的资金费率构建数据库模型
+func buildFundingRateModel(rate *futures.FundingRate) model.FundingRate {
+	fundingRate := model.FundingRate{
+		Symbol:      rate.Symbol,
+		FundingTime: time.UnixMilli(rate.FundingTime).UTC(),
+		FundingRate: utils.StringToFloat64(rate.FundingRate),
+	}
+	// 早期记录没有标记价格
+	if rate.MarkPrice != "" {
+		fundingRate.MarkPrice = utils.StringToFloat64(rate.MarkPrice)
+	}
+	return fundingRate
+}
@@ -0,0 +1,59 @@
+package funding
+
+import (
+	"testing"
+	"time"
+
+	"trade/db"
+	"trade/internal/fakebinance"
+	"trade/model"
+	"trade/store"
+)
+
+const fundingRatePath = "/fapi/v1/fundingRate"
+
+func TestUpdateFundingRateIncremental(t *testing.T) {
+	memory := store.NewMemoryStore()
+	store.SetDefault(memory)
+	server := fakebinance.NewServer()
+	db.InitBinance(&model.BinanceConfig{DataURL: server.URL()})
+	t.Cleanup(func() {
+		server.Close()
+		store.SetDefault(nil)
+		db.BinanceClient = nil
+	})
+
+	rates := fakebinance.GenerateFundingRates("BTCUSDT", defaultStartTime, 1203)
+	server.AddFundingRates(rates[:1200]...)
+
+	// 首次从默认起始时间分页拉取
+	UpdateFundingRate("BTCUSDT")
+	stored, _ := memory.FindFundingRates("BTCUSDT", time.Time{}, time.Time{})
+	if len(stored) != 1200 {
+		t.Fatalf("应写入 1200 条资金费率，实际 %d", len(stored))
+	}
+	if requests := server.Requests(fundingRatePath); requests != 2 {
+		t.Errorf("每页 1000 条应请求 2 次，实际 %d", requests)
+	}
+	if stored[3].FundingRate != rates[3].FundingRate || !stored[3].FundingTime.Equal(rates[3].FundingTime) {
+		t.Errorf("资金费率解析错误: %+v", stored[3])
+	}
+
+	// 增量更新只拉取最新一条之后的数据
+	server.AddFundingRates(rates[1200:]...)
+	UpdateFundingRate("BTCUSDT")
+	stored, _ = memory.FindFundingRates("BTCUSDT", time.Time{}, time.Time{})
+	if len(stored) != 1203 {
+		t.Fatalf("增量更新后应有 1203 条，实际 %d", len(stored))
+	}
+	if requests := server.Requests(fundingRatePath); requests != 3 {
+		t.Errorf("增量更新应只请求 1 次，实际共 %d 次", requests)
+	}
+
+	// 请求失败时保留已写入数据，下次继续
+	server.InjectError(fundingRatePath, 500, -1001, "Internal error; unable to process your request.", 1)
+	UpdateFundingRate("BTCUSDT")
+	if latest, _ := memory.LatestFundingRate("BTCUSDT"); latest == nil || !latest.FundingTime.Equal(rates[1202].FundingTime) {
+		t.Errorf("请求失败后最新资金费率应保持不变: %+v", latest)
+	}
+}
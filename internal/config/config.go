@@ -1,16 +1,22 @@
 package config
 
 import (
+	"log"
 	"sync"
 
 	"trade/db"
+	"trade/utils"
 )
 
 var once sync.Once
 
 func InitConfig() {
 	once.Do(func() {
-		db.InitPostgreSqlWs()
-		db.InitBinance("", "")
+		config, err := utils.LoadConfig(utils.DefaultConfigPath())
+		if err != nil {
+			log.Fatalf("加载配置文件失败: %v", err)
+		}
+		db.InitPostgreSqlWs(&config.Database)
+		db.InitBinance(&config.Binance)
 	})
 }
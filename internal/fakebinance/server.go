@@ -0,0 +1,790 @@
+// Package fakebinance 本地模拟的币安U本位合约行情服务，用于离线集成测试
+// 提供 /fapi/v1/continuousKlines、/fapi/v1/fundingRate、/fapi/v1/exchangeInfo、/futures/data 下的持仓统计接口
+// 和组合 WebSocket 流(/stream)，
+// 支持可编程的限频(HTTP 429/418)和错误注入
+package fakebinance
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"trade/model"
+	"trade/utils"
+
+	"github.com/gorilla/websocket"
+)
+
+// 币安限频错误码：429 请求过多，继续请求会被 418 封禁
+const (
+	CodeTooManyRequests = -1003
+	defaultKlineLimit   = 500
+	maxKlineLimit       = 1500
+	defaultFundingLimit = 100
+	maxFundingLimit     = 1000
+	defaultDataLimit    = 30
+	maxDataLimit        = 500
+)
+
+// Positioning 一个统计时间点的持仓量、大户多空比和主动买卖量，供 /futures/data 下的接口返回
+type Positioning struct {
+	Symbol            string
+	Period            string
+	Time              time.Time
+	OpenInterest      float64 // 持仓总数量
+	OpenInterestValue float64 // 持仓总价值
+	TopAccountRatio   float64 // 大户账户数多空比
+	TopPositionRatio  float64 // 大户持仓量多空比
+	TakerBuyVolume    float64 // 主动买入量
+	TakerSellVolume   float64 // 主动卖出量
+}
+
+// RateLimit 限频规则：Window 内超过 Requests 次请求返回 429，
+// 连续 BanAfter 次 429 后在 BanDuration 内所有请求返回 418；零值表示不限频
+type RateLimit struct {
+	Requests    int
+	Window      time.Duration
+	BanAfter    int
+	BanDuration time.Duration
+}
+
+// injectedError 注入的错误响应
+type injectedError struct {
+	status  int
+	code    int
+	message string
+	times   int
+}
+
+// Server 模拟币安合约行情服务
+type Server struct {
+	mu sync.Mutex
+
+	server   *httptest.Server
+	upgrader websocket.Upgrader
+
+	klines   map[string][]model.Kline       // pair|interval -> 按开盘时间升序的K线
+	funding  map[string][]model.FundingRate // symbol -> 按结算时间升序的资金费率
+	stats    map[string][]Positioning       // symbol|period -> 按统计时间升序的持仓统计
+	requests map[string]int                 // 路径 -> 请求次数
+	errors   map[string][]*injectedError
+
+	rateLimit    RateLimit
+	windowStart  time.Time
+	windowCount  int
+	limitedCount int
+	bannedUntil  time.Time
+
+	conns       map[*websocket.Conn][]string // 连接 -> 订阅的流名称
+	connections int                          // 累计建立的连接数
+}
+
+// NewServer 启动模拟服务，使用完毕后调用 Close
+func NewServer() *Server {
+	s := &Server{
+		klines:   make(map[string][]model.Kline),
+		funding:  make(map[string][]model.FundingRate),
+		stats:    make(map[string][]Positioning),
+		requests: make(map[string]int),
+		errors:   make(map[string][]*injectedError),
+		conns:    make(map[*websocket.Conn][]string),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/fapi/v1/ping", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	})
+	mux.HandleFunc("/fapi/v1/time", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]int64{"serverTime": time.Now().UnixMilli()})
+	})
+	mux.HandleFunc("/fapi/v1/continuousKlines", s.handleContinuousKlines)
+	mux.HandleFunc("/fapi/v1/fundingRate", s.handleFundingRate)
+	mux.HandleFunc("/fapi/v1/exchangeInfo", s.handleExchangeInfo)
+	mux.HandleFunc("/futures/data/openInterestHist", s.handlePositioning(openInterestRow))
+	mux.HandleFunc("/futures/data/topLongShortAccountRatio", s.handlePositioning(topAccountRow))
+	mux.HandleFunc("/futures/data/topLongShortPositionRatio", s.handlePositioning(topPositionRow))
+	mux.HandleFunc("/futures/data/takerlongshortRatio", s.handlePositioning(takerRow))
+	mux.HandleFunc("/stream", s.handleStream)
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stream" && !s.admit(w, r.URL.Path) {
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	return s
+}
+
+// URL 返回 REST 接口地址，例如 http://127.0.0.1:12345
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// StreamURL 返回 WebSocket 地址，例如 ws://127.0.0.1:12345
+func (s *Server) StreamURL() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http")
+}
+
+// Close 断开所有 WebSocket 连接并关闭服务
+func (s *Server) Close() {
+	s.Disconnect()
+	s.server.Close()
+}
+
+// AddKlines 添加K线数据，相同开盘时间的K线会被覆盖
+func (s *Server) AddKlines(klines ...model.Kline) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	touched := make(map[string]bool)
+	for _, k := range klines {
+		key := seriesKey(k.Symbol, k.Interval)
+		series := s.klines[key]
+		replaced := false
+		for i := range series {
+			if series[i].OpenTime.Equal(k.OpenTime) {
+				series[i] = k
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			series = append(series, k)
+		}
+		s.klines[key] = series
+		touched[key] = true
+	}
+	for key := range touched {
+		series := s.klines[key]
+		sort.Slice(series, func(i, j int) bool {
+			return series[i].OpenTime.Before(series[j].OpenTime)
+		})
+	}
+}
+
+// Klines 返回服务中某交易对+周期的全部K线
+func (s *Server) Klines(pair, interval string) []model.Kline {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]model.Kline{}, s.klines[seriesKey(pair, interval)]...)
+}
+
+// AddFundingRates 添加资金费率，相同结算时间的记录会被覆盖
+func (s *Server) AddFundingRates(rates ...model.FundingRate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	touched := make(map[string]bool)
+	for _, rate := range rates {
+		series := s.funding[rate.Symbol]
+		replaced := false
+		for i := range series {
+			if series[i].FundingTime.Equal(rate.FundingTime) {
+				series[i] = rate
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			series = append(series, rate)
+		}
+		s.funding[rate.Symbol] = series
+		touched[rate.Symbol] = true
+	}
+	for symbol := range touched {
+		series := s.funding[symbol]
+		sort.Slice(series, func(i, j int) bool {
+			return series[i].FundingTime.Before(series[j].FundingTime)
+		})
+	}
+}
+
+// AddPositioning 添加持仓统计，相同交易对+周期+统计时间的记录会被覆盖
+func (s *Server) AddPositioning(points ...Positioning) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	touched := make(map[string]bool)
+	for _, point := range points {
+		key := seriesKey(point.Symbol, point.Period)
+		series := s.stats[key]
+		replaced := false
+		for i := range series {
+			if series[i].Time.Equal(point.Time) {
+				series[i] = point
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			series = append(series, point)
+		}
+		s.stats[key] = series
+		touched[key] = true
+	}
+	for key := range touched {
+		series := s.stats[key]
+		sort.Slice(series, func(i, j int) bool {
+			return series[i].Time.Before(series[j].Time)
+		})
+	}
+}
+
+// SetRateLimit 设置限频规则，零值表示不限频
+func (s *Server) SetRateLimit(limit RateLimit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimit = limit
+	s.windowStart = time.Time{}
+	s.windowCount = 0
+	s.limitedCount = 0
+	s.bannedUntil = time.Time{}
+}
+
+// InjectError 让 path 接下来的 times 次请求返回 HTTP status 和币安错误码 code
+func (s *Server) InjectError(path string, status, code int, message string, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors[path] = append(s.errors[path], &injectedError{status: status, code: code, message: message, times: times})
+}
+
+// Requests 返回 path 收到的请求次数（含被限频和注入错误的请求）
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// admit 统计请求并按注入错误和限频规则决定是否放行，拒绝时已写入错误响应
+func (s *Server) admit(w http.ResponseWriter, path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[path]++
+
+	if queue := s.errors[path]; len(queue) > 0 {
+		injected := queue[0]
+		injected.times--
+		if injected.times <= 0 {
+			s.errors[path] = queue[1:]
+		}
+		writeJSON(w, injected.status, map[string]interface{}{"code": injected.code, "msg": injected.message})
+		return false
+	}
+
+	limit := s.rateLimit
+	if limit.Requests <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	if now.Before(s.bannedUntil) {
+		writeJSON(w, http.StatusTeapot, map[string]interface{}{
+			"code": CodeTooManyRequests,
+			"msg":  fmt.Sprintf("Way too many requests; IP banned until %d.", s.bannedUntil.UnixMilli()),
+		})
+		return false
+	}
+	if s.windowStart.IsZero() || now.Sub(s.windowStart) >= limit.Window {
+		s.windowStart = now
+		s.windowCount = 0
+	}
+	s.windowCount++
+	if s.windowCount <= limit.Requests {
+		s.limitedCount = 0
+		return true
+	}
+
+	s.limitedCount++
+	if limit.BanAfter > 0 && s.limitedCount >= limit.BanAfter {
+		s.bannedUntil = now.Add(limit.BanDuration)
+	}
+	retryAfter := int(s.windowStart.Add(limit.Window).Sub(now).Seconds() + 1)
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	writeJSON(w, http.StatusTooManyRequests, map[string]interface{}{
+		"code": CodeTooManyRequests,
+		"msg":  "Too many requests; current limit is exceeded.",
+	})
+	return false
+}
+
+// handleContinuousKlines 返回开盘时间在 [startTime, endTime] 内的K线，格式与币安一致
+func (s *Server) handleContinuousKlines(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	pair, interval := query.Get("pair"), query.Get("interval")
+	if pair == "" || interval == "" || query.Get("contractType") == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"code": -1102, "msg": "Mandatory parameter was not sent."})
+		return
+	}
+
+	limit := defaultKlineLimit
+	if value := query.Get("limit"); value != "" {
+		limit, _ = strconv.Atoi(value)
+		if limit <= 0 || limit > maxKlineLimit {
+			limit = maxKlineLimit
+		}
+	}
+	start, end := int64(0), int64(1<<62)
+	if value := query.Get("startTime"); value != "" {
+		start, _ = strconv.ParseInt(value, 10, 64)
+	}
+	if value := query.Get("endTime"); value != "" {
+		end, _ = strconv.ParseInt(value, 10, 64)
+	}
+
+	rows := make([][]interface{}, 0)
+	for _, k := range s.Klines(pair, interval) {
+		openTime := k.OpenTime.UnixMilli()
+		if openTime < start || openTime > end {
+			continue
+		}
+		rows = append(rows, []interface{}{
+			openTime, formatFloat(k.Open), formatFloat(k.High), formatFloat(k.Low), formatFloat(k.Close),
+			formatFloat(k.Volume), closeTimeMillis(k), formatFloat(k.QuoteVolume), k.TradeNum,
+			formatFloat(k.TakerBuyVolume), formatFloat(k.TakerBuyQuoteVolume), "0",
+		})
+		if len(rows) >= limit {
+			break
+		}
+	}
+	writeJSON(w, http.StatusOK, rows)
+}
+
+// handleFundingRate 返回结算时间在 [startTime, endTime] 内的资金费率，格式与币安一致
+// 与币安相同：只传 endTime 时返回其之前最近的 limit 条，否则从 startTime 起取 limit 条
+func (s *Server) handleFundingRate(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	symbol := query.Get("symbol")
+
+	limit := defaultFundingLimit
+	if value := query.Get("limit"); value != "" {
+		limit, _ = strconv.Atoi(value)
+		if limit <= 0 || limit > maxFundingLimit {
+			limit = maxFundingLimit
+		}
+	}
+	start, end := int64(0), int64(1<<62)
+	if value := query.Get("startTime"); value != "" {
+		start, _ = strconv.ParseInt(value, 10, 64)
+	}
+	if value := query.Get("endTime"); value != "" {
+		end, _ = strconv.ParseInt(value, 10, 64)
+	}
+
+	s.mu.Lock()
+	matched := make([]model.FundingRate, 0)
+	for _, rate := range s.funding[symbol] {
+		if fundingTime := rate.FundingTime.UnixMilli(); fundingTime >= start && fundingTime <= end {
+			matched = append(matched, rate)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(matched) > limit {
+		if query.Get("startTime") == "" && query.Get("endTime") != "" {
+			matched = matched[len(matched)-limit:]
+		} else {
+			matched = matched[:limit]
+		}
+	}
+
+	rows := make([]map[string]interface{}, 0, len(matched))
+	for _, rate := range matched {
+		rows = append(rows, map[string]interface{}{
+			"symbol":      rate.Symbol,
+			"fundingTime": rate.FundingTime.UnixMilli(),
+			"fundingRate": strconv.FormatFloat(rate.FundingRate, 'f', 8, 64),
+			"markPrice":   formatFloat(rate.MarkPrice),
+		})
+	}
+	writeJSON(w, http.StatusOK, rows)
+}
+
+// handlePositioning 返回统计时间在 [startTime, endTime] 内的持仓统计，row 生成单条记录的响应字段
+// 与币安相同：不传 startTime 时返回最近的 limit 条，否则从 startTime 起取 limit 条
+func (s *Server) handlePositioning(row func(point Positioning) map[string]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		limit := defaultDataLimit
+		if value := query.Get("limit"); value != "" {
+			limit, _ = strconv.Atoi(value)
+			if limit <= 0 || limit > maxDataLimit {
+				limit = maxDataLimit
+			}
+		}
+		start, end := int64(0), int64(1<<62)
+		if value := query.Get("startTime"); value != "" {
+			start, _ = strconv.ParseInt(value, 10, 64)
+		}
+		if value := query.Get("endTime"); value != "" {
+			end, _ = strconv.ParseInt(value, 10, 64)
+		}
+
+		s.mu.Lock()
+		matched := make([]Positioning, 0)
+		for _, point := range s.stats[seriesKey(query.Get("symbol"), query.Get("period"))] {
+			if at := point.Time.UnixMilli(); at >= start && at <= end {
+				matched = append(matched, point)
+			}
+		}
+		s.mu.Unlock()
+
+		if len(matched) > limit {
+			if query.Get("startTime") == "" {
+				matched = matched[len(matched)-limit:]
+			} else {
+				matched = matched[:limit]
+			}
+		}
+
+		rows := make([]map[string]interface{}, 0, len(matched))
+		for _, point := range matched {
+			rows = append(rows, row(point))
+		}
+		writeJSON(w, http.StatusOK, rows)
+	}
+}
+
+func openInterestRow(point Positioning) map[string]interface{} {
+	return map[string]interface{}{
+		"symbol":               point.Symbol,
+		"sumOpenInterest":      formatFloat(point.OpenInterest),
+		"sumOpenInterestValue": formatFloat(point.OpenInterestValue),
+		"timestamp":            point.Time.UnixMilli(),
+	}
+}
+
+func topAccountRow(point Positioning) map[string]interface{} {
+	return longShortRow(point, point.TopAccountRatio)
+}
+
+func topPositionRow(point Positioning) map[string]interface{} {
+	return longShortRow(point, point.TopPositionRatio)
+}
+
+// longShortRow 多空比记录，多空占比由多空比换算：long = ratio / (1 + ratio)
+func longShortRow(point Positioning, ratio float64) map[string]interface{} {
+	long := ratio / (1 + ratio)
+	return map[string]interface{}{
+		"symbol":         point.Symbol,
+		"longShortRatio": formatFloat(ratio),
+		"longAccount":    strconv.FormatFloat(long, 'f', 4, 64),
+		"shortAccount":   strconv.FormatFloat(1-long, 'f', 4, 64),
+		"timestamp":      point.Time.UnixMilli(),
+	}
+}
+
+func takerRow(point Positioning) map[string]interface{} {
+	ratio := 0.0
+	if point.TakerSellVolume > 0 {
+		ratio = point.TakerBuyVolume / point.TakerSellVolume
+	}
+	return map[string]interface{}{
+		"buySellRatio": strconv.FormatFloat(ratio, 'f', 4, 64),
+		"buyVol":       formatFloat(point.TakerBuyVolume),
+		"sellVol":      formatFloat(point.TakerSellVolume),
+		"timestamp":    point.Time.UnixMilli(),
+	}
+}
+
+// handleExchangeInfo 返回已添加K线的交易对的规则
+func (s *Server) handleExchangeInfo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	pairs := make(map[string]bool)
+	for key := range s.klines {
+		pairs[strings.SplitN(key, "|", 2)[0]] = true
+	}
+	s.mu.Unlock()
+
+	names := make([]string, 0, len(pairs))
+	for pair := range pairs {
+		names = append(names, pair)
+	}
+	sort.Strings(names)
+
+	symbols := make([]map[string]interface{}, 0, len(names))
+	for _, pair := range names {
+		symbols = append(symbols, map[string]interface{}{
+			"symbol":       pair,
+			"pair":         pair,
+			"contractType": "PERPETUAL",
+			"status":       "TRADING",
+			"baseAsset":    strings.TrimSuffix(pair, "USDT"),
+			"quoteAsset":   "USDT",
+			"filters": []map[string]string{
+				{"filterType": "PRICE_FILTER", "tickSize": "0.10", "minPrice": "0.10", "maxPrice": "1000000"},
+				{"filterType": "LOT_SIZE", "stepSize": "0.001", "minQty": "0.001", "maxQty": "1000"},
+				{"filterType": "MARKET_LOT_SIZE", "stepSize": "0.001", "minQty": "0.001", "maxQty": "120"},
+				{"filterType": "MIN_NOTIONAL", "notional": "5"},
+			},
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"timezone":   "UTC",
+		"serverTime": time.Now().UnixMilli(),
+		"rateLimits": []map[string]interface{}{
+			{"rateLimitType": "REQUEST_WEIGHT", "interval": "MINUTE", "intervalNum": 1, "limit": 2400},
+		},
+		"symbols": symbols,
+	})
+}
+
+// handleStream 组合流：/stream?streams=btcusdt_perpetual@continuousKline_1h/...
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	streams := strings.Split(r.URL.Query().Get("streams"), "/")
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.conns[conn] = streams
+	s.connections++
+	s.mu.Unlock()
+
+	// 读取直到客户端断开，以便及时清理连接
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				s.mu.Lock()
+				delete(s.conns, conn)
+				s.mu.Unlock()
+				conn.Close()
+				return
+			}
+		}
+	}()
+}
+
+// Connections 返回累计建立的 WebSocket 连接数和当前连接数
+func (s *Server) Connections() (total, active int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections, len(s.conns)
+}
+
+// WaitConnections 等待累计连接数达到 total，超时返回 false
+func (s *Server) WaitConnections(total int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if n, active := s.Connections(); n >= total && active > 0 {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return false
+}
+
+// Disconnect 断开所有 WebSocket 连接，用于测试重连
+func (s *Server) Disconnect() {
+	s.mu.Lock()
+	conns := make([]*websocket.Conn, 0, len(s.conns))
+	for conn := range s.conns {
+		conns = append(conns, conn)
+	}
+	s.conns = make(map[*websocket.Conn][]string)
+	s.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.Close()
+	}
+}
+
+// Push 向订阅了该K线流的连接推送一条连续合约K线事件，final 表示K线是否已收盘
+// 已收盘的K线同时加入 REST 数据，返回收到推送的连接数
+func (s *Server) Push(k model.Kline, final bool) int {
+	if final {
+		s.AddKlines(k)
+	}
+
+	stream := fmt.Sprintf("%s_perpetual@continuousKline_%s", strings.ToLower(k.Symbol), k.Interval)
+	message, _ := json.Marshal(map[string]interface{}{
+		"stream": stream,
+		"data": map[string]interface{}{
+			"e":  "continuous_kline",
+			"E":  time.Now().UnixMilli(),
+			"ps": k.Symbol,
+			"ct": "PERPETUAL",
+			"k": map[string]interface{}{
+				"t": k.OpenTime.UnixMilli(),
+				"T": closeTimeMillis(k),
+				"i": k.Interval,
+				"f": 0,
+				"L": k.TradeNum,
+				"o": formatFloat(k.Open),
+				"c": formatFloat(k.Close),
+				"h": formatFloat(k.High),
+				"l": formatFloat(k.Low),
+				"v": formatFloat(k.Volume),
+				"n": k.TradeNum,
+				"x": final,
+				"q": formatFloat(k.QuoteVolume),
+				"V": formatFloat(k.TakerBuyVolume),
+				"Q": formatFloat(k.TakerBuyQuoteVolume),
+			},
+		},
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sent := 0
+	for conn, streams := range s.conns {
+		for _, subscribed := range streams {
+			if subscribed != stream {
+				continue
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, message); err == nil {
+				sent++
+			}
+			break
+		}
+	}
+	return sent
+}
+
+// GenerateKlines 生成从 start 开始连续 count 根确定性的合成K线，价格围绕 base 小幅波动
+func GenerateKlines(pair, interval string, start time.Time, count int, base float64) []model.Kline {
+	step := utils.IntervalDuration(interval)
+	klines := make([]model.Kline, 0, count)
+	price := base
+	for i := 0; i < count; i++ {
+		openTime := start.Add(time.Duration(i) * step).UTC()
+		// 周期为7的锯齿波动，保证涨跌都有
+		change := float64(i%7-3) * base * 0.001
+		open, close := price, price+change
+		high, low := open, close
+		if close > open {
+			high, low = close, open
+		}
+		k := model.Kline{
+			Symbol:              pair,
+			Interval:            interval,
+			OpenTime:            openTime,
+			CloseTime:           openTime.Add(step - time.Millisecond),
+			Open:                open,
+			High:                high + base*0.0005,
+			Low:                 low - base*0.0005,
+			Close:               close,
+			Volume:              float64(100 + i%10),
+			QuoteVolume:         float64(100+i%10) * close,
+			TradeNum:            int64(1000 + i),
+			TakerBuyVolume:      float64(50 + i%5),
+			TakerBuyQuoteVolume: float64(50+i%5) * close,
+		}
+		klines = append(klines, k)
+		price = close
+	}
+	return klines
+}
+
+// GenerateFundingRates 生成从 start 开始每 8 小时一次、共 count 条确定性的合成资金费率
+// 费率在 -0.05% 到 0.07% 之间循环，正负和大小都有覆盖
+func GenerateFundingRates(symbol string, start time.Time, count int) []model.FundingRate {
+	pattern := []float64{0.0001, 0.0001, 0.0003, 0.0007, 0.0001, -0.0001, -0.0005, 0.0001}
+	rates := make([]model.FundingRate, 0, count)
+	for i := 0; i < count; i++ {
+		rates = append(rates, model.FundingRate{
+			Symbol:      symbol,
+			FundingTime: start.Add(time.Duration(i) * 8 * time.Hour).UTC(),
+			FundingRate: pattern[i%len(pattern)],
+		})
+	}
+	return rates
+}
+
+// GeneratePositioning 生成从 start 开始按 period 间隔、共 count 条确定性的合成持仓统计
+func GeneratePositioning(symbol, period string, start time.Time, count int) []Positioning {
+	step := utils.IntervalDuration(period)
+	points := make([]Positioning, 0, count)
+	for i := 0; i < count; i++ {
+		openInterest := 80000 + float64(i%50)*100
+		points = append(points, Positioning{
+			Symbol:            symbol,
+			Period:            period,
+			Time:              start.Add(time.Duration(i) * step).UTC(),
+			OpenInterest:      openInterest,
+			OpenInterestValue: openInterest * 42000,
+			TopAccountRatio:   1 + float64(i%7-3)*0.1,
+			TopPositionRatio:  1 + float64(i%5-2)*0.05,
+			TakerBuyVolume:    1000 + float64(i%9)*50,
+			TakerSellVolume:   1200 - float64(i%9)*25,
+		})
+	}
+	return points
+}
+
+// LoadFixture 解析币安 continuousKlines 接口格式的 JSON 数组（可直接保存真实接口的响应作为夹具）
+func LoadFixture(pair, interval string, data []byte) ([]model.Kline, error) {
+	var rows [][]json.RawMessage
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("解析K线夹具失败: %w", err)
+	}
+
+	klines := make([]model.Kline, 0, len(rows))
+	for i, row := range rows {
+		if len(row) < 11 {
+			return nil, fmt.Errorf("第%d行K线字段不足: %d", i, len(row))
+		}
+		var openTime, closeTime, tradeNum int64
+		var fields [8]string
+		if err := json.Unmarshal(row[0], &openTime); err != nil {
+			return nil, fmt.Errorf("第%d行开盘时间错误: %w", i, err)
+		}
+		if err := json.Unmarshal(row[6], &closeTime); err != nil {
+			return nil, fmt.Errorf("第%d行收盘时间错误: %w", i, err)
+		}
+		if err := json.Unmarshal(row[8], &tradeNum); err != nil {
+			return nil, fmt.Errorf("第%d行成交笔数错误: %w", i, err)
+		}
+		for j, index := range []int{1, 2, 3, 4, 5, 7, 9, 10} {
+			if err := json.Unmarshal(row[index], &fields[j]); err != nil {
+				return nil, fmt.Errorf("第%d行第%d列错误: %w", i, index, err)
+			}
+		}
+
+		klines = append(klines, model.Kline{
+			Symbol:              pair,
+			Interval:            interval,
+			OpenTime:            time.UnixMilli(openTime).UTC(),
+			CloseTime:           time.UnixMilli(closeTime).UTC(),
+			Open:                utils.StringToFloat64(fields[0]),
+			High:                utils.StringToFloat64(fields[1]),
+			Low:                 utils.StringToFloat64(fields[2]),
+			Close:               utils.StringToFloat64(fields[3]),
+			Volume:              utils.StringToFloat64(fields[4]),
+			QuoteVolume:         utils.StringToFloat64(fields[5]),
+			TradeNum:            tradeNum,
+			TakerBuyVolume:      utils.StringToFloat64(fields[6]),
+			TakerBuyQuoteVolume: utils.StringToFloat64(fields[7]),
+		})
+	}
+	return klines, nil
+}
+
+func seriesKey(pair, interval string) string {
+	return pair + "|" + interval
+}
+
+// closeTimeMillis K线收盘时间，未设置时按周期推算
+func closeTimeMillis(k model.Kline) int64 {
+	if !k.CloseTime.IsZero() {
+		return k.CloseTime.UnixMilli()
+	}
+	return k.OpenTime.Add(utils.IntervalDuration(k.Interval)).UnixMilli() - 1
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,97 @@
+package fakebinance
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+// newTestClient 创建指向模拟服务的合约客户端
+func newTestClient(t *testing.T) (*Server, *futures.Client) {
+	t.Helper()
+	server := NewServer()
+	t.Cleanup(server.Close)
+	client := futures.NewClient("", "")
+	client.BaseURL = server.URL()
+	return server, client
+}
+
+func TestFixtureKlines(t *testing.T) {
+	data, err := os.ReadFile("testdata/btcusdt_1h.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	klines, err := LoadFixture("BTCUSDT", "1h", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(klines) != 3 || klines[0].Open != 42314 || klines[2].TradeNum != 35402 {
+		t.Fatalf("夹具解析错误: %+v", klines)
+	}
+
+	server, client := newTestClient(t)
+	server.AddKlines(klines...)
+
+	result, err := client.NewContinuousKlinesService().ContractType("PERPETUAL").Pair("BTCUSDT").Interval("1h").
+		StartTime(klines[1].OpenTime.UnixMilli()).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 || result[0].Open != "42465.4" || result[0].CloseTime != 1704074399999 {
+		t.Fatalf("按 startTime 过滤错误: %+v", result[0])
+	}
+
+	info, err := client.NewExchangeInfoService().Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Symbols) != 1 || info.Symbols[0].Symbol != "BTCUSDT" || len(info.Symbols[0].Filters) != 4 {
+		t.Fatalf("exchangeInfo 错误: %+v", info.Symbols)
+	}
+}
+
+func TestRateLimitAndBan(t *testing.T) {
+	server, client := newTestClient(t)
+	server.AddKlines(GenerateKlines("BTCUSDT", "1h", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 10, 100)...)
+	server.SetRateLimit(RateLimit{Requests: 2, Window: time.Hour, BanAfter: 2, BanDuration: time.Hour})
+
+	fetch := func() error {
+		_, err := client.NewContinuousKlinesService().ContractType("PERPETUAL").Pair("BTCUSDT").Interval("1h").Do(context.Background())
+		return err
+	}
+	var apiErr *common.APIError
+	for i, banned := range []bool{false, false, false, false, true} {
+		err := fetch()
+		if i < 2 {
+			if err != nil {
+				t.Fatalf("第%d次请求不应限频: %v", i+1, err)
+			}
+			continue
+		}
+		if !errors.As(err, &apiErr) || apiErr.Code != CodeTooManyRequests {
+			t.Fatalf("第%d次请求应返回 -1003: %v", i+1, err)
+		}
+		// 连续 2 次 429 后封禁，之后返回 418 且错误信息包含 banned
+		if strings.Contains(apiErr.Message, "banned") != banned {
+			t.Fatalf("第%d次请求封禁状态错误: %v", i+1, err)
+		}
+	}
+	if requests := server.Requests("/fapi/v1/continuousKlines"); requests != 5 {
+		t.Errorf("请求计数错误: %d", requests)
+	}
+
+	server.SetRateLimit(RateLimit{})
+	server.InjectError("/fapi/v1/continuousKlines", 500, -1001, "Internal error; unable to process your request.", 1)
+	if err := fetch(); !errors.As(err, &apiErr) || apiErr.Code != -1001 {
+		t.Fatalf("应返回注入的错误: %v", err)
+	}
+	if err := fetch(); err != nil {
+		t.Fatalf("注入的错误只生效一次: %v", err)
+	}
+}
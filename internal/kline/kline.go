@@ -10,6 +10,7 @@ import (
 	"trade/model"
 	"trade/utils"
 
+	"github.com/adshao/go-binance/v2/futures"
 	"gorm.io/gorm/clause"
 )
 
@@ -93,7 +94,34 @@ func GetAllKlines(symbol string, interval string) {
 }
 
 func GetKline(symbol string, interval string, startTime, endTime time.Time) {
-	klines, err := db.BinanceClient.NewContinuousKlinesService().
+	klineModels, err := fetchKlines(db.BinanceClient, symbol, interval, startTime, endTime)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// 批量插入，遇到重复则更新成交量字段（ON CONFLICT DO UPDATE）
+	// 性能提升：1000条数据从 ~2000ms 降到 ~50ms
+	if len(klineModels) > 0 {
+		result := db.Pog.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "symbol"}, {Name: "interval"}, {Name: "open_time"}},
+			// 遇到冲突时补写成交量字段，兼容升级前已入库的数据
+			DoUpdates: clause.AssignmentColumns([]string{
+				"volume", "quote_volume", "trade_num", "taker_buy_volume", "taker_buy_quote_volume",
+			}),
+		}).Create(&klineModels)
+
+		if result.Error != nil {
+			fmt.Printf("批量插入失败: %v\n", result.Error)
+		} else {
+			fmt.Printf("批量插入 %d 条数据\n", result.RowsAffected)
+		}
+	}
+}
+
+// fetchKlines 获取 [startTime, endTime] 内的连续合约K线并转换为入库模型，单次最多 1000 根
+func fetchKlines(api *futures.Client, symbol string, interval string, startTime, endTime time.Time) ([]model.KlineWs, error) {
+	klines, err := api.NewContinuousKlinesService().
 		ContractType("PERPETUAL").
 		Pair(symbol).
 		Interval(interval).
@@ -102,8 +130,7 @@ func GetKline(symbol string, interval string, startTime, endTime time.Time) {
 		Limit(1000).
 		Do(context.Background())
 	if err != nil {
-		fmt.Println(err)
-		return
+		return nil, err
 	}
 
 	klineModels := make([]model.KlineWs, 0, len(klines))
@@ -113,35 +140,26 @@ func GetKline(symbol string, interval string, startTime, endTime time.Time) {
 		year := openTime.Year()
 
 		klineModel := model.KlineWs{
-			Symbol:    symbol,
-			Interval:  interval, // 添加时间周期字段
-			Open:      utils.StringToFloat64(k.Open),
-			High:      utils.StringToFloat64(k.High),
-			Low:       utils.StringToFloat64(k.Low),
-			Close:     utils.StringToFloat64(k.Close),
-			OpenTime:  openTime,
-			CloseTime: closeTime,
-			Date:      strconv.Itoa(year),
-			Day:       fmt.Sprintf("%02d-%02d", int(openTime.Month()), openTime.Day()), // 修复：使用两位数格式
-			Hour:      strconv.Itoa(openTime.Hour()),
-			Week:      strconv.Itoa(int(openTime.Weekday())%7 + 1),
-			Min:       strconv.Itoa(openTime.Minute()),
+			Symbol:              symbol,
+			Interval:            interval, // 添加时间周期字段
+			Open:                utils.StringToFloat64(k.Open),
+			High:                utils.StringToFloat64(k.High),
+			Low:                 utils.StringToFloat64(k.Low),
+			Close:               utils.StringToFloat64(k.Close),
+			OpenTime:            openTime,
+			CloseTime:           closeTime,
+			Volume:              utils.StringToFloat64(k.Volume),
+			QuoteVolume:         utils.StringToFloat64(k.QuoteAssetVolume),
+			TradeNum:            k.TradeNum,
+			TakerBuyVolume:      utils.StringToFloat64(k.TakerBuyBaseAssetVolume),
+			TakerBuyQuoteVolume: utils.StringToFloat64(k.TakerBuyQuoteAssetVolume),
+			Date:                strconv.Itoa(year),
+			Day:                 fmt.Sprintf("%02d-%02d", int(openTime.Month()), openTime.Day()), // 修复：使用两位数格式
+			Hour:                strconv.Itoa(openTime.Hour()),
+			Week:                strconv.Itoa(int(openTime.Weekday())%7 + 1),
+			Min:                 strconv.Itoa(openTime.Minute()),
 		}
 		klineModels = append(klineModels, klineModel)
 	}
-
-	// 批量插入，遇到重复则跳过（ON CONFLICT DO NOTHING）
-	// 性能提升：1000条数据从 ~2000ms 降到 ~50ms
-	if len(klineModels) > 0 {
-		result := db.Pog.Clauses(clause.OnConflict{
-			Columns:   []clause.Column{{Name: "symbol"}, {Name: "interval"}, {Name: "open_time"}},
-			DoNothing: true, // 遇到冲突直接跳过，不报错
-		}).Create(&klineModels)
-
-		if result.Error != nil {
-			fmt.Printf("批量插入失败: %v\n", result.Error)
-		} else {
-			fmt.Printf("批量插入 %d 条数据\n", result.RowsAffected)
-		}
-	}
+	return klineModels, nil
 }
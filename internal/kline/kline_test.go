@@ -0,0 +1,66 @@
+package kline
+
+import (
+	"testing"
+	"time"
+
+	"trade/db"
+	"trade/internal/fakebinance"
+	"trade/model"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+const klinesPath = "/fapi/v1/continuousKlines"
+
+// newFakeBinance 启动模拟币安服务并让 db.BinanceClient 指向它
+func newFakeBinance(t *testing.T) *fakebinance.Server {
+	t.Helper()
+	server := fakebinance.NewServer()
+	wsURL, combinedURL := futures.BaseWsMainUrl, futures.BaseCombinedMainURL
+	db.InitBinance(&model.BinanceConfig{DataURL: server.URL(), StreamURL: server.StreamURL()})
+	t.Cleanup(func() {
+		server.Close()
+		db.BinanceClient = nil
+		futures.BaseWsMainUrl, futures.BaseCombinedMainURL = wsURL, combinedURL
+	})
+	return server
+}
+
+func TestFetchKlines(t *testing.T) {
+	server := newFakeBinance(t)
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	klines := fakebinance.GenerateKlines("BTCUSDT", "1h", start, 48, 42000)
+	server.AddKlines(klines...)
+
+	got, err := fetchKlines(db.BinanceClient, "BTCUSDT", "1h", start.Add(10*time.Hour), start.Add(19*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 10 {
+		t.Fatalf("应返回区间内 10 根K线，实际 %d", len(got))
+	}
+
+	first, want := got[0], klines[10]
+	if !first.OpenTime.Equal(want.OpenTime) || first.Open != want.Open || first.Close != want.Close ||
+		first.Volume != want.Volume || first.TradeNum != want.TradeNum {
+		t.Errorf("K线字段转换错误: %+v", first)
+	}
+	if first.Symbol != "BTCUSDT" || first.Interval != "1h" || first.Date != "2024" || first.Day != "03-01" ||
+		first.Hour != "10" || first.Week != "6" || first.Min != "0" {
+		t.Errorf("分组字段错误: %+v", first)
+	}
+}
+
+func TestGetKlineRequestError(t *testing.T) {
+	server := newFakeBinance(t)
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	server.AddKlines(fakebinance.GenerateKlines("BTCUSDT", "1h", start, 48, 42000)...)
+	server.InjectError(klinesPath, 429, fakebinance.CodeTooManyRequests, "Too many requests; current limit is exceeded.", 1)
+
+	// 请求失败时直接返回，不写数据库（测试中 db.Pog 为空，写入会 panic）
+	GetKline("BTCUSDT", "1h", start, start.Add(47*time.Hour))
+	if requests := server.Requests(klinesPath); requests != 1 {
+		t.Errorf("应请求 1 次，实际 %d", requests)
+	}
+}
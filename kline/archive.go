@@ -0,0 +1,377 @@
+package kline
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"trade/model"
+	"trade/store"
+	"trade/utils"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+// archiveNamePattern 币安公共数据(data.binance.vision)的K线文件名：
+// 月度 BTCUSDT-1h-2024-01.zip，日度 BTCUSDT-1h-2024-01-15.zip，解压后的 CSV 同名
+var archiveNamePattern = regexp.MustCompile(`^([A-Z0-9]+)-(\d+[mhdwM])-(\d{4}-\d{2}(?:-\d{2})?)\.(zip|csv)$`)
+
+// ArchiveFile 一个公共数据K线文件
+type ArchiveFile struct {
+	Path     string    // 文件路径
+	Symbol   string    // 交易对
+	Interval string    // 时间周期
+	Daily    bool      // 是否为日度文件（否则为月度）
+	Start    time.Time // 文件覆盖时间段的起点（含）
+	End      time.Time // 文件覆盖时间段的终点（不含）
+}
+
+// ArchiveImport 单个文件的导入结果
+type ArchiveImport struct {
+	File      ArchiveFile
+	Rows      int         // 文件中的K线数量
+	FirstOpen time.Time   // 最早开盘时间
+	LastOpen  time.Time   // 最晚开盘时间
+	Gaps      []KlineGap  // 文件覆盖时间段内缺失的K线
+	Overlap   int         // 数据库中已存在的K线数量
+	Conflicts []time.Time // 已存在但价格或成交量与文件不一致的K线开盘时间
+	Written   int64       // 写入的K线数量
+}
+
+// ParseArchiveName 按公共数据的命名规则解析文件名，不符合时返回 false
+func ParseArchiveName(path string) (ArchiveFile, bool) {
+	match := archiveNamePattern.FindStringSubmatch(filepath.Base(path))
+	if match == nil || !utils.IsSupportedInterval(match[2]) {
+		return ArchiveFile{}, false
+	}
+
+	file := ArchiveFile{Path: path, Symbol: match[1], Interval: match[2], Daily: len(match[3]) == len("2006-01-02")}
+	if file.Daily {
+		start, err := time.Parse("2006-01-02", match[3])
+		if err != nil {
+			return ArchiveFile{}, false
+		}
+		file.Start, file.End = start, start.AddDate(0, 0, 1)
+	} else {
+		start, err := time.Parse("2006-01", match[3])
+		if err != nil {
+			return ArchiveFile{}, false
+		}
+		file.Start, file.End = start, start.AddDate(0, 1, 0)
+	}
+	return file, true
+}
+
+// ScanArchives 递归查找目录下的公共数据K线文件，按交易对、周期、起始时间排序
+// 同名的 ZIP 和 CSV 只保留 ZIP；symbol/interval 为空表示不过滤
+func ScanArchives(dir, symbol, interval string) ([]ArchiveFile, error) {
+	files := make(map[string]ArchiveFile)
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		file, ok := ParseArchiveName(path)
+		if !ok || (symbol != "" && file.Symbol != symbol) || (interval != "" && file.Interval != interval) {
+			return nil
+		}
+		key := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if existing, ok := files[key]; ok && strings.HasSuffix(existing.Path, ".zip") {
+			return nil
+		}
+		files[key] = file
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("扫描目录 %s 失败: %v", dir, err)
+	}
+
+	result := make([]ArchiveFile, 0, len(files))
+	for _, file := range files {
+		result = append(result, file)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		if a.Interval != b.Interval {
+			return a.Interval < b.Interval
+		}
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		// 同一时间段月度文件在前，日度文件补充月度文件之后的数据
+		return !a.Daily && b.Daily
+	})
+	return result, nil
+}
+
+// ReadArchive 读取并校验一个文件中的K线，返回按开盘时间升序的K线
+// 任何一行格式错误、价格不合理、开盘时间未对齐或超出文件时间段都会使整个文件失败
+func ReadArchive(file ArchiveFile) ([]model.Kline, error) {
+	if strings.HasSuffix(file.Path, ".zip") {
+		archive, err := zip.OpenReader(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("打开 %s 失败: %v", file.Path, err)
+		}
+		defer archive.Close()
+
+		for _, entry := range archive.File {
+			if !strings.HasSuffix(entry.Name, ".csv") {
+				continue
+			}
+			reader, err := entry.Open()
+			if err != nil {
+				return nil, fmt.Errorf("解压 %s 失败: %v", file.Path, err)
+			}
+			defer reader.Close()
+			return parseArchiveCSV(file, reader)
+		}
+		return nil, fmt.Errorf("%s 中没有 CSV 文件", file.Path)
+	}
+
+	reader, err := os.Open(file.Path)
+	if err != nil {
+		return nil, fmt.Errorf("打开 %s 失败: %v", file.Path, err)
+	}
+	defer reader.Close()
+	return parseArchiveCSV(file, reader)
+}
+
+// parseArchiveCSV 解析公共数据CSV：open_time,open,high,low,close,volume,close_time,
+// quote_volume,count,taker_buy_volume,taker_buy_quote_volume,ignore，2022 年以后的文件带表头
+func parseArchiveCSV(file ArchiveFile, r io.Reader) ([]model.Kline, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	name := filepath.Base(file.Path)
+
+	klines := make([]model.Kline, 0)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s 第%d行: %v", name, line, err)
+		}
+		if line == 1 && record[0] == "open_time" {
+			continue
+		}
+
+		k, err := parseArchiveRecord(file, record)
+		if err != nil {
+			return nil, fmt.Errorf("%s 第%d行: %v", name, line, err)
+		}
+		if n := len(klines); n > 0 && !k.OpenTime.After(klines[n-1].OpenTime) {
+			return nil, fmt.Errorf("%s 第%d行: 开盘时间 %s 未按升序排列或重复", name, line, k.OpenTime.Format(time.RFC3339))
+		}
+		klines = append(klines, k)
+	}
+	if len(klines) == 0 {
+		return nil, fmt.Errorf("%s 没有K线数据", name)
+	}
+	return klines, nil
+}
+
+// parseArchiveRecord 解析并校验一行K线
+func parseArchiveRecord(file ArchiveFile, record []string) (model.Kline, error) {
+	if len(record) < 11 {
+		return model.Kline{}, fmt.Errorf("字段数量 %d 少于 11", len(record))
+	}
+	openTime, err := archiveMillis(record[0])
+	if err != nil {
+		return model.Kline{}, fmt.Errorf("开盘时间错误: %v", err)
+	}
+	closeTime, err := archiveMillis(record[6])
+	if err != nil {
+		return model.Kline{}, fmt.Errorf("收盘时间错误: %v", err)
+	}
+	tradeNum, err := strconv.ParseInt(record[8], 10, 64)
+	if err != nil {
+		return model.Kline{}, fmt.Errorf("成交笔数错误: %v", err)
+	}
+	for _, index := range []int{1, 2, 3, 4, 5, 7, 9, 10} {
+		if _, err := strconv.ParseFloat(record[index], 64); err != nil {
+			return model.Kline{}, fmt.Errorf("第%d列不是数字: %q", index+1, record[index])
+		}
+	}
+
+	// 与 REST/WebSocket 入库使用同一套转换，保证导入的数据与接口拉取的一致
+	k := buildKlineModel(file.Symbol, file.Interval, &futures.ContinuousKline{
+		OpenTime:                 openTime,
+		Open:                     record[1],
+		High:                     record[2],
+		Low:                      record[3],
+		Close:                    record[4],
+		Volume:                   record[5],
+		CloseTime:                closeTime,
+		QuoteAssetVolume:         record[7],
+		TradeNum:                 tradeNum,
+		TakerBuyBaseAssetVolume:  record[9],
+		TakerBuyQuoteAssetVolume: record[10],
+	})
+
+	if k.OpenTime.Before(file.Start) || !k.OpenTime.Before(file.End) {
+		return model.Kline{}, fmt.Errorf("开盘时间 %s 超出文件时间段", k.OpenTime.Format(time.RFC3339))
+	}
+	if !alignUp(k.OpenTime, file.Interval).Equal(k.OpenTime) {
+		return model.Kline{}, fmt.Errorf("开盘时间 %s 未按周期 %s 对齐", k.OpenTime.Format(time.RFC3339), file.Interval)
+	}
+	if closeTime <= openTime {
+		return model.Kline{}, fmt.Errorf("收盘时间早于开盘时间")
+	}
+	if k.Low <= 0 || k.High < k.Low || k.High < k.Open || k.High < k.Close || k.Low > k.Open || k.Low > k.Close {
+		return model.Kline{}, fmt.Errorf("价格不合理: open=%v high=%v low=%v close=%v", k.Open, k.High, k.Low, k.Close)
+	}
+	if k.Volume < 0 || k.TakerBuyVolume < 0 || k.TakerBuyVolume > k.Volume*(1+1e-9) {
+		return model.Kline{}, fmt.Errorf("成交量不合理: volume=%v taker_buy_volume=%v", k.Volume, k.TakerBuyVolume)
+	}
+	return k, nil
+}
+
+// archiveMillis 解析毫秒时间戳，兼容 2025 年起部分文件使用的微秒时间戳
+func archiveMillis(value string) (int64, error) {
+	ts, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if ts > 1e15 {
+		ts /= 1000
+	}
+	return ts, nil
+}
+
+// ImportArchive 导入一个文件：与数据库中同一时间段的K线比对，报告重叠和不一致的记录
+// overwrite 为 false 时只写入数据库中不存在的K线；dryRun 为 true 时只比对不写入
+// 没有同步断点时增量同步会从最新K线继续，因此首次回填可以先导入归档再运行 once 模式
+func ImportArchive(file ArchiveFile, overwrite, dryRun bool) (*ArchiveImport, error) {
+	klines, err := ReadArchive(file)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ArchiveImport{
+		File:      file,
+		Rows:      len(klines),
+		FirstOpen: klines[0].OpenTime,
+		LastOpen:  klines[len(klines)-1].OpenTime,
+	}
+	result.Gaps = archiveGaps(file, klines)
+
+	existing, err := store.Default().FindKlines(store.KlineQuery{
+		Symbol:   file.Symbol,
+		Interval: file.Interval,
+		Start:    result.FirstOpen,
+		End:      result.LastOpen,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查询 %s %s 已有K线失败: %v", file.Symbol, file.Interval, err)
+	}
+	stored := make(map[int64]model.Kline, len(existing))
+	for _, k := range existing {
+		stored[k.OpenTime.UnixMilli()] = k
+	}
+
+	toWrite := make([]model.Kline, 0, len(klines))
+	for _, k := range klines {
+		old, ok := stored[k.OpenTime.UnixMilli()]
+		if !ok {
+			toWrite = append(toWrite, k)
+			continue
+		}
+		result.Overlap++
+		if !sameKline(old, k) {
+			result.Conflicts = append(result.Conflicts, k.OpenTime)
+		}
+		if overwrite {
+			toWrite = append(toWrite, k)
+		}
+	}
+	if dryRun || len(toWrite) == 0 {
+		return result, nil
+	}
+
+	written, err := store.Default().UpsertKlines(toWrite)
+	if err != nil {
+		return result, fmt.Errorf("写入 %s 失败: %v", filepath.Base(file.Path), err)
+	}
+	result.Written = written
+
+	// 导入的数据与同步断点相连时推进断点，之后的增量同步从文件末尾继续
+	if checkpoint, ok := GetCheckpoint(file.Symbol, file.Interval); ok && checkpoint.LastOpenTime.Before(result.LastOpen) &&
+		!result.FirstOpen.After(utils.NextOpenTime(checkpoint.LastOpenTime, file.Interval)) {
+		advanceCheckpoint(file.Symbol, file.Interval, result.LastOpen, checkpoint.Status)
+	}
+	return result, nil
+}
+
+// archiveGaps 文件时间段内缺失的K线，首尾缺失也计入
+func archiveGaps(file ArchiveFile, klines []model.Kline) []KlineGap {
+	first, end := alignUp(file.Start, file.Interval), alignUp(file.End, file.Interval)
+	if !first.Before(end) {
+		return nil
+	}
+
+	// 用文件时间段前后各一根虚拟K线作为边界，让首尾缺失也能被识别
+	openTimes := make([]time.Time, 0, len(klines)+2)
+	openTimes = append(openTimes, previousOpenTime(first, file.Interval))
+	for _, k := range klines {
+		openTimes = append(openTimes, k.OpenTime)
+	}
+	openTimes = append(openTimes, end)
+
+	gaps, _ := findGaps(openTimes, file.Interval)
+	return gaps
+}
+
+// weekAnchor 周线的开盘时间以周一 00:00 (UTC) 对齐
+var weekAnchor = time.Date(1970, 1, 5, 0, 0, 0, 0, time.UTC)
+
+// alignUp 返回不早于 t 的第一个K线开盘时间：月线按自然月，周线按周一，其余按 Unix 纪元对齐
+func alignUp(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	if interval == "1M" {
+		month := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if month.Before(t) {
+			month = month.AddDate(0, 1, 0)
+		}
+		return month
+	}
+
+	anchor := time.Unix(0, 0).UTC()
+	if interval == "1w" {
+		anchor = weekAnchor
+	}
+	step := utils.IntervalDuration(interval)
+	offset := t.Sub(anchor) % step
+	if offset == 0 {
+		return t
+	}
+	if offset < 0 {
+		return t.Add(-offset)
+	}
+	return t.Add(step - offset)
+}
+
+// previousOpenTime 返回上一根K线的开盘时间（月线按自然月计算）
+func previousOpenTime(openTime time.Time, interval string) time.Time {
+	if interval == "1M" {
+		return openTime.AddDate(0, -1, 0)
+	}
+	return openTime.Add(-utils.IntervalDuration(interval))
+}
+
+// sameKline 比较价格和成交量字段是否一致
+func sameKline(a, b model.Kline) bool {
+	return a.Open == b.Open && a.High == b.High && a.Low == b.Low && a.Close == b.Close &&
+		a.Volume == b.Volume && a.QuoteVolume == b.QuoteVolume && a.TradeNum == b.TradeNum &&
+		a.TakerBuyVolume == b.TakerBuyVolume && a.TakerBuyQuoteVolume == b.TakerBuyQuoteVolume
+}
@@ -0,0 +1,184 @@
+package kline
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"trade/internal/fakebinance"
+	"trade/model"
+	"trade/store"
+)
+
+// archiveCSV 按公共数据格式生成 CSV 内容
+func archiveCSV(klines []model.Kline, header bool) string {
+	var b strings.Builder
+	if header {
+		b.WriteString("open_time,open,high,low,close,volume,close_time,quote_volume,count,taker_buy_volume,taker_buy_quote_volume,ignore\n")
+	}
+	for _, k := range klines {
+		fmt.Fprintf(&b, "%d,%v,%v,%v,%v,%v,%d,%v,%d,%v,%v,0\n",
+			k.OpenTime.UnixMilli(), k.Open, k.High, k.Low, k.Close, k.Volume, k.CloseTime.UnixMilli(),
+			k.QuoteVolume, k.TradeNum, k.TakerBuyVolume, k.TakerBuyQuoteVolume)
+	}
+	return b.String()
+}
+
+// writeArchiveZip 把 CSV 压缩为与公共数据同名的 ZIP
+func writeArchiveZip(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	entry, err := w.Create(strings.TrimSuffix(filepath.Base(path), ".zip") + ".csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry.Write([]byte(content))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseArchiveName(t *testing.T) {
+	file, ok := ParseArchiveName("/data/BTCUSDT-1h-2024-02.zip")
+	if !ok || file.Symbol != "BTCUSDT" || file.Interval != "1h" || file.Daily ||
+		!file.End.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("月度文件名解析错误: %+v", file)
+	}
+	file, ok = ParseArchiveName("ETHUSDT-1d-2024-02-29.csv")
+	if !ok || !file.Daily || !file.Start.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("日度文件名解析错误: %+v", file)
+	}
+	for _, name := range []string{"BTCUSDT-7h-2024-02.zip", "BTCUSDT-1h-2024-13.zip", "BTCUSDT-1h-2024-02.zip.CHECKSUM"} {
+		if _, ok := ParseArchiveName(name); ok {
+			t.Errorf("%s 不应被识别", name)
+		}
+	}
+}
+
+func TestImportArchive(t *testing.T) {
+	memory := store.NewMemoryStore()
+	store.SetDefault(memory)
+	t.Cleanup(func() { store.SetDefault(nil) })
+
+	dir := t.TempDir()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := fakebinance.GenerateKlines("BTCUSDT", "1h", start, 24*31, 42000)
+	for i := range klines {
+		klines[i].CloseTime = klines[i].OpenTime.Add(time.Hour - time.Millisecond)
+	}
+
+	// 月度 ZIP 缺少第 100 根；同名 CSV 被忽略；次月第一天的日度 CSV 带表头
+	monthly := append(append([]model.Kline{}, klines[:100]...), klines[101:]...)
+	writeArchiveZip(t, filepath.Join(dir, "BTCUSDT-1h-2024-01.zip"), archiveCSV(monthly, false))
+	os.WriteFile(filepath.Join(dir, "BTCUSDT-1h-2024-01.csv"), []byte("broken"), 0o644)
+	next := fakebinance.GenerateKlines("BTCUSDT", "1h", start.AddDate(0, 1, 0), 24, 43000)
+	for i := range next {
+		next[i].CloseTime = next[i].OpenTime.Add(time.Hour - time.Millisecond)
+	}
+	os.MkdirAll(filepath.Join(dir, "daily"), 0o755)
+	os.WriteFile(filepath.Join(dir, "daily", "BTCUSDT-1h-2024-02-01.csv"), []byte(archiveCSV(next, true)), 0o644)
+
+	files, err := ScanArchives(dir, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || !strings.HasSuffix(files[0].Path, ".zip") || !files[1].Daily {
+		t.Fatalf("扫描结果错误: %+v", files)
+	}
+
+	// 数据库中已有两根，其中一根收盘价不同
+	existing := []model.Kline{klines[0], klines[1]}
+	existing[1].Close++
+	memory.UpsertKlines(existing)
+
+	result, err := ImportArchive(files[0], false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rows != 743 || result.Overlap != 2 || len(result.Conflicts) != 1 || result.Written != 741 {
+		t.Fatalf("导入结果错误: rows=%d overlap=%d conflicts=%v written=%d",
+			result.Rows, result.Overlap, result.Conflicts, result.Written)
+	}
+	if len(result.Gaps) != 1 || !result.Gaps[0].Start.Equal(klines[100].OpenTime) || result.Gaps[0].Missing != 1 {
+		t.Errorf("文件缺口识别错误: %+v", result.Gaps)
+	}
+	stored, _ := memory.FindKlines(store.KlineQuery{Symbol: "BTCUSDT", Interval: "1h"})
+	if len(stored) != 743 || stored[1].Close != existing[1].Close {
+		t.Fatalf("默认不应覆盖已有K线: %d %+v", len(stored), stored[1])
+	}
+	if k := stored[50]; k.Date != "2024" || k.Day != "01-03" || k.Hour != "2" || k.Week != "4" || k.Volume != klines[50].Volume {
+		t.Errorf("派生时间字段或成交量错误: %+v", k)
+	}
+
+	// 覆盖模式用文件数据替换不一致的K线
+	if result, err = ImportArchive(files[0], true, false); err != nil || result.Written != 743 {
+		t.Fatalf("覆盖导入错误: %v %+v", err, result)
+	}
+	stored, _ = memory.FindKlines(store.KlineQuery{Symbol: "BTCUSDT", Interval: "1h"})
+	if stored[1].Close != klines[1].Close {
+		t.Errorf("覆盖模式应写入文件数据: %+v", stored[1])
+	}
+
+	// dry-run 只比对不写入
+	if result, err = ImportArchive(files[1], false, true); err != nil || result.Rows != 24 || result.Written != 0 || len(result.Gaps) != 0 {
+		t.Fatalf("dry-run 结果错误: %v %+v", err, result)
+	}
+	if stored, _ = memory.FindKlines(store.KlineQuery{Symbol: "BTCUSDT", Interval: "1h"}); len(stored) != 743 {
+		t.Errorf("dry-run 不应写入，实际 %d", len(stored))
+	}
+}
+
+func TestReadArchiveValidation(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	good := fakebinance.GenerateKlines("BTCUSDT", "1h", start, 3, 42000)
+	for i := range good {
+		good[i].CloseTime = good[i].OpenTime.Add(time.Hour - time.Millisecond)
+	}
+
+	cases := map[string]func(klines []model.Kline) []model.Kline{
+		"价格不合理": func(klines []model.Kline) []model.Kline {
+			klines[1].High = klines[1].Low - 1
+			return klines
+		},
+		"超出文件时间段": func(klines []model.Kline) []model.Kline {
+			klines[2].OpenTime = start.AddDate(0, 0, 1)
+			return klines
+		},
+		"未按周期": func(klines []model.Kline) []model.Kline {
+			klines[2].OpenTime = klines[2].OpenTime.Add(time.Minute)
+			return klines
+		},
+		"升序": func(klines []model.Kline) []model.Kline {
+			klines[2].OpenTime = klines[1].OpenTime
+			return klines
+		},
+	}
+	for want, mutate := range cases {
+		path := filepath.Join(dir, "BTCUSDT-1h-2024-01-01.csv")
+		os.WriteFile(path, []byte(archiveCSV(mutate(append([]model.Kline{}, good...)), false)), 0o644)
+		file, _ := ParseArchiveName(path)
+		if _, err := ReadArchive(file); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("应报告 %q，实际 %v", want, err)
+		}
+	}
+
+	// 微秒时间戳与毫秒时间戳解析结果一致
+	path := filepath.Join(dir, "BTCUSDT-1h-2024-01-01.csv")
+	content := strings.Replace(archiveCSV(good, false), fmt.Sprint(start.UnixMilli()), fmt.Sprint(start.UnixMicro()), 1)
+	os.WriteFile(path, []byte(content), 0o644)
+	file, _ := ParseArchiveName(path)
+	klines, err := ReadArchive(file)
+	if err != nil || !klines[0].OpenTime.Equal(start) {
+		t.Errorf("微秒时间戳解析错误: %v %+v", err, klines)
+	}
+}
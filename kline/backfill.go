@@ -0,0 +1,31 @@
+package kline
+
+import (
+	"fmt"
+
+	"trade/db"
+	"trade/store"
+)
+
+// BackfillVolume 重新拉取成交量字段为空（trade_num = 0）的K线，覆盖写入成交量相关字段
+// 返回回填后仍缺少成交量的K线数量
+func BackfillVolume(symbol, interval string) (int64, error) {
+	bounds, err := store.Default().MissingVolumeRange(symbol, interval)
+	if err != nil {
+		return 0, fmt.Errorf("查询 %s %s 缺少成交量的K线失败: %v", symbol, interval, err)
+	}
+	if bounds.Count == 0 {
+		fmt.Printf("%s %s 无需回填\n", symbol, interval)
+		return 0, nil
+	}
+
+	fmt.Printf("回填 %s %s 成交量: %d 根，%s ~ %s\n", symbol, interval, bounds.Count,
+		bounds.Earliest.Format("2006-01-02 15:04:05"), bounds.Latest.Format("2006-01-02 15:04:05"))
+	updateKlineData(db.BinanceClient, symbol, interval, bounds.Earliest, bounds.Latest)
+
+	remaining, err := store.Default().MissingVolumeRange(symbol, interval)
+	if err != nil {
+		return 0, fmt.Errorf("统计 %s %s 回填结果失败: %v", symbol, interval, err)
+	}
+	return remaining.Count, nil
+}
@@ -0,0 +1,47 @@
+package kline
+
+import (
+	"fmt"
+	"time"
+
+	"trade/model"
+	"trade/store"
+)
+
+// GetCheckpoint 查询交易对+时间周期的同步断点
+func GetCheckpoint(symbol, interval string) (*model.KlineCheckpoint, bool) {
+	checkpoint, err := store.Default().GetCheckpoint(symbol, interval)
+	if err != nil || checkpoint == nil {
+		return nil, false
+	}
+	return checkpoint, true
+}
+
+// ResumeStartTime 返回增量同步的起始时间
+// 优先使用断点；没有断点时（旧数据）退回到该交易对+时间周期的最新K线
+// 起始K线会被重新拉取并覆盖写入，因此重复执行是安全的
+func ResumeStartTime(symbol, interval string) (time.Time, bool) {
+	if checkpoint, ok := GetCheckpoint(symbol, interval); ok && !checkpoint.LastOpenTime.IsZero() {
+		return checkpoint.LastOpenTime, true
+	}
+
+	latestKline, err := store.Default().LatestKline(symbol, interval)
+	if err != nil || latestKline == nil {
+		return time.Time{}, false
+	}
+	return latestKline.OpenTime, true
+}
+
+// advanceCheckpoint 将断点推进到 lastOpenTime（只前进不后退）
+func advanceCheckpoint(symbol, interval string, lastOpenTime time.Time, status string) {
+	if err := store.Default().AdvanceCheckpoint(symbol, interval, lastOpenTime, status); err != nil {
+		fmt.Printf("保存同步断点失败 %s %s: %v\n", symbol, interval, err)
+	}
+}
+
+// markCheckpoint 只更新断点状态，不修改 last_open_time
+func markCheckpoint(symbol, interval, status, message string) {
+	if err := store.Default().MarkCheckpoint(symbol, interval, status, message); err != nil {
+		fmt.Printf("保存同步断点失败 %s %s: %v\n", symbol, interval, err)
+	}
+}
@@ -0,0 +1,256 @@
+package kline
+
+import (
+	"testing"
+	"time"
+
+	"trade/db"
+	"trade/internal/fakebinance"
+	"trade/model"
+	"trade/store"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+const klinesPath = "/fapi/v1/continuousKlines"
+
+// newFakeBinance 启动模拟币安服务并让 db.BinanceClient 和 WebSocket 指向它，使用内存存储
+func newFakeBinance(t *testing.T) (*fakebinance.Server, *store.MemoryStore) {
+	t.Helper()
+	memory := store.NewMemoryStore()
+	store.SetDefault(memory)
+
+	server := fakebinance.NewServer()
+	wsURL, combinedURL := futures.BaseWsMainUrl, futures.BaseCombinedMainURL
+	db.InitBinance(&model.BinanceConfig{DataURL: server.URL(), StreamURL: server.StreamURL()})
+
+	t.Cleanup(func() {
+		server.Close()
+		store.SetDefault(nil)
+		db.BinanceClient = nil
+		futures.BaseWsMainUrl, futures.BaseCombinedMainURL = wsURL, combinedURL
+	})
+	return server, memory
+}
+
+// storedOpenTimes 返回内存存储中的K线开盘时间
+func storedOpenTimes(t *testing.T, memory *store.MemoryStore, symbol, interval string) []time.Time {
+	t.Helper()
+	openTimes, err := memory.OpenTimes(symbol, interval)
+	if err != nil {
+		t.Fatalf("查询K线失败: %v", err)
+	}
+	return openTimes
+}
+
+func TestUpdateKlineRangePaging(t *testing.T) {
+	server, memory := newFakeBinance(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	server.AddKlines(fakebinance.GenerateKlines("BTCUSDT", "1h", start, 2500, 42000)...)
+
+	UpdateKlineRange("BTCUSDT", "1h", start, start.Add(2499*time.Hour))
+
+	openTimes := storedOpenTimes(t, memory, "BTCUSDT", "1h")
+	if len(openTimes) != 2500 {
+		t.Fatalf("应分页拉取全部 2500 根K线，实际 %d", len(openTimes))
+	}
+	if requests := server.Requests(klinesPath); requests != 3 {
+		t.Errorf("每页 1000 根应请求 3 次，实际 %d", requests)
+	}
+	checkpoint, ok := GetCheckpoint("BTCUSDT", "1h")
+	if !ok || checkpoint.Status != model.CheckpointStatusOK || !checkpoint.LastOpenTime.Equal(start.Add(2499*time.Hour)) {
+		t.Errorf("断点应推进到最后一根K线: %+v", checkpoint)
+	}
+}
+
+func TestUpdateKlineRateLimit(t *testing.T) {
+	server, memory := newFakeBinance(t)
+	retries, backoff := rateLimitRetries, rateLimitBackoff
+	rateLimitBackoff = time.Millisecond
+	t.Cleanup(func() { rateLimitRetries, rateLimitBackoff = retries, backoff })
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(2499 * time.Hour)
+	server.AddKlines(fakebinance.GenerateKlines("BTCUSDT", "1h", start, 2500, 42000)...)
+
+	// 429 在重试次数内恢复
+	server.InjectError(klinesPath, 429, fakebinance.CodeTooManyRequests, "Too many requests; current limit is exceeded.", 2)
+	UpdateKlineRange("BTCUSDT", "1h", start, start.Add(99*time.Hour))
+	if n := len(storedOpenTimes(t, memory, "BTCUSDT", "1h")); n != 100 {
+		t.Fatalf("限频重试后应写入 100 根K线，实际 %d", n)
+	}
+	if requests := server.Requests(klinesPath); requests != 3 {
+		t.Errorf("应重试 2 次，实际请求 %d 次", requests)
+	}
+
+	// 第二页触发 429 后被封禁(418)，停止并标记断点失败
+	server.SetRateLimit(fakebinance.RateLimit{Requests: 1, Window: time.Hour, BanAfter: 1, BanDuration: time.Hour})
+	startTime, _ := ResumeStartTime("BTCUSDT", "1h")
+	UpdateKlineRange("BTCUSDT", "1h", startTime, end)
+	checkpoint, _ := GetCheckpoint("BTCUSDT", "1h")
+	if checkpoint.Status != model.CheckpointStatusFailed {
+		t.Fatalf("封禁后断点应为 failed: %+v", checkpoint)
+	}
+	if n := len(storedOpenTimes(t, memory, "BTCUSDT", "1h")); n != 1099 {
+		t.Fatalf("封禁前应已写入第一页，共 1099 根，实际 %d", n)
+	}
+
+	// 解除限频后从断点继续
+	server.SetRateLimit(fakebinance.RateLimit{})
+	startTime, _ = ResumeStartTime("BTCUSDT", "1h")
+	UpdateKlineRange("BTCUSDT", "1h", startTime, end)
+	if n := len(storedOpenTimes(t, memory, "BTCUSDT", "1h")); n != 2500 {
+		t.Fatalf("从断点继续后应有 2500 根K线，实际 %d", n)
+	}
+	if checkpoint, _ := GetCheckpoint("BTCUSDT", "1h"); checkpoint.Status != model.CheckpointStatusOK {
+		t.Errorf("续传完成后断点应为 ok: %+v", checkpoint)
+	}
+}
+
+func TestEarliestKlineTime(t *testing.T) {
+	server, _ := newFakeBinance(t)
+	listed := time.Date(2019, 9, 8, 0, 0, 0, 0, time.UTC)
+	server.AddKlines(fakebinance.GenerateKlines("BTCUSDT", "1d", listed, 30, 10000)...)
+
+	if earliest := EarliestKlineTime(db.BinanceClient, "BTCUSDT"); !earliest.Equal(listed) {
+		t.Errorf("最早K线时间应为上线日 %s，实际 %s", listed, earliest)
+	}
+
+	// 没有数据或请求失败时退回默认时间
+	fallback := time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC)
+	if earliest := EarliestKlineTime(db.BinanceClient, "ETHUSDT"); !earliest.Equal(fallback) {
+		t.Errorf("没有K线时应退回 %s，实际 %s", fallback, earliest)
+	}
+	server.InjectError(klinesPath, 500, -1000, "An unknown error occurred while processing the request.", 1)
+	if earliest := EarliestKlineTime(db.BinanceClient, "BTCUSDT"); !earliest.Equal(fallback) {
+		t.Errorf("请求失败时应退回 %s，实际 %s", fallback, earliest)
+	}
+}
+
+func TestRepairGapsFromServer(t *testing.T) {
+	server, memory := newFakeBinance(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := fakebinance.GenerateKlines("BTCUSDT", "1h", start, 48, 42000)
+	server.AddKlines(klines...)
+
+	// 本地缺少 10~14 和 30 这两段
+	local := append(append([]model.Kline{}, klines[:10]...), klines[15:30]...)
+	local = append(local, klines[31:]...)
+	memory.UpsertKlines(local)
+
+	report, err := ScanGaps("BTCUSDT", "1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Gaps) != 2 || report.MissingCount() != 6 {
+		t.Fatalf("缺口识别错误: %+v", report.Gaps)
+	}
+
+	RepairGaps(report)
+
+	report, _ = ScanGaps("BTCUSDT", "1h")
+	if len(report.Gaps) != 0 || report.Actual != 48 {
+		t.Fatalf("修复后不应有缺口: actual=%d gaps=%+v", report.Actual, report.Gaps)
+	}
+	if requests := server.Requests(klinesPath); requests != 2 {
+		t.Errorf("每个缺口应请求一次，实际 %d", requests)
+	}
+}
+
+func TestWsConnectReconnect(t *testing.T) {
+	server, memory := newFakeBinance(t)
+	minBackoff := wsMinBackoff
+	wsMinBackoff = 10 * time.Millisecond
+	t.Cleanup(func() { wsMinBackoff = minBackoff })
+
+	// 以当前整点往前 12 小时为起点，保证K线都已收盘
+	start := time.Now().UTC().Truncate(time.Hour).Add(-12 * time.Hour)
+	klines := fakebinance.GenerateKlines("BTCUSDT", "1h", start, 12, 42000)
+	server.AddKlines(klines[:9]...)
+	memory.UpsertKlines(klines[:5])
+
+	config := &model.Config{Symbols: []model.SymbolConfig{{Symbol: "BTCUSDT", Intervals: []string{"1h"}}}}
+	stopC := make(chan struct{})
+	doneC := make(chan struct{})
+	go func() {
+		defer close(doneC)
+		WsConnect(config, stopC)
+	}()
+	t.Cleanup(func() {
+		close(stopC)
+		<-doneC
+	})
+
+	waitStored := func(count int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if len(storedOpenTimes(t, memory, "BTCUSDT", "1h")) >= count {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		t.Fatalf("等待 %d 根K线超时，实际 %d", count, len(storedOpenTimes(t, memory, "BTCUSDT", "1h")))
+	}
+
+	if !server.WaitConnections(1, 5*time.Second) {
+		t.Fatal("WebSocket 未连接")
+	}
+	// 连接前已通过 REST 补齐到服务端最新K线
+	waitStored(9)
+
+	// 未收盘的推送不入库，收盘推送入库
+	server.Push(klines[9], false)
+	server.Push(klines[9], true)
+	waitStored(10)
+
+	// 断线期间产生的K线在重连前通过 REST 补齐
+	server.AddKlines(klines[10])
+	server.Disconnect()
+	if !server.WaitConnections(2, 5*time.Second) {
+		t.Fatal("WebSocket 未重连")
+	}
+	waitStored(11)
+
+	server.Push(klines[11], true)
+	waitStored(12)
+
+	report, _ := ScanGaps("BTCUSDT", "1h")
+	if len(report.Gaps) != 0 || report.Actual != 12 {
+		t.Errorf("重连后K线应连续: actual=%d gaps=%+v", report.Actual, report.Gaps)
+	}
+}
+
+func TestWsConnectBackfillAfterSubscribe(t *testing.T) {
+	server, memory := newFakeBinance(t)
+
+	start := time.Now().UTC().Truncate(time.Hour).Add(-6 * time.Hour)
+	klines := fakebinance.GenerateKlines("BTCUSDT", "1h", start, 6, 42000)
+	server.AddKlines(klines...)
+	memory.UpsertKlines(klines[:3])
+	// 连接前的补齐失败（相当于补齐期间才收盘、且不会再推送的K线），只能由连接后的补齐取回
+	server.InjectError(klinesPath, 400, -1100, "Illegal characters found in parameter.", 1)
+
+	config := &model.Config{Symbols: []model.SymbolConfig{{Symbol: "BTCUSDT", Intervals: []string{"1h"}}}}
+	stopC := make(chan struct{})
+	doneC := make(chan struct{})
+	go func() {
+		defer close(doneC)
+		WsConnect(config, stopC)
+	}()
+	t.Cleanup(func() {
+		close(stopC)
+		<-doneC
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(storedOpenTimes(t, memory, "BTCUSDT", "1h")) < 6 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := len(storedOpenTimes(t, memory, "BTCUSDT", "1h")); got != 6 {
+		t.Fatalf("连接后应再次补齐，实际 %d 根", got)
+	}
+	if total, _ := server.Connections(); total != 1 {
+		t.Errorf("不应重连，实际连接 %d 次", total)
+	}
+}
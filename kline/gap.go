@@ -0,0 +1,102 @@
+package kline
+
+import (
+	"fmt"
+	"time"
+
+	"trade/db"
+	"trade/store"
+	"trade/utils"
+)
+
+// KlineGap 一段连续缺失的K线（Start、End 均为缺失K线的开盘时间，闭区间）
+type KlineGap struct {
+	Start   time.Time // 第一根缺失K线的开盘时间
+	End     time.Time // 最后一根缺失K线的开盘时间
+	Missing int       // 缺失的K线数量
+}
+
+// GapReport 单个交易对+时间周期的连续性检查报告
+type GapReport struct {
+	Symbol     string      // 交易对
+	Interval   string      // 时间周期
+	FirstOpen  time.Time   // 最早K线开盘时间
+	LastOpen   time.Time   // 最新K线开盘时间
+	Actual     int         // 实际K线数量
+	Expected   int         // 按时间网格应有的K线数量
+	Gaps       []KlineGap  // 缺口列表
+	Duplicates []time.Time // 重复的开盘时间
+}
+
+// MissingCount 缺失K线总数
+func (r *GapReport) MissingCount() int {
+	total := 0
+	for _, gap := range r.Gaps {
+		total += gap.Missing
+	}
+	return total
+}
+
+// ScanGaps 检查数据库中某交易对+时间周期的K线是否连续
+func ScanGaps(symbol, interval string) (*GapReport, error) {
+	openTimes, err := store.Default().OpenTimes(symbol, interval)
+	if err != nil {
+		return nil, fmt.Errorf("查询 %s %s 的K线失败: %v", symbol, interval, err)
+	}
+
+	report := &GapReport{
+		Symbol:   symbol,
+		Interval: interval,
+		Actual:   len(openTimes),
+	}
+	if len(openTimes) == 0 {
+		return report, nil
+	}
+
+	report.FirstOpen = openTimes[0].UTC()
+	report.LastOpen = openTimes[len(openTimes)-1].UTC()
+	report.Gaps, report.Duplicates = findGaps(openTimes, interval)
+	report.Expected = report.Actual - len(report.Duplicates) + report.MissingCount()
+	return report, nil
+}
+
+// findGaps 按时间周期推算期望的开盘时间网格，找出缺口和重复K线
+// openTimes 必须按升序排列
+func findGaps(openTimes []time.Time, interval string) ([]KlineGap, []time.Time) {
+	gaps := make([]KlineGap, 0)
+	duplicates := make([]time.Time, 0)
+
+	for i := 1; i < len(openTimes); i++ {
+		prev := openTimes[i-1].UTC()
+		cur := openTimes[i].UTC()
+
+		if cur.Equal(prev) {
+			duplicates = append(duplicates, cur)
+			continue
+		}
+
+		expected := utils.NextOpenTime(prev, interval)
+		if !cur.After(expected) {
+			continue
+		}
+
+		gap := KlineGap{Start: expected}
+		for t := expected; t.Before(cur); t = utils.NextOpenTime(t, interval) {
+			gap.End = t
+			gap.Missing++
+		}
+		gaps = append(gaps, gap)
+	}
+
+	return gaps, duplicates
+}
+
+// RepairGaps 从币安重新拉取报告中缺失的时间段
+func RepairGaps(report *GapReport) {
+	for _, gap := range report.Gaps {
+		fmt.Printf("修复 %s %s 缺口: %s ~ %s (%d 根)\n",
+			report.Symbol, report.Interval,
+			gap.Start.Format("2006-01-02 15:04:05"), gap.End.Format("2006-01-02 15:04:05"), gap.Missing)
+		updateKlineData(db.BinanceClient, report.Symbol, report.Interval, gap.Start, gap.End)
+	}
+}
@@ -0,0 +1,42 @@
+package kline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindGaps(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	openTimes := []time.Time{
+		base,
+		base.Add(1 * time.Hour),
+		base.Add(1 * time.Hour), // 重复
+		base.Add(4 * time.Hour), // 缺失 2h、3h
+		base.Add(5 * time.Hour),
+	}
+
+	gaps, duplicates := findGaps(openTimes, "1h")
+	if len(duplicates) != 1 || !duplicates[0].Equal(base.Add(time.Hour)) {
+		t.Fatalf("重复K线识别错误: %v", duplicates)
+	}
+	if len(gaps) != 1 {
+		t.Fatalf("缺口数量错误: %d", len(gaps))
+	}
+	if gaps[0].Missing != 2 || !gaps[0].Start.Equal(base.Add(2*time.Hour)) || !gaps[0].End.Equal(base.Add(3*time.Hour)) {
+		t.Fatalf("缺口范围错误: %+v", gaps[0])
+	}
+}
+
+func TestFindGapsMonthly(t *testing.T) {
+	openTimes := []time.Time{
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	gaps, _ := findGaps(openTimes, "1M")
+	if len(gaps) != 1 || gaps[0].Missing != 1 || gaps[0].Start.Month() != time.April {
+		t.Fatalf("月线缺口识别错误: %+v", gaps)
+	}
+}
@@ -2,45 +2,38 @@ package kline
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strconv"
+	"strings"
 	"time"
 
 	"trade/db"
 	"trade/model"
+	"trade/store"
 	"trade/utils"
 
+	"github.com/adshao/go-binance/v2/common"
 	"github.com/adshao/go-binance/v2/futures"
-	"gorm.io/gorm/clause"
 )
 
-func UpdateKline(symbol string, interval string) {
-	// 查询数据库中该交易对的最新记录
-	var latestKline model.Kline
-	result := db.Pog.Where("symbol = ?", symbol).
-		Order("close_time DESC").
-		Limit(1).
-		Find(&latestKline)
+// 币安限频错误码，HTTP 429 和 418 均返回该错误码
+const errCodeTooManyRequests = -1003
+
+// 限频重试参数，测试中可调小
+var (
+	rateLimitRetries = 3               // 单批次最多重试次数
+	rateLimitBackoff = 2 * time.Second // 首次重试等待时间，之后每次翻倍
+)
 
-	var startTime time.Time
-	if result.Error != nil || result.RowsAffected == 0 {
+// UpdateKline 增量更新K线数据，从该交易对+时间周期的同步断点继续
+func UpdateKline(symbol string, interval string) {
+	startTime, ok := ResumeStartTime(symbol, interval)
+	if !ok {
 		// 如果没有找到记录,从默认时间开始
-		fmt.Printf("未找到 %s 的历史数据,将从 2018-08-01 开始获取\n", symbol)
+		fmt.Printf("未找到 %s %s 的历史数据,将从 2018-01-01 开始获取\n", symbol, interval)
 		startTime = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
 	} else {
-		// 删除最新记录(因为它可能是不完整的)
-		deleteResult := db.Pog.Delete(&latestKline)
-		if deleteResult.Error != nil {
-			fmt.Printf("删除最新记录失败: %v\n", deleteResult.Error)
-			return
-		}
-		fmt.Printf("已删除 %s 的最新记录: %s\n",
-			symbol,
-			latestKline.CloseTime.Format("2006-01-02 15:04:05"))
-
-		// 从被删除记录的开始时间重新获取
-		startTime = latestKline.OpenTime
-		fmt.Printf("将从 %s 开始重新获取数据\n", startTime.Format("2006-01-02 15:04:05"))
+		fmt.Printf("将从断点 %s 开始获取 %s %s 的数据\n", startTime.Format("2006-01-02 15:04:05"), symbol, interval)
 	}
 
 	// 结束时间设置为昨日最后一刻
@@ -50,7 +43,7 @@ func UpdateKline(symbol string, interval string) {
 
 	// 如果开始时间已经超过结束时间,说明数据已经是最新的
 	if startTime.After(endTime) {
-		fmt.Printf("%s 的数据已经是最新的,无需更新\n", symbol)
+		fmt.Printf("%s %s 的数据已经是最新的,无需更新\n", symbol, interval)
 		return
 	}
 
@@ -58,11 +51,40 @@ func UpdateKline(symbol string, interval string) {
 	updateKlineData(db.BinanceClient, symbol, interval, startTime, endTime)
 }
 
+// UpdateKlineRange 获取指定时间范围的K线数据并推进同步断点
+func UpdateKlineRange(symbol string, interval string, startTime, endTime time.Time) {
+	updateKlineData(db.BinanceClient, symbol, interval, startTime, endTime)
+}
+
 func GetKline(symbol string, interval string) {
 	// 使用全局币安客户端
 	getAllKlines(db.BinanceClient, symbol, interval)
 }
 
+// EarliestKlineTime 获取交易对最早的K线时间（通过API查询）
+func EarliestKlineTime(api *futures.Client, symbol string) time.Time {
+	// 从2019年初开始尝试（币安永续合约大约从这个时间开始）
+	testTime := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 尝试获取一条数据
+	klines, err := api.NewContinuousKlinesService().
+		ContractType("PERPETUAL").
+		Pair(symbol).
+		Interval("1d").
+		StartTime(testTime.UnixMilli()).
+		Limit(1).
+		Do(context.Background())
+
+	if err != nil || len(klines) == 0 {
+		fmt.Printf("⚠️  无法获取 %s 的最早时间，使用 2019-09-01\n", symbol)
+		return time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	earliestTime := time.Unix(klines[0].OpenTime/1000, 0).UTC()
+	fmt.Printf("📅 %s 的最早数据时间: %s\n", symbol, earliestTime.Format("2006-01-02"))
+	return earliestTime
+}
+
 // updateKlineData 更新K线数据(支持自定义时间范围)
 func updateKlineData(api *futures.Client, symbol string, interval string, startTime, endTime time.Time) {
 	totalCount := 0
@@ -70,7 +92,7 @@ func updateKlineData(api *futures.Client, symbol string, interval string, startT
 
 	for {
 		// 计算当前批次的结束时间
-		batchEndTime := startTime.Add(time.Duration(1000) * getIntervalDuration(interval))
+		batchEndTime := startTime.Add(time.Duration(1000) * utils.IntervalDuration(interval))
 		if batchEndTime.After(endTime) {
 			batchEndTime = endTime
 		}
@@ -79,16 +101,10 @@ func updateKlineData(api *futures.Client, symbol string, interval string, startT
 			symbol, startTime.Format("2006-01-02"), batchEndTime.Format("2006-01-02"))
 
 		// 请求当前批次的K线数据
-		kline, err := api.NewContinuousKlinesService().
-			ContractType("PERPETUAL").
-			Pair(symbol).
-			Interval(interval).
-			StartTime(startTime.UnixMilli()).
-			EndTime(batchEndTime.UnixMilli()).
-			Limit(1000).
-			Do(context.Background())
+		kline, err := fetchKlines(api, symbol, interval, startTime, batchEndTime)
 		if err != nil {
 			fmt.Printf("获取K线数据失败: %v\n", err)
+			markCheckpoint(symbol, interval, model.CheckpointStatusFailed, err.Error())
 			return
 		}
 		fmt.Println(kline)
@@ -97,48 +113,34 @@ func updateKlineData(api *futures.Client, symbol string, interval string, startT
 			break
 		}
 
-		// 批量构建K线数据（提高性能）
+		// 批量构建K线数据（提高性能），未收盘的K线不入库
+		nowMs := time.Now().UnixMilli()
 		klineModels := make([]model.Kline, 0, len(kline))
 		for _, k := range kline {
-			openTime := time.Unix(k.OpenTime/1000, 0).UTC()
-			closeTime := time.Unix(k.CloseTime/1000, 0).UTC()
-			year := openTime.Year()
-
-			klineModel := model.Kline{
-				Symbol:    symbol,
-				Interval:  interval, // 添加时间周期字段
-				Open:      utils.StringToFloat64(k.Open),
-				High:      utils.StringToFloat64(k.High),
-				Low:       utils.StringToFloat64(k.Low),
-				Close:     utils.StringToFloat64(k.Close),
-				OpenTime:  openTime,
-				CloseTime: closeTime,
-				Date:      strconv.Itoa(year),
-				Day:       fmt.Sprintf("%02d-%02d", int(openTime.Month()), openTime.Day()), // 修复：使用两位数格式
-				Hour:      strconv.Itoa(openTime.Hour()),
-				Week:      strconv.Itoa(int(openTime.Weekday())%7 + 1),
-				Min:       strconv.Itoa(openTime.Minute()),
+			if k.CloseTime >= nowMs {
+				continue
 			}
+			klineModel := buildKlineModel(symbol, interval, k)
 			klineModels = append(klineModels, klineModel)
-			lastKlineTime = closeTime
+			lastKlineTime = klineModel.CloseTime
 		}
 
-		// 批量插入，遇到重复则跳过（ON CONFLICT DO NOTHING）
+		// 批量写入，遇到重复则覆盖（ON CONFLICT DO UPDATE），重复执行结果一致
 		// 性能提升：1000条数据从 ~2000ms 降到 ~50ms
 		if len(klineModels) > 0 {
-			result := db.Pog.Clauses(clause.OnConflict{
-				Columns:   []clause.Column{{Name: "symbol"}, {Name: "interval"}, {Name: "open_time"}},
-				DoNothing: true, // 遇到冲突直接跳过，不报错
-			}).Create(&klineModels)
-
-			if result.Error != nil {
-				fmt.Printf("批量插入失败: %v\n", result.Error)
-			} else {
-				insertedCount := result.RowsAffected
-				totalCount += int(insertedCount)
-				fmt.Printf("批量插入 %d 条数据（跳过 %d 条重复数据）\n",
-					insertedCount, len(klineModels)-int(insertedCount))
+			writtenCount, err := store.Default().UpsertKlines(klineModels)
+			if err != nil {
+				// 写入失败时停止，断点停留在上一批次，下次从这里继续
+				fmt.Printf("批量写入失败: %v\n", err)
+				markCheckpoint(symbol, interval, model.CheckpointStatusFailed, err.Error())
+				return
 			}
+
+			totalCount += int(writtenCount)
+			fmt.Printf("批量写入 %d 条数据\n", writtenCount)
+
+			// 每批写入成功后推进断点，中途退出也能从这里继续
+			advanceCheckpoint(symbol, interval, klineModels[len(klineModels)-1].OpenTime, model.CheckpointStatusRunning)
 		}
 
 		// 更新开始时间为最后一条K线的收盘时间
@@ -156,12 +158,68 @@ func updateKlineData(api *futures.Client, symbol string, interval string, startT
 		time.Sleep(100 * time.Millisecond)
 	}
 
+	markCheckpoint(symbol, interval, model.CheckpointStatusOK, "")
+
 	fmt.Printf("完成！总共获取了 %d 条K线数据\n", totalCount)
 	if !lastKlineTime.IsZero() {
 		fmt.Printf("最后一条K线时间: %s\n", lastKlineTime.Format("2006-01-02 15:04:05"))
 	}
 }
 
+// fetchKlines 请求一批连续合约K线，触发限频(429)时按退避时间重试
+// 已被封禁(418)或重试次数用完时返回错误，由调用方标记断点失败，下次从断点继续
+func fetchKlines(api *futures.Client, symbol, interval string, startTime, endTime time.Time) ([]*futures.ContinuousKline, error) {
+	backoff := rateLimitBackoff
+	for attempt := 0; ; attempt++ {
+		kline, err := api.NewContinuousKlinesService().
+			ContractType("PERPETUAL").
+			Pair(symbol).
+			Interval(interval).
+			StartTime(startTime.UnixMilli()).
+			EndTime(endTime.UnixMilli()).
+			Limit(1000).
+			Do(context.Background())
+		if err == nil || !isRateLimited(err) || attempt >= rateLimitRetries {
+			return kline, err
+		}
+
+		fmt.Printf("获取K线触发限频: %v，%s 后重试\n", err, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// isRateLimited 是否为可重试的限频错误：429 与 418 的错误码都是 -1003，封禁时错误信息包含 banned
+func isRateLimited(err error) bool {
+	var apiErr *common.APIError
+	return errors.As(err, &apiErr) && apiErr.Code == errCodeTooManyRequests &&
+		!strings.Contains(strings.ToLower(apiErr.Message), "banned")
+}
+
+// buildKlineModel 根据币安返回的K线构建数据库模型（REST与WebSocket共用）
+func buildKlineModel(symbol, interval string, k *futures.ContinuousKline) model.Kline {
+	openTime := time.Unix(k.OpenTime/1000, 0).UTC()
+	closeTime := time.Unix(k.CloseTime/1000, 0).UTC()
+
+	klineModel := model.Kline{
+		Symbol:              symbol,
+		Interval:            interval, // 添加时间周期字段
+		Open:                utils.StringToFloat64(k.Open),
+		High:                utils.StringToFloat64(k.High),
+		Low:                 utils.StringToFloat64(k.Low),
+		Close:               utils.StringToFloat64(k.Close),
+		OpenTime:            openTime,
+		CloseTime:           closeTime,
+		Volume:              utils.StringToFloat64(k.Volume),
+		QuoteVolume:         utils.StringToFloat64(k.QuoteAssetVolume),
+		TradeNum:            k.TradeNum,
+		TakerBuyVolume:      utils.StringToFloat64(k.TakerBuyBaseAssetVolume),
+		TakerBuyQuoteVolume: utils.StringToFloat64(k.TakerBuyQuoteAssetVolume),
+	}
+	klineModel.FillTimeFields()
+	return klineModel
+}
+
 // getAllKlines 分页获取所有K线数据
 func getAllKlines(api *futures.Client, symbol string, interval string) {
 	// 设置开始时间（可以根据需要调整）
@@ -173,41 +231,3 @@ func getAllKlines(api *futures.Client, symbol string, interval string) {
 	// 调用统一的更新函数
 	updateKlineData(api, symbol, interval, startTime, endTime)
 }
-
-// getIntervalDuration 根据间隔字符串返回对应的时长
-func getIntervalDuration(interval string) time.Duration {
-	switch interval {
-	case "1m":
-		return time.Minute
-	case "3m":
-		return 3 * time.Minute
-	case "5m":
-		return 5 * time.Minute
-	case "15m":
-		return 15 * time.Minute
-	case "30m":
-		return 30 * time.Minute
-	case "1h":
-		return time.Hour
-	case "2h":
-		return 2 * time.Hour
-	case "4h":
-		return 4 * time.Hour
-	case "6h":
-		return 6 * time.Hour
-	case "8h":
-		return 8 * time.Hour
-	case "12h":
-		return 12 * time.Hour
-	case "1d":
-		return 24 * time.Hour
-	case "3d":
-		return 3 * 24 * time.Hour
-	case "1w":
-		return 7 * 24 * time.Hour
-	case "1M":
-		return 30 * 24 * time.Hour
-	default:
-		return time.Hour // 默认1小时
-	}
-}
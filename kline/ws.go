@@ -2,27 +2,187 @@ package kline
 
 import (
 	"fmt"
+	"sync"
+	"time"
+
+	"trade/db"
+	"trade/model"
+	"trade/store"
+	"trade/utils"
 
 	"github.com/adshao/go-binance/v2/futures"
 )
 
+var (
+	wsMinBackoff = time.Second      // 重连最小等待时间，测试中可调小
+	wsMaxBackoff = 60 * time.Second // 重连最大等待时间
+
+	closedKlineMu       sync.RWMutex
+	closedKlineHandlers []func(model.Kline)
+)
+
+// OnClosedKline 注册已收盘K线的回调（模拟盘等实时消费者使用），K线入库后按注册顺序同步调用
+func OnClosedKline(handler func(model.Kline)) {
+	closedKlineMu.Lock()
+	defer closedKlineMu.Unlock()
+	closedKlineHandlers = append(closedKlineHandlers, handler)
+}
+
+// notifyClosedKline 通知所有已注册的回调
+func notifyClosedKline(k model.Kline) {
+	closedKlineMu.RLock()
+	handlers := make([]func(model.Kline), len(closedKlineHandlers))
+	copy(handlers, closedKlineHandlers)
+	closedKlineMu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(k)
+	}
+}
+
+// ParseContinuousKlineEvent 处理连续合约K线推送，只保存已收盘的K线
 func ParseContinuousKlineEvent(event *futures.WsContinuousKlineEvent) {
+	if event == nil || !event.Kline.IsFinal {
+		return
+	}
+
+	k := event.Kline
+	klineModel := buildKlineModel(event.PairSymbol, k.Interval, &futures.ContinuousKline{
+		OpenTime:                 k.StartTime,
+		Open:                     k.Open,
+		High:                     k.High,
+		Low:                      k.Low,
+		Close:                    k.Close,
+		Volume:                   k.Volume,
+		CloseTime:                k.EndTime,
+		QuoteAssetVolume:         k.QuoteVolume,
+		TradeNum:                 k.TradeNum,
+		TakerBuyBaseAssetVolume:  k.ActiveBuyVolume,
+		TakerBuyQuoteAssetVolume: k.ActiveBuyQuoteVolume,
+	})
+
+	// 已收盘的K线以推送数据为准，覆盖REST拉取时可能存在的旧值
+	if _, err := store.Default().UpsertKlines([]model.Kline{klineModel}); err != nil {
+		fmt.Printf("保存实时K线失败 %s %s: %v\n", klineModel.Symbol, klineModel.Interval, err)
+		return
+	}
+
+	fmt.Printf("已保存实时K线 %s %s: %s\n",
+		klineModel.Symbol, klineModel.Interval, klineModel.OpenTime.Format("2006-01-02 15:04:05"))
+
+	// 只有与断点连续时才推进断点，避免跳过尚未补齐的缺口
+	if checkpoint, ok := GetCheckpoint(klineModel.Symbol, klineModel.Interval); ok && !checkpoint.LastOpenTime.IsZero() &&
+		!klineModel.OpenTime.After(utils.NextOpenTime(checkpoint.LastOpenTime, klineModel.Interval)) {
+		advanceCheckpoint(klineModel.Symbol, klineModel.Interval, klineModel.OpenTime, model.CheckpointStatusOK)
+	}
+
+	notifyClosedKline(klineModel)
 }
 
 func ErrHandler(err error) {
 	fmt.Println(err)
 }
 
-func WsConnect() {
-	var subscribeArgsList []*futures.WsContinuousKlineSubscribeArgs
-	subscribeArgsList = append(subscribeArgsList, &futures.WsContinuousKlineSubscribeArgs{
-		Pair:     "BTCUSDT",
-		Interval: "1m",
-	})
-	doneC, _, err := futures.WsCombinedContinuousKlineServe(subscribeArgsList, ParseContinuousKlineEvent, ErrHandler)
-	if err != nil {
-		fmt.Println(err)
+// WsConnect 订阅配置文件中所有交易对和时间周期的连续合约K线
+// 连接断开后按指数退避重连，重连前通过REST接口补齐断线期间缺失的K线
+// 连接建立后再补齐一次，覆盖补齐期间收盘、推送时尚未订阅的K线（按开盘时间覆盖写入，与推送重复无影响）
+// stopC 关闭时退出
+func WsConnect(config *model.Config, stopC <-chan struct{}) {
+	subscribeArgsList := buildSubscribeArgs(config)
+	if len(subscribeArgsList) == 0 {
+		fmt.Println("⚠️  配置文件中没有可订阅的交易对")
 		return
 	}
-	<-doneC
+
+	backoff := wsMinBackoff
+	for {
+		// 每次建立连接前先补齐缺口（首次启动同样适用）
+		backfillGaps(config)
+
+		doneC, wsStopC, err := futures.WsCombinedContinuousKlineServe(subscribeArgsList, ParseContinuousKlineEvent, ErrHandler)
+		if err != nil {
+			fmt.Printf("WebSocket连接失败: %v，%s 后重试\n", err, backoff)
+		} else {
+			fmt.Printf("WebSocket已连接，订阅 %d 个K线流\n", len(subscribeArgsList))
+			connectedAt := time.Now()
+
+			backfillDone := make(chan struct{})
+			go func() {
+				defer close(backfillDone)
+				backfillGaps(config)
+			}()
+
+			select {
+			case <-stopC:
+				close(wsStopC)
+				<-doneC
+				<-backfillDone
+				fmt.Println("WebSocket已关闭")
+				return
+			case <-doneC:
+			}
+			<-backfillDone
+
+			// 连接稳定运行过一段时间则重置退避时间
+			if time.Since(connectedAt) > wsMaxBackoff {
+				backoff = wsMinBackoff
+			}
+			fmt.Printf("WebSocket连接断开，%s 后重连\n", backoff)
+		}
+
+		select {
+		case <-stopC:
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > wsMaxBackoff {
+			backoff = wsMaxBackoff
+		}
+	}
+}
+
+// buildSubscribeArgs 根据配置生成订阅参数
+func buildSubscribeArgs(config *model.Config) []*futures.WsContinuousKlineSubscribeArgs {
+	var subscribeArgsList []*futures.WsContinuousKlineSubscribeArgs
+	if config == nil {
+		return subscribeArgsList
+	}
+
+	for _, symbolConfig := range config.Symbols {
+		for _, interval := range symbolConfig.Intervals {
+			subscribeArgsList = append(subscribeArgsList, &futures.WsContinuousKlineSubscribeArgs{
+				Pair:         symbolConfig.Symbol,
+				ContractType: "PERPETUAL",
+				Interval:     interval,
+			})
+		}
+	}
+	return subscribeArgsList
+}
+
+// backfillGaps 从每个交易对/周期的同步断点开始补齐到最近一根已收盘K线
+func backfillGaps(config *model.Config) {
+	now := time.Now().UTC()
+
+	for _, symbolConfig := range config.Symbols {
+		for _, interval := range symbolConfig.Intervals {
+			startTime, ok := ResumeStartTime(symbolConfig.Symbol, interval)
+			if !ok {
+				// 没有历史数据时交给 UpdateKline 做全量拉取，这里不处理
+				continue
+			}
+
+			// 只拉取已收盘的K线：开盘时间不晚于 now - 周期
+			endTime := now.Add(-utils.IntervalDuration(interval))
+			if !startTime.Before(endTime) {
+				continue
+			}
+
+			fmt.Printf("补齐 %s %s 从 %s 开始的K线\n",
+				symbolConfig.Symbol, interval, startTime.Format("2006-01-02 15:04:05"))
+			updateKlineData(db.BinanceClient, symbolConfig.Symbol, interval, startTime, endTime)
+		}
+	}
 }
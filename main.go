@@ -6,11 +6,18 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"trade/db"
+	"trade/execution"
+	"trade/funding"
 	"trade/kline"
 	"trade/model"
+	"trade/paper"
+	"trade/positioning"
+	"trade/resample"
 	"trade/scheduler"
 	"trade/strategy"
 	"trade/utils"
@@ -18,22 +25,30 @@ import (
 
 func main() {
 	// 命令行参数
-	mode := flag.String("mode", "once", "运行模式: once(单次运行) 或 daemon(定时任务)")
+	mode := flag.String("mode", "once", "运行模式: once(单次运行)、daemon(定时任务)、ws(实时K线入库)、paper(实时K线驱动的模拟盘) 或 trade(按策略信号下单)")
 	runNow := flag.Bool("now", false, "daemon模式下是否立即执行一次")
+	strategyNames := flag.String("strategy", "", "once模式下只运行指定策略(逗号分隔)，默认运行全部已注册策略(walk_forward 等按需运行的策略除外)")
+	asOfStr := flag.String("as-of", "", "once模式下按指定时点(UTC)运行策略，只使用该时刻之前已收盘的K线，格式：2024-10-30 或 2024-10-30T08:00:00Z")
+	configPath := flag.String("config", utils.DefaultConfigPath(), "配置文件路径")
 	flag.Parse()
 
-	// 初始化币安客户端(如果需要API密钥,请在这里填写)
-	db.InitBinance("", "")
-
-	// 初始化数据库连接
-	db.InitPostgreSql()
+	asOf, err := utils.ParseAsOf(*asOfStr)
+	if err != nil {
+		log.Fatalf("as-of 参数错误: %v", err)
+	}
 
 	// 加载配置文件
-	config, err := utils.LoadConfig("config.json")
+	config, err := utils.LoadConfig(*configPath)
 	if err != nil {
 		log.Fatalf("加载配置文件失败: %v", err)
 	}
 
+	// 初始化币安客户端(API密钥在配置文件或环境变量中设置)
+	db.InitBinance(&config.Binance)
+
+	// 初始化数据库连接
+	db.InitPostgreSql(&config.Database)
+
 	switch *mode {
 	case "daemon":
 		// 定时任务模式
@@ -41,19 +56,34 @@ func main() {
 
 	case "once":
 		// 单次运行模式
-		runOnceMode(config)
+		runOnceMode(config, *strategyNames, asOf)
+
+	case "ws":
+		// 实时K线入库模式
+		runWsMode(config)
+
+	case "paper":
+		// 模拟盘模式
+		runPaperMode(config)
+
+	case "trade":
+		// 下单模式
+		runTradeMode(config)
 
 	default:
 		fmt.Printf("未知的运行模式: %s\n", *mode)
 		fmt.Println("支持的模式:")
 		fmt.Println("  once   - 单次运行（默认）")
-		fmt.Println("  daemon - 定时任务模式，每天零点自动执行")
+		fmt.Println("  daemon - 定时任务模式，按配置的 scheduler.daily_spec 自动执行")
+		fmt.Println("  ws     - 实时K线入库模式，订阅WebSocket并保存已收盘K线")
+		fmt.Println("  paper  - 模拟盘模式，在 ws 模式基础上按策略信号模拟开平仓")
+		fmt.Println("  trade  - 下单模式，在 ws 模式基础上按策略信号向币安合约下单（trading.execution_mode 控制 dry_run/testnet/live）")
 		os.Exit(1)
 	}
 }
 
-// runOnceMode 单次运行模式
-func runOnceMode(config *model.Config) {
+// runOnceMode 单次运行模式，asOf 非零时按该时点复现策略结果
+func runOnceMode(config *model.Config, strategyNames string, asOf time.Time) {
 	fmt.Printf("开始更新 %d 个交易对的K线数据...\n", len(config.Symbols))
 
 	// 遍历配置文件中的所有交易对和时间区间
@@ -65,17 +95,43 @@ func runOnceMode(config *model.Config) {
 			// 更新K线数据(从数据库最新记录开始更新到昨天)
 			kline.UpdateKline(symbolConfig.Symbol, interval)
 		}
+
+		if symbolConfig.Resample != nil {
+			fmt.Printf("\n--- 本地合成(基础周期 %s) ---\n", symbolConfig.Resample.Base)
+			resample.UpdateSymbol(symbolConfig)
+		}
+
+		fmt.Println("\n--- 资金费率 ---")
+		funding.UpdateFundingRate(symbolConfig.Symbol)
+
+		fmt.Println("\n--- 持仓统计 ---")
+		for _, interval := range symbolConfig.Intervals {
+			if positioning.SupportsInterval(interval) {
+				positioning.UpdatePositioning(symbolConfig.Symbol, interval)
+			}
+		}
 	}
 
 	fmt.Println("\n========== 所有数据更新完成 ==========")
 
-	// 运行策略一: 分析历史同期涨跌概率（传入配置文件）
-	fmt.Println("\n========== 开始运行策略一 ==========")
-	strategy.Strategy1(config)
+	if !asOf.IsZero() {
+		fmt.Printf("\n⏪ 按时点 %s 运行策略，只使用之前已收盘的K线\n", asOf.Format(time.RFC3339))
+	}
 
-	// 运行策略二: 小时级别涨跌分析
-	fmt.Println("\n========== 开始运行策略二 ==========")
-	strategy.Strategy2(config)
+	// 运行策略（传入配置文件）
+	if strategyNames == "" {
+		strategy.RunAll(config, asOf)
+		return
+	}
+	for _, name := range strings.Split(strategyNames, ",") {
+		s, ok := strategy.Get(strings.TrimSpace(name))
+		if !ok {
+			fmt.Printf("未知的策略: %s，可用策略: %s\n", name, strings.Join(strategy.Names(), ", "))
+			continue
+		}
+		fmt.Printf("\n========== 开始运行策略: %s ==========\n", s.Name())
+		s.Run(config, asOf)
+	}
 }
 
 // runDaemonMode 定时任务模式
@@ -101,7 +157,7 @@ func runDaemonMode(config *model.Config, runNow bool) {
 	s.GetSchedulerInfo()
 
 	fmt.Println("\n💡 提示:")
-	fmt.Println("  - 程序将在每天 00:00:00 自动执行策略更新")
+	fmt.Printf("  - 程序将按 %s 自动执行策略更新\n", config.Scheduler.DailySpec)
 	fmt.Println("  - 按 Ctrl+C 退出程序")
 	fmt.Println()
 
@@ -114,3 +170,73 @@ func runDaemonMode(config *model.Config, runNow bool) {
 	s.Stop()
 	fmt.Println("程序已退出")
 }
+
+// runWsMode 实时K线入库模式
+func runWsMode(config *model.Config) {
+	fmt.Println("╔════════════════════════════════════════════════════════════════╗")
+	fmt.Println("║          币安合约实时K线入库服务                               ║")
+	fmt.Println("╚════════════════════════════════════════════════════════════════╝")
+	fmt.Println()
+
+	stopC := make(chan struct{})
+	doneC := make(chan struct{})
+	go func() {
+		kline.WsConnect(config, stopC)
+		close(doneC)
+	}()
+
+	fmt.Println("💡 按 Ctrl+C 退出程序")
+
+	// 等待中断信号
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case <-quit:
+		close(stopC)
+		<-doneC
+	case <-doneC:
+	}
+	fmt.Println("程序已退出")
+}
+
+// runPaperMode 模拟盘模式：实时K线入库后交给模拟盘引擎，按策略信号模拟开平仓
+func runPaperMode(config *model.Config) {
+	engine, err := paper.NewEngine(config)
+	if err != nil {
+		log.Fatalf("创建模拟盘引擎失败: %v", err)
+	}
+
+	account := engine.Account()
+	fmt.Printf("📒 模拟盘账户 %s: 余额 %.2f USDT，策略 %s，每笔保证金 %.2f USDT，杠杆 %dx\n",
+		account.Name, account.Balance, strings.Join(config.Trading.Strategies, ","),
+		config.Trading.Margin, config.Trading.Leverage)
+
+	kline.OnClosedKline(func(k model.Kline) {
+		if err := engine.OnKline(k); err != nil {
+			fmt.Printf("模拟盘处理K线失败 %s %s: %v\n", k.Symbol, k.Interval, err)
+		}
+	})
+	runWsMode(config)
+}
+
+// runTradeMode 下单模式：实时K线入库后交给下单执行器，按策略信号调整合约仓位
+func runTradeMode(config *model.Config) {
+	executor, err := execution.NewExecutor(config)
+	if err != nil {
+		log.Fatalf("创建下单执行器失败: %v", err)
+	}
+
+	if executor.Mode() == model.ExecutionModeLive {
+		fmt.Println("⚠️  当前为实盘模式，将使用真实资金下单")
+	}
+	fmt.Printf("🧾 下单模式 %s: 周期 %s，策略 %s，每笔保证金 %.2f USDT，%s %dx\n",
+		executor.Mode(), config.Trading.Interval, strings.Join(config.Trading.Strategies, ","),
+		config.Trading.Margin, config.Trading.MarginType, config.Trading.Leverage)
+
+	kline.OnClosedKline(func(k model.Kline) {
+		if err := executor.OnKline(k); err != nil {
+			fmt.Printf("下单处理K线失败 %s %s: %v\n", k.Symbol, k.Interval, err)
+		}
+	})
+	runWsMode(config)
+}
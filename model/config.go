@@ -1,12 +1,222 @@
 package model
 
-// SymbolConfig 交易对配置
+import (
+	"fmt"
+	"strings"
+)
+
+// SymbolConfig 交易对配置，交易参数未设置(零值)时使用 trading 中的全局默认值
 type SymbolConfig struct {
 	Symbol    string   `json:"symbol"`    // 交易对符号
 	Intervals []string `json:"intervals"` // K线时间区间列表
+
+	Leverage      int      `json:"leverage,omitempty"`        // 杠杆倍数
+	MarginType    string   `json:"margin_type,omitempty"`     // 保证金模式(ISOLATED/CROSSED)
+	Notional      float64  `json:"notional,omitempty"`        // 每笔开仓名义价值(USDT)，未设置时为 trading.notional 或 保证金×杠杆
+	Strategies    []string `json:"strategies,omitempty"`      // 启用的策略
+	MaxPosition   float64  `json:"max_position,omitempty"`    // 单个交易对最大持仓名义价值(USDT)
+	StopLossPct   float64  `json:"stop_loss_pct,omitempty"`   // 止损百分比(相对开仓价)
+	TakeProfitPct float64  `json:"take_profit_pct,omitempty"` // 止盈百分比(相对开仓价)
+
+	Resample *ResampleConfig `json:"resample,omitempty"` // 由基础周期K线在本地合成的周期，不再从交易所拉取
+}
+
+// ResampleConfig 本地重采样配置
+type ResampleConfig struct {
+	Base      string   `json:"base"`      // 基础周期(1m/1h等)，必须同时在 intervals 中从交易所拉取
+	Intervals []string `json:"intervals"` // 合成的周期标签，如 4h、1d、1d@America/New_York、1d+8h
+}
+
+// DatabaseConfig 数据库配置
+type DatabaseConfig struct {
+	Host            string `json:"host"`              // 主机
+	Port            int    `json:"port"`              // 端口
+	User            string `json:"user"`              // 用户名
+	Password        string `json:"password"`          // 密码（建议通过环境变量 TRADE_DB_PASSWORD 设置）
+	DBName          string `json:"dbname"`            // 数据库名
+	SSLMode         string `json:"sslmode"`           // SSL模式
+	TimeZone        string `json:"timezone"`          // 时区
+	MaxIdleConns    int    `json:"max_idle_conns"`    // 最大空闲连接数
+	MaxOpenConns    int    `json:"max_open_conns"`    // 最大打开连接数
+	ConnMaxLifetime string `json:"conn_max_lifetime"` // 连接最大复用时间，例如 1h
+}
+
+// DSN 返回 PostgreSQL 连接字符串
+func (c *DatabaseConfig) DSN() string {
+	parts := []string{
+		fmt.Sprintf("host=%s", c.Host),
+		fmt.Sprintf("user=%s", c.User),
+		fmt.Sprintf("dbname=%s", c.DBName),
+		fmt.Sprintf("port=%d", c.Port),
+		fmt.Sprintf("sslmode=%s", c.SSLMode),
+		fmt.Sprintf("TimeZone=%s", c.TimeZone),
+	}
+	if c.Password != "" {
+		parts = append(parts, fmt.Sprintf("password=%s", c.Password))
+	}
+	return strings.Join(parts, " ")
+}
+
+// BinanceConfig 币安接口配置
+type BinanceConfig struct {
+	APIKey    string `json:"api_key"`    // API Key（建议通过环境变量 TRADE_BINANCE_API_KEY 设置）
+	SecretKey string `json:"secret_key"` // Secret Key（建议通过环境变量 TRADE_BINANCE_SECRET_KEY 设置）
+	BaseURL   string `json:"base_url"`   // 下单接口地址，为空时按 trading.execution_mode 选择正式网或测试网
+	DataURL   string `json:"data_url"`   // 行情接口地址，为空时使用正式网，可指向本地模拟服务做离线测试
+	StreamURL string `json:"stream_url"` // 行情 WebSocket 地址(ws://host:port)，为空时使用正式网
+}
+
+// ServerConfig HTTP服务配置
+type ServerConfig struct {
+	Host string `json:"host"` // 监听地址，空表示所有网卡
+	Port int    `json:"port"` // 监听端口
+}
+
+// Addr 返回监听地址，例如 :8080
+func (c *ServerConfig) Addr() string {
+	return fmt.Sprintf("%s:%d", c.Host, c.Port)
+}
+
+// SchedulerConfig 定时任务配置
+type SchedulerConfig struct {
+	DailySpec string `json:"daily_spec"` // 每日更新任务的cron表达式(带秒)，例如 "0 0 0 * * *"
+}
+
+// 下单模式
+const (
+	ExecutionModeDryRun  = "dry_run" // 只计算并记录订单，不发送到交易所
+	ExecutionModeTestnet = "testnet" // 发送到币安合约测试网
+	ExecutionModeLive    = "live"    // 实盘下单
+)
+
+// 保证金模式
+const (
+	MarginTypeIsolated = "ISOLATED" // 逐仓
+	MarginTypeCrossed  = "CROSSED"  // 全仓
+)
+
+// TradingConfig 自动交易配置（模拟盘和实盘下单共用）
+type TradingConfig struct {
+	Strategies     []string `json:"strategies"`      // 产生交易信号的策略，例如 ["strategy_1", "strategy_2"]
+	Margin         float64  `json:"margin"`          // 每笔开仓占用的保证金(USDT)
+	Leverage       int      `json:"leverage"`        // 杠杆倍数
+	FeeRate        float64  `json:"fee_rate"`        // 手续费率，按成交额收取，例如 0.0004
+	SlippageRate   float64  `json:"slippage_rate"`   // 滑点，按成交价的比例，例如 0.0005
+	FundingRate    float64  `json:"funding_rate"`    // 每8小时的资金费率(模拟)，正数表示多头付给空头
+	InitialBalance float64  `json:"initial_balance"` // 模拟账户初始资金(USDT)
+
+	ExecutionMode string `json:"execution_mode"` // 下单模式(dry_run/testnet/live)
+	Interval      string `json:"interval"`       // 实盘下单使用的信号周期，只响应该周期的已收盘K线
+	MarginType    string `json:"margin_type"`    // 保证金模式(ISOLATED/CROSSED)
+
+	Notional      float64 `json:"notional"`        // 每笔开仓名义价值(USDT)，0 表示 保证金×杠杆
+	MaxPosition   float64 `json:"max_position"`    // 单个交易对最大持仓名义价值(USDT)，0 表示不限制
+	StopLossPct   float64 `json:"stop_loss_pct"`   // 止损百分比(相对开仓价)，0 表示不止损
+	TakeProfitPct float64 `json:"take_profit_pct"` // 止盈百分比(相对开仓价)，0 表示不止盈
+}
+
+// RiskConfig 风控配置，只限制开新仓，平仓不受影响；0 表示不限制
+// 单个交易对的最大持仓使用 trading.max_position 或交易对的 max_position
+type RiskConfig struct {
+	MaxTotalExposure     float64 `json:"max_total_exposure"`     // 全部交易对持仓名义价值合计上限(USDT)
+	MaxDailyLoss         float64 `json:"max_daily_loss"`         // 当日(UTC)已实现亏损上限(USDT，正数)
+	MaxConsecutiveLosses int     `json:"max_consecutive_losses"` // 连续亏损平仓次数上限，达到后需通过接口重置
+}
+
+// SymbolTrading 交易对生效的交易参数
+type SymbolTrading struct {
+	Symbol        string   `json:"symbol"`          // 交易对
+	Strategies    []string `json:"strategies"`      // 启用的策略
+	Leverage      int      `json:"leverage"`        // 杠杆倍数
+	MarginType    string   `json:"margin_type"`     // 保证金模式
+	Notional      float64  `json:"notional"`        // 每笔开仓名义价值(USDT)
+	MaxPosition   float64  `json:"max_position"`    // 最大持仓名义价值(USDT)，0 表示不限制
+	StopLossPct   float64  `json:"stop_loss_pct"`   // 止损百分比，0 表示不止损
+	TakeProfitPct float64  `json:"take_profit_pct"` // 止盈百分比，0 表示不止盈
+}
+
+// Margin 每笔开仓占用的保证金 = 名义价值 / 杠杆
+func (t SymbolTrading) Margin() float64 {
+	if t.Leverage <= 0 {
+		return 0
+	}
+	return t.Notional / float64(t.Leverage)
+}
+
+// StopLossPrice 按开仓价计算止损价，direction 多头为1、空头为-1，未设置止损时返回0
+func (t SymbolTrading) StopLossPrice(entry, direction float64) float64 {
+	if t.StopLossPct <= 0 {
+		return 0
+	}
+	return entry * (1 - direction*t.StopLossPct/100)
+}
+
+// TakeProfitPrice 按开仓价计算止盈价，direction 多头为1、空头为-1，未设置止盈时返回0
+func (t SymbolTrading) TakeProfitPrice(entry, direction float64) float64 {
+	if t.TakeProfitPct <= 0 {
+		return 0
+	}
+	return entry * (1 + direction*t.TakeProfitPct/100)
+}
+
+// ForSymbol 合并交易对配置和全局默认值，得到交易对生效的交易参数
+func (c TradingConfig) ForSymbol(symbol SymbolConfig) SymbolTrading {
+	t := SymbolTrading{
+		Symbol:        symbol.Symbol,
+		Strategies:    c.Strategies,
+		Leverage:      c.Leverage,
+		MarginType:    c.MarginType,
+		Notional:      c.Notional,
+		MaxPosition:   c.MaxPosition,
+		StopLossPct:   c.StopLossPct,
+		TakeProfitPct: c.TakeProfitPct,
+	}
+	if len(symbol.Strategies) > 0 {
+		t.Strategies = symbol.Strategies
+	}
+	if symbol.Leverage != 0 {
+		t.Leverage = symbol.Leverage
+	}
+	if symbol.MarginType != "" {
+		t.MarginType = symbol.MarginType
+	}
+	if symbol.MaxPosition != 0 {
+		t.MaxPosition = symbol.MaxPosition
+	}
+	if symbol.StopLossPct != 0 {
+		t.StopLossPct = symbol.StopLossPct
+	}
+	if symbol.TakeProfitPct != 0 {
+		t.TakeProfitPct = symbol.TakeProfitPct
+	}
+	// 名义价值：交易对配置 > trading.notional > 保证金×杠杆
+	switch {
+	case symbol.Notional != 0:
+		t.Notional = symbol.Notional
+	case t.Notional == 0:
+		t.Notional = c.Margin * float64(t.Leverage)
+	}
+	return t
 }
 
 // Config 全局配置
 type Config struct {
-	Symbols []SymbolConfig `json:"symbols"` // 交易对配置列表
+	Database  DatabaseConfig  `json:"database"`  // 数据库配置
+	Binance   BinanceConfig   `json:"binance"`   // 币安接口配置
+	API       ServerConfig    `json:"api"`       // API服务配置
+	Web       ServerConfig    `json:"web"`       // Web服务配置
+	Scheduler SchedulerConfig `json:"scheduler"` // 定时任务配置
+	Trading   TradingConfig   `json:"trading"`   // 自动交易配置
+	Risk      RiskConfig      `json:"risk"`      // 风控配置
+	Symbols   []SymbolConfig  `json:"symbols"`   // 交易对配置列表
+}
+
+// SymbolTrading 返回交易对生效的交易参数，未在 symbols 中配置的交易对使用全局默认值
+func (c *Config) SymbolTrading(symbol string) SymbolTrading {
+	for _, symbolConfig := range c.Symbols {
+		if symbolConfig.Symbol == symbol {
+			return c.Trading.ForSymbol(symbolConfig)
+		}
+	}
+	return c.Trading.ForSymbol(SymbolConfig{Symbol: symbol})
 }
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+)
+
+// 实盘订单状态（交易所返回的状态之外的本地状态）
+const (
+	ExecutionStatusDryRun   = "DRY_RUN"  // 模拟下单，未发送到交易所
+	ExecutionStatusRejected = "REJECTED" // 本地校验未通过或交易所拒单
+)
+
+// ExecutionOrder 实盘(含测试网、dry-run)订单记录表
+type ExecutionOrder struct {
+	ID              int       `json:"id" gorm:"primaryKey"`
+	Mode            string    `json:"mode"`                         // 下单模式(dry_run/testnet/live)
+	Symbol          string    `json:"symbol" gorm:"index"`          // 交易对
+	Interval        string    `json:"interval"`                     // 信号周期
+	Signal          string    `json:"signal"`                       // 触发下单的信号(bullish/bearish/neutral)
+	Side            string    `json:"side"`                         // 方向(BUY/SELL)
+	Type            string    `json:"type"`                         // 订单类型(MARKET)
+	ReduceOnly      bool      `json:"reduce_only"`                  // 是否只减仓(平仓单)
+	Quantity        float64   `json:"quantity"`                     // 下单数量(已按步长取整)
+	ReferencePrice  float64   `json:"reference_price"`              // 参考价格(已按价格步长取整)
+	ClientOrderID   string    `json:"client_order_id" gorm:"index"` // 本地生成的客户端订单ID
+	ExchangeOrderID int64     `json:"exchange_order_id"`            // 交易所订单ID
+	Status          string    `json:"status"`                       // 订单状态(交易所状态/DRY_RUN/REJECTED)
+	ExecutedQty     float64   `json:"executed_qty"`                 // 成交数量
+	AvgPrice        float64   `json:"avg_price"`                    // 成交均价
+	Error           string    `json:"error"`                        // 失败原因
+	CreatedAt       time.Time `json:"created_at"`                   // 下单时间
+}
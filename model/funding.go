@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// FundingRate 资金费率历史表，每个交易对每个结算时间一条记录
+type FundingRate struct {
+	ID          int       `json:"id" gorm:"primaryKey"`
+	Symbol      string    `json:"symbol" gorm:"index:idx_unique_funding_rate,unique"`       // 交易对
+	FundingTime time.Time `json:"funding_time" gorm:"index:idx_unique_funding_rate,unique"` // 结算时间
+	FundingRate float64   `json:"funding_rate"`                                             // 资金费率（小数，0.0001 即 0.01%），正数多头付给空头
+	MarkPrice   float64   `json:"mark_price"`                                               // 结算时的标记价格（早期数据为0）
+	CreatedAt   time.Time `json:"created_at"`                                               // 创建时间
+	UpdatedAt   time.Time `json:"updated_at"`                                               // 更新时间
+}
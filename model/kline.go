@@ -1,40 +1,80 @@
 package model
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // Kline 表示K线数据
 type Kline struct {
-	ID        int       `json:"id" db:"id"`                                                    // 主键ID
-	Symbol    string    `json:"symbol" db:"symbol" gorm:"index:idx_unique_kline,unique"`       // 交易对符号
-	Interval  string    `json:"interval" db:"interval" gorm:"index:idx_unique_kline,unique"`   // 时间周期(1m,1h,1d等)
-	Open      float64   `json:"open" db:"open"`                                                // 开盘价
-	Close     float64   `json:"close" db:"close"`                                              // 收盘价
-	High      float64   `json:"high" db:"high"`                                                // 最高价
-	Low       float64   `json:"low" db:"low"`                                                  // 最低价
-	OpenTime  time.Time `json:"open_time" db:"open_time" gorm:"index:idx_unique_kline,unique"` // 开盘时间
-	CloseTime time.Time `json:"close_time" db:"close_time"`                                    // 收盘时间
-	Date      string    `json:"date" db:"date"`                                                // 日期字符串
-	Day       string    `json:"day" db:"day"`                                                  // 日
-	Hour      string    `json:"hour" db:"hour"`                                                // 小时
-	Week      string    `json:"week" db:"week"`                                                // 周
-	Min       string    `json:"min" db:"min"`                                                  // 分钟
+	ID                  int       `json:"id" db:"id"`                                                          // 主键ID
+	Symbol              string    `json:"symbol" db:"symbol" gorm:"index:idx_unique_kline,unique"`             // 交易对符号
+	Interval            string    `json:"interval" db:"interval" gorm:"index:idx_unique_kline,unique"`         // 时间周期(1m,1h,1d等)
+	Open                float64   `json:"open" db:"open"`                                                      // 开盘价
+	Close               float64   `json:"close" db:"close"`                                                    // 收盘价
+	High                float64   `json:"high" db:"high"`                                                      // 最高价
+	Low                 float64   `json:"low" db:"low"`                                                        // 最低价
+	OpenTime            time.Time `json:"open_time" db:"open_time" gorm:"index:idx_unique_kline,unique"`       // 开盘时间
+	CloseTime           time.Time `json:"close_time" db:"close_time"`                                          // 收盘时间
+	Volume              float64   `json:"volume" db:"volume" gorm:"default:0"`                                 // 成交量
+	QuoteVolume         float64   `json:"quote_volume" db:"quote_volume" gorm:"default:0"`                     // 成交额
+	TradeNum            int64     `json:"trade_num" db:"trade_num" gorm:"default:0"`                           // 成交笔数
+	TakerBuyVolume      float64   `json:"taker_buy_volume" db:"taker_buy_volume" gorm:"default:0"`             // 主动买入成交量
+	TakerBuyQuoteVolume float64   `json:"taker_buy_quote_volume" db:"taker_buy_quote_volume" gorm:"default:0"` // 主动买入成交额
+	Date                string    `json:"date" db:"date"`                                                      // 日期字符串
+	Day                 string    `json:"day" db:"day"`                                                        // 日
+	Hour                string    `json:"hour" db:"hour"`                                                      // 小时
+	Week                string    `json:"week" db:"week"`                                                      // 周
+	Min                 string    `json:"min" db:"min"`                                                        // 分钟
+	Source              string    `json:"source,omitempty" db:"source" gorm:"default:''"`                      // 数据来源，空为交易所，resample:1h 表示由1h K线合成
+}
+
+// KlineSourceExchange 从交易所拉取的K线的来源标记
+const KlineSourceExchange = ""
+
+// resampledSourcePrefix 本地重采样合成的K线的来源前缀
+const resampledSourcePrefix = "resample:"
+
+// ResampledSource 由 base 周期K线合成的K线的来源标记，如 resample:1h
+func ResampledSource(base string) string {
+	return resampledSourcePrefix + base
+}
+
+// IsResampled 是否为本地重采样合成的K线
+func (k *Kline) IsResampled() bool {
+	return strings.HasPrefix(k.Source, resampledSourcePrefix)
 }
 
 type KlineWs struct {
-	ID        int       `json:"id" db:"id"`                                                    // 主键ID
-	Symbol    string    `json:"symbol" db:"symbol" gorm:"index:idx_unique_kline,unique"`       // 交易对符号
-	Interval  string    `json:"interval" db:"interval" gorm:"index:idx_unique_kline,unique"`   // 时间周期(1m,1h,1d等)
-	Open      float64   `json:"open" db:"open"`                                                // 开盘价
-	Close     float64   `json:"close" db:"close"`                                              // 收盘价
-	High      float64   `json:"high" db:"high"`                                                // 最高价
-	Low       float64   `json:"low" db:"low"`                                                  // 最低价
-	OpenTime  time.Time `json:"open_time" db:"open_time" gorm:"index:idx_unique_kline,unique"` // 开盘时间
-	CloseTime time.Time `json:"close_time" db:"close_time"`                                    // 收盘时间
-	Date      string    `json:"date" db:"date"`                                                // 日期字符串
-	Day       string    `json:"day" db:"day"`                                                  // 日
-	Hour      string    `json:"hour" db:"hour"`                                                // 小时
-	Week      string    `json:"week" db:"week"`                                                // 周
-	Min       string    `json:"min" db:"min"`                                                  // 分钟
+	ID                  int       `json:"id" db:"id"`                                                          // 主键ID
+	Symbol              string    `json:"symbol" db:"symbol" gorm:"index:idx_unique_kline,unique"`             // 交易对符号
+	Interval            string    `json:"interval" db:"interval" gorm:"index:idx_unique_kline,unique"`         // 时间周期(1m,1h,1d等)
+	Open                float64   `json:"open" db:"open"`                                                      // 开盘价
+	Close               float64   `json:"close" db:"close"`                                                    // 收盘价
+	High                float64   `json:"high" db:"high"`                                                      // 最高价
+	Low                 float64   `json:"low" db:"low"`                                                        // 最低价
+	OpenTime            time.Time `json:"open_time" db:"open_time" gorm:"index:idx_unique_kline,unique"`       // 开盘时间
+	CloseTime           time.Time `json:"close_time" db:"close_time"`                                          // 收盘时间
+	Volume              float64   `json:"volume" db:"volume" gorm:"default:0"`                                 // 成交量
+	QuoteVolume         float64   `json:"quote_volume" db:"quote_volume" gorm:"default:0"`                     // 成交额
+	TradeNum            int64     `json:"trade_num" db:"trade_num" gorm:"default:0"`                           // 成交笔数
+	TakerBuyVolume      float64   `json:"taker_buy_volume" db:"taker_buy_volume" gorm:"default:0"`             // 主动买入成交量
+	TakerBuyQuoteVolume float64   `json:"taker_buy_quote_volume" db:"taker_buy_quote_volume" gorm:"default:0"` // 主动买入成交额
+	Date                string    `json:"date" db:"date"`                                                      // 日期字符串
+	Day                 string    `json:"day" db:"day"`                                                        // 日
+	Hour                string    `json:"hour" db:"hour"`                                                      // 小时
+	Week                string    `json:"week" db:"week"`                                                      // 周
+	Min                 string    `json:"min" db:"min"`                                                        // 分钟
+}
+
+// FillTimeFields 根据开盘时间(UTC)填充 Date/Day/Hour/Week/Min 等冗余时间字段
+func (k *Kline) FillTimeFields() {
+	openTime := k.OpenTime.UTC()
+	k.Date = strconv.Itoa(openTime.Year())
+	k.Day = fmt.Sprintf("%02d-%02d", int(openTime.Month()), openTime.Day())
+	k.Hour = strconv.Itoa(openTime.Hour())
+	k.Week = strconv.Itoa(int(openTime.Weekday())%7 + 1)
+	k.Min = strconv.Itoa(openTime.Minute())
 }
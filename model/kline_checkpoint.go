@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+// 断点状态
+const (
+	CheckpointStatusRunning = "running" // 正在同步
+	CheckpointStatusOK      = "ok"      // 同步完成
+	CheckpointStatusFailed  = "failed"  // 同步失败
+)
+
+// KlineCheckpoint K线同步断点表，每个交易对+时间周期一条记录
+type KlineCheckpoint struct {
+	ID           int       `json:"id" gorm:"primaryKey"`
+	Symbol       string    `json:"symbol" gorm:"index:idx_unique_kline_checkpoint,unique"`   // 交易对
+	Interval     string    `json:"interval" gorm:"index:idx_unique_kline_checkpoint,unique"` // 时间周期
+	LastOpenTime time.Time `json:"last_open_time"`                                           // 最后一根已收盘K线的开盘时间
+	LastRunAt    time.Time `json:"last_run_at"`                                              // 最后一次同步时间
+	Status       string    `json:"status"`                                                   // 同步状态(running/ok/failed)
+	Message      string    `json:"message"`                                                  // 失败原因
+	CreatedAt    time.Time `json:"created_at"`                                               // 创建时间
+	UpdatedAt    time.Time `json:"updated_at"`                                               // 更新时间
+}
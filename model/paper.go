@@ -0,0 +1,116 @@
+package model
+
+import (
+	"time"
+)
+
+// 持仓方向
+const (
+	PositionSideLong  = "LONG"  // 多头
+	PositionSideShort = "SHORT" // 空头
+)
+
+// 持仓状态
+const (
+	PositionStatusOpen   = "OPEN"   // 持仓中
+	PositionStatusClosed = "CLOSED" // 已平仓
+)
+
+// 订单方向
+const (
+	OrderSideBuy  = "BUY"  // 买入
+	OrderSideSell = "SELL" // 卖出
+)
+
+// 订单动作
+const (
+	OrderActionOpen        = "OPEN"        // 开仓
+	OrderActionClose       = "CLOSE"       // 平仓
+	OrderActionLiquidation = "LIQUIDATION" // 强平
+)
+
+// PaperAccount 模拟盘账户表
+type PaperAccount struct {
+	ID             int       `json:"id" gorm:"primaryKey"`
+	Name           string    `json:"name" gorm:"uniqueIndex"` // 账户名称
+	InitialBalance float64   `json:"initial_balance"`         // 初始资金(USDT)
+	Balance        float64   `json:"balance"`                 // 钱包余额：初始资金 + 已实现盈亏 - 手续费 - 资金费
+	RealizedPnL    float64   `json:"realized_pnl"`            // 累计已实现盈亏(不含手续费和资金费)
+	Fees           float64   `json:"fees"`                    // 累计手续费
+	FundingFees    float64   `json:"funding_fees"`            // 累计资金费(正数为支出)
+	CreatedAt      time.Time `json:"created_at"`              // 创建时间
+	UpdatedAt      time.Time `json:"updated_at"`              // 更新时间
+}
+
+// PaperPosition 模拟盘持仓表，每个策略在每个交易对+周期上同时最多一个持仓
+type PaperPosition struct {
+	ID               int        `json:"id" gorm:"primaryKey"`
+	AccountID        int        `json:"account_id" gorm:"index"` // 账户ID
+	Symbol           string     `json:"symbol" gorm:"index"`     // 交易对
+	Interval         string     `json:"interval"`                // 信号周期
+	Strategy         string     `json:"strategy"`                // 产生信号的策略
+	Side             string     `json:"side"`                    // 方向(LONG/SHORT)
+	Status           string     `json:"status" gorm:"index"`     // 状态(OPEN/CLOSED)
+	Quantity         float64    `json:"quantity"`                // 持仓数量
+	EntryPrice       float64    `json:"entry_price"`             // 开仓均价
+	ExitPrice        float64    `json:"exit_price"`              // 平仓均价
+	Margin           float64    `json:"margin"`                  // 占用保证金(USDT)
+	Leverage         int        `json:"leverage"`                // 杠杆倍数
+	LiquidationPrice float64    `json:"liquidation_price"`       // 强平价格
+	StopLossPrice    float64    `json:"stop_loss_price"`         // 止损价格，0 表示不止损
+	TakeProfitPrice  float64    `json:"take_profit_price"`       // 止盈价格，0 表示不止盈
+	MarkPrice        float64    `json:"mark_price"`              // 最新标记价格(最近一根K线收盘价)
+	UnrealizedPnL    float64    `json:"unrealized_pnl"`          // 未实现盈亏
+	RealizedPnL      float64    `json:"realized_pnl"`            // 已实现盈亏(不含手续费和资金费)
+	Fees             float64    `json:"fees"`                    // 开平仓手续费
+	FundingFee       float64    `json:"funding_fee"`             // 累计资金费(正数为支出)
+	CloseReason      string     `json:"close_reason"`            // 平仓原因(signal/liquidation/stop_loss/take_profit)
+	OpenedAt         time.Time  `json:"opened_at"`               // 开仓时间
+	ClosedAt         *time.Time `json:"closed_at"`               // 平仓时间
+	LastFundingAt    time.Time  `json:"last_funding_at"`         // 最后一次结算资金费的时间
+	CreatedAt        time.Time  `json:"created_at"`              // 创建时间
+	UpdatedAt        time.Time  `json:"updated_at"`              // 更新时间
+}
+
+// PaperOrder 模拟盘订单表（市价单，按K线收盘价加滑点立即成交）
+type PaperOrder struct {
+	ID         int       `json:"id" gorm:"primaryKey"`
+	AccountID  int       `json:"account_id" gorm:"index"`  // 账户ID
+	PositionID int       `json:"position_id" gorm:"index"` // 关联持仓ID
+	Symbol     string    `json:"symbol" gorm:"index"`      // 交易对
+	Interval   string    `json:"interval"`                 // 信号周期
+	Strategy   string    `json:"strategy"`                 // 产生信号的策略
+	Side       string    `json:"side"`                     // 方向(BUY/SELL)
+	Action     string    `json:"action"`                   // 动作(OPEN/CLOSE/LIQUIDATION)
+	Quantity   float64   `json:"quantity"`                 // 数量
+	Price      float64   `json:"price"`                    // 下单参考价(K线收盘价或强平价)
+	Status     string    `json:"status"`                   // 状态(FILLED)
+	CreatedAt  time.Time `json:"created_at"`               // 下单时间
+}
+
+// PaperFill 模拟盘成交表
+type PaperFill struct {
+	ID          int       `json:"id" gorm:"primaryKey"`
+	AccountID   int       `json:"account_id" gorm:"index"` // 账户ID
+	OrderID     int       `json:"order_id" gorm:"index"`   // 关联订单ID
+	PositionID  int       `json:"position_id"`             // 关联持仓ID
+	Symbol      string    `json:"symbol"`                  // 交易对
+	Side        string    `json:"side"`                    // 方向(BUY/SELL)
+	Quantity    float64   `json:"quantity"`                // 成交数量
+	Price       float64   `json:"price"`                   // 成交价(含滑点)
+	Fee         float64   `json:"fee"`                     // 手续费
+	RealizedPnL float64   `json:"realized_pnl"`            // 本次成交的已实现盈亏(平仓时)
+	FilledAt    time.Time `json:"filled_at"`               // 成交时间
+}
+
+// PaperEquity 模拟盘账户权益快照表，每处理一根K线记录一次
+type PaperEquity struct {
+	ID            int       `json:"id" gorm:"primaryKey"`
+	AccountID     int       `json:"account_id" gorm:"index"` // 账户ID
+	Time          time.Time `json:"time" gorm:"index"`       // 快照时间(K线收盘时间)
+	Balance       float64   `json:"balance"`                 // 钱包余额
+	UnrealizedPnL float64   `json:"unrealized_pnl"`          // 全部持仓未实现盈亏
+	Equity        float64   `json:"equity"`                  // 权益 = 钱包余额 + 未实现盈亏
+	UsedMargin    float64   `json:"used_margin"`             // 占用保证金
+	OpenPositions int       `json:"open_positions"`          // 持仓数量
+}
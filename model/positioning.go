@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+)
+
+// 大户多空比的统计口径
+const (
+	LongShortKindTopAccount  = "top_account"  // 大户账户数多空比
+	LongShortKindTopPosition = "top_position" // 大户持仓量多空比
+)
+
+// OpenInterest 合约持仓量历史，每个交易对+统计周期+时间一条记录
+type OpenInterest struct {
+	ID                   int       `json:"id" gorm:"primaryKey"`
+	Symbol               string    `json:"symbol" gorm:"index:idx_unique_open_interest,unique"` // 交易对
+	Period               string    `json:"period" gorm:"index:idx_unique_open_interest,unique"` // 统计周期(5m,1h等)
+	Time                 time.Time `json:"time" gorm:"index:idx_unique_open_interest,unique"`   // 统计时间，与同周期K线的开盘时间对齐
+	SumOpenInterest      float64   `json:"sum_open_interest"`                                   // 持仓总数量（张）
+	SumOpenInterestValue float64   `json:"sum_open_interest_value"`                             // 持仓总价值（USDT）
+	CreatedAt            time.Time `json:"created_at"`                                          // 创建时间
+	UpdatedAt            time.Time `json:"updated_at"`                                          // 更新时间
+}
+
+// LongShortRatio 大户多空比历史，Kind 区分账户数口径和持仓量口径
+type LongShortRatio struct {
+	ID             int       `json:"id" gorm:"primaryKey"`
+	Symbol         string    `json:"symbol" gorm:"index:idx_unique_long_short_ratio,unique"` // 交易对
+	Period         string    `json:"period" gorm:"index:idx_unique_long_short_ratio,unique"` // 统计周期
+	Kind           string    `json:"kind" gorm:"index:idx_unique_long_short_ratio,unique"`   // 口径(top_account/top_position)
+	Time           time.Time `json:"time" gorm:"index:idx_unique_long_short_ratio,unique"`   // 统计时间
+	LongShortRatio float64   `json:"long_short_ratio"`                                       // 多空比
+	LongShare      float64   `json:"long_share"`                                             // 多头占比（小数）
+	ShortShare     float64   `json:"short_share"`                                            // 空头占比（小数）
+	CreatedAt      time.Time `json:"created_at"`                                             // 创建时间
+	UpdatedAt      time.Time `json:"updated_at"`                                             // 更新时间
+}
+
+// TakerVolume 主动买卖量比历史
+type TakerVolume struct {
+	ID           int       `json:"id" gorm:"primaryKey"`
+	Symbol       string    `json:"symbol" gorm:"index:idx_unique_taker_volume,unique"` // 交易对
+	Period       string    `json:"period" gorm:"index:idx_unique_taker_volume,unique"` // 统计周期
+	Time         time.Time `json:"time" gorm:"index:idx_unique_taker_volume,unique"`   // 统计时间
+	BuySellRatio float64   `json:"buy_sell_ratio"`                                     // 主动买入量/主动卖出量
+	BuyVolume    float64   `json:"buy_volume"`                                         // 主动买入量
+	SellVolume   float64   `json:"sell_volume"`                                        // 主动卖出量
+	CreatedAt    time.Time `json:"created_at"`                                         // 创建时间
+	UpdatedAt    time.Time `json:"updated_at"`                                         // 更新时间
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+)
+
+// 风控拒绝规则
+const (
+	RiskRuleKillSwitch        = "kill_switch"        // 手动紧急停止
+	RiskRuleMaxPosition       = "max_position"       // 超过交易对最大持仓
+	RiskRuleMaxTotalExposure  = "max_total_exposure" // 超过总持仓上限
+	RiskRuleMaxDailyLoss      = "max_daily_loss"     // 当日已实现亏损达到上限
+	RiskRuleConsecutiveLosses = "consecutive_losses" // 连续亏损次数达到上限
+)
+
+// KillSwitch 紧急停止开关表，只有一行；开启后所有账户停止开新仓，平仓不受影响
+type KillSwitch struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	Active    bool      `json:"active"`     // 是否开启
+	Reason    string    `json:"reason"`     // 开启或关闭的原因
+	UpdatedAt time.Time `json:"updated_at"` // 更新时间
+}
+
+// RiskState 风控状态表，每个账户(模拟盘或下单模式)一行，记录当日已实现盈亏和连续亏损次数
+type RiskState struct {
+	ID                int       `json:"id" gorm:"primaryKey"`
+	Name              string    `json:"name" gorm:"uniqueIndex"` // 账户名称(paper/dry_run/testnet/live)
+	Day               string    `json:"day"`                     // 当日已实现盈亏对应的日期(UTC, YYYY-MM-DD)
+	DailyRealizedPnL  float64   `json:"daily_realized_pnl"`      // 当日已实现盈亏(USDT)
+	ConsecutiveLosses int       `json:"consecutive_losses"`      // 连续亏损平仓次数，盈利平仓后清零
+	UpdatedAt         time.Time `json:"updated_at"`              // 更新时间
+}
+
+// RiskRejection 被风控拒绝的开仓信号表
+type RiskRejection struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	Account   string    `json:"account" gorm:"index"` // 账户名称
+	Symbol    string    `json:"symbol" gorm:"index"`  // 交易对
+	Interval  string    `json:"interval"`             // 信号周期
+	Strategy  string    `json:"strategy"`             // 产生信号的策略，合并信号时为空
+	Side      string    `json:"side"`                 // 拟开仓方向(LONG/SHORT)
+	Notional  float64   `json:"notional"`             // 拟开仓名义价值(USDT)
+	Rule      string    `json:"rule"`                 // 触发的风控规则
+	Reason    string    `json:"reason"`               // 拒绝原因
+	CreatedAt time.Time `json:"created_at"`           // 拒绝时间
+}
@@ -4,6 +4,31 @@ import (
 	"time"
 )
 
+// ReturnStats 一组K线的涨跌幅统计(单位: %)，嵌入各策略结果表
+type ReturnStats struct {
+	MeanReturn   float64 `json:"mean_return"`   // 平均涨跌幅
+	MedianReturn float64 `json:"median_return"` // 涨跌幅中位数
+	StdDev       float64 `json:"std_dev"`       // 涨跌幅标准差(样本)
+	AvgWin       float64 `json:"avg_win"`       // 上涨K线平均涨幅
+	AvgLoss      float64 `json:"avg_loss"`      // 下跌K线平均跌幅(负数)
+	ProfitFactor float64 `json:"profit_factor"` // 盈亏比：总涨幅/总跌幅绝对值，没有下跌样本时为0
+	Expectancy   float64 `json:"expectancy"`    // 期望收益：胜率×平均涨幅 + 败率×平均跌幅
+}
+
+// Significance 上涨率相对基准的统计显著性(单位: %)，嵌入各策略结果表
+type Significance struct {
+	Baseline          float64 `json:"baseline"`            // 基准上涨率：该交易对+周期全部K线的上涨率
+	CILower           float64 `json:"ci_lower"`            // 上涨率95% Wilson置信区间下限
+	CIUpper           float64 `json:"ci_upper"`            // 上涨率95% Wilson置信区间上限
+	PValue            float64 `json:"p_value"`             // 相对基准上涨率的精确二项检验双侧p值(0-1)
+	MinDetectableEdge float64 `json:"min_detectable_edge"` // 当前样本量下可检测的最小优势(百分点，α=0.05，功效80%)
+
+	// 多重比较校正：在同一次扫描的所有分组(如24小时、12个月)上计算
+	PValueBonferroni   float64 `json:"p_value_bonferroni"`  // Bonferroni 校正后的p值
+	PValueBH           float64 `json:"p_value_bh"`          // Benjamini–Hochberg 校正后的p值
+	SurvivesCorrection bool    `json:"survives_correction"` // BH校正后是否仍显著(p_value_bh<0.05)
+}
+
 // Strategy1Result 策略一分析结果表
 type Strategy1Result struct {
 	ID         int       `json:"id" gorm:"primaryKey"`
@@ -17,6 +42,8 @@ type Strategy1Result struct {
 	DownCount  int       `json:"down_count"`                                            // 下跌次数
 	FlatCount  int       `json:"flat_count"`                                            // 平盘次数
 	UpRate     float64   `json:"up_rate"`                                               // 上涨概率
+	ReturnStats
+	Significance
 	BestMonth  int       `json:"best_month"`                                            // 最佳月份
 	BestUpRate float64   `json:"best_up_rate"`                                          // 最佳月份上涨率
 	WorstMonth int       `json:"worst_month"`                                           // 最差月份
@@ -49,6 +76,8 @@ type Strategy2Result struct {
 	DownCount  int       `json:"down_count"`                                         // 下跌次数
 	FlatCount  int       `json:"flat_count"`                                         // 平盘次数
 	UpRate     float64   `json:"up_rate"`                                            // 上涨概率
+	ReturnStats
+	Significance
 	CreatedAt  time.Time `json:"created_at"`                                         // 创建时间
 	UpdatedAt  time.Time `json:"updated_at"`                                         // 更新时间
 }
@@ -65,3 +94,22 @@ type Strategy2DetailRecord struct {
 	CloseTime  time.Time `json:"close_time"`             // 收盘时间
 	CreatedAt  time.Time `json:"created_at"`             // 创建时间
 }
+
+// Strategy3Result 策略三分析结果表(星期效应)
+// Hour 为 -1 表示整天汇总，0-23 表示星期×小时（仅日内周期）
+type Strategy3Result struct {
+	ID         int       `json:"id" gorm:"primaryKey"`
+	Symbol     string    `json:"symbol" gorm:"index:idx_strategy3_unique,unique"`   // 交易对
+	Interval   string    `json:"interval" gorm:"index:idx_strategy3_unique,unique"` // 时间周期
+	Weekday    int       `json:"weekday" gorm:"index:idx_strategy3_unique,unique"`  // 星期(0=周日 ... 6=周六)
+	Hour       int       `json:"hour" gorm:"index:idx_strategy3_unique,unique"`     // 小时(0-23)，-1 表示整天
+	TotalCount int       `json:"total_count"`                                        // 总样本数
+	UpCount    int       `json:"up_count"`                                           // 上涨次数
+	DownCount  int       `json:"down_count"`                                         // 下跌次数
+	FlatCount  int       `json:"flat_count"`                                         // 平盘次数
+	UpRate     float64   `json:"up_rate"`                                            // 上涨概率
+	ReturnStats
+	Significance
+	CreatedAt  time.Time `json:"created_at"`                                         // 创建时间
+	UpdatedAt  time.Time `json:"updated_at"`                                         // 更新时间
+}
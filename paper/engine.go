@@ -0,0 +1,524 @@
+package paper
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"trade/model"
+	"trade/risk"
+	"trade/store"
+	"trade/strategy"
+	"trade/utils"
+)
+
+// DefaultAccountName 模拟盘默认账户名称
+const DefaultAccountName = "default"
+
+// RiskAccountName 模拟盘在风控中的账户名称
+const RiskAccountName = "paper"
+
+// FundingInterval 资金费结算间隔，币安永续合约在 00:00/08:00/16:00 UTC 结算
+const FundingInterval = 8 * time.Hour
+
+// 平仓原因
+const (
+	CloseReasonSignal      = "signal"      // 信号反转或转为中性
+	CloseReasonLiquidation = "liquidation" // 触及强平价
+	CloseReasonStopLoss    = "stop_loss"   // 触及止损价
+	CloseReasonTakeProfit  = "take_profit" // 触及止盈价
+)
+
+// orderStatusFilled 模拟盘市价单立即全部成交
+const orderStatusFilled = "FILLED"
+
+// FundingRateFunc 返回交易对在 at 时刻结算的资金费率，正数表示多头付给空头
+type FundingRateFunc func(symbol string, at time.Time) float64
+
+// Engine 模拟盘引擎：消费已收盘K线，按策略信号在下一根K线开盘(即本根收盘价)开平仓
+// 每个策略在每个交易对+周期上同时最多一个持仓，每笔的名义价值、杠杆和止盈止损按交易对配置
+type Engine struct {
+	mu sync.Mutex
+
+	config    model.TradingConfig
+	symbols   map[string]model.SymbolTrading // 交易对生效的交易参数
+	account   *model.PaperAccount
+	risk      *risk.Manager
+	providers map[string]strategy.SignalProvider
+	lastOpen  map[string]time.Time // symbol|interval -> 最后处理的K线开盘时间
+
+	// FundingRate 资金费率来源，默认使用配置中的固定费率
+	FundingRate FundingRateFunc
+}
+
+// NewEngine 按 config.Trading 创建模拟盘引擎，加载或创建默认账户
+func NewEngine(config *model.Config) (*Engine, error) {
+	if config == nil {
+		return nil, fmt.Errorf("配置不能为空")
+	}
+	trading := config.Trading
+	if len(trading.Strategies) == 0 {
+		return nil, fmt.Errorf("trading.strategies 不能为空")
+	}
+
+	symbols := make(map[string]model.SymbolTrading, len(config.Symbols))
+	providers := make(map[string]strategy.SignalProvider)
+	for _, t := range append([]model.SymbolTrading{trading.ForSymbol(model.SymbolConfig{})}, symbolTradings(config)...) {
+		if t.Symbol != "" {
+			symbols[t.Symbol] = t
+		}
+		for _, name := range t.Strategies {
+			if providers[name] != nil {
+				continue
+			}
+			provider, err := strategy.GetSignalProvider(name)
+			if err != nil {
+				return nil, err
+			}
+			providers[name] = provider
+		}
+	}
+
+	account, err := loadAccount(DefaultAccountName, trading.InitialBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Engine{
+		config:    trading,
+		symbols:   symbols,
+		account:   account,
+		risk:      risk.NewManager(RiskAccountName, config.Risk),
+		providers: providers,
+		lastOpen:  make(map[string]time.Time),
+		FundingRate: func(string, time.Time) float64 {
+			return trading.FundingRate
+		},
+	}, nil
+}
+
+// symbolTradings 返回 symbols 中每个交易对生效的交易参数
+func symbolTradings(config *model.Config) []model.SymbolTrading {
+	tradings := make([]model.SymbolTrading, 0, len(config.Symbols))
+	for _, symbolConfig := range config.Symbols {
+		tradings = append(tradings, config.Trading.ForSymbol(symbolConfig))
+	}
+	return tradings
+}
+
+// trading 返回交易对生效的交易参数，未配置的交易对使用全局默认值
+func (e *Engine) trading(symbol string) model.SymbolTrading {
+	if t, ok := e.symbols[symbol]; ok {
+		return t
+	}
+	return e.config.ForSymbol(model.SymbolConfig{Symbol: symbol})
+}
+
+// loadAccount 加载模拟盘账户，不存在时按初始资金创建
+func loadAccount(name string, initialBalance float64) (*model.PaperAccount, error) {
+	account, err := store.Default().GetPaperAccount(name)
+	if err != nil {
+		return nil, fmt.Errorf("加载模拟盘账户失败: %w", err)
+	}
+	if account != nil {
+		return account, nil
+	}
+
+	account = &model.PaperAccount{
+		Name:           name,
+		InitialBalance: initialBalance,
+		Balance:        initialBalance,
+	}
+	if err := store.Default().SavePaperAccount(account); err != nil {
+		return nil, fmt.Errorf("创建模拟盘账户失败: %w", err)
+	}
+	return account, nil
+}
+
+// Account 返回当前账户状态的副本
+func (e *Engine) Account() model.PaperAccount {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return *e.account
+}
+
+// OnKline 处理一根已收盘K线：结算资金费、检查强平和止盈止损、更新浮动盈亏，再按策略信号调仓并记录权益
+// 重复或乱序推送的K线会被忽略
+func (e *Engine) OnKline(kline model.Kline) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	key := kline.Symbol + "|" + kline.Interval
+	if last, ok := e.lastOpen[key]; ok && !kline.OpenTime.After(last) {
+		return nil
+	}
+	e.lastOpen[key] = kline.OpenTime
+
+	barEnd := utils.NextOpenTime(kline.OpenTime, kline.Interval)
+
+	positions, err := store.Default().ListPaperPositions(store.PaperPositionQuery{
+		AccountID: e.account.ID,
+		Symbol:    kline.Symbol,
+		Interval:  kline.Interval,
+		Status:    model.PositionStatusOpen,
+	})
+	if err != nil {
+		return fmt.Errorf("加载模拟盘持仓失败: %w", err)
+	}
+
+	open := make(map[string]*model.PaperPosition, len(positions))
+	for i := range positions {
+		p := &positions[i]
+		e.settleFunding(p, kline.Close, barEnd)
+
+		// 止损价比强平价更靠近开仓价，同一根K线都触及时先触发止损
+		price, reason := protectiveExit(p, kline)
+		if reason != CloseReasonStopLoss && liquidated(p, kline) {
+			if err := e.close(p, p.LiquidationPrice, barEnd, model.OrderActionLiquidation, CloseReasonLiquidation); err != nil {
+				return err
+			}
+			continue
+		}
+		if reason != "" {
+			if err := e.close(p, price, barEnd, model.OrderActionClose, reason); err != nil {
+				return err
+			}
+			continue
+		}
+
+		p.MarkPrice = kline.Close
+		p.UnrealizedPnL = unrealized(p, kline.Close)
+		if err := store.Default().SavePaperPosition(p); err != nil {
+			return fmt.Errorf("保存模拟盘持仓失败: %w", err)
+		}
+		open[p.Strategy] = p
+	}
+
+	// 信号针对下一根K线，只使用本根及之前已收盘的K线
+	var errs []error
+	trading := e.trading(kline.Symbol)
+	for _, name := range trading.Strategies {
+		provider := e.providers[name]
+		if !provider.Supports(kline.Interval) {
+			continue
+		}
+		signal, err := provider.Signal(kline.Symbol, kline.Interval, barEnd, barEnd)
+		if err != nil {
+			if !errors.Is(err, strategy.ErrNoData) {
+				errs = append(errs, fmt.Errorf("%s 生成信号失败: %w", provider.Name(), err))
+			}
+			continue
+		}
+
+		side := positionSide(signal)
+		current := open[provider.Name()]
+		if current != nil && current.Side != side {
+			if err := e.close(current, kline.Close, barEnd, model.OrderActionClose, CloseReasonSignal); err != nil {
+				return err
+			}
+			current = nil
+		}
+		if current == nil && side != "" {
+			if err := e.open(provider.Name(), side, trading, kline, barEnd); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := e.snapshot(barEnd); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// settleFunding 结算 LastFundingAt 之后、at 及之前的所有资金费时点，按 price 计算名义价值
+func (e *Engine) settleFunding(p *model.PaperPosition, price float64, at time.Time) {
+	next := p.LastFundingAt.UTC().Truncate(FundingInterval).Add(FundingInterval)
+	for !next.After(at) {
+		fee := direction(p.Side) * p.Quantity * price * e.FundingRate(p.Symbol, next)
+		p.FundingFee += fee
+		e.account.Balance -= fee
+		e.account.FundingFees += fee
+		p.LastFundingAt = next
+		next = next.Add(FundingInterval)
+	}
+}
+
+// open 按K线收盘价加滑点开仓，可用余额不足或被风控拒绝时跳过
+func (e *Engine) open(strategyName, side string, trading model.SymbolTrading, kline model.Kline, at time.Time) error {
+	fill := fillPrice(side, kline.Close, e.config.SlippageRate, true)
+	if fill <= 0 {
+		return nil
+	}
+	margin := trading.Margin()
+	leverage := float64(trading.Leverage)
+	quantity := trading.Notional / fill
+	fee := quantity * fill * e.config.FeeRate
+
+	symbolExposure, totalExposure, err := e.exposure(kline.Symbol)
+	if err != nil {
+		return err
+	}
+	err = e.risk.Check(risk.Request{
+		Symbol:         kline.Symbol,
+		Interval:       kline.Interval,
+		Strategy:       strategyName,
+		Side:           side,
+		Notional:       trading.Notional,
+		SymbolExposure: symbolExposure,
+		TotalExposure:  totalExposure,
+		MaxPosition:    trading.MaxPosition,
+	}, at)
+	if errors.Is(err, risk.ErrRejected) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	used, err := e.usedMargin()
+	if err != nil {
+		return err
+	}
+	if available := e.account.Balance - used; available < margin+fee {
+		fmt.Printf("⚠️  模拟盘可用余额不足，跳过开仓 %s %s %s: 可用 %.2f，需要 %.2f\n",
+			strategyName, kline.Symbol, side, available, margin+fee)
+		return nil
+	}
+
+	p := &model.PaperPosition{
+		AccountID:        e.account.ID,
+		Symbol:           kline.Symbol,
+		Interval:         kline.Interval,
+		Strategy:         strategyName,
+		Side:             side,
+		Status:           model.PositionStatusOpen,
+		Quantity:         quantity,
+		EntryPrice:       fill,
+		Margin:           margin,
+		Leverage:         trading.Leverage,
+		LiquidationPrice: fill * (1 - direction(side)/leverage),
+		StopLossPrice:    trading.StopLossPrice(fill, direction(side)),
+		TakeProfitPrice:  trading.TakeProfitPrice(fill, direction(side)),
+		MarkPrice:        kline.Close,
+		UnrealizedPnL:    unrealized(&model.PaperPosition{Side: side, Quantity: quantity, EntryPrice: fill}, kline.Close),
+		Fees:             fee,
+		OpenedAt:         at,
+		LastFundingAt:    at,
+	}
+	if err := store.Default().SavePaperPosition(p); err != nil {
+		return fmt.Errorf("保存模拟盘持仓失败: %w", err)
+	}
+
+	e.account.Balance -= fee
+	e.account.Fees += fee
+	if err := e.record(p, model.OrderActionOpen, kline.Close, fill, fee, 0, at); err != nil {
+		return err
+	}
+
+	fmt.Printf("📈 模拟盘开仓 %s %s %s %s: 数量 %.6f 价格 %.4f 保证金 %.2f 杠杆 %dx\n",
+		strategyName, kline.Symbol, kline.Interval, side, quantity, fill, margin, trading.Leverage)
+	return nil
+}
+
+// close 以 price 平仓，强平时成交价即强平价，不再计滑点
+func (e *Engine) close(p *model.PaperPosition, price float64, at time.Time, action, reason string) error {
+	fill := price
+	if action != model.OrderActionLiquidation {
+		fill = fillPrice(p.Side, price, e.config.SlippageRate, false)
+	}
+
+	fee := p.Quantity * fill * e.config.FeeRate
+	pnl := unrealized(p, fill)
+	closedAt := at
+
+	p.Status = model.PositionStatusClosed
+	p.ExitPrice = fill
+	p.MarkPrice = fill
+	p.UnrealizedPnL = 0
+	p.RealizedPnL = pnl
+	p.Fees += fee
+	p.CloseReason = reason
+	p.ClosedAt = &closedAt
+	if err := store.Default().SavePaperPosition(p); err != nil {
+		return fmt.Errorf("保存模拟盘持仓失败: %w", err)
+	}
+
+	e.account.Balance += pnl - fee
+	e.account.RealizedPnL += pnl
+	e.account.Fees += fee
+	if err := e.record(p, action, price, fill, fee, pnl, at); err != nil {
+		return err
+	}
+	// 风控按扣除手续费和资金费后的净盈亏计算
+	if err := e.risk.RecordClose(pnl-p.Fees-p.FundingFee, at); err != nil {
+		return err
+	}
+
+	fmt.Printf("📉 模拟盘平仓(%s) %s %s %s %s: 价格 %.4f 盈亏 %+.4f 手续费 %.4f 资金费 %.4f\n",
+		reason, p.Strategy, p.Symbol, p.Interval, p.Side, fill, pnl, p.Fees, p.FundingFee)
+	return nil
+}
+
+// record 保存订单、成交和账户
+func (e *Engine) record(p *model.PaperPosition, action string, price, fill, fee, pnl float64, at time.Time) error {
+	side := model.OrderSideBuy
+	if (p.Side == model.PositionSideLong) != (action == model.OrderActionOpen) {
+		side = model.OrderSideSell
+	}
+
+	order := &model.PaperOrder{
+		AccountID:  e.account.ID,
+		PositionID: p.ID,
+		Symbol:     p.Symbol,
+		Interval:   p.Interval,
+		Strategy:   p.Strategy,
+		Side:       side,
+		Action:     action,
+		Quantity:   p.Quantity,
+		Price:      price,
+		Status:     orderStatusFilled,
+		CreatedAt:  at,
+	}
+	fillRecord := &model.PaperFill{
+		AccountID:   e.account.ID,
+		PositionID:  p.ID,
+		Symbol:      p.Symbol,
+		Side:        side,
+		Quantity:    p.Quantity,
+		Price:       fill,
+		Fee:         fee,
+		RealizedPnL: pnl,
+		FilledAt:    at,
+	}
+	if err := store.Default().SavePaperOrder(order, fillRecord); err != nil {
+		return fmt.Errorf("保存模拟盘订单失败: %w", err)
+	}
+	if err := store.Default().SavePaperAccount(e.account); err != nil {
+		return fmt.Errorf("保存模拟盘账户失败: %w", err)
+	}
+	return nil
+}
+
+// usedMargin 返回全部持仓占用的保证金
+func (e *Engine) usedMargin() (float64, error) {
+	positions, err := store.Default().ListPaperPositions(store.PaperPositionQuery{
+		AccountID: e.account.ID,
+		Status:    model.PositionStatusOpen,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("加载模拟盘持仓失败: %w", err)
+	}
+	used := 0.0
+	for _, p := range positions {
+		used += p.Margin
+	}
+	return used, nil
+}
+
+// exposure 返回交易对及全部持仓按开仓价计算的名义价值
+func (e *Engine) exposure(symbol string) (float64, float64, error) {
+	positions, err := store.Default().ListPaperPositions(store.PaperPositionQuery{
+		AccountID: e.account.ID,
+		Status:    model.PositionStatusOpen,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("加载模拟盘持仓失败: %w", err)
+	}
+	symbolExposure, totalExposure := 0.0, 0.0
+	for _, p := range positions {
+		notional := p.Quantity * p.EntryPrice
+		totalExposure += notional
+		if p.Symbol == symbol {
+			symbolExposure += notional
+		}
+	}
+	return symbolExposure, totalExposure, nil
+}
+
+// snapshot 保存账户权益快照，其他交易对的持仓按最近一次标记价格计算
+func (e *Engine) snapshot(at time.Time) error {
+	positions, err := store.Default().ListPaperPositions(store.PaperPositionQuery{
+		AccountID: e.account.ID,
+		Status:    model.PositionStatusOpen,
+	})
+	if err != nil {
+		return fmt.Errorf("加载模拟盘持仓失败: %w", err)
+	}
+
+	point := &model.PaperEquity{
+		AccountID:     e.account.ID,
+		Time:          at,
+		Balance:       e.account.Balance,
+		OpenPositions: len(positions),
+	}
+	for _, p := range positions {
+		point.UnrealizedPnL += p.UnrealizedPnL
+		point.UsedMargin += p.Margin
+	}
+	point.Equity = point.Balance + point.UnrealizedPnL
+
+	if err := store.Default().SavePaperAccount(e.account); err != nil {
+		return fmt.Errorf("保存模拟盘账户失败: %w", err)
+	}
+	if err := store.Default().SavePaperEquity(point); err != nil {
+		return fmt.Errorf("保存模拟盘权益失败: %w", err)
+	}
+	return nil
+}
+
+// positionSide 信号对应的持仓方向，中性返回空字符串
+func positionSide(signal string) string {
+	switch signal {
+	case strategy.SignalBullish:
+		return model.PositionSideLong
+	case strategy.SignalBearish:
+		return model.PositionSideShort
+	default:
+		return ""
+	}
+}
+
+// direction 多头为1，空头为-1
+func direction(side string) float64 {
+	if side == model.PositionSideShort {
+		return -1
+	}
+	return 1
+}
+
+// unrealized 按 price 计算的浮动盈亏
+func unrealized(p *model.PaperPosition, price float64) float64 {
+	return direction(p.Side) * p.Quantity * (price - p.EntryPrice)
+}
+
+// liquidated K线最低价(多头)或最高价(空头)是否触及强平价
+func liquidated(p *model.PaperPosition, kline model.Kline) bool {
+	if p.Side == model.PositionSideShort {
+		return kline.High >= p.LiquidationPrice
+	}
+	return kline.Low <= p.LiquidationPrice
+}
+
+// protectiveExit K线是否触及止损价或止盈价，返回触发价格和平仓原因
+// 同一根K线同时触及两者时按止损处理
+func protectiveExit(p *model.PaperPosition, kline model.Kline) (float64, string) {
+	short := p.Side == model.PositionSideShort
+	if p.StopLossPrice > 0 && ((!short && kline.Low <= p.StopLossPrice) || (short && kline.High >= p.StopLossPrice)) {
+		return p.StopLossPrice, CloseReasonStopLoss
+	}
+	if p.TakeProfitPrice > 0 && ((!short && kline.High >= p.TakeProfitPrice) || (short && kline.Low <= p.TakeProfitPrice)) {
+		return p.TakeProfitPrice, CloseReasonTakeProfit
+	}
+	return 0, ""
+}
+
+// fillPrice 成交价计入滑点：买入价格上浮，卖出价格下浮
+func fillPrice(side string, price, slippage float64, opening bool) float64 {
+	buying := (side == model.PositionSideLong) == opening
+	if buying {
+		return price * (1 + slippage)
+	}
+	return price * (1 - slippage)
+}
@@ -0,0 +1,291 @@
+package paper
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"trade/model"
+	"trade/store"
+	"trade/strategy"
+)
+
+// scriptedStrategy 按下一根K线开盘时间返回预设信号的测试策略
+type scriptedStrategy struct {
+	signals map[time.Time]string
+}
+
+var scripted = &scriptedStrategy{}
+
+func init() {
+	strategy.Register(scripted)
+}
+
+func (s *scriptedStrategy) Name() string                 { return "paper_scripted" }
+func (s *scriptedStrategy) Description() string          { return "测试用预设信号" }
+func (s *scriptedStrategy) Parameters() []strategy.Param { return nil }
+func (s *scriptedStrategy) Run(*model.Config, time.Time) {}
+func (s *scriptedStrategy) Supports(interval string) bool {
+	return interval == "1h"
+}
+
+func (s *scriptedStrategy) Analyze(context.Context, *strategy.Input) (*strategy.Result, error) {
+	return nil, strategy.ErrNoData
+}
+
+func (s *scriptedStrategy) Signal(symbol, interval string, openTime, asOf time.Time) (string, error) {
+	if signal, ok := s.signals[openTime]; ok {
+		return signal, nil
+	}
+	return strategy.SignalNeutral, nil
+}
+
+var base = time.Date(2024, 1, 1, 5, 0, 0, 0, time.UTC)
+
+// hourKline 生成 base 之后第 n 根小时K线
+func hourKline(n int, open, high, low, close float64) model.Kline {
+	openTime := base.Add(time.Duration(n) * time.Hour)
+	return model.Kline{
+		Symbol:    "BTCUSDT",
+		Interval:  "1h",
+		OpenTime:  openTime,
+		CloseTime: openTime.Add(time.Hour - time.Millisecond),
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+	}
+}
+
+// newTestEngine 使用内存存储和预设信号创建引擎，无滑点、无资金费
+func newTestEngine(t *testing.T, signals map[int]string, symbols ...model.SymbolConfig) *Engine {
+	t.Helper()
+	store.SetDefault(store.NewMemoryStore())
+	t.Cleanup(func() { store.SetDefault(nil) })
+
+	scripted.signals = make(map[time.Time]string)
+	for n, signal := range signals {
+		scripted.signals[base.Add(time.Duration(n)*time.Hour)] = signal
+	}
+
+	config := &model.Config{Trading: model.TradingConfig{
+		Strategies:     []string{scripted.Name()},
+		Margin:         5,
+		Leverage:       10,
+		FeeRate:        0.001,
+		InitialBalance: 1000,
+	}, Symbols: symbols}
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("创建引擎失败: %v", err)
+	}
+	return engine
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEngineOpenAndFlip(t *testing.T) {
+	// 第0根收盘后看多，第1、2根收盘后看空
+	engine := newTestEngine(t, map[int]string{1: strategy.SignalBullish, 2: strategy.SignalBearish, 3: strategy.SignalBearish})
+
+	for n, k := range []model.Kline{
+		hourKline(0, 100, 101, 99, 100),
+		hourKline(1, 100, 111, 99, 110),
+		hourKline(2, 110, 111, 104, 105),
+	} {
+		if err := engine.OnKline(k); err != nil {
+			t.Fatalf("第%d根K线处理失败: %v", n, err)
+		}
+	}
+
+	account := engine.Account()
+	positions, _ := store.Default().ListPaperPositions(store.PaperPositionQuery{AccountID: account.ID})
+	if len(positions) != 2 {
+		t.Fatalf("应有一笔已平多单和一笔空单，实际 %d", len(positions))
+	}
+
+	// 多单：5U×10倍 / 100 = 0.5 个，100 -> 110 盈利 5U
+	long := positions[1]
+	if long.Side != model.PositionSideLong || long.Status != model.PositionStatusClosed || !almostEqual(long.RealizedPnL, 5) {
+		t.Errorf("多单错误: %+v", long)
+	}
+	if !almostEqual(long.Fees, 0.5*100*0.001+0.5*110*0.001) {
+		t.Errorf("多单手续费错误: %v", long.Fees)
+	}
+
+	// 空单：50U / 110 个，110 -> 105 浮盈
+	short := positions[0]
+	if short.Side != model.PositionSideShort || short.Status != model.PositionStatusOpen {
+		t.Fatalf("空单错误: %+v", short)
+	}
+	if want := 50.0 / 110 * 5; !almostEqual(short.UnrealizedPnL, want) {
+		t.Errorf("空单浮盈应为 %v，实际 %v", want, short.UnrealizedPnL)
+	}
+
+	wantBalance := 1000 + 5 - long.Fees - short.Fees
+	if !almostEqual(account.Balance, wantBalance) || !almostEqual(account.RealizedPnL, 5) {
+		t.Errorf("账户余额应为 %v，实际 %+v", wantBalance, account)
+	}
+
+	orders, _ := store.Default().ListPaperOrders(account.ID, "", 0)
+	if len(orders) != 3 {
+		t.Fatalf("应有开多、平多、开空三笔订单，实际 %d", len(orders))
+	}
+	if orders[0].Action != model.OrderActionOpen || orders[0].Side != model.OrderSideSell ||
+		orders[1].Action != model.OrderActionClose || orders[1].Side != model.OrderSideSell {
+		t.Errorf("订单顺序或方向错误: %+v", orders)
+	}
+	if fills, _ := store.Default().ListPaperFills(orders[1].ID); len(fills) != 1 || !almostEqual(fills[0].RealizedPnL, 5) {
+		t.Errorf("平仓成交错误: %+v", fills)
+	}
+
+	equity, _ := store.Default().ListPaperEquity(account.ID, time.Time{}, time.Time{})
+	if len(equity) != 3 {
+		t.Fatalf("每根K线应记录一次权益，实际 %d", len(equity))
+	}
+	if last := equity[2]; !almostEqual(last.Equity, account.Balance+short.UnrealizedPnL) || last.OpenPositions != 1 {
+		t.Errorf("最后权益错误: %+v", last)
+	}
+
+	// 重复推送的K线被忽略
+	if err := engine.OnKline(hourKline(2, 110, 111, 104, 105)); err != nil {
+		t.Fatal(err)
+	}
+	if equity, _ := store.Default().ListPaperEquity(account.ID, time.Time{}, time.Time{}); len(equity) != 3 {
+		t.Errorf("重复K线不应再记录权益，实际 %d", len(equity))
+	}
+}
+
+func TestEngineSignalTimeWithStoredCloseTime(t *testing.T) {
+	// 入库的收盘时间截断到秒(05:59:59)，信号仍应针对下一根K线的开盘时间 06:00
+	engine := newTestEngine(t, map[int]string{1: strategy.SignalBullish})
+	k := hourKline(0, 100, 101, 99, 100)
+	k.CloseTime = k.OpenTime.Add(time.Hour - time.Second)
+	if err := engine.OnKline(k); err != nil {
+		t.Fatal(err)
+	}
+
+	positions, _ := store.Default().ListPaperPositions(store.PaperPositionQuery{AccountID: engine.Account().ID})
+	if len(positions) != 1 || positions[0].Side != model.PositionSideLong || !positions[0].OpenedAt.Equal(base.Add(time.Hour)) {
+		t.Fatalf("应按 06:00 的信号开多: %+v", positions)
+	}
+}
+
+func TestEngineLiquidation(t *testing.T) {
+	engine := newTestEngine(t, map[int]string{1: strategy.SignalBullish, 2: strategy.SignalBullish})
+
+	engine.OnKline(hourKline(0, 100, 101, 99, 100))
+	// 10倍多单强平价为 90
+	engine.OnKline(hourKline(1, 100, 101, 89, 95))
+
+	account := engine.Account()
+	positions, _ := store.Default().ListPaperPositions(store.PaperPositionQuery{AccountID: account.ID})
+	var liquidatedPos *model.PaperPosition
+	for i := range positions {
+		if positions[i].CloseReason == CloseReasonLiquidation {
+			liquidatedPos = &positions[i]
+		}
+	}
+	if liquidatedPos == nil || !almostEqual(liquidatedPos.ExitPrice, 90) || !almostEqual(liquidatedPos.RealizedPnL, -5) {
+		t.Fatalf("应在90强平并亏损全部保证金: %+v", positions)
+	}
+
+	// 信号仍然看多，强平后按收盘价重新开仓
+	open, _ := store.Default().ListPaperPositions(store.PaperPositionQuery{AccountID: account.ID, Status: model.PositionStatusOpen})
+	if len(open) != 1 || !almostEqual(open[0].EntryPrice, 95) {
+		t.Errorf("应按95重新开多: %+v", open)
+	}
+}
+
+func TestEngineFunding(t *testing.T) {
+	engine := newTestEngine(t, map[int]string{1: strategy.SignalBullish, 2: strategy.SignalBullish, 3: strategy.SignalBullish})
+	engine.FundingRate = func(symbol string, at time.Time) float64 { return 0.001 }
+
+	// 05:00 开始，06:00 开多，08:00 结算一次资金费
+	engine.OnKline(hourKline(0, 100, 101, 99, 100))
+	engine.OnKline(hourKline(1, 100, 101, 99, 100))
+	engine.OnKline(hourKline(2, 100, 101, 99, 120))
+
+	account := engine.Account()
+	open, _ := store.Default().ListPaperPositions(store.PaperPositionQuery{AccountID: account.ID, Status: model.PositionStatusOpen})
+	if len(open) != 1 {
+		t.Fatalf("应持有一笔多单: %+v", open)
+	}
+	// 0.5 个 × 120 × 0.1%
+	if want := 0.5 * 120 * 0.001; !almostEqual(open[0].FundingFee, want) || !almostEqual(account.FundingFees, want) {
+		t.Errorf("资金费应为 %v，实际 持仓 %v 账户 %v", want, open[0].FundingFee, account.FundingFees)
+	}
+	if !open[0].LastFundingAt.Equal(time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("最后结算时间错误: %v", open[0].LastFundingAt)
+	}
+}
+
+func TestEngineSymbolTrading(t *testing.T) {
+	// BTCUSDT 20倍、每笔 100U，止损 2%，止盈 3%
+	engine := newTestEngine(t, map[int]string{1: strategy.SignalBullish, 2: strategy.SignalBullish, 3: strategy.SignalBearish},
+		model.SymbolConfig{Symbol: "BTCUSDT", Leverage: 20, Notional: 100, StopLossPct: 2, TakeProfitPct: 3})
+
+	engine.OnKline(hourKline(0, 100, 101, 99, 100))
+	account := engine.Account()
+	positions, _ := store.Default().ListPaperPositions(store.PaperPositionQuery{AccountID: account.ID})
+	if len(positions) != 1 {
+		t.Fatalf("应开一笔多单，实际 %d", len(positions))
+	}
+	long := positions[0]
+	if long.Leverage != 20 || !almostEqual(long.Margin, 5) || !almostEqual(long.Quantity, 1) ||
+		!almostEqual(long.StopLossPrice, 98) || !almostEqual(long.TakeProfitPrice, 103) {
+		t.Errorf("多单应按交易对参数开仓: %+v", long)
+	}
+
+	// 最高价触及止盈 103，按止盈价平仓，信号仍看多则重新开仓
+	engine.OnKline(hourKline(1, 100, 104, 99.5, 102))
+	positions, _ = store.Default().ListPaperPositions(store.PaperPositionQuery{AccountID: account.ID})
+	if len(positions) != 2 || positions[1].CloseReason != CloseReasonTakeProfit || !almostEqual(positions[1].RealizedPnL, 3) {
+		t.Fatalf("应止盈平仓并重新开多: %+v", positions)
+	}
+
+	// 最低价跌破止损 102×0.98=99.96
+	engine.OnKline(hourKline(2, 102, 102, 99, 100))
+	positions, _ = store.Default().ListPaperPositions(store.PaperPositionQuery{AccountID: account.ID})
+	stopped := positions[1]
+	if stopped.CloseReason != CloseReasonStopLoss || !almostEqual(stopped.ExitPrice, 102*0.98) {
+		t.Errorf("应按止损价平仓: %+v", stopped)
+	}
+	if positions[0].Side != model.PositionSideShort || positions[0].Status != model.PositionStatusOpen {
+		t.Errorf("止损后按看空信号开空: %+v", positions[0])
+	}
+}
+
+func TestEngineStopLossBeforeLiquidation(t *testing.T) {
+	// 20倍多单强平价 95，止损 2% 在 98
+	engine := newTestEngine(t, map[int]string{1: strategy.SignalBullish},
+		model.SymbolConfig{Symbol: "BTCUSDT", Leverage: 20, Notional: 100, StopLossPct: 2})
+
+	engine.OnKline(hourKline(0, 100, 101, 99, 100))
+	// 最低价同时跌破止损价和强平价，价格先经过止损价
+	engine.OnKline(hourKline(1, 100, 101, 90, 96))
+
+	account := engine.Account()
+	positions, _ := store.Default().ListPaperPositions(store.PaperPositionQuery{AccountID: account.ID})
+	if len(positions) != 1 {
+		t.Fatalf("应只有一笔持仓: %+v", positions)
+	}
+	stopped := positions[0]
+	if stopped.CloseReason != CloseReasonStopLoss || !almostEqual(stopped.ExitPrice, 98) || !almostEqual(stopped.RealizedPnL, -2) {
+		t.Errorf("应按止损价平仓而不是强平: %+v", stopped)
+	}
+}
+
+func TestEngineMaxPosition(t *testing.T) {
+	engine := newTestEngine(t, map[int]string{1: strategy.SignalBullish},
+		model.SymbolConfig{Symbol: "BTCUSDT", Notional: 50, MaxPosition: 40})
+
+	engine.OnKline(hourKline(0, 100, 101, 99, 100))
+	positions, _ := store.Default().ListPaperPositions(store.PaperPositionQuery{AccountID: engine.Account().ID})
+	if len(positions) != 0 {
+		t.Errorf("超过最大持仓时不应开仓: %+v", positions)
+	}
+}
@@ -0,0 +1,115 @@
+package positioning
+
+import (
+	"time"
+
+	"trade/model"
+	"trade/store"
+)
+
+// AlignedKline 一根K线及统计时间与其开盘时间相同的持仓统计，没有对应统计的字段为 nil
+type AlignedKline struct {
+	OpenTime          time.Time `json:"open_time"`
+	Open              float64   `json:"open"`
+	High              float64   `json:"high"`
+	Low               float64   `json:"low"`
+	Close             float64   `json:"close"`
+	Volume            float64   `json:"volume"`
+	OpenInterest      *float64  `json:"open_interest"`        // 持仓总数量
+	OpenInterestValue *float64  `json:"open_interest_value"`  // 持仓总价值
+	TopAccountRatio   *float64  `json:"top_account_ratio"`    // 大户账户数多空比
+	TopPositionRatio  *float64  `json:"top_position_ratio"`   // 大户持仓量多空比
+	TakerBuySellRatio *float64  `json:"taker_buy_sell_ratio"` // 主动买卖量比
+	TakerBuyVolume    *float64  `json:"taker_buy_volume"`     // 主动买入量
+	TakerSellVolume   *float64  `json:"taker_sell_volume"`    // 主动卖出量
+}
+
+// Coverage 对齐结果中各类统计覆盖的K线数量
+type Coverage struct {
+	Klines       int `json:"klines"`
+	OpenInterest int `json:"open_interest"`
+	TopAccount   int `json:"top_account"`
+	TopPosition  int `json:"top_position"`
+	TakerVolume  int `json:"taker_volume"`
+}
+
+// Align 查询开盘时间在 [start, end] 内的K线，并按开盘时间关联同周期的持仓统计
+func Align(symbol, interval string, start, end time.Time) ([]AlignedKline, Coverage, error) {
+	var coverage Coverage
+	s := store.Default()
+	klines, err := s.FindKlines(store.KlineQuery{Symbol: symbol, Interval: interval, Start: start, End: end})
+	if err != nil {
+		return nil, coverage, err
+	}
+
+	rows := make([]AlignedKline, 0, len(klines))
+	index := make(map[int64]int, len(klines))
+	for i, k := range klines {
+		index[k.OpenTime.UnixMilli()] = i
+		rows = append(rows, AlignedKline{
+			OpenTime: k.OpenTime,
+			Open:     k.Open,
+			High:     k.High,
+			Low:      k.Low,
+			Close:    k.Close,
+			Volume:   k.Volume,
+		})
+	}
+	coverage.Klines = len(rows)
+	if len(rows) == 0 || !SupportsInterval(interval) {
+		return rows, coverage, nil
+	}
+
+	// 统计查询是半开区间，结束时间顺延一个毫秒以包含最后一根K线
+	first, last := klines[0].OpenTime, klines[len(klines)-1].OpenTime.Add(time.Millisecond)
+
+	points, err := s.FindOpenInterest(symbol, interval, first, last)
+	if err != nil {
+		return nil, coverage, err
+	}
+	for _, point := range points {
+		if i, ok := index[point.Time.UnixMilli()]; ok {
+			rows[i].OpenInterest = float64Ptr(point.SumOpenInterest)
+			rows[i].OpenInterestValue = float64Ptr(point.SumOpenInterestValue)
+			coverage.OpenInterest++
+		}
+	}
+
+	for _, kind := range []string{model.LongShortKindTopAccount, model.LongShortKindTopPosition} {
+		ratios, err := s.FindLongShortRatios(symbol, interval, kind, first, last)
+		if err != nil {
+			return nil, coverage, err
+		}
+		for _, ratio := range ratios {
+			i, ok := index[ratio.Time.UnixMilli()]
+			if !ok {
+				continue
+			}
+			if kind == model.LongShortKindTopAccount {
+				rows[i].TopAccountRatio = float64Ptr(ratio.LongShortRatio)
+				coverage.TopAccount++
+			} else {
+				rows[i].TopPositionRatio = float64Ptr(ratio.LongShortRatio)
+				coverage.TopPosition++
+			}
+		}
+	}
+
+	volumes, err := s.FindTakerVolumes(symbol, interval, first, last)
+	if err != nil {
+		return nil, coverage, err
+	}
+	for _, volume := range volumes {
+		if i, ok := index[volume.Time.UnixMilli()]; ok {
+			rows[i].TakerBuySellRatio = float64Ptr(volume.BuySellRatio)
+			rows[i].TakerBuyVolume = float64Ptr(volume.BuyVolume)
+			rows[i].TakerSellVolume = float64Ptr(volume.SellVolume)
+			coverage.TakerVolume++
+		}
+	}
+	return rows, coverage, nil
+}
+
+func float64Ptr(value float64) *float64 {
+	return &value
+}
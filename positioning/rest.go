@@ -0,0 +1,293 @@
+// Package positioning 持仓量、大户多空比和主动买卖量比的拉取、入库以及与K线对齐
+package positioning
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trade/db"
+	"trade/model"
+	"trade/store"
+	"trade/utils"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+// 币安 /futures/data 接口每次最多返回 500 条，且只保留最近 30 天的数据
+const pageLimit = 500
+
+// Retention 交易所保留的历史长度，更早的缺口无法再补齐
+const Retention = 30 * 24 * time.Hour
+
+// supportedPeriods /futures/data 接口支持的统计周期
+var supportedPeriods = map[string]bool{
+	"5m": true, "15m": true, "30m": true, "1h": true, "2h": true,
+	"4h": true, "6h": true, "12h": true, "1d": true,
+}
+
+// SupportsInterval 判断K线周期是否有对应的持仓统计周期
+func SupportsInterval(interval string) bool {
+	return supportedPeriods[interval]
+}
+
+// series 一类持仓统计的查询和拉取方式
+type series struct {
+	name  string
+	times func(symbol, period string, start, end time.Time) ([]time.Time, error)
+	// fetch 拉取 [start, end] 内从 start 起的一页并写入，返回本页条数和最后一条的统计时间
+	fetch func(api *futures.Client, symbol, period string, start, end time.Time) (int, time.Time, error)
+}
+
+var allSeries = []series{
+	{name: "持仓量", times: openInterestTimes, fetch: fetchOpenInterest},
+	{name: "大户账户多空比", times: longShortTimes(model.LongShortKindTopAccount), fetch: fetchTopAccountRatio},
+	{name: "大户持仓多空比", times: longShortTimes(model.LongShortKindTopPosition), fetch: fetchTopPositionRatio},
+	{name: "主动买卖量", times: takerVolumeTimes, fetch: fetchTakerVolume},
+}
+
+// UpdatePositioning 更新一个交易对+周期的全部持仓统计
+// 只补齐交易所保留期内缺失的统计时间段，已入库的时间点不会重复请求
+func UpdatePositioning(symbol, interval string) {
+	if !SupportsInterval(interval) {
+		fmt.Printf("%s 周期 %s 没有持仓统计数据，跳过\n", symbol, interval)
+		return
+	}
+	updatePositioning(db.BinanceClient, symbol, interval, time.Now())
+}
+
+// updatePositioning 以 now 为当前时间补齐每类统计的缺口，中途失败的统计类型下次重新计算缺口继续
+func updatePositioning(api *futures.Client, symbol, period string, now time.Time) {
+	step := utils.IntervalDuration(period)
+	// 只统计已完整结束的周期，当前周期的数据可能尚未发布
+	end := now.UTC().Truncate(step).Add(-step)
+	start := now.UTC().Add(-Retention).Truncate(step).Add(step)
+
+	for _, item := range allSeries {
+		times, err := item.times(symbol, period, start, end.Add(step))
+		if err != nil {
+			fmt.Printf("查询 %s %s %s 失败: %v\n", symbol, period, item.name, err)
+			continue
+		}
+
+		total := 0
+		failed := false
+		for _, gap := range MissingRanges(times, start, end, step) {
+			count, err := fetchRange(api, item, symbol, period, gap[0], gap[1])
+			total += count
+			if err != nil {
+				fmt.Printf("获取 %s %s %s 失败: %v\n", symbol, period, item.name, err)
+				failed = true
+				break
+			}
+		}
+		if !failed {
+			fmt.Printf("%s %s %s 更新完成，共写入 %d 条\n", symbol, period, item.name, total)
+		}
+	}
+}
+
+// fetchRange 分页拉取 [start, end] 内的统计，每页写入成功后再请求下一页
+func fetchRange(api *futures.Client, item series, symbol, period string, start, end time.Time) (int, error) {
+	total := 0
+	for !start.After(end) {
+		count, last, err := item.fetch(api, symbol, period, start, end)
+		if err != nil {
+			return total, err
+		}
+		total += count
+		if count < pageLimit {
+			break
+		}
+		start = last.Add(time.Millisecond)
+
+		// 添加延迟避免API限制
+		time.Sleep(100 * time.Millisecond)
+	}
+	return total, nil
+}
+
+// MissingRanges 返回 [start, end] 内按 step 对齐、但不在 times 中的连续时间段（闭区间）
+// times 需按升序排列
+func MissingRanges(times []time.Time, start, end time.Time, step time.Duration) [][2]time.Time {
+	existing := make(map[int64]bool, len(times))
+	for _, t := range times {
+		existing[t.UnixMilli()] = true
+	}
+
+	var ranges [][2]time.Time
+	var gapStart time.Time
+	inGap := false
+	for t := start; !t.After(end); t = t.Add(step) {
+		if existing[t.UnixMilli()] {
+			if inGap {
+				ranges = append(ranges, [2]time.Time{gapStart, t.Add(-step)})
+				inGap = false
+			}
+			continue
+		}
+		if !inGap {
+			gapStart = t
+			inGap = true
+		}
+	}
+	if inGap {
+		ranges = append(ranges, [2]time.Time{gapStart, end})
+	}
+	return ranges
+}
+
+func openInterestTimes(symbol, period string, start, end time.Time) ([]time.Time, error) {
+	points, err := store.Default().FindOpenInterest(symbol, period, start, end)
+	if err != nil {
+		return nil, err
+	}
+	times := make([]time.Time, 0, len(points))
+	for _, point := range points {
+		times = append(times, point.Time)
+	}
+	return times, nil
+}
+
+func longShortTimes(kind string) func(symbol, period string, start, end time.Time) ([]time.Time, error) {
+	return func(symbol, period string, start, end time.Time) ([]time.Time, error) {
+		ratios, err := store.Default().FindLongShortRatios(symbol, period, kind, start, end)
+		if err != nil {
+			return nil, err
+		}
+		times := make([]time.Time, 0, len(ratios))
+		for _, ratio := range ratios {
+			times = append(times, ratio.Time)
+		}
+		return times, nil
+	}
+}
+
+func takerVolumeTimes(symbol, period string, start, end time.Time) ([]time.Time, error) {
+	volumes, err := store.Default().FindTakerVolumes(symbol, period, start, end)
+	if err != nil {
+		return nil, err
+	}
+	times := make([]time.Time, 0, len(volumes))
+	for _, volume := range volumes {
+		times = append(times, volume.Time)
+	}
+	return times, nil
+}
+
+func fetchOpenInterest(api *futures.Client, symbol, period string, start, end time.Time) (int, time.Time, error) {
+	stats, err := api.NewOpenInterestStatisticsService().
+		Symbol(symbol).
+		Period(period).
+		StartTime(start.UnixMilli()).
+		EndTime(end.UnixMilli()).
+		Limit(pageLimit).
+		Do(context.Background())
+	if err != nil || len(stats) == 0 {
+		return 0, time.Time{}, err
+	}
+
+	points := make([]model.OpenInterest, 0, len(stats))
+	for _, stat := range stats {
+		points = append(points, model.OpenInterest{
+			Symbol:               symbol,
+			Period:               period,
+			Time:                 time.UnixMilli(stat.Timestamp).UTC(),
+			SumOpenInterest:      utils.StringToFloat64(stat.SumOpenInterest),
+			SumOpenInterestValue: utils.StringToFloat64(stat.SumOpenInterestValue),
+		})
+	}
+	if _, err := store.Default().UpsertOpenInterest(points); err != nil {
+		return 0, time.Time{}, err
+	}
+	return len(points), points[len(points)-1].Time, nil
+}
+
+func fetchTopAccountRatio(api *futures.Client, symbol, period string, start, end time.Time) (int, time.Time, error) {
+	stats, err := api.NewTopLongShortAccountRatioService().
+		Symbol(symbol).
+		Period(period).
+		StartTime(uint64(start.UnixMilli())).
+		EndTime(uint64(end.UnixMilli())).
+		Limit(pageLimit).
+		Do(context.Background())
+	if err != nil || len(stats) == 0 {
+		return 0, time.Time{}, err
+	}
+
+	ratios := make([]model.LongShortRatio, 0, len(stats))
+	for _, stat := range stats {
+		ratios = append(ratios, buildLongShortRatio(symbol, period, model.LongShortKindTopAccount,
+			stat.Timestamp, stat.LongShortRatio, stat.LongAccount, stat.ShortAccount))
+	}
+	return saveLongShortRatios(ratios)
+}
+
+func fetchTopPositionRatio(api *futures.Client, symbol, period string, start, end time.Time) (int, time.Time, error) {
+	stats, err := api.NewTopLongShortPositionRatioService().
+		Symbol(symbol).
+		Period(period).
+		StartTime(uint64(start.UnixMilli())).
+		EndTime(uint64(end.UnixMilli())).
+		Limit(pageLimit).
+		Do(context.Background())
+	if err != nil || len(stats) == 0 {
+		return 0, time.Time{}, err
+	}
+
+	ratios := make([]model.LongShortRatio, 0, len(stats))
+	for _, stat := range stats {
+		ratios = append(ratios, buildLongShortRatio(symbol, period, model.LongShortKindTopPosition,
+			stat.Timestamp, stat.LongShortRatio, stat.LongAccount, stat.ShortAccount))
+	}
+	return saveLongShortRatios(ratios)
+}
+
+// buildLongShortRatio 根据币安返回的多空比构建数据库模型，持仓口径的 longAccount/shortAccount 为多空持仓占比
+func buildLongShortRatio(symbol, period, kind string, timestamp uint64, ratio, long, short string) model.LongShortRatio {
+	return model.LongShortRatio{
+		Symbol:         symbol,
+		Period:         period,
+		Kind:           kind,
+		Time:           time.UnixMilli(int64(timestamp)).UTC(),
+		LongShortRatio: utils.StringToFloat64(ratio),
+		LongShare:      utils.StringToFloat64(long),
+		ShortShare:     utils.StringToFloat64(short),
+	}
+}
+
+func saveLongShortRatios(ratios []model.LongShortRatio) (int, time.Time, error) {
+	if _, err := store.Default().UpsertLongShortRatios(ratios); err != nil {
+		return 0, time.Time{}, err
+	}
+	return len(ratios), ratios[len(ratios)-1].Time, nil
+}
+
+func fetchTakerVolume(api *futures.Client, symbol, period string, start, end time.Time) (int, time.Time, error) {
+	stats, err := api.NewTakerLongShortRatioService().
+		Symbol(symbol).
+		Period(period).
+		StartTime(uint64(start.UnixMilli())).
+		EndTime(uint64(end.UnixMilli())).
+		Limit(pageLimit).
+		Do(context.Background())
+	if err != nil || len(stats) == 0 {
+		return 0, time.Time{}, err
+	}
+
+	volumes := make([]model.TakerVolume, 0, len(stats))
+	for _, stat := range stats {
+		volumes = append(volumes, model.TakerVolume{
+			Symbol:       symbol,
+			Period:       period,
+			Time:         time.UnixMilli(int64(stat.Timestamp)).UTC(),
+			BuySellRatio: utils.StringToFloat64(stat.BuySellRatio),
+			BuyVolume:    utils.StringToFloat64(stat.BuyVol),
+			SellVolume:   utils.StringToFloat64(stat.SellVol),
+		})
+	}
+	if _, err := store.Default().UpsertTakerVolumes(volumes); err != nil {
+		return 0, time.Time{}, err
+	}
+	return len(volumes), volumes[len(volumes)-1].Time, nil
+}
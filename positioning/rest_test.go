@@ -0,0 +1,119 @@
+package positioning
+
+import (
+	"testing"
+	"time"
+
+	"trade/db"
+	"trade/internal/fakebinance"
+	"trade/model"
+	"trade/store"
+)
+
+const openInterestPath = "/futures/data/openInterestHist"
+
+func TestUpdatePositioningGaps(t *testing.T) {
+	memory := store.NewMemoryStore()
+	store.SetDefault(memory)
+	server := fakebinance.NewServer()
+	db.InitBinance(&model.BinanceConfig{DataURL: server.URL()})
+	t.Cleanup(func() {
+		server.Close()
+		store.SetDefault(nil)
+		db.BinanceClient = nil
+	})
+
+	// 保留期内已完整结束的 1h 统计共 719 个时间点
+	now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	start := now.Add(-Retention).Truncate(time.Hour).Add(time.Hour)
+	points := fakebinance.GeneratePositioning("BTCUSDT", "1h", start, 720)
+	server.AddPositioning(points...)
+
+	updatePositioning(db.BinanceClient, "BTCUSDT", "1h", now)
+	stored, _ := memory.FindOpenInterest("BTCUSDT", "1h", time.Time{}, time.Time{})
+	if len(stored) != 719 {
+		t.Fatalf("应写入 719 条持仓量（不含未结束的周期），实际 %d", len(stored))
+	}
+	if requests := server.Requests(openInterestPath); requests != 2 {
+		t.Errorf("每页 500 条应请求 2 次，实际 %d", requests)
+	}
+	if stored[0].SumOpenInterest != points[0].OpenInterest || !stored[0].Time.Equal(start) {
+		t.Errorf("持仓量解析错误: %+v", stored[0])
+	}
+	ratios, _ := memory.FindLongShortRatios("BTCUSDT", "1h", model.LongShortKindTopPosition, time.Time{}, time.Time{})
+	if len(ratios) != 719 || ratios[1].LongShortRatio != points[1].TopPositionRatio || ratios[1].LongShare <= 0 {
+		t.Errorf("大户持仓多空比写入错误: %d %+v", len(ratios), ratios[1])
+	}
+
+	// 已入库的时间点不再请求，只补齐中间缺口和新结束的周期
+	memory = store.NewMemoryStore()
+	store.SetDefault(memory)
+	var local []model.OpenInterest
+	for i, point := range points[:719] {
+		if i >= 100 && i < 110 {
+			continue
+		}
+		local = append(local, model.OpenInterest{Symbol: "BTCUSDT", Period: "1h", Time: point.Time})
+	}
+	memory.UpsertOpenInterest(local)
+
+	before := server.Requests(openInterestPath)
+	updatePositioning(db.BinanceClient, "BTCUSDT", "1h", now.Add(time.Hour))
+	stored, _ = memory.FindOpenInterest("BTCUSDT", "1h", time.Time{}, time.Time{})
+	if len(stored) != 720 {
+		t.Fatalf("补齐缺口和新结束的周期后应有 720 条，实际 %d", len(stored))
+	}
+	if requests := server.Requests(openInterestPath) - before; requests != 2 {
+		t.Errorf("两个缺口应各请求一次，实际 %d", requests)
+	}
+	if stored[100].SumOpenInterest != points[100].OpenInterest {
+		t.Errorf("缺口数据错误: %+v", stored[100])
+	}
+}
+
+func TestAlign(t *testing.T) {
+	memory := store.NewMemoryStore()
+	store.SetDefault(memory)
+	t.Cleanup(func() { store.SetDefault(nil) })
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	memory.UpsertKlines(fakebinance.GenerateKlines("BTCUSDT", "1h", start, 5, 42000))
+	memory.UpsertOpenInterest([]model.OpenInterest{
+		{Symbol: "BTCUSDT", Period: "1h", Time: start.Add(time.Hour), SumOpenInterest: 100},
+		{Symbol: "BTCUSDT", Period: "1h", Time: start.Add(4 * time.Hour), SumOpenInterest: 200},
+		{Symbol: "BTCUSDT", Period: "1h", Time: start.Add(5 * time.Hour), SumOpenInterest: 300},
+	})
+	memory.UpsertTakerVolumes([]model.TakerVolume{
+		{Symbol: "BTCUSDT", Period: "1h", Time: start.Add(2 * time.Hour), BuySellRatio: 1.2},
+	})
+
+	rows, coverage, err := Align("BTCUSDT", "1h", start, start.Add(4*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 5 || coverage.OpenInterest != 2 || coverage.TakerVolume != 1 || coverage.TopAccount != 0 {
+		t.Fatalf("覆盖统计错误: %d %+v", len(rows), coverage)
+	}
+	if rows[0].OpenInterest != nil || *rows[1].OpenInterest != 100 || *rows[4].OpenInterest != 200 {
+		t.Errorf("持仓量应按开盘时间对齐: %+v", rows)
+	}
+	if *rows[2].TakerBuySellRatio != 1.2 || rows[2].TopAccountRatio != nil {
+		t.Errorf("主动买卖量比对齐错误: %+v", rows[2])
+	}
+}
+
+func TestMissingRanges(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return start.Add(time.Duration(hours) * time.Hour) }
+
+	ranges := MissingRanges([]time.Time{at(1), at(2), at(5)}, start, at(7), time.Hour)
+	want := [][2]time.Time{{at(0), at(0)}, {at(3), at(4)}, {at(6), at(7)}}
+	if len(ranges) != len(want) {
+		t.Fatalf("缺口数量错误: %v", ranges)
+	}
+	for i := range want {
+		if !ranges[i][0].Equal(want[i][0]) || !ranges[i][1].Equal(want[i][1]) {
+			t.Errorf("第%d个缺口错误: %v", i, ranges[i])
+		}
+	}
+}
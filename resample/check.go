@@ -0,0 +1,103 @@
+package resample
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"trade/model"
+	"trade/store"
+)
+
+// DefaultTolerance 价格和成交量比对的默认相对误差（累加浮点数的舍入误差远小于此值）
+const DefaultTolerance = 1e-6
+
+// Mismatch 合成K线与交易所K线不一致的字段
+type Mismatch struct {
+	OpenTime time.Time
+	Fields   []string    // 不一致的字段名
+	Derived  model.Kline // 本地合成
+	Exchange model.Kline // 交易所提供
+}
+
+// CheckReport 合成K线与交易所K线的一致性比对结果
+type CheckReport struct {
+	Spec       Spec
+	Compared   int          // 双方都有的K线数量
+	Matched    int          // 一致的数量
+	Mismatches []Mismatch   // 不一致的K线
+	NoExchange int          // 能合成但交易所K线不存在的数量
+	Incomplete []Incomplete // 基础K线不齐无法合成的K线
+}
+
+// Check 用基础K线合成目标周期K线（不写入），与交易所提供的同周期K线逐根比对
+// 只比对 Source 为交易所的K线，已被本地合成结果占用的时间点计入 NoExchange
+func Check(spec Spec, start, end time.Time, tolerance float64) (*CheckReport, error) {
+	if !spec.Aligned() {
+		return nil, fmt.Errorf("%s 带时区或偏移，交易所没有对应的K线可比对", spec.Label())
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	report := &CheckReport{Spec: spec}
+	err := walk(spec, start, end, func(chunkStart, chunkEnd time.Time, bars []model.Kline, incomplete []Incomplete) error {
+		report.Incomplete = append(report.Incomplete, incomplete...)
+		if len(bars) == 0 {
+			return nil
+		}
+		existing, err := store.Default().FindKlines(store.KlineQuery{
+			Symbol: spec.Symbol, Interval: spec.Target, Start: chunkStart, End: chunkEnd.Add(-time.Millisecond),
+		})
+		if err != nil {
+			return fmt.Errorf("查询 %s %s 交易所K线失败: %v", spec.Symbol, spec.Target, err)
+		}
+		exchange := make(map[int64]model.Kline, len(existing))
+		for _, k := range existing {
+			if k.Source == model.KlineSourceExchange {
+				exchange[k.OpenTime.Unix()] = k
+			}
+		}
+
+		for _, bar := range bars {
+			k, ok := exchange[bar.OpenTime.Unix()]
+			if !ok {
+				report.NoExchange++
+				continue
+			}
+			report.Compared++
+			if fields := diffFields(bar, k, tolerance); len(fields) > 0 {
+				report.Mismatches = append(report.Mismatches, Mismatch{OpenTime: bar.OpenTime, Fields: fields, Derived: bar, Exchange: k})
+			} else {
+				report.Matched++
+			}
+		}
+		return nil
+	})
+	return report, err
+}
+
+// diffFields 返回超出相对误差的字段，成交笔数要求完全一致
+// 补齐成交量之前入库的交易所K线 trade_num 为 0，此时跳过成交量相关字段
+func diffFields(derived, exchange model.Kline, tolerance float64) []string {
+	var fields []string
+	compare := func(name string, a, b float64) {
+		if math.Abs(a-b) > tolerance*math.Max(math.Abs(a), math.Abs(b)) {
+			fields = append(fields, name)
+		}
+	}
+	compare("open", derived.Open, exchange.Open)
+	compare("high", derived.High, exchange.High)
+	compare("low", derived.Low, exchange.Low)
+	compare("close", derived.Close, exchange.Close)
+	if exchange.TradeNum == 0 {
+		return fields
+	}
+	compare("volume", derived.Volume, exchange.Volume)
+	compare("quote_volume", derived.QuoteVolume, exchange.QuoteVolume)
+	compare("taker_buy_volume", derived.TakerBuyVolume, exchange.TakerBuyVolume)
+	compare("taker_buy_quote_volume", derived.TakerBuyQuoteVolume, exchange.TakerBuyQuoteVolume)
+	if derived.TradeNum != exchange.TradeNum {
+		fields = append(fields, "trade_num")
+	}
+	return fields
+}
@@ -0,0 +1,292 @@
+// Package resample 由已入库的基础周期K线（1m/1h等）在本地合成更高周期K线
+// 合成的K线以周期标签为 interval 入库，Source 标记为 resample:<基础周期>
+package resample
+
+import (
+	"fmt"
+	"time"
+
+	"trade/model"
+	"trade/store"
+	"trade/utils"
+)
+
+// chunkBaseKlines 每批读取的基础K线数量上限（按整根目标K线切分批次）
+const chunkBaseKlines = 50000
+
+// Spec 一个合成任务：交易对 + 基础周期 + 目标周期标签
+type Spec struct {
+	Symbol string
+	Base   string
+	utils.ResampleLabel
+}
+
+// ParseSpec 解析周期标签，如 4h、1d@America/New_York、1d+8h
+func ParseSpec(symbol, base, label string) (Spec, error) {
+	parsed, err := utils.ParseResampleLabel(base, label)
+	if err != nil {
+		return Spec{}, err
+	}
+	return Spec{Symbol: symbol, Base: base, ResampleLabel: parsed}, nil
+}
+
+// Label 合成K线入库使用的 interval
+func (s Spec) Label() string {
+	return s.ResampleLabel.String()
+}
+
+// Aligned 是否与交易所K线对齐（UTC 零点、无偏移），只有对齐的周期能与交易所K线比对
+func (s Spec) Aligned() bool {
+	return s.Location == nil && s.Offset == 0
+}
+
+func (s Spec) location() *time.Location {
+	if s.Location == nil {
+		return time.UTC
+	}
+	return s.Location
+}
+
+// dayStart t 所在的（偏移后）当地自然日零点，周线为所在周的周一零点
+func (s Spec) dayStart(t time.Time) time.Time {
+	local := t.Add(-s.Offset).In(s.location())
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.location())
+	if s.Target == "1w" {
+		day = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+	return day
+}
+
+// bucketStart t 所在目标K线的开盘时间
+// 日内周期从当地零点起按周期切分，夏令时切换日最后一根K线可能比周期短或长
+func (s Spec) bucketStart(t time.Time) time.Time {
+	day := s.dayStart(t)
+	if s.Target == "1d" || s.Target == "1w" {
+		return day.Add(s.Offset).UTC()
+	}
+	period := utils.IntervalDuration(s.Target)
+	n := t.Add(-s.Offset).Sub(day) / period
+	return day.Add(n*period + s.Offset).UTC()
+}
+
+// bucketEnd 开盘时间为 start 的目标K线的结束时间（即下一根的开盘时间）
+func (s Spec) bucketEnd(start time.Time) time.Time {
+	day := s.dayStart(start)
+	switch s.Target {
+	case "1w":
+		return day.AddDate(0, 0, 7).Add(s.Offset).UTC()
+	case "1d":
+		return day.AddDate(0, 0, 1).Add(s.Offset).UTC()
+	}
+	end := start.Add(utils.IntervalDuration(s.Target))
+	if next := day.AddDate(0, 0, 1).Add(s.Offset); next.Before(end) {
+		end = next
+	}
+	return end.UTC()
+}
+
+// Incomplete 缺少基础K线而未能合成的目标K线
+type Incomplete struct {
+	OpenTime time.Time
+	Have     int // 已有的基础K线数量
+	Want     int // 应有的基础K线数量
+}
+
+// Build 把按开盘时间升序的基础K线合成为目标周期K线
+// 只合成结束时间不晚于 until 的K线，之后的视为尚未收盘；基础K线不齐的K线记入 incomplete
+func Build(spec Spec, base []model.Kline, until time.Time) (bars []model.Kline, incomplete []Incomplete) {
+	baseDuration := utils.IntervalDuration(spec.Base)
+	for i := 0; i < len(base); {
+		start := spec.bucketStart(base[i].OpenTime)
+		end := spec.bucketEnd(start)
+		j := i
+		for j < len(base) && base[j].OpenTime.Before(end) {
+			j++
+		}
+		group := base[i:j]
+		i = j
+		if end.After(until) {
+			continue
+		}
+		if want := int(end.Sub(start) / baseDuration); len(group) != want {
+			incomplete = append(incomplete, Incomplete{OpenTime: start, Have: len(group), Want: want})
+			continue
+		}
+		bars = append(bars, aggregate(spec, start, end, group))
+	}
+	return bars, incomplete
+}
+
+// aggregate 合成一根K线：首根开盘价、末根收盘价、最高/最低价取极值，成交量等累加
+func aggregate(spec Spec, start, end time.Time, group []model.Kline) model.Kline {
+	bar := model.Kline{
+		Symbol:    spec.Symbol,
+		Interval:  spec.Label(),
+		OpenTime:  start,
+		CloseTime: end.Add(-time.Second),
+		Open:      group[0].Open,
+		Close:     group[len(group)-1].Close,
+		High:      group[0].High,
+		Low:       group[0].Low,
+		Source:    model.ResampledSource(spec.Base),
+	}
+	for _, k := range group {
+		if k.High > bar.High {
+			bar.High = k.High
+		}
+		if k.Low < bar.Low {
+			bar.Low = k.Low
+		}
+		bar.Volume += k.Volume
+		bar.QuoteVolume += k.QuoteVolume
+		bar.TradeNum += k.TradeNum
+		bar.TakerBuyVolume += k.TakerBuyVolume
+		bar.TakerBuyQuoteVolume += k.TakerBuyQuoteVolume
+	}
+	bar.FillTimeFields()
+	return bar
+}
+
+// Result 一次合成的结果
+type Result struct {
+	Spec       Spec
+	Built      int          // 合成的K线数量
+	Exchange   int          // 已有交易所K线而跳过的数量
+	Written    int64        // 写入数量
+	Incomplete []Incomplete // 基础K线不齐的K线
+}
+
+// walk 按整根目标K线切分批次，读取从 start 所在的目标K线起、到 end 之前开盘的目标K线所需的基础K线并合成
+// start 为零值时从最早的基础K线开始，end 为零值时到当前时间；基础K线最新一根之后的K线视为尚未收盘
+func walk(spec Spec, start, end time.Time, fn func(chunkStart, chunkEnd time.Time, bars []model.Kline, incomplete []Incomplete) error) error {
+	latest, err := store.Default().LatestKline(spec.Symbol, spec.Base)
+	if err != nil {
+		return fmt.Errorf("查询 %s %s 最新K线失败: %v", spec.Symbol, spec.Base, err)
+	}
+	if latest == nil {
+		return nil
+	}
+	until := latest.OpenTime.Add(utils.IntervalDuration(spec.Base))
+	if now := time.Now(); until.After(now) {
+		until = now
+	}
+	if end.IsZero() || end.After(until) {
+		end = until
+	}
+	if start.IsZero() {
+		first, err := store.Default().FindKlines(store.KlineQuery{Symbol: spec.Symbol, Interval: spec.Base, Limit: 1})
+		if err != nil || len(first) == 0 {
+			return err
+		}
+		start = first[0].OpenTime
+	}
+
+	period := utils.IntervalDuration(spec.Target)
+	barsPerChunk := chunkBaseKlines * int(utils.IntervalDuration(spec.Base)) / int(period)
+	for cur := spec.bucketStart(start); cur.Before(end); {
+		chunkEnd := spec.bucketEnd(cur)
+		for n := 1; n < barsPerChunk && chunkEnd.Before(end); n++ {
+			chunkEnd = spec.bucketEnd(chunkEnd)
+		}
+		base, err := store.Default().FindKlines(store.KlineQuery{
+			Symbol: spec.Symbol, Interval: spec.Base, Start: cur, End: chunkEnd.Add(-time.Millisecond),
+		})
+		if err != nil {
+			return fmt.Errorf("查询 %s %s K线失败: %v", spec.Symbol, spec.Base, err)
+		}
+		bars, incomplete := Build(spec, base, until)
+		// 批次最后一根K线可能在 end 之后开盘
+		for len(bars) > 0 && !bars[len(bars)-1].OpenTime.Before(end) {
+			bars = bars[:len(bars)-1]
+		}
+		for len(incomplete) > 0 && !incomplete[len(incomplete)-1].OpenTime.Before(end) {
+			incomplete = incomplete[:len(incomplete)-1]
+		}
+		if err := fn(cur, chunkEnd, bars, incomplete); err != nil {
+			return err
+		}
+		cur = chunkEnd
+	}
+	return nil
+}
+
+// Resample 合成从 start 所在的K线起、到 end 之前开盘的目标K线并写入（dryRun 时只合成不写入）
+// 交易所已提供的同周期K线不会被覆盖；之前合成的K线按最新的基础K线重新计算
+func Resample(spec Spec, start, end time.Time, dryRun bool) (*Result, error) {
+	result := &Result{Spec: spec}
+	err := walk(spec, start, end, func(chunkStart, chunkEnd time.Time, bars []model.Kline, incomplete []Incomplete) error {
+		result.Incomplete = append(result.Incomplete, incomplete...)
+		if len(bars) == 0 {
+			return nil
+		}
+		existing, err := store.Default().FindKlines(store.KlineQuery{
+			Symbol: spec.Symbol, Interval: spec.Label(), Start: chunkStart, End: chunkEnd.Add(-time.Millisecond),
+		})
+		if err != nil {
+			return fmt.Errorf("查询 %s %s 已有K线失败: %v", spec.Symbol, spec.Label(), err)
+		}
+		exchange := make(map[int64]bool, len(existing))
+		for _, k := range existing {
+			if k.Source == model.KlineSourceExchange {
+				exchange[k.OpenTime.Unix()] = true
+			}
+		}
+
+		toWrite := make([]model.Kline, 0, len(bars))
+		for _, bar := range bars {
+			if exchange[bar.OpenTime.Unix()] {
+				result.Exchange++
+				continue
+			}
+			toWrite = append(toWrite, bar)
+		}
+		result.Built += len(toWrite)
+		if dryRun || len(toWrite) == 0 {
+			return nil
+		}
+		written, err := store.Default().UpsertKlines(toWrite)
+		if err != nil {
+			return fmt.Errorf("写入 %s %s 合成K线失败: %v", spec.Symbol, spec.Label(), err)
+		}
+		result.Written += written
+		return nil
+	})
+	return result, err
+}
+
+// Update 增量合成：从最新一根已有K线（重新计算）开始合成到当前已收盘的K线
+func Update(spec Spec) (*Result, error) {
+	var start time.Time
+	latest, err := store.Default().LatestKline(spec.Symbol, spec.Label())
+	if err != nil {
+		return nil, fmt.Errorf("查询 %s %s 最新K线失败: %v", spec.Symbol, spec.Label(), err)
+	}
+	if latest != nil {
+		start = latest.OpenTime
+	}
+	return Resample(spec, start, time.Time{}, false)
+}
+
+// UpdateSymbol 按交易对的 resample 配置增量合成全部周期，供定时任务和单次运行调用
+func UpdateSymbol(symbolConfig model.SymbolConfig) {
+	if symbolConfig.Resample == nil {
+		return
+	}
+	for _, label := range symbolConfig.Resample.Intervals {
+		spec, err := ParseSpec(symbolConfig.Symbol, symbolConfig.Resample.Base, label)
+		if err != nil {
+			fmt.Printf("❌ %s 重采样配置错误: %v\n", symbolConfig.Symbol, err)
+			continue
+		}
+		result, err := Update(spec)
+		if err != nil {
+			fmt.Printf("❌ %s %s 重采样失败: %v\n", spec.Symbol, spec.Label(), err)
+			continue
+		}
+		fmt.Printf("%s %s 由 %s 合成 %d 根K线", spec.Symbol, spec.Label(), spec.Base, result.Written)
+		if len(result.Incomplete) > 0 {
+			fmt.Printf("，%d 根缺少基础K线未合成", len(result.Incomplete))
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,140 @@
+package resample
+
+import (
+	"testing"
+	"time"
+
+	"trade/internal/fakebinance"
+	"trade/model"
+	"trade/store"
+)
+
+func mustSpec(t *testing.T, base, label string) Spec {
+	t.Helper()
+	spec, err := ParseSpec("BTCUSDT", base, label)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+func TestBuild(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) // 周一
+	base := fakebinance.GenerateKlines("BTCUSDT", "1h", start, 24*14, 42000)
+	until := start.Add(14 * 24 * time.Hour)
+
+	bars, incomplete := Build(mustSpec(t, "1h", "4h"), base, until)
+	if len(bars) != 14*6 || len(incomplete) != 0 {
+		t.Fatalf("4h 合成数量错误: %d %v", len(bars), incomplete)
+	}
+	first := bars[0]
+	if !first.OpenTime.Equal(start) || !first.CloseTime.Equal(start.Add(4*time.Hour-time.Second)) ||
+		first.Open != base[0].Open || first.Close != base[3].Close || first.Source != "resample:1h" || first.Interval != "4h" {
+		t.Errorf("4h K线错误: %+v", first)
+	}
+	var high, volume float64
+	var trades int64
+	for _, k := range base[:4] {
+		if k.High > high {
+			high = k.High
+		}
+		volume += k.Volume
+		trades += k.TradeNum
+	}
+	if first.High != high || first.Volume != volume || first.TradeNum != trades {
+		t.Errorf("4h 最高价/成交量错误: %+v", first)
+	}
+
+	// 周线从周一开始；until 之后收盘的K线不合成
+	if bars, _ := Build(mustSpec(t, "1h", "1w"), base, until.Add(-time.Hour)); len(bars) != 1 || !bars[0].OpenTime.Equal(start) {
+		t.Errorf("1w 合成错误: %+v", bars)
+	}
+
+	// 偏移：每天 08:00 UTC 开盘，开头不完整的一天报告缺失，末尾未收盘的一天不合成
+	bars, incomplete = Build(mustSpec(t, "1h", "1d+8h"), base, until)
+	if len(bars) != 13 || !bars[0].OpenTime.Equal(start.Add(8*time.Hour)) || bars[0].Interval != "1d+8h" || len(incomplete) != 1 {
+		t.Errorf("1d+8h 合成错误: %d %v %v", len(bars), bars[0].OpenTime, incomplete)
+	}
+
+	// 缺少一根基础K线时不合成所在的K线
+	gapped := append(append([]model.Kline{}, base[:5]...), base[6:]...)
+	bars, incomplete = Build(mustSpec(t, "1h", "4h"), gapped, until)
+	if len(bars) != 14*6-1 || len(incomplete) != 1 || !incomplete[0].OpenTime.Equal(start.Add(4*time.Hour)) || incomplete[0].Have != 3 {
+		t.Errorf("缺失基础K线处理错误: %d %v", len(bars), incomplete)
+	}
+}
+
+func TestBuildSessionAligned(t *testing.T) {
+	// 2024-03-10 美国进入夏令时，纽约当地的这一天只有 23 小时
+	start := time.Date(2024, 3, 9, 5, 0, 0, 0, time.UTC)
+	base := fakebinance.GenerateKlines("BTCUSDT", "1h", start, 24+23+24, 42000)
+	bars, incomplete := Build(mustSpec(t, "1h", "1d@America/New_York"), base, start.Add(72*time.Hour))
+	if len(bars) != 3 || len(incomplete) != 0 {
+		t.Fatalf("会话对齐合成数量错误: %d %v", len(bars), incomplete)
+	}
+	if !bars[1].OpenTime.Equal(time.Date(2024, 3, 10, 5, 0, 0, 0, time.UTC)) ||
+		!bars[2].OpenTime.Equal(time.Date(2024, 3, 11, 4, 0, 0, 0, time.UTC)) || bars[1].Close != base[24+22].Close {
+		t.Errorf("夏令时切换日边界错误: %v %v", bars[1].OpenTime, bars[2].OpenTime)
+	}
+}
+
+func TestResampleAndCheck(t *testing.T) {
+	memory := store.NewMemoryStore()
+	store.SetDefault(memory)
+	t.Cleanup(func() { store.SetDefault(nil) })
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	memory.UpsertKlines(fakebinance.GenerateKlines("BTCUSDT", "1h", start, 24*5, 42000))
+
+	// 交易所的 4h K线：一根收盘价不同，最后一天没有拉取
+	spec := mustSpec(t, "1h", "4h")
+	exchange, _ := Build(spec, fakebinance.GenerateKlines("BTCUSDT", "1h", start, 24*4, 42000), start.Add(96*time.Hour))
+	for i := range exchange {
+		exchange[i].Source = model.KlineSourceExchange
+	}
+	exchange[3].Close++
+	memory.UpsertKlines(exchange)
+
+	report, err := Check(spec, time.Time{}, time.Time{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Compared != 24 || report.Matched != 23 || report.NoExchange != 6 || len(report.Mismatches) != 1 {
+		t.Fatalf("一致性检查结果错误: %+v", report)
+	}
+	if m := report.Mismatches[0]; !m.OpenTime.Equal(exchange[3].OpenTime) || len(m.Fields) != 1 || m.Fields[0] != "close" {
+		t.Errorf("不一致字段错误: %+v", m)
+	}
+
+	// 合成只补齐交易所没有的K线，不覆盖交易所数据
+	result, err := Resample(spec, time.Time{}, time.Time{}, false)
+	if err != nil || result.Built != 6 || result.Exchange != 24 || result.Written != 6 {
+		t.Fatalf("合成结果错误: %v %+v", err, result)
+	}
+	stored, _ := memory.FindKlines(store.KlineQuery{Symbol: "BTCUSDT", Interval: "4h"})
+	if len(stored) != 30 || stored[3].Close != exchange[3].Close || stored[3].IsResampled() || !stored[29].IsResampled() {
+		t.Errorf("入库结果错误: %d %+v", len(stored), stored[29])
+	}
+
+	// 增量合成：新增基础K线后只重新计算最新一根及之后的K线
+	memory.UpsertKlines(fakebinance.GenerateKlines("BTCUSDT", "1h", start, 24*6, 42000)[24*5:])
+	if result, err = Update(spec); err != nil || result.Built != 7 || result.Exchange != 0 {
+		t.Fatalf("增量合成结果错误: %v %+v", err, result)
+	}
+
+	// 补齐成交量之前入库的交易所K线只比对价格
+	derived, legacy := exchange[0], exchange[0]
+	legacy.Volume, legacy.QuoteVolume, legacy.TakerBuyVolume, legacy.TradeNum = 0, 0, 0, 0
+	if fields := diffFields(derived, legacy, DefaultTolerance); len(fields) != 0 {
+		t.Errorf("trade_num 为0时不应比对成交量: %v", fields)
+	}
+	legacy.High++
+	if fields := diffFields(derived, legacy, DefaultTolerance); len(fields) != 1 || fields[0] != "high" {
+		t.Errorf("trade_num 为0时仍应比对价格: %v", fields)
+	}
+
+	// 带偏移的周期无法与交易所比对
+	if _, err := Check(mustSpec(t, "1h", "1d+8h"), time.Time{}, time.Time{}, 0); err == nil {
+		t.Error("带偏移的周期应拒绝一致性检查")
+	}
+}
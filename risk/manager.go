@@ -0,0 +1,184 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"trade/model"
+	"trade/store"
+)
+
+// ErrRejected 开仓请求被风控拒绝，具体规则见 *Rejection
+var ErrRejected = errors.New("风控拒绝开仓")
+
+// exposureTolerance 比较名义价值时容忍的浮点误差(USDT)
+const exposureTolerance = 1e-9
+
+// Rejection 风控拒绝开仓的原因
+type Rejection struct {
+	Rule   string // 触发的规则，见 model.RiskRule*
+	Reason string // 拒绝原因
+}
+
+func (r *Rejection) Error() string {
+	return fmt.Sprintf("风控拒绝开仓(%s): %s", r.Rule, r.Reason)
+}
+
+// Is 使 errors.Is(err, ErrRejected) 成立
+func (r *Rejection) Is(target error) bool {
+	return target == ErrRejected
+}
+
+// Request 开仓请求
+type Request struct {
+	Symbol         string  // 交易对
+	Interval       string  // 信号周期
+	Strategy       string  // 产生信号的策略，合并信号时为空
+	Side           string  // 开仓方向(LONG/SHORT)
+	Notional       float64 // 拟开仓名义价值(USDT)
+	SymbolExposure float64 // 交易对当前持仓名义价值合计
+	TotalExposure  float64 // 全部交易对当前持仓名义价值合计
+	MaxPosition    float64 // 交易对最大持仓名义价值，0 表示不限制
+}
+
+// Manager 位于策略信号和下单之间的风控：检查紧急停止开关、持仓上限、当日亏损和连续亏损
+// 状态保存在存储中，API 服务修改开关或重置状态后，运行中的模拟盘和下单进程下一次开仓即生效
+type Manager struct {
+	name   string
+	config model.RiskConfig
+}
+
+// NewManager 创建账户 name 的风控
+func NewManager(name string, config model.RiskConfig) *Manager {
+	return &Manager{name: name, config: config}
+}
+
+// Name 返回风控账户名称
+func (m *Manager) Name() string {
+	return m.name
+}
+
+// Check 检查开仓请求，at 为请求时间（用于划分当日亏损）
+// 被拒绝时保存拒绝记录并返回 *Rejection，存储出错时返回其他错误
+func (m *Manager) Check(req Request, at time.Time) error {
+	rule, reason, err := m.evaluate(req, at)
+	if err != nil {
+		return err
+	}
+	if rule == "" {
+		return nil
+	}
+
+	rejection := &model.RiskRejection{
+		Account:   m.name,
+		Symbol:    req.Symbol,
+		Interval:  req.Interval,
+		Strategy:  req.Strategy,
+		Side:      req.Side,
+		Notional:  req.Notional,
+		Rule:      rule,
+		Reason:    reason,
+		CreatedAt: at,
+	}
+	if err := store.Default().SaveRiskRejection(rejection); err != nil {
+		return fmt.Errorf("保存风控拒绝记录失败: %w", err)
+	}
+	fmt.Printf("🛑 [%s] 风控拒绝开仓 %s %s %s: %s\n", m.name, req.Symbol, req.Interval, req.Side, reason)
+	return &Rejection{Rule: rule, Reason: reason}
+}
+
+// evaluate 按顺序检查各项规则，返回第一条触发的规则和原因
+func (m *Manager) evaluate(req Request, at time.Time) (string, string, error) {
+	killSwitch, err := store.Default().GetKillSwitch()
+	if err != nil {
+		return "", "", fmt.Errorf("加载紧急停止开关失败: %w", err)
+	}
+	if killSwitch != nil && killSwitch.Active {
+		return model.RiskRuleKillSwitch, fmt.Sprintf("紧急停止已开启: %s", killSwitch.Reason), nil
+	}
+
+	state, err := m.State(at)
+	if err != nil {
+		return "", "", err
+	}
+	if limit := m.config.MaxConsecutiveLosses; limit > 0 && state.ConsecutiveLosses >= limit {
+		return model.RiskRuleConsecutiveLosses,
+			fmt.Sprintf("连续亏损 %d 次，达到上限 %d，需重置后才能继续开仓", state.ConsecutiveLosses, limit), nil
+	}
+	if limit := m.config.MaxDailyLoss; limit > 0 && -state.DailyRealizedPnL >= limit {
+		return model.RiskRuleMaxDailyLoss,
+			fmt.Sprintf("%s 已实现亏损 %.4f，达到上限 %v", state.Day, -state.DailyRealizedPnL, limit), nil
+	}
+	if req.MaxPosition > 0 && req.SymbolExposure+req.Notional > req.MaxPosition+exposureTolerance {
+		return model.RiskRuleMaxPosition,
+			fmt.Sprintf("%s 已持有 %.4f，开仓 %.4f 后超过上限 %v", req.Symbol, req.SymbolExposure, req.Notional, req.MaxPosition), nil
+	}
+	if limit := m.config.MaxTotalExposure; limit > 0 && req.TotalExposure+req.Notional > limit+exposureTolerance {
+		return model.RiskRuleMaxTotalExposure,
+			fmt.Sprintf("总持仓 %.4f，开仓 %.4f 后超过上限 %v", req.TotalExposure, req.Notional, limit), nil
+	}
+	return "", "", nil
+}
+
+// RecordClose 记录一次平仓的已实现盈亏(含手续费等成本)，更新当日盈亏和连续亏损次数
+func (m *Manager) RecordClose(pnl float64, at time.Time) error {
+	state, err := m.State(at)
+	if err != nil {
+		return err
+	}
+
+	state.DailyRealizedPnL += pnl
+	if pnl < 0 {
+		state.ConsecutiveLosses++
+	} else {
+		state.ConsecutiveLosses = 0
+	}
+	if err := store.Default().SaveRiskState(state); err != nil {
+		return fmt.Errorf("保存风控状态失败: %w", err)
+	}
+	return nil
+}
+
+// State 返回 at 时刻的风控状态，跨日(UTC)后当日已实现盈亏归零
+func (m *Manager) State(at time.Time) (*model.RiskState, error) {
+	state, err := store.Default().GetRiskState(m.name)
+	if err != nil {
+		return nil, fmt.Errorf("加载风控状态失败: %w", err)
+	}
+	if state == nil {
+		state = &model.RiskState{Name: m.name}
+	}
+	if day := at.UTC().Format("2006-01-02"); state.Day != day {
+		state.Day = day
+		state.DailyRealizedPnL = 0
+	}
+	return state, nil
+}
+
+// SetKillSwitch 开启或关闭紧急停止开关
+func SetKillSwitch(active bool, reason string) (*model.KillSwitch, error) {
+	killSwitch := &model.KillSwitch{Active: active, Reason: reason}
+	if err := store.Default().SaveKillSwitch(killSwitch); err != nil {
+		return nil, fmt.Errorf("保存紧急停止开关失败: %w", err)
+	}
+	return killSwitch, nil
+}
+
+// Reset 清零账户的连续亏损次数和当日已实现盈亏，账户不存在时返回 nil
+func Reset(name string) (*model.RiskState, error) {
+	state, err := store.Default().GetRiskState(name)
+	if err != nil {
+		return nil, fmt.Errorf("加载风控状态失败: %w", err)
+	}
+	if state == nil {
+		return nil, nil
+	}
+
+	state.ConsecutiveLosses = 0
+	state.DailyRealizedPnL = 0
+	if err := store.Default().SaveRiskState(state); err != nil {
+		return nil, fmt.Errorf("保存风控状态失败: %w", err)
+	}
+	return state, nil
+}
@@ -0,0 +1,112 @@
+package risk
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"trade/model"
+	"trade/store"
+)
+
+func newTestManager(t *testing.T, config model.RiskConfig) *Manager {
+	t.Helper()
+	store.SetDefault(store.NewMemoryStore())
+	t.Cleanup(func() { store.SetDefault(nil) })
+	return NewManager("paper", config)
+}
+
+// rule 返回拒绝规则，未拒绝时返回空字符串
+func rule(t *testing.T, err error) string {
+	t.Helper()
+	if err == nil {
+		return ""
+	}
+	var rejection *Rejection
+	if !errors.As(err, &rejection) || !errors.Is(err, ErrRejected) {
+		t.Fatalf("应返回 *Rejection，实际 %v", err)
+	}
+	return rejection.Rule
+}
+
+func TestManagerExposureLimits(t *testing.T) {
+	m := newTestManager(t, model.RiskConfig{MaxTotalExposure: 120})
+	now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	req := Request{Symbol: "BTCUSDT", Interval: "1h", Side: model.PositionSideLong, Notional: 50, MaxPosition: 100}
+
+	if got := rule(t, m.Check(req, now)); got != "" {
+		t.Errorf("未超限不应拒绝: %s", got)
+	}
+
+	req.SymbolExposure = 60
+	if got := rule(t, m.Check(req, now)); got != model.RiskRuleMaxPosition {
+		t.Errorf("应触发 max_position，实际 %q", got)
+	}
+
+	req.SymbolExposure, req.TotalExposure = 0, 80
+	if got := rule(t, m.Check(req, now)); got != model.RiskRuleMaxTotalExposure {
+		t.Errorf("应触发 max_total_exposure，实际 %q", got)
+	}
+
+	rejections, _ := store.Default().ListRiskRejections("BTCUSDT", 0)
+	if len(rejections) != 2 || rejections[0].Rule != model.RiskRuleMaxTotalExposure || rejections[0].Reason == "" ||
+		rejections[0].Account != "paper" || rejections[0].Notional != 50 {
+		t.Errorf("拒绝记录错误: %+v", rejections)
+	}
+}
+
+func TestManagerLossLimits(t *testing.T) {
+	m := newTestManager(t, model.RiskConfig{MaxDailyLoss: 10, MaxConsecutiveLosses: 3})
+	day := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	req := Request{Symbol: "ETHUSDT", Side: model.PositionSideShort, Notional: 50}
+
+	// 当日累计亏损 -4 - 7 = -11，超过 10
+	m.RecordClose(-4, day)
+	m.RecordClose(-7, day.Add(time.Hour))
+	if got := rule(t, m.Check(req, day.Add(2*time.Hour))); got != model.RiskRuleMaxDailyLoss {
+		t.Errorf("应触发 max_daily_loss，实际 %q", got)
+	}
+
+	// 次日当日亏损归零，但连续亏损继续累计
+	next := day.Add(24 * time.Hour)
+	if got := rule(t, m.Check(req, next)); got != "" {
+		t.Errorf("跨日后不应拒绝: %s", got)
+	}
+	m.RecordClose(-1, next)
+	if got := rule(t, m.Check(req, next)); got != model.RiskRuleConsecutiveLosses {
+		t.Errorf("应触发 consecutive_losses，实际 %q", got)
+	}
+
+	state, err := Reset("paper")
+	if err != nil || state.ConsecutiveLosses != 0 {
+		t.Fatalf("重置失败: %v %+v", err, state)
+	}
+	if got := rule(t, m.Check(req, next)); got != "" {
+		t.Errorf("重置后不应拒绝: %s", got)
+	}
+
+	// 盈利平仓清零连续亏损
+	m.RecordClose(-1, next)
+	m.RecordClose(2, next)
+	if state, _ := m.State(next); state.ConsecutiveLosses != 0 || state.DailyRealizedPnL != 1 {
+		t.Errorf("风控状态错误: %+v", state)
+	}
+}
+
+func TestManagerKillSwitch(t *testing.T) {
+	m := newTestManager(t, model.RiskConfig{})
+	now := time.Now()
+	req := Request{Symbol: "BTCUSDT", Side: model.PositionSideLong, Notional: 50}
+
+	if _, err := SetKillSwitch(true, "交易所维护"); err != nil {
+		t.Fatal(err)
+	}
+	if got := rule(t, m.Check(req, now)); got != model.RiskRuleKillSwitch {
+		t.Errorf("应触发 kill_switch，实际 %q", got)
+	}
+
+	SetKillSwitch(false, "")
+	if got := rule(t, m.Check(req, now)); got != "" {
+		t.Errorf("关闭后不应拒绝: %s", got)
+	}
+}
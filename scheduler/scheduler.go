@@ -3,18 +3,24 @@ package scheduler
 import (
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/robfig/cron/v3"
+	"trade/funding"
 	"trade/kline"
 	"trade/model"
+	"trade/positioning"
+	"trade/resample"
 	"trade/strategy"
+	"trade/utils"
 )
 
 // Scheduler 定时任务调度器
 type Scheduler struct {
-	cron   *cron.Cron
-	config *model.Config
+	cron     *cron.Cron
+	config   *model.Config
+	schedule cron.Schedule // 每日更新任务的执行计划
 }
 
 // NewScheduler 创建新的调度器
@@ -29,17 +35,20 @@ func NewScheduler(config *model.Config) *Scheduler {
 
 // Start 启动调度器
 func (s *Scheduler) Start() {
-	// 每天零点执行策略更新任务
-	// cron表达式: "0 0 0 * * *" 表示每天的00:00:00执行
-	_, err := s.cron.AddFunc("0 0 0 * * *", s.runDailyUpdate)
+	// 按配置的cron表达式执行策略更新任务
+	// 默认 "0 0 0 * * *" 表示每天的00:00:00执行
+	spec := s.config.Scheduler.DailySpec
+	schedule, err := utils.ParseCronSpec(spec)
 	if err != nil {
-		log.Fatalf("添加定时任务失败: %v", err)
+		log.Fatalf("解析定时任务表达式 %q 失败: %v", spec, err)
 	}
+	s.schedule = schedule
+	s.cron.Schedule(schedule, cron.FuncJob(s.runDailyUpdate))
 
 	fmt.Println("╔════════════════════════════════════════════════════════════════╗")
 	fmt.Println("║              定时任务调度器已启动                              ║")
 	fmt.Println("╚════════════════════════════════════════════════════════════════╝")
-	fmt.Printf("⏰ 每日更新时间: 00:00:00\n")
+	fmt.Printf("⏰ 更新任务计划: %s\n", spec)
 	fmt.Printf("📊 监控交易对数量: %d\n", len(s.config.Symbols))
 	fmt.Printf("🕐 当前时间: %s\n", time.Now().Format("2006-01-02 15:04:05"))
 
@@ -77,7 +86,7 @@ func (s *Scheduler) runDailyUpdate() {
 	fmt.Println("════════════════════════════════════════════════════════════════")
 	fmt.Println()
 
-	// 1. 更新K线数据
+	// 1. 更新K线数据、资金费率和持仓统计
 	fmt.Printf("开始更新 %d 个交易对的K线数据...\n", len(s.config.Symbols))
 	for i, symbolConfig := range s.config.Symbols {
 		fmt.Printf("\n[%d/%d] 处理交易对: %s\n", i+1, len(s.config.Symbols), symbolConfig.Symbol)
@@ -86,16 +95,26 @@ func (s *Scheduler) runDailyUpdate() {
 			fmt.Printf("  - 更新时间周期: %s\n", interval)
 			kline.UpdateKline(symbolConfig.Symbol, interval)
 		}
-	}
-	fmt.Println("\n✅ K线数据更新完成")
 
-	// 2. 运行策略一
-	fmt.Println("\n========== 开始运行策略一 ==========")
-	strategy.Strategy1(s.config)
+		if symbolConfig.Resample != nil {
+			fmt.Printf("  - 由 %s 合成: %s\n", symbolConfig.Resample.Base, strings.Join(symbolConfig.Resample.Intervals, ","))
+			resample.UpdateSymbol(symbolConfig)
+		}
 
-	// 3. 运行策略二
-	fmt.Println("\n========== 开始运行策略二 ==========")
-	strategy.Strategy2(s.config)
+		fmt.Println("  - 更新资金费率")
+		funding.UpdateFundingRate(symbolConfig.Symbol)
+
+		for _, interval := range symbolConfig.Intervals {
+			if positioning.SupportsInterval(interval) {
+				fmt.Printf("  - 更新持仓统计: %s\n", interval)
+				positioning.UpdatePositioning(symbolConfig.Symbol, interval)
+			}
+		}
+	}
+	fmt.Println("\n✅ K线数据、资金费率和持仓统计更新完成")
+
+	// 2. 运行所有已注册的策略(按需运行的除外)，只使用任务开始前已收盘的K线，保证结果可按时点复现
+	strategy.RunAll(s.config, startTime)
 
 	// 计算耗时
 	duration := time.Since(startTime)
@@ -115,21 +134,16 @@ func (s *Scheduler) runDailyUpdate() {
 
 // getNextRunTime 获取下次执行时间
 func (s *Scheduler) getNextRunTime() time.Time {
-	now := time.Now()
-
-	// 计算明天零点
-	tomorrow := now.AddDate(0, 0, 1)
-	nextRun := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(),
-		0, 0, 0, 0, now.Location())
-
-	// 如果当前时间还没到今天的零点，则下次执行时间是今天零点
-	todayMidnight := time.Date(now.Year(), now.Month(), now.Day(),
-		0, 0, 0, 0, now.Location())
-	if now.Before(todayMidnight) {
-		return todayMidnight
+	schedule := s.schedule
+	if schedule == nil {
+		// 调度器尚未启动时按配置计算
+		parsed, err := utils.ParseCronSpec(s.config.Scheduler.DailySpec)
+		if err != nil {
+			return time.Time{}
+		}
+		schedule = parsed
 	}
-
-	return nextRun
+	return schedule.Next(time.Now())
 }
 
 // GetSchedulerInfo 获取调度器信息
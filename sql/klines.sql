@@ -29,6 +29,11 @@ CREATE TABLE "public"."klines" (
   "low" numeric,
   "open_time" timestamptz(6),
   "close_time" timestamptz(6),
+  "volume" numeric NOT NULL DEFAULT 0,
+  "quote_volume" numeric NOT NULL DEFAULT 0,
+  "trade_num" int8 NOT NULL DEFAULT 0,
+  "taker_buy_volume" numeric NOT NULL DEFAULT 0,
+  "taker_buy_quote_volume" numeric NOT NULL DEFAULT 0,
   "date" text COLLATE "pg_catalog"."default",
   "day" varchar(255) COLLATE "pg_catalog"."default",
   "hour" varchar(255) COLLATE "pg_catalog"."default",
@@ -0,0 +1,36 @@
+-- ========================================
+-- 数据库迁移脚本：添加成交量、成交额、成交笔数和主动买入字段
+-- ========================================
+
+-- 步骤 1: 添加字段（如果不存在），已有数据填充为 0
+ALTER TABLE public.klines ADD COLUMN IF NOT EXISTS volume numeric NOT NULL DEFAULT 0;
+ALTER TABLE public.klines ADD COLUMN IF NOT EXISTS quote_volume numeric NOT NULL DEFAULT 0;
+ALTER TABLE public.klines ADD COLUMN IF NOT EXISTS trade_num int8 NOT NULL DEFAULT 0;
+ALTER TABLE public.klines ADD COLUMN IF NOT EXISTS taker_buy_volume numeric NOT NULL DEFAULT 0;
+ALTER TABLE public.klines ADD COLUMN IF NOT EXISTS taker_buy_quote_volume numeric NOT NULL DEFAULT 0;
+
+ALTER TABLE public.kline_ws ADD COLUMN IF NOT EXISTS volume numeric NOT NULL DEFAULT 0;
+ALTER TABLE public.kline_ws ADD COLUMN IF NOT EXISTS quote_volume numeric NOT NULL DEFAULT 0;
+ALTER TABLE public.kline_ws ADD COLUMN IF NOT EXISTS trade_num int8 NOT NULL DEFAULT 0;
+ALTER TABLE public.kline_ws ADD COLUMN IF NOT EXISTS taker_buy_volume numeric NOT NULL DEFAULT 0;
+ALTER TABLE public.kline_ws ADD COLUMN IF NOT EXISTS taker_buy_quote_volume numeric NOT NULL DEFAULT 0;
+
+-- 步骤 2: 查看需要回填的数据量（旧数据这些字段为 0）
+SELECT symbol, interval, COUNT(*) AS missing_count, MIN(open_time) AS earliest, MAX(open_time) AS latest
+FROM public.klines
+WHERE trade_num = 0
+GROUP BY symbol, interval
+ORDER BY symbol, interval;
+
+-- 步骤 3: 回填历史数据
+-- 成交量数据只能从币安重新获取，执行以下命令（按缺失区间重新拉取并覆盖写入）：
+--   go run ./cmd/backfill_volume
+-- 只回填指定交易对/周期：
+--   go run ./cmd/backfill_volume -symbol=BTCUSDT -interval=1d
+
+-- 步骤 4: 验证回填结果（应返回 0）
+SELECT COUNT(*) AS missing_count
+FROM public.klines
+WHERE trade_num = 0;
+
+-- 迁移完成！
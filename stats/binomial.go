@@ -0,0 +1,88 @@
+// Package stats 提供策略统计检验用到的基础统计函数（比例置信区间、二项检验、检验功效）
+package stats
+
+import "math"
+
+const (
+	// DefaultConfidence 默认置信水平
+	DefaultConfidence = 0.95
+	// DefaultAlpha 默认显著性水平
+	DefaultAlpha = 0.05
+	// DefaultPower 默认检验功效
+	DefaultPower = 0.8
+)
+
+// NormalQuantile 标准正态分布的分位数函数，p 需在 (0, 1) 之间
+func NormalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// WilsonInterval 计算成功率的 Wilson score 置信区间（返回比例，0-1）
+// 相比正态近似区间，在样本少或比例接近0/1时更可靠
+func WilsonInterval(successes, trials int, confidence float64) (lower, upper float64) {
+	if trials <= 0 {
+		return 0, 1
+	}
+
+	n := float64(trials)
+	phat := float64(successes) / n
+	z := NormalQuantile(1 - (1-confidence)/2)
+	z2 := z * z
+
+	denom := 1 + z2/n
+	center := (phat + z2/(2*n)) / denom
+	margin := z * math.Sqrt(phat*(1-phat)/n+z2/(4*n*n)) / denom
+
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// BinomialPValue 精确二项检验的双侧p值：原假设为成功概率等于 p
+// 双侧p值为所有概率不大于观测值概率的结果之和（与 R 的 binom.test 一致）
+func BinomialPValue(successes, trials int, p float64) float64 {
+	if trials <= 0 || successes < 0 || successes > trials {
+		return 1
+	}
+	if p <= 0 {
+		if successes == 0 {
+			return 1
+		}
+		return 0
+	}
+	if p >= 1 {
+		if successes == trials {
+			return 1
+		}
+		return 0
+	}
+
+	observed := binomialLogPMF(successes, trials, p)
+	// 允许浮点误差，避免与观测值概率相等的结果被漏掉
+	threshold := observed + math.Log1p(1e-7)
+
+	var pValue float64
+	for k := 0; k <= trials; k++ {
+		if logPMF := binomialLogPMF(k, trials, p); logPMF <= threshold {
+			pValue += math.Exp(logPMF)
+		}
+	}
+	return math.Min(1, pValue)
+}
+
+// MinDetectableEdge 在给定样本量下，以 alpha 显著性水平(双侧)和 power 检验功效
+// 能检测出的成功率与基准 p 的最小差值（返回比例，0-1）
+func MinDetectableEdge(trials int, p, alpha, power float64) float64 {
+	if trials <= 0 {
+		return 1
+	}
+	zAlpha := NormalQuantile(1 - alpha/2)
+	zBeta := NormalQuantile(power)
+	return (zAlpha + zBeta) * math.Sqrt(p*(1-p)/float64(trials))
+}
+
+// binomialLogPMF 二项分布概率质量函数的对数
+func binomialLogPMF(k, n int, p float64) float64 {
+	lgN, _ := math.Lgamma(float64(n + 1))
+	lgK, _ := math.Lgamma(float64(k + 1))
+	lgNK, _ := math.Lgamma(float64(n - k + 1))
+	return lgN - lgK - lgNK + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p)
+}
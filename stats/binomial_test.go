@@ -0,0 +1,57 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func TestWilsonInterval(t *testing.T) {
+	lower, upper := WilsonInterval(5, 10, DefaultConfidence)
+	if !almostEqual(lower, 0.2366, 1e-4) || !almostEqual(upper, 0.7634, 1e-4) {
+		t.Errorf("5/10 的区间错误: [%.4f, %.4f]", lower, upper)
+	}
+
+	// 全部成功时上限为1，下限仍小于1
+	lower, upper = WilsonInterval(10, 10, DefaultConfidence)
+	if upper != 1 || !almostEqual(lower, 0.7225, 1e-4) {
+		t.Errorf("10/10 的区间错误: [%.4f, %.4f]", lower, upper)
+	}
+
+	if lower, upper := WilsonInterval(0, 0, DefaultConfidence); lower != 0 || upper != 1 {
+		t.Errorf("无样本时应返回 [0, 1]，实际 [%.4f, %.4f]", lower, upper)
+	}
+}
+
+func TestBinomialPValue(t *testing.T) {
+	tests := []struct {
+		successes, trials int
+		p, want           float64
+	}{
+		{7, 10, 0.5, 0.34375},
+		{9, 10, 0.5, 0.021484375},
+		{5, 10, 0.5, 1},
+		{3, 20, 0.3, 0.220418}, // 非对称分布
+	}
+	for _, tt := range tests {
+		got := BinomialPValue(tt.successes, tt.trials, tt.p)
+		if !almostEqual(got, tt.want, 1e-4) {
+			t.Errorf("BinomialPValue(%d, %d, %.2f) = %.6f，期望 %.6f", tt.successes, tt.trials, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestMinDetectableEdge(t *testing.T) {
+	got := MinDetectableEdge(100, 0.5, DefaultAlpha, DefaultPower)
+	if !almostEqual(got, 0.140079, 1e-5) {
+		t.Errorf("MinDetectableEdge(100) = %.6f，期望 0.140079", got)
+	}
+
+	// 样本量变为4倍，最小可检测优势减半
+	if quarter := MinDetectableEdge(400, 0.5, DefaultAlpha, DefaultPower); !almostEqual(quarter*2, got, 1e-9) {
+		t.Errorf("样本量4倍时应减半: %.6f vs %.6f", quarter, got)
+	}
+}
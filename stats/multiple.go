@@ -0,0 +1,47 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Bonferroni 返回 Bonferroni 校正后的p值（p×检验次数，上限为1），控制族错误率(FWER)
+func Bonferroni(pValues []float64) []float64 {
+	m := float64(len(pValues))
+	adjusted := make([]float64, len(pValues))
+	for i, p := range pValues {
+		adjusted[i] = math.Min(1, p*m)
+	}
+	return adjusted
+}
+
+// BenjaminiHochberg 返回 Benjamini–Hochberg 校正后的p值(q值)，控制错误发现率(FDR)
+// 结果与输入顺序一致，与 R 的 p.adjust(method = "BH") 相同
+func BenjaminiHochberg(pValues []float64) []float64 {
+	m := len(pValues)
+	adjusted := make([]float64, m)
+	if m == 0 {
+		return adjusted
+	}
+
+	// 按p值从大到小遍历，q(i) = min(q(i+1), p(i)×m/i)
+	order := make([]int, m)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return pValues[order[a]] > pValues[order[b]]
+	})
+
+	minSoFar := 1.0
+	for rank := 0; rank < m; rank++ {
+		index := order[rank]
+		i := m - rank // 升序下的名次(1..m)
+		q := pValues[index] * float64(m) / float64(i)
+		if q < minSoFar {
+			minSoFar = q
+		}
+		adjusted[index] = minSoFar
+	}
+	return adjusted
+}
@@ -0,0 +1,28 @@
+package stats
+
+import "testing"
+
+func TestBonferroni(t *testing.T) {
+	got := Bonferroni([]float64{0.01, 0.04, 0.5})
+	want := []float64{0.03, 0.12, 1}
+	for i := range want {
+		if !almostEqual(got[i], want[i], 1e-12) {
+			t.Errorf("Bonferroni[%d] = %.4f，期望 %.4f", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBenjaminiHochberg(t *testing.T) {
+	// 与 R: p.adjust(c(0.01, 0.04, 0.03, 0.005, 0.5), "BH") 一致
+	got := BenjaminiHochberg([]float64{0.01, 0.04, 0.03, 0.005, 0.5})
+	want := []float64{0.025, 0.05, 0.05, 0.025, 0.5}
+	for i := range want {
+		if !almostEqual(got[i], want[i], 1e-12) {
+			t.Errorf("BenjaminiHochberg[%d] = %.4f，期望 %.4f", i, got[i], want[i])
+		}
+	}
+
+	if got := BenjaminiHochberg(nil); len(got) != 0 {
+		t.Errorf("空输入应返回空结果: %v", got)
+	}
+}
@@ -0,0 +1,920 @@
+package store
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"trade/model"
+)
+
+// MemoryStore 内存存储实现，用于单元测试和离线运行
+type MemoryStore struct {
+	mu sync.RWMutex
+
+	klines      map[string][]model.Kline // symbol|interval -> 按开盘时间升序的K线
+	checkpoints map[string]*model.KlineCheckpoint
+	funding     map[string][]model.FundingRate // symbol -> 按结算时间升序的资金费率
+
+	openInterest    map[string][]model.OpenInterest   // symbol|period -> 按时间升序
+	longShortRatios map[string][]model.LongShortRatio // symbol|period|kind -> 按时间升序
+	takerVolumes    map[string][]model.TakerVolume    // symbol|period -> 按时间升序
+
+	nextID           int
+	strategy1Results []model.Strategy1Result
+	strategy1Details map[int][]model.Strategy1DetailRecord
+	strategy2Results []model.Strategy2Result
+	strategy2Details map[int][]model.Strategy2DetailRecord
+	strategy3Results []model.Strategy3Result
+
+	paperAccounts  []model.PaperAccount
+	paperPositions []model.PaperPosition
+	paperOrders    []model.PaperOrder
+	paperFills     []model.PaperFill
+	paperEquity    []model.PaperEquity
+
+	executionOrders []model.ExecutionOrder
+
+	killSwitch     *model.KillSwitch
+	riskStates     []model.RiskState
+	riskRejections []model.RiskRejection
+}
+
+// NewMemoryStore 创建空的内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		klines:           make(map[string][]model.Kline),
+		checkpoints:      make(map[string]*model.KlineCheckpoint),
+		funding:          make(map[string][]model.FundingRate),
+		openInterest:     make(map[string][]model.OpenInterest),
+		longShortRatios:  make(map[string][]model.LongShortRatio),
+		takerVolumes:     make(map[string][]model.TakerVolume),
+		strategy1Details: make(map[int][]model.Strategy1DetailRecord),
+		strategy2Details: make(map[int][]model.Strategy2DetailRecord),
+	}
+}
+
+func seriesKey(symbol, interval string) string {
+	return symbol + "|" + interval
+}
+
+func (s *MemoryStore) UpsertKlines(klines []model.Kline) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	touched := make(map[string]bool)
+	for _, k := range klines {
+		key := seriesKey(k.Symbol, k.Interval)
+		series := s.klines[key]
+
+		replaced := false
+		for i := range series {
+			if series[i].OpenTime.Equal(k.OpenTime) {
+				k.ID = series[i].ID
+				series[i] = k
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.nextID++
+			k.ID = s.nextID
+			series = append(series, k)
+		}
+
+		s.klines[key] = series
+		touched[key] = true
+	}
+
+	for key := range touched {
+		series := s.klines[key]
+		sort.Slice(series, func(i, j int) bool {
+			return series[i].OpenTime.Before(series[j].OpenTime)
+		})
+	}
+
+	return int64(len(klines)), nil
+}
+
+func (s *MemoryStore) FindKlines(query KlineQuery) ([]model.Kline, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	klines := make([]model.Kline, 0)
+	for _, series := range s.klines {
+		for _, k := range series {
+			if query.Symbol != "" && k.Symbol != query.Symbol {
+				continue
+			}
+			if query.Interval != "" && k.Interval != query.Interval {
+				continue
+			}
+			if query.Day != "" && k.Day != query.Day {
+				continue
+			}
+			if query.Hour != "" && k.Hour != query.Hour {
+				continue
+			}
+			if !query.Start.IsZero() && k.OpenTime.Before(query.Start) {
+				continue
+			}
+			if !query.End.IsZero() && k.OpenTime.After(query.End) {
+				continue
+			}
+			if !query.ClosedBefore.IsZero() && !k.CloseTime.Before(query.ClosedBefore) {
+				continue
+			}
+			klines = append(klines, k)
+		}
+	}
+
+	sort.SliceStable(klines, func(i, j int) bool {
+		return klines[i].OpenTime.Before(klines[j].OpenTime)
+	})
+	if query.Limit > 0 && len(klines) > query.Limit {
+		klines = klines[:query.Limit]
+	}
+	return klines, nil
+}
+
+func (s *MemoryStore) LatestKline(symbol, interval string) (*model.Kline, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	series := s.klines[seriesKey(symbol, interval)]
+	if len(series) == 0 {
+		return nil, nil
+	}
+	latest := series[len(series)-1]
+	return &latest, nil
+}
+
+func (s *MemoryStore) OpenTimes(symbol, interval string) ([]time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	series := s.klines[seriesKey(symbol, interval)]
+	openTimes := make([]time.Time, 0, len(series))
+	for _, k := range series {
+		openTimes = append(openTimes, k.OpenTime)
+	}
+	return openTimes, nil
+}
+
+func (s *MemoryStore) MissingVolumeRange(symbol, interval string) (*KlineRange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	bounds := &KlineRange{}
+	for _, k := range s.klines[seriesKey(symbol, interval)] {
+		if k.TradeNum != 0 {
+			continue
+		}
+		if bounds.Count == 0 || k.OpenTime.Before(bounds.Earliest) {
+			bounds.Earliest = k.OpenTime
+		}
+		if bounds.Count == 0 || k.OpenTime.After(bounds.Latest) {
+			bounds.Latest = k.OpenTime
+		}
+		bounds.Count++
+	}
+	return bounds, nil
+}
+
+func (s *MemoryStore) GetCheckpoint(symbol, interval string) (*model.KlineCheckpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	checkpoint, ok := s.checkpoints[seriesKey(symbol, interval)]
+	if !ok {
+		return nil, nil
+	}
+	copied := *checkpoint
+	return &copied, nil
+}
+
+// checkpointLocked 返回断点，不存在时创建（调用方需持有写锁）
+func (s *MemoryStore) checkpointLocked(symbol, interval string, now time.Time) *model.KlineCheckpoint {
+	key := seriesKey(symbol, interval)
+	checkpoint, ok := s.checkpoints[key]
+	if !ok {
+		s.nextID++
+		checkpoint = &model.KlineCheckpoint{
+			ID:        s.nextID,
+			Symbol:    symbol,
+			Interval:  interval,
+			CreatedAt: now,
+		}
+		s.checkpoints[key] = checkpoint
+	}
+	return checkpoint
+}
+
+func (s *MemoryStore) AdvanceCheckpoint(symbol, interval string, lastOpenTime time.Time, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	checkpoint := s.checkpointLocked(symbol, interval, now)
+	if lastOpenTime.After(checkpoint.LastOpenTime) {
+		checkpoint.LastOpenTime = lastOpenTime
+	}
+	checkpoint.LastRunAt = now
+	checkpoint.Status = status
+	checkpoint.Message = ""
+	checkpoint.UpdatedAt = now
+	return nil
+}
+
+func (s *MemoryStore) MarkCheckpoint(symbol, interval, status, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	checkpoint := s.checkpointLocked(symbol, interval, now)
+	checkpoint.LastRunAt = now
+	checkpoint.Status = status
+	checkpoint.Message = message
+	checkpoint.UpdatedAt = now
+	return nil
+}
+
+func (s *MemoryStore) UpsertFundingRates(rates []model.FundingRate) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	touched := make(map[string]bool)
+	now := time.Now().UTC()
+	for _, rate := range rates {
+		series := s.funding[rate.Symbol]
+		replaced := false
+		for i := range series {
+			if series[i].FundingTime.Equal(rate.FundingTime) {
+				rate.ID = series[i].ID
+				rate.CreatedAt = series[i].CreatedAt
+				rate.UpdatedAt = now
+				series[i] = rate
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.nextID++
+			rate.ID = s.nextID
+			rate.CreatedAt = now
+			rate.UpdatedAt = now
+			series = append(series, rate)
+		}
+
+		s.funding[rate.Symbol] = series
+		touched[rate.Symbol] = true
+	}
+
+	for symbol := range touched {
+		series := s.funding[symbol]
+		sort.Slice(series, func(i, j int) bool {
+			return series[i].FundingTime.Before(series[j].FundingTime)
+		})
+	}
+
+	return int64(len(rates)), nil
+}
+
+func (s *MemoryStore) FindFundingRates(symbol string, start, end time.Time) ([]model.FundingRate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rates := make([]model.FundingRate, 0)
+	for _, rate := range s.funding[symbol] {
+		if !start.IsZero() && rate.FundingTime.Before(start) {
+			continue
+		}
+		if !end.IsZero() && !rate.FundingTime.Before(end) {
+			continue
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+func (s *MemoryStore) LatestFundingRate(symbol string) (*model.FundingRate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	series := s.funding[symbol]
+	if len(series) == 0 {
+		return nil, nil
+	}
+	latest := series[len(series)-1]
+	return &latest, nil
+}
+
+// upsertTimeSeries 按统计时间覆盖或追加一条记录并保持升序，返回更新后的序列
+// timeOf 取记录的统计时间，keep 在覆盖时把旧记录的 ID/CreatedAt 带到新记录上，assign 为新记录分配 ID
+func upsertTimeSeries[T any](series []T, item T, timeOf func(*T) time.Time, keep func(item, old *T), assign func(*T)) []T {
+	at := timeOf(&item)
+	i := sort.Search(len(series), func(i int) bool { return !timeOf(&series[i]).Before(at) })
+	if i < len(series) && timeOf(&series[i]).Equal(at) {
+		keep(&item, &series[i])
+		series[i] = item
+		return series
+	}
+	assign(&item)
+	series = append(series, item)
+	copy(series[i+1:], series[i:])
+	series[i] = item
+	return series
+}
+
+// findTimeSeries 返回统计时间在 [start, end) 内的记录，零值表示不限制
+func findTimeSeries[T any](series []T, timeOf func(*T) time.Time, start, end time.Time) []T {
+	items := make([]T, 0)
+	for i := range series {
+		at := timeOf(&series[i])
+		if !start.IsZero() && at.Before(start) {
+			continue
+		}
+		if !end.IsZero() && !at.Before(end) {
+			continue
+		}
+		items = append(items, series[i])
+	}
+	return items
+}
+
+func (s *MemoryStore) UpsertOpenInterest(points []model.OpenInterest) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	for _, point := range points {
+		key := seriesKey(point.Symbol, point.Period)
+		point.UpdatedAt = now
+		s.openInterest[key] = upsertTimeSeries(s.openInterest[key], point,
+			func(p *model.OpenInterest) time.Time { return p.Time },
+			func(p, old *model.OpenInterest) { p.ID, p.CreatedAt = old.ID, old.CreatedAt },
+			func(p *model.OpenInterest) { s.nextID++; p.ID, p.CreatedAt = s.nextID, now })
+	}
+	return int64(len(points)), nil
+}
+
+func (s *MemoryStore) FindOpenInterest(symbol, period string, start, end time.Time) ([]model.OpenInterest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return findTimeSeries(s.openInterest[seriesKey(symbol, period)],
+		func(p *model.OpenInterest) time.Time { return p.Time }, start, end), nil
+}
+
+func (s *MemoryStore) UpsertLongShortRatios(ratios []model.LongShortRatio) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	for _, ratio := range ratios {
+		key := seriesKey(ratio.Symbol, ratio.Period) + "|" + ratio.Kind
+		ratio.UpdatedAt = now
+		s.longShortRatios[key] = upsertTimeSeries(s.longShortRatios[key], ratio,
+			func(r *model.LongShortRatio) time.Time { return r.Time },
+			func(r, old *model.LongShortRatio) { r.ID, r.CreatedAt = old.ID, old.CreatedAt },
+			func(r *model.LongShortRatio) { s.nextID++; r.ID, r.CreatedAt = s.nextID, now })
+	}
+	return int64(len(ratios)), nil
+}
+
+func (s *MemoryStore) FindLongShortRatios(symbol, period, kind string, start, end time.Time) ([]model.LongShortRatio, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return findTimeSeries(s.longShortRatios[seriesKey(symbol, period)+"|"+kind],
+		func(r *model.LongShortRatio) time.Time { return r.Time }, start, end), nil
+}
+
+func (s *MemoryStore) UpsertTakerVolumes(volumes []model.TakerVolume) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	for _, volume := range volumes {
+		key := seriesKey(volume.Symbol, volume.Period)
+		volume.UpdatedAt = now
+		s.takerVolumes[key] = upsertTimeSeries(s.takerVolumes[key], volume,
+			func(v *model.TakerVolume) time.Time { return v.Time },
+			func(v, old *model.TakerVolume) { v.ID, v.CreatedAt = old.ID, old.CreatedAt },
+			func(v *model.TakerVolume) { s.nextID++; v.ID, v.CreatedAt = s.nextID, now })
+	}
+	return int64(len(volumes)), nil
+}
+
+func (s *MemoryStore) FindTakerVolumes(symbol, period string, start, end time.Time) ([]model.TakerVolume, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return findTimeSeries(s.takerVolumes[seriesKey(symbol, period)],
+		func(v *model.TakerVolume) time.Time { return v.Time }, start, end), nil
+}
+
+func (s *MemoryStore) SaveStrategy1Result(result *model.Strategy1Result, details []model.Strategy1DetailRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	result.UpdatedAt = now
+
+	index := -1
+	for i, existing := range s.strategy1Results {
+		if existing.Symbol == result.Symbol && existing.Interval == result.Interval && existing.AnalyzeDay == result.AnalyzeDay {
+			index = i
+			break
+		}
+	}
+	if index >= 0 {
+		result.ID = s.strategy1Results[index].ID
+		result.CreatedAt = s.strategy1Results[index].CreatedAt
+		s.strategy1Results[index] = *result
+	} else {
+		s.nextID++
+		result.ID = s.nextID
+		result.CreatedAt = now
+		s.strategy1Results = append(s.strategy1Results, *result)
+	}
+
+	// 替换详细记录
+	records := make([]model.Strategy1DetailRecord, 0, len(details))
+	for i := range details {
+		s.nextID++
+		details[i].ID = s.nextID
+		details[i].ResultID = result.ID
+		details[i].CreatedAt = now
+		records = append(records, details[i])
+	}
+	s.strategy1Details[result.ID] = records
+	return nil
+}
+
+func (s *MemoryStore) ListStrategy1Results(symbol, interval string) ([]model.Strategy1Result, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]model.Strategy1Result, 0)
+	for _, result := range s.strategy1Results {
+		if symbol != "" && result.Symbol != symbol {
+			continue
+		}
+		if interval != "" && result.Interval != interval {
+			continue
+		}
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].CreatedAt.After(results[j].CreatedAt)
+	})
+	return results, nil
+}
+
+func (s *MemoryStore) ListStrategy1Details(resultID int) ([]model.Strategy1DetailRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	details := append([]model.Strategy1DetailRecord(nil), s.strategy1Details[resultID]...)
+	sort.SliceStable(details, func(i, j int) bool {
+		return details[i].Year < details[j].Year
+	})
+	return details, nil
+}
+
+func (s *MemoryStore) SaveStrategy2Result(result *model.Strategy2Result, details []model.Strategy2DetailRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	result.UpdatedAt = now
+
+	index := -1
+	for i, existing := range s.strategy2Results {
+		if existing.Symbol == result.Symbol && existing.Interval == result.Interval && existing.Hour == result.Hour {
+			index = i
+			break
+		}
+	}
+	if index >= 0 {
+		result.ID = s.strategy2Results[index].ID
+		result.CreatedAt = s.strategy2Results[index].CreatedAt
+		s.strategy2Results[index] = *result
+	} else {
+		s.nextID++
+		result.ID = s.nextID
+		result.CreatedAt = now
+		s.strategy2Results = append(s.strategy2Results, *result)
+	}
+
+	// 替换详细记录
+	records := make([]model.Strategy2DetailRecord, 0, len(details))
+	for i := range details {
+		s.nextID++
+		details[i].ID = s.nextID
+		details[i].ResultID = result.ID
+		details[i].CreatedAt = now
+		records = append(records, details[i])
+	}
+	s.strategy2Details[result.ID] = records
+	return nil
+}
+
+func (s *MemoryStore) ListStrategy2Results(symbol, interval string, hour *int) ([]model.Strategy2Result, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]model.Strategy2Result, 0)
+	for _, result := range s.strategy2Results {
+		if symbol != "" && result.Symbol != symbol {
+			continue
+		}
+		if interval != "" && result.Interval != interval {
+			continue
+		}
+		if hour != nil && result.Hour != *hour {
+			continue
+		}
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Hour < results[j].Hour
+	})
+	return results, nil
+}
+
+func (s *MemoryStore) ListStrategy2Details(resultID int) ([]model.Strategy2DetailRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	details := append([]model.Strategy2DetailRecord(nil), s.strategy2Details[resultID]...)
+	sort.SliceStable(details, func(i, j int) bool {
+		return details[i].Date < details[j].Date
+	})
+	return details, nil
+}
+
+func (s *MemoryStore) SaveStrategy3Result(result *model.Strategy3Result) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	result.UpdatedAt = now
+
+	for i, existing := range s.strategy3Results {
+		if existing.Symbol == result.Symbol && existing.Interval == result.Interval &&
+			existing.Weekday == result.Weekday && existing.Hour == result.Hour {
+			result.ID = existing.ID
+			result.CreatedAt = existing.CreatedAt
+			s.strategy3Results[i] = *result
+			return nil
+		}
+	}
+
+	s.nextID++
+	result.ID = s.nextID
+	result.CreatedAt = now
+	s.strategy3Results = append(s.strategy3Results, *result)
+	return nil
+}
+
+func (s *MemoryStore) ListStrategy3Results(symbol, interval string, weekday *int) ([]model.Strategy3Result, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]model.Strategy3Result, 0)
+	for _, result := range s.strategy3Results {
+		if symbol != "" && result.Symbol != symbol {
+			continue
+		}
+		if interval != "" && result.Interval != interval {
+			continue
+		}
+		if weekday != nil && result.Weekday != *weekday {
+			continue
+		}
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Weekday != results[j].Weekday {
+			return results[i].Weekday < results[j].Weekday
+		}
+		return results[i].Hour < results[j].Hour
+	})
+	return results, nil
+}
+
+func (s *MemoryStore) GetPaperAccount(name string) (*model.PaperAccount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, account := range s.paperAccounts {
+		if account.Name == name {
+			copied := account
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) SavePaperAccount(account *model.PaperAccount) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	account.UpdatedAt = now
+	for i, existing := range s.paperAccounts {
+		if existing.ID == account.ID {
+			s.paperAccounts[i] = *account
+			return nil
+		}
+	}
+
+	s.nextID++
+	account.ID = s.nextID
+	account.CreatedAt = now
+	s.paperAccounts = append(s.paperAccounts, *account)
+	return nil
+}
+
+func (s *MemoryStore) SavePaperPosition(position *model.PaperPosition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	position.UpdatedAt = now
+	for i, existing := range s.paperPositions {
+		if existing.ID == position.ID {
+			s.paperPositions[i] = *position
+			return nil
+		}
+	}
+
+	s.nextID++
+	position.ID = s.nextID
+	position.CreatedAt = now
+	s.paperPositions = append(s.paperPositions, *position)
+	return nil
+}
+
+func (s *MemoryStore) ListPaperPositions(query PaperPositionQuery) ([]model.PaperPosition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	positions := make([]model.PaperPosition, 0)
+	for _, position := range s.paperPositions {
+		if query.AccountID != 0 && position.AccountID != query.AccountID {
+			continue
+		}
+		if query.Symbol != "" && position.Symbol != query.Symbol {
+			continue
+		}
+		if query.Interval != "" && position.Interval != query.Interval {
+			continue
+		}
+		if query.Strategy != "" && position.Strategy != query.Strategy {
+			continue
+		}
+		if query.Status != "" && position.Status != query.Status {
+			continue
+		}
+		positions = append(positions, position)
+	}
+
+	sort.SliceStable(positions, func(i, j int) bool {
+		return positions[i].OpenedAt.After(positions[j].OpenedAt)
+	})
+	return positions, nil
+}
+
+func (s *MemoryStore) SavePaperOrder(order *model.PaperOrder, fill *model.PaperFill) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	order.ID = s.nextID
+	s.paperOrders = append(s.paperOrders, *order)
+
+	if fill != nil {
+		s.nextID++
+		fill.ID = s.nextID
+		fill.OrderID = order.ID
+		s.paperFills = append(s.paperFills, *fill)
+	}
+	return nil
+}
+
+func (s *MemoryStore) ListPaperOrders(accountID int, symbol string, limit int) ([]model.PaperOrder, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	orders := make([]model.PaperOrder, 0)
+	for i := len(s.paperOrders) - 1; i >= 0; i-- {
+		order := s.paperOrders[i]
+		if accountID != 0 && order.AccountID != accountID {
+			continue
+		}
+		if symbol != "" && order.Symbol != symbol {
+			continue
+		}
+		orders = append(orders, order)
+	}
+
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.After(orders[j].CreatedAt)
+	})
+	if limit > 0 && len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
+
+func (s *MemoryStore) ListPaperFills(orderID int) ([]model.PaperFill, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fills := make([]model.PaperFill, 0)
+	for _, fill := range s.paperFills {
+		if fill.OrderID == orderID {
+			fills = append(fills, fill)
+		}
+	}
+	return fills, nil
+}
+
+func (s *MemoryStore) SavePaperEquity(point *model.PaperEquity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	point.ID = s.nextID
+	s.paperEquity = append(s.paperEquity, *point)
+	return nil
+}
+
+func (s *MemoryStore) ListPaperEquity(accountID int, start, end time.Time) ([]model.PaperEquity, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	points := make([]model.PaperEquity, 0)
+	for _, point := range s.paperEquity {
+		if accountID != 0 && point.AccountID != accountID {
+			continue
+		}
+		if !start.IsZero() && point.Time.Before(start) {
+			continue
+		}
+		if !end.IsZero() && point.Time.After(end) {
+			continue
+		}
+		points = append(points, point)
+	}
+
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Time.Before(points[j].Time)
+	})
+	return points, nil
+}
+
+func (s *MemoryStore) SaveExecutionOrder(order *model.ExecutionOrder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.executionOrders {
+		if existing.ID == order.ID {
+			s.executionOrders[i] = *order
+			return nil
+		}
+	}
+
+	s.nextID++
+	order.ID = s.nextID
+	if order.CreatedAt.IsZero() {
+		order.CreatedAt = time.Now()
+	}
+	s.executionOrders = append(s.executionOrders, *order)
+	return nil
+}
+
+func (s *MemoryStore) ListExecutionOrders(symbol string, limit int) ([]model.ExecutionOrder, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	orders := make([]model.ExecutionOrder, 0)
+	for i := len(s.executionOrders) - 1; i >= 0; i-- {
+		if symbol != "" && s.executionOrders[i].Symbol != symbol {
+			continue
+		}
+		orders = append(orders, s.executionOrders[i])
+	}
+
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.After(orders[j].CreatedAt)
+	})
+	if limit > 0 && len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
+
+func (s *MemoryStore) GetKillSwitch() (*model.KillSwitch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.killSwitch == nil {
+		return nil, nil
+	}
+	copied := *s.killSwitch
+	return &copied, nil
+}
+
+func (s *MemoryStore) SaveKillSwitch(killSwitch *model.KillSwitch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	killSwitch.ID = 1
+	killSwitch.UpdatedAt = time.Now()
+	copied := *killSwitch
+	s.killSwitch = &copied
+	return nil
+}
+
+func (s *MemoryStore) GetRiskState(name string) (*model.RiskState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, state := range s.riskStates {
+		if state.Name == name {
+			copied := state
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) SaveRiskState(state *model.RiskState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state.UpdatedAt = time.Now()
+	for i, existing := range s.riskStates {
+		if existing.ID == state.ID {
+			s.riskStates[i] = *state
+			return nil
+		}
+	}
+
+	s.nextID++
+	state.ID = s.nextID
+	s.riskStates = append(s.riskStates, *state)
+	return nil
+}
+
+func (s *MemoryStore) ListRiskStates() ([]model.RiskState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	states := append([]model.RiskState{}, s.riskStates...)
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+	return states, nil
+}
+
+func (s *MemoryStore) SaveRiskRejection(rejection *model.RiskRejection) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	rejection.ID = s.nextID
+	if rejection.CreatedAt.IsZero() {
+		rejection.CreatedAt = time.Now()
+	}
+	s.riskRejections = append(s.riskRejections, *rejection)
+	return nil
+}
+
+func (s *MemoryStore) ListRiskRejections(symbol string, limit int) ([]model.RiskRejection, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rejections := make([]model.RiskRejection, 0)
+	for i := len(s.riskRejections) - 1; i >= 0; i-- {
+		if symbol != "" && s.riskRejections[i].Symbol != symbol {
+			continue
+		}
+		rejections = append(rejections, s.riskRejections[i])
+	}
+
+	sort.SliceStable(rejections, func(i, j int) bool {
+		return rejections[i].CreatedAt.After(rejections[j].CreatedAt)
+	})
+	if limit > 0 && len(rejections) > limit {
+		rejections = rejections[:limit]
+	}
+	return rejections, nil
+}
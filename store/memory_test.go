@@ -0,0 +1,131 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"trade/model"
+	"trade/utils"
+)
+
+func newTestKline(symbol, interval string, openTime time.Time, open, close float64) model.Kline {
+	k := model.Kline{
+		Symbol:   symbol,
+		Interval: interval,
+		Open:     open,
+		Close:    close,
+		OpenTime: openTime,
+		TradeNum: 1,
+	}
+	k.CloseTime = openTime.Add(utils.IntervalDuration(interval) - time.Millisecond)
+	k.FillTimeFields()
+	return k
+}
+
+func TestMemoryStoreUpsertAndFind(t *testing.T) {
+	s := NewMemoryStore()
+	base := time.Date(2024, 10, 30, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.UpsertKlines([]model.Kline{
+		newTestKline("BTCUSDT", "1h", base.Add(2*time.Hour), 3, 4),
+		newTestKline("BTCUSDT", "1h", base, 1, 2),
+		newTestKline("BTCUSDT", "1h", base.Add(time.Hour), 2, 3),
+		newTestKline("ETHUSDT", "1h", base, 1, 2),
+	})
+	if err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+
+	// 相同开盘时间覆盖写入
+	if _, err := s.UpsertKlines([]model.Kline{newTestKline("BTCUSDT", "1h", base, 1, 10)}); err != nil {
+		t.Fatalf("覆盖写入失败: %v", err)
+	}
+
+	klines, _ := s.FindKlines(KlineQuery{Symbol: "BTCUSDT", Interval: "1h"})
+	if len(klines) != 3 {
+		t.Fatalf("期望 3 根K线，实际 %d", len(klines))
+	}
+	if !klines[0].OpenTime.Equal(base) || klines[0].Close != 10 {
+		t.Errorf("第一根K线错误: %+v", klines[0])
+	}
+
+	klines, _ = s.FindKlines(KlineQuery{Symbol: "BTCUSDT", Interval: "1h", Hour: "1"})
+	if len(klines) != 1 || !klines[0].OpenTime.Equal(base.Add(time.Hour)) {
+		t.Errorf("按小时过滤错误: %+v", klines)
+	}
+
+	klines, _ = s.FindKlines(KlineQuery{Symbol: "BTCUSDT", Interval: "1h", Start: base.Add(time.Hour)})
+	if len(klines) != 2 {
+		t.Errorf("按开始时间过滤期望 2 根，实际 %d", len(klines))
+	}
+
+	// as-of: 01:00 开盘的K线在 02:00 前 1ms 收盘，02:00 时点只能看到前两根
+	klines, _ = s.FindKlines(KlineQuery{Symbol: "BTCUSDT", Interval: "1h", ClosedBefore: base.Add(2 * time.Hour)})
+	if len(klines) != 2 || !klines[1].OpenTime.Equal(base.Add(time.Hour)) {
+		t.Errorf("按收盘时间过滤期望 2 根，实际 %+v", klines)
+	}
+	klines, _ = s.FindKlines(KlineQuery{Symbol: "BTCUSDT", Interval: "1h", ClosedBefore: base.Add(2*time.Hour - time.Millisecond)})
+	if len(klines) != 1 {
+		t.Errorf("收盘时间等于上界的K线不应返回，实际 %d 根", len(klines))
+	}
+
+	latest, _ := s.LatestKline("BTCUSDT", "1h")
+	if latest == nil || !latest.OpenTime.Equal(base.Add(2*time.Hour)) {
+		t.Errorf("最新K线错误: %+v", latest)
+	}
+	if latest, _ := s.LatestKline("BTCUSDT", "4h"); latest != nil {
+		t.Errorf("无数据时应返回 nil，实际 %+v", latest)
+	}
+}
+
+func TestMemoryStoreCheckpointNeverMovesBack(t *testing.T) {
+	s := NewMemoryStore()
+	base := time.Date(2024, 10, 30, 0, 0, 0, 0, time.UTC)
+
+	if checkpoint, _ := s.GetCheckpoint("BTCUSDT", "1h"); checkpoint != nil {
+		t.Fatalf("初始不应有断点: %+v", checkpoint)
+	}
+
+	s.AdvanceCheckpoint("BTCUSDT", "1h", base.Add(time.Hour), model.CheckpointStatusRunning)
+	s.AdvanceCheckpoint("BTCUSDT", "1h", base, model.CheckpointStatusRunning)
+	s.MarkCheckpoint("BTCUSDT", "1h", model.CheckpointStatusFailed, "timeout")
+
+	checkpoint, _ := s.GetCheckpoint("BTCUSDT", "1h")
+	if checkpoint == nil {
+		t.Fatal("断点不存在")
+	}
+	if !checkpoint.LastOpenTime.Equal(base.Add(time.Hour)) {
+		t.Errorf("断点不应后退，实际 %s", checkpoint.LastOpenTime)
+	}
+	if checkpoint.Status != model.CheckpointStatusFailed || checkpoint.Message != "timeout" {
+		t.Errorf("断点状态错误: %+v", checkpoint)
+	}
+}
+
+func TestMemoryStoreSaveStrategy1ResultReplacesDetails(t *testing.T) {
+	s := NewMemoryStore()
+
+	result := &model.Strategy1Result{Symbol: "BTCUSDT", Interval: "1d", AnalyzeDay: "10-30", TotalCount: 2}
+	details := []model.Strategy1DetailRecord{{Year: "2023"}, {Year: "2022"}}
+	if err := s.SaveStrategy1Result(result, details); err != nil {
+		t.Fatalf("保存失败: %v", err)
+	}
+	firstID := result.ID
+
+	result = &model.Strategy1Result{Symbol: "BTCUSDT", Interval: "1d", AnalyzeDay: "10-30", TotalCount: 1}
+	if err := s.SaveStrategy1Result(result, []model.Strategy1DetailRecord{{Year: "2024"}}); err != nil {
+		t.Fatalf("覆盖保存失败: %v", err)
+	}
+	if result.ID != firstID {
+		t.Errorf("覆盖保存应复用ID %d，实际 %d", firstID, result.ID)
+	}
+
+	results, _ := s.ListStrategy1Results("BTCUSDT", "")
+	if len(results) != 1 || results[0].TotalCount != 1 {
+		t.Fatalf("结果错误: %+v", results)
+	}
+	saved, _ := s.ListStrategy1Details(firstID)
+	if len(saved) != 1 || saved[0].Year != "2024" {
+		t.Errorf("详细记录应被替换: %+v", saved)
+	}
+}
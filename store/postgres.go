@@ -0,0 +1,546 @@
+package store
+
+import (
+	"time"
+
+	"trade/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// klineUpdateColumns K线冲突时覆盖的列（以最新拉取的数据为准）
+var klineUpdateColumns = []string{
+	"open", "high", "low", "close", "close_time",
+	"volume", "quote_volume", "trade_num", "taker_buy_volume", "taker_buy_quote_volume", "source",
+}
+
+// PostgresStore 基于 gorm/PostgreSQL 的存储实现
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresStore 创建 PostgreSQL 存储
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) UpsertKlines(klines []model.Kline) (int64, error) {
+	if len(klines) == 0 {
+		return 0, nil
+	}
+	result := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol"}, {Name: "interval"}, {Name: "open_time"}},
+		DoUpdates: clause.AssignmentColumns(klineUpdateColumns),
+	}).Create(&klines)
+	return result.RowsAffected, result.Error
+}
+
+func (s *PostgresStore) FindKlines(query KlineQuery) ([]model.Kline, error) {
+	tx := s.db.Model(&model.Kline{})
+	if query.Symbol != "" {
+		tx = tx.Where("symbol = ?", query.Symbol)
+	}
+	if query.Interval != "" {
+		tx = tx.Where("interval = ?", query.Interval)
+	}
+	if query.Day != "" {
+		tx = tx.Where("day = ?", query.Day)
+	}
+	if query.Hour != "" {
+		tx = tx.Where("hour = ?", query.Hour)
+	}
+	if !query.Start.IsZero() {
+		tx = tx.Where("open_time >= ?", query.Start)
+	}
+	if !query.End.IsZero() {
+		tx = tx.Where("open_time <= ?", query.End)
+	}
+	if !query.ClosedBefore.IsZero() {
+		tx = tx.Where("close_time < ?", query.ClosedBefore)
+	}
+	if query.Limit > 0 {
+		tx = tx.Limit(query.Limit)
+	}
+
+	var klines []model.Kline
+	err := tx.Order("open_time ASC").Find(&klines).Error
+	return klines, err
+}
+
+func (s *PostgresStore) LatestKline(symbol, interval string) (*model.Kline, error) {
+	var latest model.Kline
+	result := s.db.Where("symbol = ? AND interval = ?", symbol, interval).
+		Order("open_time DESC").
+		Limit(1).
+		Find(&latest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &latest, nil
+}
+
+func (s *PostgresStore) OpenTimes(symbol, interval string) ([]time.Time, error) {
+	var openTimes []time.Time
+	err := s.db.Model(&model.Kline{}).
+		Where("symbol = ? AND interval = ?", symbol, interval).
+		Order("open_time ASC").
+		Pluck("open_time", &openTimes).Error
+	return openTimes, err
+}
+
+func (s *PostgresStore) MissingVolumeRange(symbol, interval string) (*KlineRange, error) {
+	var bounds KlineRange
+	err := s.db.Model(&model.Kline{}).
+		Select("MIN(open_time) AS earliest, MAX(open_time) AS latest, COUNT(*) AS count").
+		Where("symbol = ? AND interval = ? AND trade_num = 0", symbol, interval).
+		Scan(&bounds).Error
+	if err != nil {
+		return nil, err
+	}
+	return &bounds, nil
+}
+
+func (s *PostgresStore) GetCheckpoint(symbol, interval string) (*model.KlineCheckpoint, error) {
+	var checkpoint model.KlineCheckpoint
+	result := s.db.Where("symbol = ? AND interval = ?", symbol, interval).
+		Limit(1).
+		Find(&checkpoint)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &checkpoint, nil
+}
+
+func (s *PostgresStore) AdvanceCheckpoint(symbol, interval string, lastOpenTime time.Time, status string) error {
+	now := time.Now().UTC()
+	checkpoint := model.KlineCheckpoint{
+		Symbol:       symbol,
+		Interval:     interval,
+		LastOpenTime: lastOpenTime,
+		LastRunAt:    now,
+		Status:       status,
+	}
+	return s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "symbol"}, {Name: "interval"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"last_open_time": clause.Expr{SQL: "GREATEST(kline_checkpoints.last_open_time, EXCLUDED.last_open_time)"},
+			"last_run_at":    now,
+			"status":         status,
+			"message":        "",
+			"updated_at":     now,
+		}),
+	}).Create(&checkpoint).Error
+}
+
+func (s *PostgresStore) MarkCheckpoint(symbol, interval, status, message string) error {
+	now := time.Now().UTC()
+	checkpoint := model.KlineCheckpoint{
+		Symbol:    symbol,
+		Interval:  interval,
+		LastRunAt: now,
+		Status:    status,
+		Message:   message,
+	}
+	return s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "symbol"}, {Name: "interval"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"last_run_at": now,
+			"status":      status,
+			"message":     message,
+			"updated_at":  now,
+		}),
+	}).Create(&checkpoint).Error
+}
+
+func (s *PostgresStore) UpsertFundingRates(rates []model.FundingRate) (int64, error) {
+	if len(rates) == 0 {
+		return 0, nil
+	}
+	result := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol"}, {Name: "funding_time"}},
+		DoUpdates: clause.AssignmentColumns([]string{"funding_rate", "mark_price", "updated_at"}),
+	}).Create(&rates)
+	return result.RowsAffected, result.Error
+}
+
+func (s *PostgresStore) FindFundingRates(symbol string, start, end time.Time) ([]model.FundingRate, error) {
+	tx := s.db.Model(&model.FundingRate{}).Where("symbol = ?", symbol)
+	if !start.IsZero() {
+		tx = tx.Where("funding_time >= ?", start)
+	}
+	if !end.IsZero() {
+		tx = tx.Where("funding_time < ?", end)
+	}
+
+	var rates []model.FundingRate
+	err := tx.Order("funding_time").Find(&rates).Error
+	return rates, err
+}
+
+func (s *PostgresStore) LatestFundingRate(symbol string) (*model.FundingRate, error) {
+	var rates []model.FundingRate
+	if err := s.db.Where("symbol = ?", symbol).Order("funding_time DESC").Limit(1).Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, nil
+	}
+	return &rates[0], nil
+}
+
+func (s *PostgresStore) UpsertOpenInterest(points []model.OpenInterest) (int64, error) {
+	if len(points) == 0 {
+		return 0, nil
+	}
+	result := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol"}, {Name: "period"}, {Name: "time"}},
+		DoUpdates: clause.AssignmentColumns([]string{"sum_open_interest", "sum_open_interest_value", "updated_at"}),
+	}).Create(&points)
+	return result.RowsAffected, result.Error
+}
+
+func (s *PostgresStore) FindOpenInterest(symbol, period string, start, end time.Time) ([]model.OpenInterest, error) {
+	var points []model.OpenInterest
+	err := timeRange(s.db.Model(&model.OpenInterest{}).Where("symbol = ? AND period = ?", symbol, period), start, end).
+		Order("time").Find(&points).Error
+	return points, err
+}
+
+func (s *PostgresStore) UpsertLongShortRatios(ratios []model.LongShortRatio) (int64, error) {
+	if len(ratios) == 0 {
+		return 0, nil
+	}
+	result := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol"}, {Name: "period"}, {Name: "kind"}, {Name: "time"}},
+		DoUpdates: clause.AssignmentColumns([]string{"long_short_ratio", "long_share", "short_share", "updated_at"}),
+	}).Create(&ratios)
+	return result.RowsAffected, result.Error
+}
+
+func (s *PostgresStore) FindLongShortRatios(symbol, period, kind string, start, end time.Time) ([]model.LongShortRatio, error) {
+	var ratios []model.LongShortRatio
+	tx := s.db.Model(&model.LongShortRatio{}).Where("symbol = ? AND period = ? AND kind = ?", symbol, period, kind)
+	err := timeRange(tx, start, end).Order("time").Find(&ratios).Error
+	return ratios, err
+}
+
+func (s *PostgresStore) UpsertTakerVolumes(volumes []model.TakerVolume) (int64, error) {
+	if len(volumes) == 0 {
+		return 0, nil
+	}
+	result := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol"}, {Name: "period"}, {Name: "time"}},
+		DoUpdates: clause.AssignmentColumns([]string{"buy_sell_ratio", "buy_volume", "sell_volume", "updated_at"}),
+	}).Create(&volumes)
+	return result.RowsAffected, result.Error
+}
+
+func (s *PostgresStore) FindTakerVolumes(symbol, period string, start, end time.Time) ([]model.TakerVolume, error) {
+	var volumes []model.TakerVolume
+	err := timeRange(s.db.Model(&model.TakerVolume{}).Where("symbol = ? AND period = ?", symbol, period), start, end).
+		Order("time").Find(&volumes).Error
+	return volumes, err
+}
+
+// timeRange 追加 time 列的 [start, end) 过滤条件，零值表示不限制
+func timeRange(tx *gorm.DB, start, end time.Time) *gorm.DB {
+	if !start.IsZero() {
+		tx = tx.Where("time >= ?", start)
+	}
+	if !end.IsZero() {
+		tx = tx.Where("time < ?", end)
+	}
+	return tx
+}
+
+func (s *PostgresStore) SaveStrategy1Result(result *model.Strategy1Result, details []model.Strategy1DetailRecord) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("symbol = ? AND interval = ? AND analyze_day = ?", result.Symbol, result.Interval, result.AnalyzeDay).
+			Assign(result).
+			FirstOrCreate(result).Error
+		if err != nil {
+			return err
+		}
+
+		// 删除旧的详细记录后重新写入
+		if err := tx.Where("result_id = ?", result.ID).Delete(&model.Strategy1DetailRecord{}).Error; err != nil {
+			return err
+		}
+		if len(details) == 0 {
+			return nil
+		}
+		for i := range details {
+			details[i].ResultID = result.ID
+		}
+		return tx.Create(&details).Error
+	})
+}
+
+func (s *PostgresStore) ListStrategy1Results(symbol, interval string) ([]model.Strategy1Result, error) {
+	query := s.db.Model(&model.Strategy1Result{})
+	if symbol != "" {
+		query = query.Where("symbol = ?", symbol)
+	}
+	if interval != "" {
+		query = query.Where("interval = ?", interval)
+	}
+
+	var results []model.Strategy1Result
+	err := query.Order("created_at DESC").Find(&results).Error
+	return results, err
+}
+
+func (s *PostgresStore) ListStrategy1Details(resultID int) ([]model.Strategy1DetailRecord, error) {
+	var details []model.Strategy1DetailRecord
+	err := s.db.Where("result_id = ?", resultID).Order("year ASC").Find(&details).Error
+	return details, err
+}
+
+func (s *PostgresStore) SaveStrategy2Result(result *model.Strategy2Result, details []model.Strategy2DetailRecord) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("symbol = ? AND interval = ? AND hour = ?", result.Symbol, result.Interval, result.Hour).
+			Assign(result).
+			FirstOrCreate(result).Error
+		if err != nil {
+			return err
+		}
+
+		// 删除旧的详细记录后重新写入
+		if err := tx.Where("result_id = ?", result.ID).Delete(&model.Strategy2DetailRecord{}).Error; err != nil {
+			return err
+		}
+		if len(details) == 0 {
+			return nil
+		}
+		for i := range details {
+			details[i].ResultID = result.ID
+		}
+		return tx.Create(&details).Error
+	})
+}
+
+func (s *PostgresStore) ListStrategy2Results(symbol, interval string, hour *int) ([]model.Strategy2Result, error) {
+	query := s.db.Model(&model.Strategy2Result{})
+	if symbol != "" {
+		query = query.Where("symbol = ?", symbol)
+	}
+	if interval != "" {
+		query = query.Where("interval = ?", interval)
+	}
+	if hour != nil {
+		query = query.Where("hour = ?", *hour)
+	}
+
+	var results []model.Strategy2Result
+	err := query.Order("hour ASC").Find(&results).Error
+	return results, err
+}
+
+func (s *PostgresStore) ListStrategy2Details(resultID int) ([]model.Strategy2DetailRecord, error) {
+	var details []model.Strategy2DetailRecord
+	err := s.db.Where("result_id = ?", resultID).Order("date ASC").Find(&details).Error
+	return details, err
+}
+
+func (s *PostgresStore) SaveStrategy3Result(result *model.Strategy3Result) error {
+	return s.db.Where("symbol = ? AND interval = ? AND weekday = ? AND hour = ?",
+		result.Symbol, result.Interval, result.Weekday, result.Hour).
+		Assign(result).
+		FirstOrCreate(result).Error
+}
+
+func (s *PostgresStore) ListStrategy3Results(symbol, interval string, weekday *int) ([]model.Strategy3Result, error) {
+	query := s.db.Model(&model.Strategy3Result{})
+	if symbol != "" {
+		query = query.Where("symbol = ?", symbol)
+	}
+	if interval != "" {
+		query = query.Where("interval = ?", interval)
+	}
+	if weekday != nil {
+		query = query.Where("weekday = ?", *weekday)
+	}
+
+	var results []model.Strategy3Result
+	err := query.Order("weekday ASC, hour ASC").Find(&results).Error
+	return results, err
+}
+
+func (s *PostgresStore) GetPaperAccount(name string) (*model.PaperAccount, error) {
+	var accounts []model.PaperAccount
+	if err := s.db.Where("name = ?", name).Limit(1).Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+	return &accounts[0], nil
+}
+
+func (s *PostgresStore) SavePaperAccount(account *model.PaperAccount) error {
+	return s.db.Save(account).Error
+}
+
+func (s *PostgresStore) SavePaperPosition(position *model.PaperPosition) error {
+	return s.db.Save(position).Error
+}
+
+func (s *PostgresStore) ListPaperPositions(query PaperPositionQuery) ([]model.PaperPosition, error) {
+	tx := s.db.Model(&model.PaperPosition{})
+	if query.AccountID != 0 {
+		tx = tx.Where("account_id = ?", query.AccountID)
+	}
+	if query.Symbol != "" {
+		tx = tx.Where("symbol = ?", query.Symbol)
+	}
+	if query.Interval != "" {
+		tx = tx.Where("interval = ?", query.Interval)
+	}
+	if query.Strategy != "" {
+		tx = tx.Where("strategy = ?", query.Strategy)
+	}
+	if query.Status != "" {
+		tx = tx.Where("status = ?", query.Status)
+	}
+
+	var positions []model.PaperPosition
+	err := tx.Order("opened_at DESC").Find(&positions).Error
+	return positions, err
+}
+
+func (s *PostgresStore) SavePaperOrder(order *model.PaperOrder, fill *model.PaperFill) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		if fill == nil {
+			return nil
+		}
+		fill.OrderID = order.ID
+		return tx.Create(fill).Error
+	})
+}
+
+func (s *PostgresStore) ListPaperOrders(accountID int, symbol string, limit int) ([]model.PaperOrder, error) {
+	tx := s.db.Model(&model.PaperOrder{})
+	if accountID != 0 {
+		tx = tx.Where("account_id = ?", accountID)
+	}
+	if symbol != "" {
+		tx = tx.Where("symbol = ?", symbol)
+	}
+	if limit > 0 {
+		tx = tx.Limit(limit)
+	}
+
+	var orders []model.PaperOrder
+	err := tx.Order("created_at DESC, id DESC").Find(&orders).Error
+	return orders, err
+}
+
+func (s *PostgresStore) ListPaperFills(orderID int) ([]model.PaperFill, error) {
+	var fills []model.PaperFill
+	err := s.db.Where("order_id = ?", orderID).Order("id ASC").Find(&fills).Error
+	return fills, err
+}
+
+func (s *PostgresStore) SavePaperEquity(point *model.PaperEquity) error {
+	return s.db.Create(point).Error
+}
+
+func (s *PostgresStore) ListPaperEquity(accountID int, start, end time.Time) ([]model.PaperEquity, error) {
+	tx := s.db.Model(&model.PaperEquity{})
+	if accountID != 0 {
+		tx = tx.Where("account_id = ?", accountID)
+	}
+	if !start.IsZero() {
+		tx = tx.Where("time >= ?", start)
+	}
+	if !end.IsZero() {
+		tx = tx.Where("time <= ?", end)
+	}
+
+	var points []model.PaperEquity
+	err := tx.Order("time ASC").Find(&points).Error
+	return points, err
+}
+
+func (s *PostgresStore) SaveExecutionOrder(order *model.ExecutionOrder) error {
+	return s.db.Save(order).Error
+}
+
+func (s *PostgresStore) ListExecutionOrders(symbol string, limit int) ([]model.ExecutionOrder, error) {
+	tx := s.db.Model(&model.ExecutionOrder{})
+	if symbol != "" {
+		tx = tx.Where("symbol = ?", symbol)
+	}
+	if limit > 0 {
+		tx = tx.Limit(limit)
+	}
+
+	var orders []model.ExecutionOrder
+	err := tx.Order("created_at DESC, id DESC").Find(&orders).Error
+	return orders, err
+}
+
+func (s *PostgresStore) GetKillSwitch() (*model.KillSwitch, error) {
+	var switches []model.KillSwitch
+	if err := s.db.Order("id").Limit(1).Find(&switches).Error; err != nil {
+		return nil, err
+	}
+	if len(switches) == 0 {
+		return nil, nil
+	}
+	return &switches[0], nil
+}
+
+func (s *PostgresStore) SaveKillSwitch(killSwitch *model.KillSwitch) error {
+	killSwitch.ID = 1
+	return s.db.Save(killSwitch).Error
+}
+
+func (s *PostgresStore) GetRiskState(name string) (*model.RiskState, error) {
+	var states []model.RiskState
+	if err := s.db.Where("name = ?", name).Limit(1).Find(&states).Error; err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, nil
+	}
+	return &states[0], nil
+}
+
+func (s *PostgresStore) SaveRiskState(state *model.RiskState) error {
+	return s.db.Save(state).Error
+}
+
+func (s *PostgresStore) ListRiskStates() ([]model.RiskState, error) {
+	var states []model.RiskState
+	err := s.db.Order("name").Find(&states).Error
+	return states, err
+}
+
+func (s *PostgresStore) SaveRiskRejection(rejection *model.RiskRejection) error {
+	return s.db.Create(rejection).Error
+}
+
+func (s *PostgresStore) ListRiskRejections(symbol string, limit int) ([]model.RiskRejection, error) {
+	tx := s.db.Model(&model.RiskRejection{})
+	if symbol != "" {
+		tx = tx.Where("symbol = ?", symbol)
+	}
+	if limit > 0 {
+		tx = tx.Limit(limit)
+	}
+
+	var rejections []model.RiskRejection
+	err := tx.Order("created_at DESC, id DESC").Find(&rejections).Error
+	return rejections, err
+}
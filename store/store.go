@@ -0,0 +1,204 @@
+package store
+
+import (
+	"sync"
+	"time"
+
+	"trade/db"
+	"trade/model"
+)
+
+// KlineQuery K线查询条件，零值字段表示不过滤
+type KlineQuery struct {
+	Symbol   string    // 交易对
+	Interval string    // 时间周期
+	Day      string    // 日期(MM-DD)
+	Hour     string    // 小时(0-23)
+	Start    time.Time // 开盘时间下界（含）
+	End      time.Time // 开盘时间上界（含）
+
+	// ClosedBefore 收盘时间上界（不含），只返回在该时刻之前已经收盘的K线，用于时点(as-of)分析
+	ClosedBefore time.Time
+
+	// Limit 最多返回的K线数量（按开盘时间从早到晚），0 表示不限制，用于分批读取
+	Limit int
+}
+
+// KlineRange 一组K线的开盘时间范围和数量
+type KlineRange struct {
+	Earliest time.Time // 最早开盘时间
+	Latest   time.Time // 最晚开盘时间
+	Count    int64     // K线数量
+}
+
+// KlineStore K线及同步断点的存储接口
+type KlineStore interface {
+	// UpsertKlines 批量写入K线，按 symbol+interval+open_time 覆盖已有记录，返回写入条数
+	UpsertKlines(klines []model.Kline) (int64, error)
+	// FindKlines 按条件查询K线，结果按开盘时间升序
+	FindKlines(query KlineQuery) ([]model.Kline, error)
+	// LatestKline 返回最新一根K线，没有数据时返回 nil
+	LatestKline(symbol, interval string) (*model.Kline, error)
+	// OpenTimes 返回全部K线的开盘时间，按升序
+	OpenTimes(symbol, interval string) ([]time.Time, error)
+	// MissingVolumeRange 返回成交量字段为空（trade_num = 0）的K线范围
+	MissingVolumeRange(symbol, interval string) (*KlineRange, error)
+
+	// GetCheckpoint 返回同步断点，不存在时返回 nil
+	GetCheckpoint(symbol, interval string) (*model.KlineCheckpoint, error)
+	// AdvanceCheckpoint 将断点推进到 lastOpenTime（只前进不后退）
+	AdvanceCheckpoint(symbol, interval string, lastOpenTime time.Time, status string) error
+	// MarkCheckpoint 只更新断点状态，不修改 last_open_time
+	MarkCheckpoint(symbol, interval, status, message string) error
+}
+
+// FundingStore 资金费率历史的存储接口
+type FundingStore interface {
+	// UpsertFundingRates 批量写入资金费率，按 symbol+funding_time 覆盖已有记录，返回写入条数
+	UpsertFundingRates(rates []model.FundingRate) (int64, error)
+	// FindFundingRates 查询结算时间在 [start, end) 内的资金费率（按结算时间升序），零值表示不限制
+	FindFundingRates(symbol string, start, end time.Time) ([]model.FundingRate, error)
+	// LatestFundingRate 返回最新一条资金费率，没有数据时返回 nil
+	LatestFundingRate(symbol string) (*model.FundingRate, error)
+}
+
+// PositioningStore 持仓量、大户多空比和主动买卖量比的存储接口
+// 查询均返回统计时间在 [start, end) 内的记录（按时间升序），零值表示不限制
+type PositioningStore interface {
+	// UpsertOpenInterest 批量写入持仓量，按 symbol+period+time 覆盖已有记录，返回写入条数
+	UpsertOpenInterest(points []model.OpenInterest) (int64, error)
+	// FindOpenInterest 查询持仓量
+	FindOpenInterest(symbol, period string, start, end time.Time) ([]model.OpenInterest, error)
+
+	// UpsertLongShortRatios 批量写入多空比，按 symbol+period+kind+time 覆盖已有记录，返回写入条数
+	UpsertLongShortRatios(ratios []model.LongShortRatio) (int64, error)
+	// FindLongShortRatios 查询指定口径的多空比
+	FindLongShortRatios(symbol, period, kind string, start, end time.Time) ([]model.LongShortRatio, error)
+
+	// UpsertTakerVolumes 批量写入主动买卖量，按 symbol+period+time 覆盖已有记录，返回写入条数
+	UpsertTakerVolumes(volumes []model.TakerVolume) (int64, error)
+	// FindTakerVolumes 查询主动买卖量
+	FindTakerVolumes(symbol, period string, start, end time.Time) ([]model.TakerVolume, error)
+}
+
+// ResultStore 策略分析结果的存储接口
+type ResultStore interface {
+	// SaveStrategy1Result 按 symbol+interval+analyze_day 覆盖保存策略一结果，并替换其详细记录
+	SaveStrategy1Result(result *model.Strategy1Result, details []model.Strategy1DetailRecord) error
+	// ListStrategy1Results 查询策略一结果（按创建时间倒序），空字符串表示不过滤
+	ListStrategy1Results(symbol, interval string) ([]model.Strategy1Result, error)
+	// ListStrategy1Details 查询策略一结果的详细记录（按年份升序）
+	ListStrategy1Details(resultID int) ([]model.Strategy1DetailRecord, error)
+
+	// SaveStrategy2Result 按 symbol+interval+hour 覆盖保存策略二结果，并替换其详细记录
+	SaveStrategy2Result(result *model.Strategy2Result, details []model.Strategy2DetailRecord) error
+	// ListStrategy2Results 查询策略二结果（按小时升序），空字符串/nil 表示不过滤
+	ListStrategy2Results(symbol, interval string, hour *int) ([]model.Strategy2Result, error)
+	// ListStrategy2Details 查询策略二结果的详细记录（按日期升序）
+	ListStrategy2Details(resultID int) ([]model.Strategy2DetailRecord, error)
+
+	// SaveStrategy3Result 按 symbol+interval+weekday+hour 覆盖保存策略三结果
+	SaveStrategy3Result(result *model.Strategy3Result) error
+	// ListStrategy3Results 查询策略三结果（按星期、小时升序），空字符串/nil 表示不过滤
+	ListStrategy3Results(symbol, interval string, weekday *int) ([]model.Strategy3Result, error)
+}
+
+// PaperPositionQuery 模拟盘持仓查询条件，零值字段表示不过滤
+type PaperPositionQuery struct {
+	AccountID int    // 账户ID
+	Symbol    string // 交易对
+	Interval  string // 信号周期
+	Strategy  string // 策略
+	Status    string // 状态(OPEN/CLOSED)
+}
+
+// PaperStore 模拟盘账户、持仓、订单、成交和权益的存储接口
+type PaperStore interface {
+	// GetPaperAccount 按名称查找模拟盘账户，不存在时返回 nil
+	GetPaperAccount(name string) (*model.PaperAccount, error)
+	// SavePaperAccount 保存账户，ID 为0时新建
+	SavePaperAccount(account *model.PaperAccount) error
+
+	// SavePaperPosition 保存持仓，ID 为0时新建
+	SavePaperPosition(position *model.PaperPosition) error
+	// ListPaperPositions 按条件查询持仓（按开仓时间倒序）
+	ListPaperPositions(query PaperPositionQuery) ([]model.PaperPosition, error)
+
+	// SavePaperOrder 在同一事务中保存订单和成交，成交的 OrderID 指向新订单
+	SavePaperOrder(order *model.PaperOrder, fill *model.PaperFill) error
+	// ListPaperOrders 查询订单（按下单时间倒序），symbol 为空表示不过滤，limit<=0 表示不限制
+	ListPaperOrders(accountID int, symbol string, limit int) ([]model.PaperOrder, error)
+	// ListPaperFills 查询订单的成交记录
+	ListPaperFills(orderID int) ([]model.PaperFill, error)
+
+	// SavePaperEquity 保存一条权益快照
+	SavePaperEquity(point *model.PaperEquity) error
+	// ListPaperEquity 查询时间范围内的权益快照（按时间升序），零值表示不限制
+	ListPaperEquity(accountID int, start, end time.Time) ([]model.PaperEquity, error)
+}
+
+// ExecutionStore 实盘订单的存储接口
+type ExecutionStore interface {
+	// SaveExecutionOrder 保存订单，ID 为0时新建
+	SaveExecutionOrder(order *model.ExecutionOrder) error
+	// ListExecutionOrders 查询订单（按下单时间倒序），symbol 为空表示不过滤，limit<=0 表示不限制
+	ListExecutionOrders(symbol string, limit int) ([]model.ExecutionOrder, error)
+}
+
+// RiskStore 风控开关、状态和拒绝记录的存储接口
+type RiskStore interface {
+	// GetKillSwitch 返回紧急停止开关，从未设置时返回 nil
+	GetKillSwitch() (*model.KillSwitch, error)
+	// SaveKillSwitch 保存紧急停止开关（只保留一行）
+	SaveKillSwitch(killSwitch *model.KillSwitch) error
+
+	// GetRiskState 按账户名称查找风控状态，不存在时返回 nil
+	GetRiskState(name string) (*model.RiskState, error)
+	// SaveRiskState 保存风控状态，ID 为0时新建
+	SaveRiskState(state *model.RiskState) error
+	// ListRiskStates 查询全部风控状态（按名称升序）
+	ListRiskStates() ([]model.RiskState, error)
+
+	// SaveRiskRejection 保存一条风控拒绝记录
+	SaveRiskRejection(rejection *model.RiskRejection) error
+	// ListRiskRejections 查询拒绝记录（按时间倒序），symbol 为空表示不过滤，limit<=0 表示不限制
+	ListRiskRejections(symbol string, limit int) ([]model.RiskRejection, error)
+}
+
+// Store 同时提供K线、资金费率、持仓统计、策略结果、模拟盘、实盘订单和风控的存储
+type Store interface {
+	KlineStore
+	FundingStore
+	PositioningStore
+	ResultStore
+	PaperStore
+	ExecutionStore
+	RiskStore
+}
+
+var (
+	defaultMu    sync.RWMutex
+	defaultStore Store
+)
+
+// Default 返回全局存储，未设置时使用 db.Pog 对应的 PostgreSQL 存储
+func Default() Store {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	if defaultStore != nil {
+		return defaultStore
+	}
+	return NewPostgresStore(db.Pog)
+}
+
+// SetDefault 替换全局存储（测试中可传入 NewMemoryStore()），传 nil 恢复默认
+func SetDefault(s Store) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultStore = s
+}
+
+var (
+	_ Store = (*PostgresStore)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
@@ -0,0 +1,696 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"trade/model"
+)
+
+// printSymbolHeader 打印交易对分隔标题
+func printSymbolHeader(index, total int, symbol string) {
+	fmt.Printf("\n")
+	fmt.Printf("═══════════════════════════════════════════════════════════════\n")
+	fmt.Printf("  交易对 [%d/%d]: %s\n", index+1, total, symbol)
+	fmt.Printf("═══════════════════════════════════════════════════════════════\n")
+}
+
+// printStrategy1Header 打印策略一标题
+func printStrategy1Header(month, day, symbolCount int) {
+	fmt.Printf("\n")
+	fmt.Printf("╔════════════════════════════════════════════════════════════════╗\n")
+	fmt.Printf("║          策略一：历史同期涨跌分析（跨月对比）                  ║\n")
+	fmt.Printf("╚════════════════════════════════════════════════════════════════╝\n")
+	fmt.Printf("当前日期: %02d月%02d日\n", month, day)
+	fmt.Printf("将分析 %d 个交易对的历史数据\n\n", symbolCount)
+}
+
+// printStrategy1Footer 打印策略一结束信息
+func printStrategy1Footer() {
+	fmt.Printf("\n")
+	fmt.Printf("╔════════════════════════════════════════════════════════════════╗\n")
+	fmt.Printf("║                    所有策略分析完成                             ║\n")
+	fmt.Printf("╚════════════════════════════════════════════════════════════════╝\n")
+}
+
+// PrintStrategy1Analysis 在控制台输出策略一分析结果
+func PrintStrategy1Analysis(analysis *Strategy1Analysis) {
+	fmt.Printf("\n【时间周期: %s】\n", analysis.Interval)
+	printComparisonResults(analysis.Current, analysis.AllMonths, analysis.AllYears, analysis.Month, analysis.Day)
+}
+
+// printComparisonResults 打印对比结果
+func printComparisonResults(currentStats *DayStats, allMonthStats []*DayStats, allYearRecords []KlineRecord, currentMonth, currentDay int) {
+	// 1. 跨年对比：所有年份同一日期（例如：2018-10-30, 2019-10-30...）
+	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	fmt.Printf("📅 跨年对比：历年 %02d月%02d日 的数据\n", currentMonth, currentDay)
+	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+
+	if len(allYearRecords) == 0 {
+		fmt.Printf("⚠️  没有找到 %02d月%02d日 的历史数据\n\n", currentMonth, currentDay)
+	} else {
+		printYearlyRecords(allYearRecords, currentMonth, currentDay)
+		printReturnStats(currentStats.ReturnStats)
+		printSignificance(currentStats.UpRate, currentStats.Significance)
+	}
+
+	// 2. 跨月对比：所有月份相同日期（例如：01-30, 02-30...）
+	fmt.Printf("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	fmt.Printf("📊 跨月对比：所有月份的 %02d号（详细数据）\n", currentDay)
+	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	if len(allMonthStats) == 0 {
+		fmt.Printf("⚠️  没有找到任何数据\n")
+		return
+	}
+
+	// 按月份排序（1月到12月）
+	sort.Slice(allMonthStats, func(i, j int) bool {
+		return allMonthStats[i].Month < allMonthStats[j].Month
+	})
+
+	// 打印每个月份的详细记录
+	for i, stat := range allMonthStats {
+		marker := ""
+		if stat.Month == currentMonth {
+			marker = " 👉 当前月份"
+		}
+
+		fmt.Printf("\n【%02d月%02d日】%s\n", stat.Month, currentDay, marker)
+		fmt.Printf("样本数: %d 条 | 上涨率: %.2f%% (%d涨/%d跌/%d平)\n",
+			stat.TotalCount, stat.UpRate, stat.UpCount, stat.DownCount, stat.FlatCount)
+
+		// 打印该月份各年份的详细记录
+		if len(stat.Records) > 0 {
+			fmt.Printf("\n%-8s %-15s %-15s %-15s %-8s\n",
+				"年份", "开盘价", "收盘价", "价差", "涨跌")
+			fmt.Printf("%-8s %-15s %-15s %-15s %-8s\n",
+				"────", "─────────────", "─────────────", "─────────────", "──────")
+
+			for _, record := range stat.Records {
+				direction := "📉 跌"
+				if record.IsUp {
+					direction = "📈 涨"
+				} else if record.PriceDiff == 0 {
+					direction = "➡️ 平"
+				}
+
+				fmt.Printf("%-8s %15.2f %15.2f %+15.2f %s\n",
+					record.Year,
+					record.OpenPrice,
+					record.ClosePrice,
+					record.PriceDiff,
+					direction,
+				)
+			}
+		}
+
+		// 如果不是最后一个，添加分隔线
+		if i < len(allMonthStats)-1 {
+			fmt.Printf("\n%s\n", "─────────────────────────────────────────────────────────────")
+		}
+	}
+
+	// 3. 统计摘要
+	printMonthComparisonSummary(allMonthStats, currentMonth, currentDay)
+}
+
+// printYearlyRecords 打印跨年对比的详细记录
+func printYearlyRecords(records []KlineRecord, month, day int) {
+	if len(records) == 0 {
+		return
+	}
+
+	// 统计涨跌次数
+	upCount := 0
+	downCount := 0
+	flatCount := 0
+	for _, record := range records {
+		if record.IsUp {
+			upCount++
+		} else if record.PriceDiff < 0 {
+			downCount++
+		} else {
+			flatCount++
+		}
+	}
+
+	upRate := float64(upCount) / float64(len(records)) * 100
+
+	fmt.Printf("样本数量: %d 条\n", len(records))
+	if len(records) < 5 {
+		fmt.Printf("⚠️  样本量不足（少于5条），统计结果可能不可靠\n\n")
+	}
+
+	fmt.Printf("\n基础统计：\n")
+	fmt.Printf("  上涨次数: %d (%.2f%%)\n", upCount, upRate)
+	fmt.Printf("  下跌次数: %d (%.2f%%)\n", downCount, float64(downCount)/float64(len(records))*100)
+	fmt.Printf("  平盘次数: %d (%.2f%%)\n\n", flatCount, float64(flatCount)/float64(len(records))*100)
+
+	fmt.Printf("历年 %02d月%02d日 记录：\n", month, day)
+	fmt.Printf("%-8s %-15s %-15s %-15s %-8s\n",
+		"年份", "开盘价", "收盘价", "价差", "涨跌")
+	fmt.Printf("%-8s %-15s %-15s %-15s %-8s\n",
+		"────", "─────────────", "─────────────", "─────────────", "──────")
+
+	for _, record := range records {
+		direction := "📉 跌"
+		if record.IsUp {
+			direction = "📈 涨"
+		} else if record.PriceDiff == 0 {
+			direction = "➡️ 平"
+		}
+
+		fmt.Printf("%-8s %15.2f %15.2f %+15.2f %s\n",
+			record.Year,
+			record.OpenPrice,
+			record.ClosePrice,
+			record.PriceDiff,
+			direction,
+		)
+	}
+}
+
+// printMonthComparisonSummary 打印跨月对比统计摘要
+func printMonthComparisonSummary(allStats []*DayStats, currentMonth, currentDay int) {
+	fmt.Printf("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	fmt.Printf("💡 跨月对比摘要\n")
+	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	// 找出校正后显著高于/低于基准的月份
+	best, worst := BestWorstMonth(allStats)
+	if best != nil {
+		fmt.Printf("📈 最佳月份: %02d月%02d日 - 上涨率 %.2f%% (%d涨/%d跌, 样本%d条)%s\n",
+			best.Month, currentDay, best.UpRate, best.UpCount, best.DownCount, best.TotalCount, correctionNote(best.Significance))
+	} else {
+		fmt.Printf("📈 最佳月份: 无（没有月份的上涨率在多重比较校正后显著高于基准）\n")
+	}
+	if worst != nil {
+		fmt.Printf("📉 最差月份: %02d月%02d日 - 上涨率 %.2f%% (%d涨/%d跌, 样本%d条)%s\n\n",
+			worst.Month, currentDay, worst.UpRate, worst.UpCount, worst.DownCount, worst.TotalCount, correctionNote(worst.Significance))
+	} else {
+		fmt.Printf("📉 最差月份: 无（没有月份的上涨率在多重比较校正后显著低于基准）\n\n")
+	}
+
+	// 找出当前月份并显示信息
+	var currentStat *DayStats
+	for _, stat := range allStats {
+		if stat.Month == currentMonth {
+			currentStat = stat
+			break
+		}
+	}
+
+	if currentStat != nil {
+		// 计算排名（按上涨率）
+		rank := 1
+		for _, stat := range allStats {
+			if stat.UpRate > currentStat.UpRate {
+				rank++
+			}
+		}
+
+		fmt.Printf("🎯 当前月份 (%02d月): 上涨率 %.2f%%, 排名 %d/%d\n",
+			currentMonth, currentStat.UpRate, rank, len(allStats))
+
+		if !currentStat.SurvivesCorrection {
+			fmt.Printf("ℹ️  当前月份与基准的差异未通过多重比较校正(BH p=%.4f)，排名可能只是随机波动\n", currentStat.PValueBH)
+		} else if rank <= len(allStats)/3 {
+			fmt.Printf("✅ 当前月份表现优秀，历史上涨概率较高\n")
+		} else if rank >= len(allStats)*2/3 {
+			fmt.Printf("⚠️  当前月份表现较差，建议谨慎操作\n")
+		} else {
+			fmt.Printf("ℹ️  当前月份表现中等\n")
+		}
+	}
+
+	fmt.Printf("\n⚠️  风险提示：历史数据不代表未来表现，请结合其他技术指标综合判断！\n")
+	fmt.Printf("════════════════════════════════════════════════════════════════\n\n")
+}
+
+// printStrategy2Header 打印策略二标题
+func printStrategy2Header(currentHour, symbolCount int) {
+	fmt.Printf("\n")
+	fmt.Printf("╔════════════════════════════════════════════════════════════════╗\n")
+	fmt.Printf("║          策略二：小时级别涨跌分析（日内时段对比）              ║\n")
+	fmt.Printf("╚════════════════════════════════════════════════════════════════╝\n")
+	fmt.Printf("当前时间: %02d:00\n", currentHour)
+	fmt.Printf("将分析 %d 个交易对的小时级别数据\n\n", symbolCount)
+}
+
+// printStrategy2Footer 打印策略二结束信息
+func printStrategy2Footer() {
+	fmt.Printf("\n")
+	fmt.Printf("╔════════════════════════════════════════════════════════════════╗\n")
+	fmt.Printf("║                    小时级别分析完成                             ║\n")
+	fmt.Printf("╚════════════════════════════════════════════════════════════════╝\n")
+}
+
+// PrintStrategy2Analysis 在控制台输出策略二分析结果
+func PrintStrategy2Analysis(analysis *Strategy2Analysis) {
+	fmt.Printf("\n【时间周期: %s】\n", analysis.Interval)
+	printHourlyAnalysis(analysis.Current, analysis.AllHours, analysis.Hour, analysis.Interval)
+}
+
+// printHourlyAnalysis 打印小时级别分析结果
+func printHourlyAnalysis(currentHourStats *HourStats, allHourStats []*HourStats, currentHour int, interval string) {
+	// 1. 打印当前小时的历史表现
+	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	fmt.Printf("🕐 当前时段 %02d:00 的历史表现\n", currentHour)
+	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+
+	if currentHourStats.TotalCount == 0 {
+		fmt.Printf("⚠️  没有找到 %02d:00 的历史数据\n\n", currentHour)
+	} else {
+		fmt.Printf("样本数量: %d 条\n", currentHourStats.TotalCount)
+		if currentHourStats.TotalCount < 10 {
+			fmt.Printf("⚠️  样本量较少（少于10条），统计结果可能不可靠\n")
+		}
+		fmt.Printf("\n基础统计：\n")
+		fmt.Printf("  上涨次数: %d (%.2f%%)\n", currentHourStats.UpCount, currentHourStats.UpRate)
+		fmt.Printf("  下跌次数: %d (%.2f%%)\n", currentHourStats.DownCount,
+			float64(currentHourStats.DownCount)/float64(currentHourStats.TotalCount)*100)
+		fmt.Printf("  平盘次数: %d (%.2f%%)\n", currentHourStats.FlatCount,
+			float64(currentHourStats.FlatCount)/float64(currentHourStats.TotalCount)*100)
+		printReturnStats(currentHourStats.ReturnStats)
+		printSignificance(currentHourStats.UpRate, currentHourStats.Significance)
+	}
+
+	// 2. 打印24小时对比分析
+	if len(allHourStats) > 0 {
+		print24HourComparison(allHourStats, currentHour, interval)
+	}
+
+	// 3. 打印最佳和最差时段
+	printBestAndWorstHours(allHourStats, currentHour)
+}
+
+// print24HourComparison 打印24小时对比
+func print24HourComparison(allStats []*HourStats, currentHour int, interval string) {
+	fmt.Printf("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	fmt.Printf("📊 24小时涨跌概率分布 (%s周期)\n", interval)
+	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	// 按小时排序
+	sort.Slice(allStats, func(i, j int) bool {
+		return allStats[i].Hour < allStats[j].Hour
+	})
+
+	fmt.Printf("%-6s %-10s %-12s %-8s %-6s %s\n", "时段", "样本数", "上涨率", "涨/跌", "显著", "图表")
+	fmt.Printf("%-6s %-10s %-12s %-8s %-6s %s\n", "────", "────────", "──────────", "──────", "────", "────────────────────")
+
+	for _, stat := range allStats {
+		marker := "  "
+		if stat.Hour == currentHour {
+			marker = "👉"
+		}
+
+		// 生成可视化柱状图
+		barLength := int(stat.UpRate / 5) // 每5%一个字符
+		bar := ""
+		for i := 0; i < barLength; i++ {
+			bar += "█"
+		}
+
+		fmt.Printf("%s%02d:00 %-10d %6.2f%%    %3d/%-3d %-6s %s\n",
+			marker,
+			stat.Hour,
+			stat.TotalCount,
+			stat.UpRate,
+			stat.UpCount,
+			stat.DownCount,
+			correctionMark(stat.Significance),
+			bar,
+		)
+	}
+}
+
+// printBestAndWorstHours 打印最佳和最差时段
+func printBestAndWorstHours(allStats []*HourStats, currentHour int) {
+	if len(allStats) == 0 {
+		return
+	}
+
+	fmt.Printf("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	fmt.Printf("💡 时段对比摘要\n")
+	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	// 找出校正后显著高于/低于基准的时段（样本数>=5）
+	best, worst := BestWorstHour(allStats, 5)
+	if best != nil {
+		fmt.Printf("📈 最佳时段: %02d:00 - 上涨率 %.2f%% (%d涨/%d跌, 样本%d条)%s\n",
+			best.Hour, best.UpRate, best.UpCount, best.DownCount, best.TotalCount, correctionNote(best.Significance))
+	} else {
+		fmt.Printf("📈 最佳时段: 无（没有时段的上涨率在多重比较校正后显著高于基准）\n")
+	}
+	if worst != nil {
+		fmt.Printf("📉 最差时段: %02d:00 - 上涨率 %.2f%% (%d涨/%d跌, 样本%d条)%s\n\n",
+			worst.Hour, worst.UpRate, worst.UpCount, worst.DownCount, worst.TotalCount, correctionNote(worst.Significance))
+	} else {
+		fmt.Printf("📉 最差时段: 无（没有时段的上涨率在多重比较校正后显著低于基准）\n\n")
+	}
+
+	// 找出当前时段并显示信息
+	var currentStat *HourStats
+	for _, stat := range allStats {
+		if stat.Hour == currentHour {
+			currentStat = stat
+			break
+		}
+	}
+
+	if currentStat != nil && currentStat.TotalCount >= 5 {
+		// 计算排名（按上涨率）
+		rank := 1
+		validCount := 0
+		for _, stat := range allStats {
+			if stat.TotalCount >= 5 {
+				validCount++
+				if stat.UpRate > currentStat.UpRate {
+					rank++
+				}
+			}
+		}
+
+		fmt.Printf("🎯 当前时段 (%02d:00): 上涨率 %.2f%%, 排名 %d/%d\n",
+			currentHour, currentStat.UpRate, rank, validCount)
+
+		if !currentStat.SurvivesCorrection {
+			fmt.Printf("ℹ️  当前时段与基准的差异未通过多重比较校正(BH p=%.4f)，排名可能只是随机波动\n", currentStat.PValueBH)
+		} else if rank <= validCount/3 {
+			fmt.Printf("✅ 当前时段表现优秀，历史上涨概率较高\n")
+		} else if rank >= validCount*2/3 {
+			fmt.Printf("⚠️  当前时段表现较差，建议谨慎操作\n")
+		} else {
+			fmt.Printf("ℹ️  当前时段表现中等\n")
+		}
+	}
+
+	// 时段建议
+	fmt.Printf("\n💡 交易时段建议：\n")
+
+	// 找出高胜率时段（上涨率>60%、样本>=10且通过多重比较校正）
+	highWinRate := make([]*HourStats, 0)
+	for _, stat := range allStats {
+		if stat.TotalCount >= 10 && stat.UpRate >= 60 && stat.SurvivesCorrection {
+			highWinRate = append(highWinRate, stat)
+		}
+	}
+
+	if len(highWinRate) > 0 {
+		sort.Slice(highWinRate, func(i, j int) bool {
+			return highWinRate[i].UpRate > highWinRate[j].UpRate
+		})
+
+		fmt.Printf("  高胜率时段（上涨率≥60%%）:\n")
+		for i, stat := range highWinRate {
+			if i >= 3 { // 最多显示3个
+				break
+			}
+			fmt.Printf("    • %02d:00 (%.2f%%, 样本%d条)\n", stat.Hour, stat.UpRate, stat.TotalCount)
+		}
+	} else {
+		fmt.Printf("  暂无高胜率时段（上涨率≥60%%、样本≥10且通过多重比较校正）\n")
+	}
+
+	fmt.Printf("\n⚠️  风险提示：历史数据不代表未来表现，请结合实时行情和其他技术指标综合判断！\n")
+	fmt.Printf("════════════════════════════════════════════════════════════════\n\n")
+}
+
+// printStrategy3Header 打印策略三标题
+func printStrategy3Header(currentWeekday, symbolCount int) {
+	fmt.Printf("\n")
+	fmt.Printf("╔════════════════════════════════════════════════════════════════╗\n")
+	fmt.Printf("║          策略三：星期效应分析（周内涨跌对比）                  ║\n")
+	fmt.Printf("╚════════════════════════════════════════════════════════════════╝\n")
+	fmt.Printf("当前星期: %s\n", WeekdayName(currentWeekday))
+	fmt.Printf("将分析 %d 个交易对的历史数据\n\n", symbolCount)
+}
+
+// printStrategy3Footer 打印策略三结束信息
+func printStrategy3Footer() {
+	fmt.Printf("\n")
+	fmt.Printf("╔════════════════════════════════════════════════════════════════╗\n")
+	fmt.Printf("║                    星期效应分析完成                             ║\n")
+	fmt.Printf("╚════════════════════════════════════════════════════════════════╝\n")
+}
+
+// PrintStrategy3Analysis 在控制台输出策略三分析结果
+func PrintStrategy3Analysis(analysis *Strategy3Analysis) {
+	fmt.Printf("\n【时间周期: %s】\n", analysis.Interval)
+
+	// 1. 打印当前星期(×小时)的历史表现
+	label := weekdayLabel(analysis.Weekday, analysis.Hour)
+	current := analysis.Current
+	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	fmt.Printf("📅 当前 %s 的历史表现\n", label)
+	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+
+	if current.TotalCount == 0 {
+		fmt.Printf("⚠️  没有找到 %s 的历史数据\n\n", label)
+	} else {
+		fmt.Printf("样本数量: %d 条\n", current.TotalCount)
+		if current.TotalCount < 10 {
+			fmt.Printf("⚠️  样本量较少（少于10条），统计结果可能不可靠\n")
+		}
+		fmt.Printf("  上涨率: %.2f%% (%d涨/%d跌/%d平)\n", current.UpRate, current.UpCount, current.DownCount, current.FlatCount)
+		printReturnStats(current.ReturnStats)
+		printSignificance(current.UpRate, current.Significance)
+	}
+
+	if len(analysis.AllWeekdays) == 0 {
+		return
+	}
+
+	// 2. 打印周内对比
+	fmt.Printf("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	fmt.Printf("📊 周内涨跌概率分布 (%s周期)\n", analysis.Interval)
+	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	printWeekdayTable(analysis.AllWeekdays, func(stat *WeekdayStats) bool {
+		return stat.Weekday == analysis.Weekday
+	})
+
+	// 3. 日内周期打印当前星期各小时的细分
+	hours := make([]*WeekdayStats, 0, 24)
+	for _, stat := range analysis.WeekdayHours {
+		if stat.Weekday == analysis.Weekday {
+			hours = append(hours, stat)
+		}
+	}
+	if len(hours) > 0 {
+		fmt.Printf("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+		fmt.Printf("🕐 %s 各小时涨跌概率分布\n", WeekdayName(analysis.Weekday))
+		fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+		printWeekdayTable(hours, func(stat *WeekdayStats) bool {
+			return stat.Hour == analysis.Hour
+		})
+	}
+
+	// 4. 摘要
+	fmt.Printf("\n💡 星期对比摘要\n")
+	best, worst := BestWorstWeekday(analysis.AllWeekdays, 5)
+	if best != nil {
+		fmt.Printf("📈 最佳星期: %s - 上涨率 %.2f%%, 期望收益 %+.4f%% (样本%d条)%s\n",
+			WeekdayName(best.Weekday), best.UpRate, best.Expectancy, best.TotalCount, correctionNote(best.Significance))
+	} else {
+		fmt.Printf("📈 最佳星期: 无（没有样本不少于5条且在多重比较校正后上涨率显著高于基准的星期）\n")
+	}
+	if worst != nil {
+		fmt.Printf("📉 最差星期: %s - 上涨率 %.2f%%, 期望收益 %+.4f%% (样本%d条)%s\n",
+			WeekdayName(worst.Weekday), worst.UpRate, worst.Expectancy, worst.TotalCount, correctionNote(worst.Significance))
+	} else {
+		fmt.Printf("📉 最差星期: 无（没有样本不少于5条且在多重比较校正后上涨率显著低于基准的星期）\n")
+	}
+
+	fmt.Printf("\n⚠️  风险提示：历史数据不代表未来表现，请结合实时行情和其他技术指标综合判断！\n")
+	fmt.Printf("════════════════════════════════════════════════════════════════\n\n")
+}
+
+// printWeekdayTable 打印星期(×小时)统计表，isCurrent 标记当前行
+func printWeekdayTable(allStats []*WeekdayStats, isCurrent func(stat *WeekdayStats) bool) {
+	fmt.Printf("%-10s %-10s %-12s %-12s %-8s %-6s %s\n", "时段", "样本数", "上涨率", "平均涨跌幅", "涨/跌", "显著", "图表")
+	fmt.Printf("%-10s %-10s %-12s %-12s %-8s %-6s %s\n", "────────", "────────", "──────────", "──────────", "──────", "────", "────────────────────")
+
+	for _, stat := range allStats {
+		marker := "  "
+		if isCurrent(stat) {
+			marker = "👉"
+		}
+
+		// 生成可视化柱状图
+		barLength := int(stat.UpRate / 5) // 每5%一个字符
+		bar := ""
+		for i := 0; i < barLength; i++ {
+			bar += "█"
+		}
+
+		fmt.Printf("%s%-10s %-10d %6.2f%%     %+9.4f%%  %3d/%-3d %-6s %s\n",
+			marker,
+			weekdayLabel(stat.Weekday, stat.Hour),
+			stat.TotalCount,
+			stat.UpRate,
+			stat.MeanReturn,
+			stat.UpCount,
+			stat.DownCount,
+			correctionMark(stat.Significance),
+			bar,
+		)
+	}
+}
+
+// printReturnStats 打印涨跌幅统计（均值、中位数、盈亏比、期望）
+func printReturnStats(stats model.ReturnStats) {
+	fmt.Printf("\n涨跌幅统计：\n")
+	fmt.Printf("  平均涨跌幅: %+.4f%% | 中位数: %+.4f%% | 标准差: %.4f%%\n",
+		stats.MeanReturn, stats.MedianReturn, stats.StdDev)
+	fmt.Printf("  平均涨幅: %+.4f%% | 平均跌幅: %+.4f%% | 盈亏比: %.2f\n",
+		stats.AvgWin, stats.AvgLoss, stats.ProfitFactor)
+	fmt.Printf("  期望收益: %+.4f%%", stats.Expectancy)
+	if stats.Expectancy < 0 {
+		fmt.Printf(" ⚠️  期望为负，亏损幅度大于盈利幅度")
+	}
+	fmt.Printf("\n\n")
+}
+
+// printSignificance 打印上涨率的置信区间和相对基准的显著性
+func printSignificance(upRate float64, sig model.Significance) {
+	fmt.Printf("显著性检验：\n")
+	fmt.Printf("  上涨率 %.2f%% | 95%%置信区间: [%.2f%%, %.2f%%] | 基准上涨率: %.2f%%\n",
+		upRate, sig.CILower, sig.CIUpper, sig.Baseline)
+	fmt.Printf("  二项检验p值: %.4f | 最小可检测优势: ±%.2f个百分点\n", sig.PValue, sig.MinDetectableEdge)
+	fmt.Printf("  多重比较校正p值: Bonferroni %.4f | BH %.4f\n", sig.PValueBonferroni, sig.PValueBH)
+	if !sig.SurvivesCorrection {
+		fmt.Printf("  ℹ️  校正后与基准上涨率的差异不显著，可能只是随机波动\n")
+	}
+	fmt.Printf("\n")
+}
+
+// correctionMark 表格中的多重比较校正标记
+func correctionMark(sig model.Significance) string {
+	if sig.SurvivesCorrection {
+		return "✅"
+	}
+	return "-"
+}
+
+// correctionNote 最佳/最差分组后附加的多重比较校正说明
+func correctionNote(sig model.Significance) string {
+	if sig.SurvivesCorrection {
+		return fmt.Sprintf(" ✅ 校正后显著(BH p=%.4f)", sig.PValueBH)
+	}
+	return fmt.Sprintf(" ⚠️  校正后不显著(BH p=%.4f)，可能只是随机波动", sig.PValueBH)
+}
+
+// printWalkForwardHeader 打印样本外验证标题
+func printWalkForwardHeader(symbolCount int) {
+	fmt.Printf("\n")
+	fmt.Printf("╔════════════════════════════════════════════════════════════════╗\n")
+	fmt.Printf("║          样本外验证：季节性优势是否持续（逐年前推）            ║\n")
+	fmt.Printf("╚════════════════════════════════════════════════════════════════╝\n")
+	fmt.Printf("每一年只使用之前年份的统计预测当年方向\n")
+	fmt.Printf("将分析 %d 个交易对的历史数据\n\n", symbolCount)
+}
+
+// printWalkForwardFooter 打印样本外验证结束信息
+func printWalkForwardFooter() {
+	fmt.Printf("\n")
+	fmt.Printf("╔════════════════════════════════════════════════════════════════╗\n")
+	fmt.Printf("║                    样本外验证完成                               ║\n")
+	fmt.Printf("╚════════════════════════════════════════════════════════════════╝\n")
+}
+
+// PrintWalkForwardAnalysis 在控制台输出样本外验证结果
+func PrintWalkForwardAnalysis(analysis *WalkForwardAnalysis) {
+	fmt.Printf("\n【时间周期: %s | 验证: %s】\n", analysis.Interval, walkForwardBaseName(analysis.Base))
+	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+
+	if analysis.Summary.Predictions == 0 {
+		fmt.Printf("⚠️  %s\n", analysis.Summary.Conclusion)
+		return
+	}
+
+	fmt.Printf("%-6s %-8s %-10s %-10s %-12s %-10s %s\n", "年份", "预测数", "样本外命中", "样本内命中", "平均收益", "p值", "图表")
+	fmt.Printf("%-6s %-8s %-10s %-10s %-12s %-10s %s\n", "────", "──────", "────────", "────────", "──────────", "────────", "────────────────────")
+	for _, year := range analysis.Years {
+		if year.Predictions == 0 {
+			fmt.Printf("%-6d %-8d %s\n", year.Year, 0, "训练样本不足")
+			continue
+		}
+		bar := strings.Repeat("█", int(year.HitRate/5)) // 每5%一个字符
+		fmt.Printf("%-6d %-8d %6.2f%%    %6.2f%%    %+9.4f%%  %-10.4f %s\n",
+			year.Year, year.Predictions, year.HitRate, year.InSampleHitRate, year.MeanReturn, year.PValue, bar)
+	}
+
+	summary := analysis.Summary
+	fmt.Printf("\n汇总：\n")
+	fmt.Printf("  样本外命中率: %.2f%% (%d/%d) | p值: %.4f | 平均收益: %+.4f%%\n",
+		summary.HitRate, summary.Hits, summary.Predictions, summary.PValue, summary.MeanReturn)
+	fmt.Printf("  样本内命中率: %.2f%% | 前半段: %.2f%% | 后半段: %.2f%% | 趋势: %+.2f个百分点/年\n",
+		summary.InSampleHitRate, summary.FirstHalfHitRate, summary.SecondHalfHitRate, summary.Slope)
+	fmt.Printf("  结论: %s，%s\n", walkForwardVerdictText(summary.Verdict), summary.Conclusion)
+}
+
+// printFundingHeader 打印资金费率结算效应分析标题
+func printFundingHeader(symbolCount int) {
+	fmt.Printf("\n")
+	fmt.Printf("╔════════════════════════════════════════════════════════════════╗\n")
+	fmt.Printf("║        资金费率结算效应：结算(00/08/16 UTC)前后涨跌对比        ║\n")
+	fmt.Printf("╚════════════════════════════════════════════════════════════════╝\n")
+	fmt.Printf("按资金费率正负和大小分组，大/小费率分界 %.4f%%\n", DefaultFundingThreshold)
+	fmt.Printf("将分析 %d 个交易对的历史数据\n\n", symbolCount)
+}
+
+// printFundingFooter 打印资金费率结算效应分析结束信息
+func printFundingFooter() {
+	fmt.Printf("\n")
+	fmt.Printf("╔════════════════════════════════════════════════════════════════╗\n")
+	fmt.Printf("║                  资金费率结算效应分析完成                       ║\n")
+	fmt.Printf("╚════════════════════════════════════════════════════════════════╝\n")
+}
+
+// PrintFundingAnalysis 在控制台输出资金费率结算效应分析结果
+func PrintFundingAnalysis(analysis *FundingAnalysis) {
+	fmt.Printf("\n【时间周期: %s | 结算前后各 %d 根K线】\n", analysis.Interval, analysis.Window)
+	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+
+	if analysis.Latest == nil || len(analysis.Buckets) == 0 {
+		fmt.Printf("⚠️  没有找到 %s 的资金费率或结算前后的K线\n\n", analysis.Symbol)
+		return
+	}
+
+	fmt.Printf("最近结算: %s 费率 %+.4f%% (%s) | 下次结算: %s\n\n",
+		analysis.Latest.FundingTime.Format("2006-01-02 15:04"), analysis.Latest.FundingRate*100,
+		FundingBucketName(analysis.LatestBucket), analysis.NextFundingTime.Format("2006-01-02 15:04"))
+
+	// 1. 各分组结算前后的累计涨跌
+	fmt.Printf("%-12s %-8s %-10s %-12s %-12s %-10s\n", "分组", "结算数", "平均费率", "结算前涨跌", "结算后涨跌", "结算后上涨率")
+	fmt.Printf("%-12s %-8s %-10s %-12s %-12s %-10s\n", "──────────", "──────", "────────", "──────────", "──────────", "──────────")
+	for _, bucket := range analysis.Buckets {
+		marker := "  "
+		if bucket.Bucket == analysis.LatestBucket {
+			marker = "👉"
+		}
+		fmt.Printf("%s%-12s %-8d %+8.4f%%  %+9.4f%%  %+9.4f%%   %6.2f%%\n",
+			marker, FundingBucketName(bucket.Bucket), bucket.Events, bucket.AvgFundingRate,
+			bucket.Before.MeanReturn, bucket.After.MeanReturn, bucket.After.UpRate)
+	}
+
+	// 2. 当前分组结算前后每根K线
+	if current := analysis.Current; current != nil {
+		fmt.Printf("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+		fmt.Printf("🕐 %s 结算前后各K线涨跌 (序号0为结算后第一根)\n", FundingBucketName(current.Bucket))
+		fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+		fmt.Printf("%-6s %-8s %-10s %-12s %-8s %-6s %s\n", "序号", "样本数", "上涨率", "平均涨跌幅", "涨/跌", "显著", "图表")
+		fmt.Printf("%-6s %-8s %-10s %-12s %-8s %-6s %s\n", "────", "──────", "────────", "──────────", "──────", "────", "────────────────────")
+		for _, stat := range current.Offsets {
+			bar := strings.Repeat("█", int(stat.UpRate/5)) // 每5%一个字符
+			fmt.Printf("%+-6d %-8d %6.2f%%   %+9.4f%%  %3d/%-3d %-6s %s\n",
+				stat.Offset, stat.TotalCount, stat.UpRate, stat.MeanReturn,
+				stat.UpCount, stat.DownCount, correctionMark(stat.Significance), bar)
+		}
+	}
+
+	fmt.Printf("\n⚠️  风险提示：历史数据不代表未来表现，资金费率每次结算都会变化！\n")
+	fmt.Printf("════════════════════════════════════════════════════════════════\n\n")
+}
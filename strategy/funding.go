@@ -0,0 +1,374 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"trade/model"
+	"trade/store"
+	"trade/utils"
+)
+
+// 资金费率分组：按费率正负和大小（与 threshold 比较）划分
+const (
+	FundingBucketAll           = "all"            // 全部结算
+	FundingBucketNegativeLarge = "negative_large" // 费率 < -threshold
+	FundingBucketNegativeSmall = "negative_small" // -threshold ≤ 费率 < 0
+	FundingBucketPositiveSmall = "positive_small" // 0 ≤ 费率 ≤ threshold
+	FundingBucketPositiveLarge = "positive_large" // 费率 > threshold
+)
+
+const (
+	// DefaultFundingWindow 结算前后各统计的K线根数
+	DefaultFundingWindow = 4
+	// DefaultFundingThreshold 大/小费率的分界(%)，币安默认费率为 0.01%
+	DefaultFundingThreshold = 0.03
+	// fundingPeriod 资金费率结算间隔，每天 00:00、08:00、16:00 (UTC)
+	fundingPeriod = 8 * time.Hour
+)
+
+// fundingBuckets 输出顺序
+var fundingBuckets = []string{
+	FundingBucketAll,
+	FundingBucketNegativeLarge,
+	FundingBucketNegativeSmall,
+	FundingBucketPositiveSmall,
+	FundingBucketPositiveLarge,
+}
+
+// FundingOffsetStats 结算时间前后某一根K线的统计
+// Offset 为相对结算时间的K线序号：-1 为结算前最后一根，0 为结算后第一根
+type FundingOffsetStats struct {
+	Offset     int     `json:"offset"`      // K线序号
+	TotalCount int     `json:"total_count"` // 总样本数
+	UpCount    int     `json:"up_count"`    // 上涨次数
+	DownCount  int     `json:"down_count"`  // 下跌次数
+	FlatCount  int     `json:"flat_count"`  // 平盘次数
+	UpRate     float64 `json:"up_rate"`     // 上涨概率
+
+	// 涨跌幅统计
+	model.ReturnStats
+	// 相对基准上涨率的显著性
+	model.Significance
+}
+
+// FundingWindowStats 结算前(后)整个窗口的累计涨跌幅统计
+type FundingWindowStats struct {
+	TotalCount int     `json:"total_count"` // 窗口K线完整的结算次数
+	UpCount    int     `json:"up_count"`    // 窗口累计上涨次数
+	UpRate     float64 `json:"up_rate"`     // 窗口累计上涨概率
+
+	// 窗口累计涨跌幅统计
+	model.ReturnStats
+}
+
+// FundingBucketStats 一个资金费率分组的统计
+type FundingBucketStats struct {
+	Bucket         string                `json:"bucket"`           // 分组
+	Events         int                   `json:"events"`           // 结算次数
+	AvgFundingRate float64               `json:"avg_funding_rate"` // 平均资金费率(%)
+	Before         FundingWindowStats    `json:"before"`           // 结算前 window 根K线的累计涨跌
+	After          FundingWindowStats    `json:"after"`            // 结算后 window 根K线的累计涨跌
+	Offsets        []*FundingOffsetStats `json:"offsets"`          // 结算前后每根K线的统计（按序号排序）
+}
+
+// FundingAnalysis 资金费率结算时间效应分析结果
+type FundingAnalysis struct {
+	Symbol          string                `json:"symbol"`            // 交易对
+	Interval        string                `json:"interval"`          // 时间周期
+	Window          int                   `json:"window"`            // 结算前后各统计的K线根数
+	Threshold       float64               `json:"threshold"`         // 大/小费率分界(%)
+	Latest          *model.FundingRate    `json:"latest"`            // 最近一次结算的资金费率
+	LatestBucket    string                `json:"latest_bucket"`     // 最近一次结算所在分组
+	NextFundingTime time.Time             `json:"next_funding_time"` // 下一次结算时间
+	Current         *FundingBucketStats   `json:"current"`           // 最近一次结算所在分组的统计
+	Buckets         []*FundingBucketStats `json:"buckets"`           // 全部及各分组统计（只包含有数据的分组）
+}
+
+// fundingGroup 统计过程中一个分组的样本
+type fundingGroup struct {
+	events  int
+	rateSum float64
+	offsets map[int][]model.Kline
+	before  []float64
+	after   []float64
+}
+
+// fundingStrategy 资金费率结算时间效应分析策略
+type fundingStrategy struct{}
+
+func init() {
+	Register(&fundingStrategy{})
+}
+
+func (s *fundingStrategy) Name() string {
+	return "funding"
+}
+
+func (s *fundingStrategy) Description() string {
+	return "资金费率结算效应：统计每次结算(00/08/16 UTC)前后各K线的涨跌，按资金费率正负和大小分组比较"
+}
+
+func (s *fundingStrategy) Parameters() []Param {
+	return []Param{
+		{Name: "window", Type: "int", Default: "4（不超过8小时的K线根数）", Description: "结算前后各统计的K线根数，窗口不能超过8小时"},
+		{Name: "threshold", Type: "float", Default: strconv.FormatFloat(DefaultFundingThreshold, 'f', -1, 64), Description: "大/小资金费率的分界(%)"},
+	}
+}
+
+func (s *fundingStrategy) Analyze(ctx context.Context, input *Input) (*Result, error) {
+	if !isFundingInterval(input.Interval) {
+		return nil, fmt.Errorf("%w: 资金费率分析只支持8小时及以下的日内周期", ErrInvalidParam)
+	}
+
+	maxWindow := maxFundingWindow(input.Interval)
+	window := defaultFundingWindow(input.Interval)
+	if value, ok := input.Params["window"]; ok && value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxWindow {
+			return nil, fmt.Errorf("%w: window必须在1-%d之间", ErrInvalidParam, maxWindow)
+		}
+		window = n
+	}
+
+	threshold := DefaultFundingThreshold
+	if value, ok := input.Params["threshold"]; ok && value != "" {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < 0 {
+			return nil, fmt.Errorf("%w: threshold必须是非负数", ErrInvalidParam)
+		}
+		threshold = f
+	}
+
+	analysis := AnalyzeFunding(input.Symbol, input.Interval, window, threshold, input.AsOf)
+	if analysis.Latest == nil || len(analysis.Buckets) == 0 {
+		return nil, fmt.Errorf("%w: %s %s 资金费率", ErrNoData, input.Symbol, input.Interval)
+	}
+
+	return &Result{
+		Strategy: s.Name(),
+		Symbol:   input.Symbol,
+		Interval: input.Interval,
+		Time:     input.Time,
+		AsOf:     resultAsOf(input.AsOf),
+		Data:     analysis,
+	}, nil
+}
+
+func (s *fundingStrategy) Run(config *model.Config, asOf time.Time) {
+	if config == nil || len(config.Symbols) == 0 {
+		fmt.Println("⚠️  配置文件为空，无法执行策略分析")
+		return
+	}
+
+	printFundingHeader(len(config.Symbols))
+	for i, symbolConfig := range config.Symbols {
+		printSymbolHeader(i, len(config.Symbols), symbolConfig.Symbol)
+		for _, interval := range symbolConfig.Intervals {
+			if !isFundingInterval(interval) {
+				continue
+			}
+			analysis := AnalyzeFunding(symbolConfig.Symbol, interval, defaultFundingWindow(interval), DefaultFundingThreshold, asOf)
+			PrintFundingAnalysis(analysis)
+		}
+	}
+	printFundingFooter()
+}
+
+// AnalyzeFunding 统计每次资金费率结算前后 window 根K线的涨跌，并按费率正负和大小(threshold, %)分组
+// asOf 非零时只使用在该时刻之前已收盘的K线和已结算的资金费率
+func AnalyzeFunding(symbol, interval string, window int, threshold float64, asOf time.Time) *FundingAnalysis {
+	analysis := &FundingAnalysis{
+		Symbol:          symbol,
+		Interval:        interval,
+		Window:          window,
+		Threshold:       threshold,
+		NextFundingTime: nextFundingTime(analysisTime(asOf)),
+		Buckets:         []*FundingBucketStats{},
+	}
+
+	rates, err := store.Default().FindFundingRates(symbol, time.Time{}, asOf)
+	if err != nil || len(rates) == 0 {
+		return analysis
+	}
+	latest := rates[len(rates)-1]
+	analysis.Latest = &latest
+	analysis.LatestBucket = fundingBucket(latest.FundingRate*100, threshold)
+
+	klines, err := store.Default().FindKlines(store.KlineQuery{Symbol: symbol, Interval: interval, ClosedBefore: asOf})
+	if err != nil || len(klines) == 0 {
+		return analysis
+	}
+	byOpenTime := make(map[int64]model.Kline, len(klines))
+	for _, kline := range klines {
+		byOpenTime[kline.OpenTime.UnixMilli()] = kline
+	}
+
+	// 逐次结算收集前后K线，同时计入“全部”和所属分组
+	step := utils.IntervalDuration(interval)
+	groups := make(map[string]*fundingGroup)
+	for _, rate := range rates {
+		// 币安返回的结算时间可能有几毫秒偏差，对齐到K线开盘时间
+		anchor := rate.FundingTime.UTC().Truncate(step)
+		bars := make(map[int]model.Kline, 2*window)
+		for offset := -window; offset < window; offset++ {
+			if kline, ok := byOpenTime[anchor.Add(time.Duration(offset)*step).UnixMilli()]; ok {
+				bars[offset] = kline
+			}
+		}
+		if len(bars) == 0 {
+			continue
+		}
+
+		for _, name := range []string{FundingBucketAll, fundingBucket(rate.FundingRate*100, threshold)} {
+			group, ok := groups[name]
+			if !ok {
+				group = &fundingGroup{offsets: make(map[int][]model.Kline)}
+				groups[name] = group
+			}
+			group.events++
+			group.rateSum += rate.FundingRate * 100
+			for offset, kline := range bars {
+				group.offsets[offset] = append(group.offsets[offset], kline)
+			}
+			if r, ok := windowReturnPct(bars, -window, -1); ok {
+				group.before = append(group.before, r)
+			}
+			if r, ok := windowReturnPct(bars, 0, window-1); ok {
+				group.after = append(group.after, r)
+			}
+		}
+	}
+
+	// 以全部K线的上涨率为基准，所有分组×序号作为一组做多重比较校正
+	baseline := countKlines(klines).UpRate
+	family := make([]*model.Significance, 0)
+	for _, name := range fundingBuckets {
+		group, ok := groups[name]
+		if !ok {
+			continue
+		}
+		bucket := &FundingBucketStats{
+			Bucket:         name,
+			Events:         group.events,
+			AvgFundingRate: group.rateSum / float64(group.events),
+			Before:         calculateWindowStats(group.before),
+			After:          calculateWindowStats(group.after),
+			Offsets:        []*FundingOffsetStats{},
+		}
+		for offset := -window; offset < window; offset++ {
+			if bars, ok := group.offsets[offset]; ok {
+				stat := calculateFundingOffsetStats(offset, bars, baseline)
+				bucket.Offsets = append(bucket.Offsets, stat)
+				family = append(family, &stat.Significance)
+			}
+		}
+		analysis.Buckets = append(analysis.Buckets, bucket)
+		if name == analysis.LatestBucket {
+			analysis.Current = bucket
+		}
+	}
+	correctFamily(family)
+
+	return analysis
+}
+
+// fundingBucket 按资金费率(%)的正负和大小返回分组
+func fundingBucket(ratePct, threshold float64) string {
+	switch {
+	case ratePct < -threshold:
+		return FundingBucketNegativeLarge
+	case ratePct < 0:
+		return FundingBucketNegativeSmall
+	case ratePct <= threshold:
+		return FundingBucketPositiveSmall
+	default:
+		return FundingBucketPositiveLarge
+	}
+}
+
+// FundingBucketName 返回资金费率分组的中文名称
+func FundingBucketName(bucket string) string {
+	switch bucket {
+	case FundingBucketAll:
+		return "全部"
+	case FundingBucketNegativeLarge:
+		return "大幅负费率"
+	case FundingBucketNegativeSmall:
+		return "小幅负费率"
+	case FundingBucketPositiveSmall:
+		return "小幅正费率"
+	case FundingBucketPositiveLarge:
+		return "大幅正费率"
+	default:
+		return bucket
+	}
+}
+
+// isFundingInterval 是否可以做结算效应分析：日内周期且能整除8小时，保证结算时间落在K线边界
+func isFundingInterval(interval string) bool {
+	step := utils.IntervalDuration(interval)
+	return isIntradayInterval(interval) && step > 0 && step <= fundingPeriod && fundingPeriod%step == 0
+}
+
+// maxFundingWindow 窗口最多覆盖一个结算间隔，避免相邻两次结算的样本重叠
+func maxFundingWindow(interval string) int {
+	return int(fundingPeriod / utils.IntervalDuration(interval))
+}
+
+// defaultFundingWindow 默认窗口根数，不超过一个结算间隔
+func defaultFundingWindow(interval string) int {
+	return min(DefaultFundingWindow, maxFundingWindow(interval))
+}
+
+// nextFundingTime 返回 t 之后的下一次结算时间
+func nextFundingTime(t time.Time) time.Time {
+	return t.UTC().Truncate(fundingPeriod).Add(fundingPeriod)
+}
+
+// windowReturnPct 从第 from 根K线开盘到第 to 根K线收盘的累计涨跌幅(%)，两端K线缺失时返回 false
+func windowReturnPct(bars map[int]model.Kline, from, to int) (float64, bool) {
+	first, ok := bars[from]
+	if !ok || first.Open == 0 {
+		return 0, false
+	}
+	last, ok := bars[to]
+	if !ok {
+		return 0, false
+	}
+	return (last.Close - first.Open) / first.Open * 100, true
+}
+
+// calculateWindowStats 计算窗口累计涨跌幅统计
+func calculateWindowStats(returns []float64) FundingWindowStats {
+	stats := FundingWindowStats{
+		TotalCount:  len(returns),
+		ReturnStats: CalculateReturnStats(returns),
+	}
+	for _, r := range returns {
+		if r > 0 {
+			stats.UpCount++
+		}
+	}
+	if stats.TotalCount > 0 {
+		stats.UpRate = float64(stats.UpCount) / float64(stats.TotalCount) * 100
+	}
+	return stats
+}
+
+// calculateFundingOffsetStats 计算结算前后某一根K线的统计，baseline 为显著性检验的基准上涨率(%)
+func calculateFundingOffsetStats(offset int, klines []model.Kline, baseline float64) *FundingOffsetStats {
+	counts := countKlines(klines)
+	return &FundingOffsetStats{
+		Offset:       offset,
+		TotalCount:   counts.TotalCount,
+		UpCount:      counts.UpCount,
+		DownCount:    counts.DownCount,
+		FlatCount:    counts.FlatCount,
+		UpRate:       counts.UpRate,
+		ReturnStats:  counts.Returns,
+		Significance: significanceOf(counts.UpCount, counts.TotalCount, baseline),
+	}
+}
@@ -0,0 +1,136 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"trade/model"
+)
+
+// fundingTestData 两天的1h K线：结算前两根上涨1%，结算后两根下跌1%，其余平盘；每8小时一次结算，正负费率交替
+func fundingTestData(t *testing.T) time.Time {
+	t.Helper()
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	klines := make([]model.Kline, 0, 48)
+	for h := 0; h < 48; h++ {
+		closePrice := 100.0
+		switch h % 8 {
+		case 6, 7:
+			closePrice = 101
+		case 0, 1:
+			closePrice = 99
+		}
+		klines = append(klines, testKline("BTCUSDT", "1h", start.Add(time.Duration(h)*time.Hour), 100, closePrice))
+	}
+	memory := useMemoryStore(t, klines...)
+
+	rates := make([]model.FundingRate, 0, 6)
+	for i := 0; i < 6; i++ {
+		rate := 0.0005
+		if i%2 == 1 {
+			rate = -0.0001
+		}
+		// 币安的结算时间可能带几毫秒偏差
+		rates = append(rates, model.FundingRate{Symbol: "BTCUSDT", FundingTime: start.Add(time.Duration(i)*8*time.Hour + 3*time.Millisecond), FundingRate: rate})
+	}
+	if _, err := memory.UpsertFundingRates(rates); err != nil {
+		t.Fatalf("写入测试资金费率失败: %v", err)
+	}
+	return start
+}
+
+func TestAnalyzeFunding(t *testing.T) {
+	start := fundingTestData(t)
+
+	analysis := AnalyzeFunding("BTCUSDT", "1h", 2, DefaultFundingThreshold, time.Time{})
+	if len(analysis.Buckets) != 3 {
+		t.Fatalf("应有 全部/小幅负费率/大幅正费率 3 个分组，实际 %d", len(analysis.Buckets))
+	}
+	all := analysis.Buckets[0]
+	if all.Bucket != FundingBucketAll || all.Events != 6 || len(all.Offsets) != 4 {
+		t.Fatalf("全部分组统计错误: %+v", all)
+	}
+	// 第一次结算之前没有K线，结算前窗口少一个样本
+	if all.Before.TotalCount != 5 || all.After.TotalCount != 6 {
+		t.Errorf("窗口样本数错误: before=%d after=%d", all.Before.TotalCount, all.After.TotalCount)
+	}
+	if math.Abs(all.Before.MeanReturn-1) > 1e-9 || math.Abs(all.After.MeanReturn+1) > 1e-9 {
+		t.Errorf("窗口累计涨跌幅错误: before=%.4f after=%.4f", all.Before.MeanReturn, all.After.MeanReturn)
+	}
+	for _, stat := range all.Offsets {
+		wantUp := 0.0
+		if stat.Offset < 0 {
+			wantUp = 100
+		}
+		if stat.UpRate != wantUp {
+			t.Errorf("序号 %d 上涨率期望 %.0f%%，实际 %.2f%%", stat.Offset, wantUp, stat.UpRate)
+		}
+	}
+
+	if positive := analysis.Buckets[2]; positive.Bucket != FundingBucketPositiveLarge || positive.Events != 3 ||
+		math.Abs(positive.AvgFundingRate-0.05) > 1e-9 {
+		t.Errorf("大幅正费率分组错误: %+v", positive)
+	}
+	if analysis.LatestBucket != FundingBucketNegativeSmall || analysis.Current != analysis.Buckets[1] {
+		t.Errorf("当前分组应为最近一次结算的小幅负费率: %s", analysis.LatestBucket)
+	}
+
+	// 时点分析：只使用 as_of 之前的结算和已收盘K线
+	asOf := start.Add(20 * time.Hour)
+	analysis = AnalyzeFunding("BTCUSDT", "1h", 2, DefaultFundingThreshold, asOf)
+	if analysis.Buckets[0].Events != 3 || analysis.LatestBucket != FundingBucketPositiveLarge {
+		t.Errorf("as_of 之后的结算不应参与统计: events=%d latest=%s", analysis.Buckets[0].Events, analysis.LatestBucket)
+	}
+	if !analysis.NextFundingTime.Equal(start.Add(24 * time.Hour)) {
+		t.Errorf("下次结算时间错误: %s", analysis.NextFundingTime)
+	}
+}
+
+func TestFundingStrategyAnalyze(t *testing.T) {
+	fundingTestData(t)
+	s, ok := Get("funding")
+	if !ok {
+		t.Fatal("funding 策略未注册")
+	}
+
+	result, err := s.Analyze(context.Background(), &Input{Symbol: "BTCUSDT", Interval: "1h", Time: time.Now(), Params: map[string]string{"window": "3"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analysis := result.Data.(*FundingAnalysis); analysis.Window != 3 || analysis.Threshold != DefaultFundingThreshold {
+		t.Errorf("参数未生效: window=%d threshold=%v", analysis.Window, analysis.Threshold)
+	}
+
+	invalid := []*Input{
+		{Symbol: "BTCUSDT", Interval: "1d"},
+		{Symbol: "BTCUSDT", Interval: "1h", Params: map[string]string{"window": "9"}},
+		{Symbol: "BTCUSDT", Interval: "1h", Params: map[string]string{"threshold": "-1"}},
+	}
+	for _, input := range invalid {
+		if _, err := s.Analyze(context.Background(), input); !errors.Is(err, ErrInvalidParam) {
+			t.Errorf("%s %v 应返回参数错误，实际 %v", input.Interval, input.Params, err)
+		}
+	}
+
+	if _, err := s.Analyze(context.Background(), &Input{Symbol: "ETHUSDT", Interval: "1h"}); !errors.Is(err, ErrNoData) {
+		t.Errorf("没有资金费率时应返回 ErrNoData，实际 %v", err)
+	}
+}
+
+func TestFundingBucket(t *testing.T) {
+	cases := map[float64]string{
+		-0.05: FundingBucketNegativeLarge,
+		-0.01: FundingBucketNegativeSmall,
+		0:     FundingBucketPositiveSmall,
+		0.03:  FundingBucketPositiveSmall,
+		0.031: FundingBucketPositiveLarge,
+	}
+	for rate, want := range cases {
+		if got := fundingBucket(rate, 0.03); got != want {
+			t.Errorf("费率 %.3f%% 期望 %s，实际 %s", rate, want, got)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package strategy
+
+import (
+	"fmt"
+	"time"
+
+	"trade/model"
+)
+
+// SignalProvider 能对下一根K线给出交易方向的策略，供模拟盘/实盘自动交易使用
+type SignalProvider interface {
+	Strategy
+	// Supports 策略是否适用于该K线周期
+	Supports(interval string) bool
+	// Signal 返回开盘时间为 openTime 的K线的交易信号(SignalBullish/SignalBearish/SignalNeutral)
+	// 只使用在 asOf 之前已收盘的K线，没有历史数据时返回 ErrNoData
+	Signal(symbol, interval string, openTime, asOf time.Time) (string, error)
+}
+
+// TradeSignal 自动交易使用的信号：在期望收益信号的基础上，要求分组通过多重比较校正，否则为中性
+func TradeSignal(returns model.ReturnStats, sig model.Significance, sampleCount int) string {
+	if !sig.SurvivesCorrection {
+		return SignalNeutral
+	}
+	return ExpectancySignal(returns, sampleCount)
+}
+
+// GetSignalProvider 按名称查找支持自动交易的策略
+func GetSignalProvider(name string) (SignalProvider, error) {
+	s, ok := Get(name)
+	if !ok {
+		return nil, fmt.Errorf("策略 %s 不存在", name)
+	}
+	provider, ok := s.(SignalProvider)
+	if !ok {
+		return nil, fmt.Errorf("策略 %s 不支持自动交易", name)
+	}
+	return provider, nil
+}
+
+// 策略一按历年同一日期给出日K线信号
+func (s *strategy1) Supports(interval string) bool {
+	return interval == "1d"
+}
+
+func (s *strategy1) Signal(symbol, interval string, openTime, asOf time.Time) (string, error) {
+	openTime = openTime.UTC()
+	analysis := AnalyzeDayOfYear(symbol, interval, int(openTime.Month()), openTime.Day(), asOf)
+	current := analysis.Current
+	if current.TotalCount == 0 {
+		return "", fmt.Errorf("%w: %s %s", ErrNoData, symbol, current.Day)
+	}
+	return TradeSignal(current.ReturnStats, current.Significance, current.TotalCount), nil
+}
+
+// 策略二按历史同一小时给出小时级别K线信号
+func (s *strategy2) Supports(interval string) bool {
+	return isHourlyInterval(interval)
+}
+
+func (s *strategy2) Signal(symbol, interval string, openTime, asOf time.Time) (string, error) {
+	hour := openTime.UTC().Hour()
+	current := AnalyzeHourOfDay(symbol, interval, hour, asOf).Current
+	if current.TotalCount == 0 {
+		return "", fmt.Errorf("%w: %s %02d:00", ErrNoData, symbol, hour)
+	}
+	return TradeSignal(current.ReturnStats, current.Significance, current.TotalCount), nil
+}
+
+// 策略三按星期(日内周期按星期×小时)给出信号
+func (s *strategy3) Supports(interval string) bool {
+	return isIntradayInterval(interval) || interval == "1d"
+}
+
+func (s *strategy3) Signal(symbol, interval string, openTime, asOf time.Time) (string, error) {
+	openTime = openTime.UTC()
+	hour := WholeDay
+	if isIntradayInterval(interval) {
+		hour = openTime.Hour()
+	}
+	weekday := int(openTime.Weekday())
+	current := AnalyzeDayOfWeek(symbol, interval, weekday, hour, asOf).Current
+	if current.TotalCount == 0 {
+		return "", fmt.Errorf("%w: %s %s", ErrNoData, symbol, weekdayLabel(weekday, hour))
+	}
+	return TradeSignal(current.ReturnStats, current.Significance, current.TotalCount), nil
+}
+
+var (
+	_ SignalProvider = (*strategy1)(nil)
+	_ SignalProvider = (*strategy2)(nil)
+	_ SignalProvider = (*strategy3)(nil)
+)
@@ -0,0 +1,170 @@
+package strategy
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"trade/model"
+)
+
+// SignalModel 在内存中按策略的分组累计已收盘K线，用与 SignalProvider 相同的规则给出信号：
+// 以全部K线的上涨率为基准计算各分组显著性，按族做多重比较校正，校正后仍显著且期望收益超过一个标准误才有方向
+// 回测和样本外验证逐根喂入K线，不必每根K线重新查询全部历史
+type SignalModel struct {
+	bucket  func(t time.Time) string // K线开盘时间所在的分组
+	family  func(key string) string  // 分组所在的多重比较校正族
+	groups  map[string]*signalGroup
+	up      int               // 全部K线中上涨的数量
+	total   int               // 全部K线数量
+	signals map[string]string // 上次 Observe 之后已计算过的信号
+}
+
+// signalGroup 一个分组的累计统计
+type signalGroup struct {
+	up      int
+	total   int
+	returns []float64          // 涨跌幅(%)，开盘价为0的脏数据不参与
+	stats   *model.ReturnStats // 收益统计缓存，分组有新K线时失效
+}
+
+// NewSignalModel 按策略名称创建内存信号模型，策略必须支持自动交易且适用于该周期
+func NewSignalModel(name, interval string) (*SignalModel, error) {
+	provider, err := GetSignalProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	if !provider.Supports(interval) {
+		return nil, fmt.Errorf("策略 %s 不适用于 %s 周期", name, interval)
+	}
+	return signalModelOf(provider, interval)
+}
+
+// signalModelOf 创建策略的内存信号模型，不检查周期是否适用（样本外验证可以用策略一的分组验证任意周期）
+func signalModelOf(s Strategy, interval string) (*SignalModel, error) {
+	withModel, ok := s.(interface {
+		signalModel(interval string) *SignalModel
+	})
+	if !ok {
+		return nil, fmt.Errorf("策略 %s 不支持内存信号模型", s.Name())
+	}
+	return withModel.signalModel(interval), nil
+}
+
+func newSignalModel(bucket func(t time.Time) string, family func(key string) string) *SignalModel {
+	return &SignalModel{
+		bucket:  bucket,
+		family:  family,
+		groups:  make(map[string]*signalGroup),
+		signals: make(map[string]string),
+	}
+}
+
+// 策略一按月-日分组，同一天在12个月中的日期为一族
+func (s *strategy1) signalModel(interval string) *SignalModel {
+	return newSignalModel(
+		func(t time.Time) string { return t.UTC().Format("01-02") },
+		func(key string) string { return key[3:] },
+	)
+}
+
+// 策略二按小时分组，24个小时为一族
+func (s *strategy2) signalModel(interval string) *SignalModel {
+	return newSignalModel(
+		func(t time.Time) string { return strconv.Itoa(t.UTC().Hour()) },
+		func(string) string { return "" },
+	)
+}
+
+// 策略三日内周期按星期×小时分组，日线按星期分组，全部分组为一族
+func (s *strategy3) signalModel(interval string) *SignalModel {
+	if isIntradayInterval(interval) {
+		return newSignalModel(
+			func(t time.Time) string { return weekdayLabel(int(t.UTC().Weekday()), t.UTC().Hour()) },
+			func(string) string { return "" },
+		)
+	}
+	return newSignalModel(
+		func(t time.Time) string { return WeekdayName(int(t.UTC().Weekday())) },
+		func(string) string { return "" },
+	)
+}
+
+// Observe 累计一根已收盘的K线
+func (m *SignalModel) Observe(kline model.Kline) {
+	key := m.bucket(kline.OpenTime)
+	group, ok := m.groups[key]
+	if !ok {
+		group = &signalGroup{}
+		m.groups[key] = group
+	}
+	group.total++
+	m.total++
+	if kline.Close > kline.Open {
+		group.up++
+		m.up++
+	}
+	if kline.Open != 0 {
+		group.returns = append(group.returns, (kline.Close-kline.Open)/kline.Open*100)
+		group.stats = nil
+	}
+	// 基准上涨率变化后所有分组的显著性都要重新计算
+	clear(m.signals)
+}
+
+// Signal 按已累计的K线给出 openTime 所在分组的信号，分组没有数据时返回 ErrNoData
+func (m *SignalModel) Signal(openTime time.Time) (string, error) {
+	key := m.bucket(openTime)
+	group, ok := m.groups[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNoData, key)
+	}
+	if signal, ok := m.signals[key]; ok {
+		return signal, nil
+	}
+
+	signal := ExpectancySignal(group.returnStats(), group.total)
+	// 期望收益规则不满足时不必做显著性检验
+	if signal != SignalNeutral {
+		signal = TradeSignal(group.returnStats(), m.significance(key), group.total)
+	}
+	m.signals[key] = signal
+	return signal, nil
+}
+
+// Samples 分组已累计的K线数量
+func (m *SignalModel) Samples(openTime time.Time) int {
+	if group, ok := m.groups[m.bucket(openTime)]; ok {
+		return group.total
+	}
+	return 0
+}
+
+// significance 计算分组的显著性，与同族分组一起做多重比较校正
+func (m *SignalModel) significance(key string) model.Significance {
+	baseline := float64(m.up) / float64(m.total) * 100
+	family := m.family(key)
+
+	var current *model.Significance
+	var members []*model.Significance
+	for k, group := range m.groups {
+		if m.family(k) != family {
+			continue
+		}
+		sig := significanceOf(group.up, group.total, baseline)
+		members = append(members, &sig)
+		if k == key {
+			current = &sig
+		}
+	}
+	correctFamily(members)
+	return *current
+}
+
+func (g *signalGroup) returnStats() model.ReturnStats {
+	if g.stats == nil {
+		stats := CalculateReturnStats(g.returns)
+		g.stats = &stats
+	}
+	return *g.stats
+}
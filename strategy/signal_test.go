@@ -0,0 +1,113 @@
+package strategy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"trade/model"
+	"trade/utils"
+)
+
+func TestSignalProviderHourly(t *testing.T) {
+	var klines []model.Kline
+	for year := 2020; year <= 2023; year++ {
+		klines = append(klines, seasonalKlines(year, true)...)
+	}
+	useMemoryStore(t, klines...)
+
+	provider, err := GetSignalProvider("strategy_2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.Supports("1d") || !provider.Supports("1h") {
+		t.Error("策略二只支持小时级别周期")
+	}
+
+	asOf := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for hour, want := range map[int]string{8: SignalBullish, 9: SignalBearish} {
+		openTime := time.Date(2024, 1, 1, hour, 0, 0, 0, time.UTC)
+		if signal, err := provider.Signal("BTCUSDT", "1h", openTime, asOf); err != nil || signal != want {
+			t.Errorf("%02d:00 信号应为 %s，实际 %s (%v)", hour, want, signal, err)
+		}
+	}
+
+	// 没有历史数据的小时
+	if _, err := provider.Signal("BTCUSDT", "1h", time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC), asOf); !errors.Is(err, ErrNoData) {
+		t.Errorf("没有数据时应返回 ErrNoData，实际 %v", err)
+	}
+
+	if _, err := GetSignalProvider("walk_forward"); err == nil {
+		t.Error("walk_forward 不支持自动交易")
+	}
+}
+
+// patternKlines 生成 n 根K线：phase 返回 0 的K线上涨、返回 1 的下跌，其余小幅波动
+func patternKlines(interval string, start time.Time, n int, phase func(t time.Time) int) []model.Kline {
+	step := utils.IntervalDuration(interval)
+	klines := make([]model.Kline, 0, n)
+	for i := 0; i < n; i++ {
+		openTime := start.Add(time.Duration(i) * step)
+		move := float64((i*7)%11-5) * 0.1
+		switch phase(openTime) {
+		case 0:
+			move = 2 + float64(i%3)*0.1
+		case 1:
+			move = -2 - float64(i%3)*0.1
+		}
+		klines = append(klines, testKline("BTCUSDT", interval, openTime, 100, 100+move))
+	}
+	return klines
+}
+
+func TestSignalModelMatchesProvider(t *testing.T) {
+	dayOfMonth := func(t time.Time) int { return t.Day() - 1 }
+	hourOfDay := func(t time.Time) int { return t.Hour() - 8 }
+	weekdayHour := func(t time.Time) int { return int(t.Weekday())*24 + t.Hour() - 8 }
+	weekday := func(t time.Time) int { return int(t.Weekday()) - 1 }
+	cases := []struct {
+		name, interval string
+		klines         []model.Kline
+		compare        int // 逐根比较最后多少根K线
+	}{
+		{"strategy_1", "1d", patternKlines("1d", time.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC), 365*11, dayOfMonth), 120},
+		{"strategy_2", "1h", patternKlines("1h", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 24*60, hourOfDay), 24 * 5},
+		{"strategy_3", "1h", patternKlines("1h", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 24*7*30, weekdayHour), 24 * 7},
+		{"strategy_3", "1d", patternKlines("1d", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 7*100, weekday), 7 * 4},
+	}
+	for _, c := range cases {
+		useMemoryStore(t, c.klines...)
+		provider, _ := GetSignalProvider(c.name)
+		m, err := NewSignalModel(c.name, c.interval)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		directional := 0
+		for i, kline := range c.klines {
+			// 模型只累计了之前已收盘的K线，应与策略按同一时点查询的结果一致
+			if i >= len(c.klines)-c.compare {
+				want, wantErr := provider.Signal("BTCUSDT", c.interval, kline.OpenTime, kline.OpenTime)
+				got, err := m.Signal(kline.OpenTime)
+				if got != want || errors.Is(err, ErrNoData) != errors.Is(wantErr, ErrNoData) {
+					t.Fatalf("%s %s %s 模型信号 %s(%v) 与策略信号 %s(%v) 不一致",
+						c.name, c.interval, kline.OpenTime, got, err, want, wantErr)
+				}
+				if got != SignalNeutral {
+					directional++
+				}
+			}
+			m.Observe(kline)
+		}
+		if directional == 0 {
+			t.Errorf("%s %s 测试数据应产生有方向的信号", c.name, c.interval)
+		}
+	}
+
+	if _, err := NewSignalModel("strategy_2", "1d"); err == nil {
+		t.Error("策略不适用的周期应报错")
+	}
+	if _, err := NewSignalModel("walk_forward", "1h"); err == nil {
+		t.Error("不支持自动交易的策略应报错")
+	}
+}
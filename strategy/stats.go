@@ -0,0 +1,202 @@
+package strategy
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"trade/model"
+	"trade/stats"
+	"trade/store"
+)
+
+// KlineRecord 单条K线记录
+type KlineRecord struct {
+	Year       string    // 年份
+	OpenPrice  float64   // 开盘价
+	ClosePrice float64   // 收盘价
+	PriceDiff  float64   // 价差 (收盘价 - 开盘价)
+	ReturnPct  float64   // 涨跌幅(%)
+	IsUp       bool      // 是否上涨
+	CloseTime  time.Time // 收盘时间
+}
+
+// klineCounts 一组K线的涨跌统计
+type klineCounts struct {
+	TotalCount int               // 总样本数
+	UpCount    int               // 上涨次数
+	DownCount  int               // 下跌次数
+	FlatCount  int               // 平盘次数
+	UpRate     float64           // 上涨概率
+	Returns    model.ReturnStats // 涨跌幅统计
+	Records    []KlineRecord     // K线记录
+}
+
+// countKlines 统计一组K线的涨跌次数、上涨概率和涨跌幅（各策略共用）
+func countKlines(klines []model.Kline) *klineCounts {
+	counts := &klineCounts{
+		TotalCount: len(klines),
+		Records:    make([]KlineRecord, 0, len(klines)),
+	}
+
+	returns := make([]float64, 0, len(klines))
+	for _, kline := range klines {
+		// 涨跌幅（开盘价为0的脏数据不参与）
+		var returnPct float64
+		if kline.Open != 0 {
+			returnPct = (kline.Close - kline.Open) / kline.Open * 100
+			returns = append(returns, returnPct)
+		}
+
+		// 记录每条K线数据
+		counts.Records = append(counts.Records, KlineRecord{
+			Year:       kline.Date,
+			OpenPrice:  kline.Open,
+			ClosePrice: kline.Close,
+			PriceDiff:  kline.Close - kline.Open,
+			ReturnPct:  returnPct,
+			IsUp:       kline.Close > kline.Open,
+			CloseTime:  kline.CloseTime,
+		})
+
+		// 统计涨跌次数
+		if kline.Close > kline.Open {
+			counts.UpCount++
+		} else if kline.Close < kline.Open {
+			counts.DownCount++
+		} else {
+			counts.FlatCount++
+		}
+	}
+
+	// 计算上涨概率
+	if counts.TotalCount > 0 {
+		counts.UpRate = float64(counts.UpCount) / float64(counts.TotalCount) * 100
+	}
+	counts.Returns = CalculateReturnStats(returns)
+
+	return counts
+}
+
+// CalculateReturnStats 根据每根K线的涨跌幅(%)计算均值、中位数、标准差、盈亏比和期望
+func CalculateReturnStats(returns []float64) model.ReturnStats {
+	var stats model.ReturnStats
+	n := len(returns)
+	if n == 0 {
+		return stats
+	}
+
+	var sum, winSum, lossSum float64
+	var winCount, lossCount int
+	for _, r := range returns {
+		sum += r
+		if r > 0 {
+			winSum += r
+			winCount++
+		} else if r < 0 {
+			lossSum += r
+			lossCount++
+		}
+	}
+	stats.MeanReturn = sum / float64(n)
+
+	// 中位数
+	sorted := append([]float64(nil), returns...)
+	sort.Float64s(sorted)
+	if n%2 == 1 {
+		stats.MedianReturn = sorted[n/2]
+	} else {
+		stats.MedianReturn = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	// 样本标准差
+	if n > 1 {
+		var variance float64
+		for _, r := range returns {
+			variance += (r - stats.MeanReturn) * (r - stats.MeanReturn)
+		}
+		stats.StdDev = math.Sqrt(variance / float64(n-1))
+	}
+
+	// 平均盈亏、盈亏比
+	if winCount > 0 {
+		stats.AvgWin = winSum / float64(winCount)
+	}
+	if lossCount > 0 {
+		stats.AvgLoss = lossSum / float64(lossCount)
+		stats.ProfitFactor = winSum / -lossSum
+	}
+
+	// 期望收益（平盘计为0）
+	winRate := float64(winCount) / float64(n)
+	lossRate := float64(lossCount) / float64(n)
+	stats.Expectancy = winRate*stats.AvgWin + lossRate*stats.AvgLoss
+
+	return stats
+}
+
+// 交易信号
+const (
+	SignalBullish = "bullish" // 看涨
+	SignalBearish = "bearish" // 看跌
+	SignalNeutral = "neutral" // 中性
+)
+
+// ExpectancySignal 根据期望收益给出交易信号（接口交易建议和回测共用）
+// 期望收益超过一个标准误(标准差/√样本数)才认为有方向性，避免噪声被当成信号
+func ExpectancySignal(returns model.ReturnStats, sampleCount int) string {
+	if sampleCount < 2 {
+		return SignalNeutral
+	}
+	stdErr := returns.StdDev / math.Sqrt(float64(sampleCount))
+	if returns.Expectancy > stdErr {
+		return SignalBullish
+	} else if returns.Expectancy < -stdErr {
+		return SignalBearish
+	}
+	return SignalNeutral
+}
+
+// baselineUpRate 返回交易对+周期全部K线(asOf 之前已收盘)的上涨率(%)，作为各分组显著性检验的基准
+func baselineUpRate(symbol, interval string, asOf time.Time) float64 {
+	klines, err := store.Default().FindKlines(store.KlineQuery{Symbol: symbol, Interval: interval, ClosedBefore: asOf})
+	if err != nil || len(klines) == 0 {
+		return 50
+	}
+	return countKlines(klines).UpRate
+}
+
+// significanceOf 计算一组K线上涨率相对基准上涨率(%)的置信区间、p值和最小可检测优势
+func significanceOf(upCount, totalCount int, baseline float64) model.Significance {
+	p := baseline / 100
+	lower, upper := stats.WilsonInterval(upCount, totalCount, stats.DefaultConfidence)
+	pValue := stats.BinomialPValue(upCount, totalCount, p)
+	return model.Significance{
+		Baseline:          baseline,
+		CILower:           lower * 100,
+		CIUpper:           upper * 100,
+		PValue:            pValue,
+		MinDetectableEdge: stats.MinDetectableEdge(totalCount, p, stats.DefaultAlpha, stats.DefaultPower) * 100,
+		// 单独检验时校正值等于原始p值，参与扫描的分组由 correctFamily 覆盖
+		PValueBonferroni:   pValue,
+		PValueBH:           pValue,
+		SurvivesCorrection: pValue < stats.DefaultAlpha,
+	}
+}
+
+// correctFamily 对同一次扫描的所有分组做多重比较校正（Bonferroni 和 Benjamini–Hochberg）
+// 扫描24个小时总会有某个小时"看起来很强"，只有BH校正后仍显著的分组才标记为 SurvivesCorrection
+func correctFamily(family []*model.Significance) {
+	pValues := make([]float64, len(family))
+	for i, sig := range family {
+		pValues[i] = sig.PValue
+	}
+
+	bonferroni := stats.Bonferroni(pValues)
+	bh := stats.BenjaminiHochberg(pValues)
+	for i, sig := range family {
+		sig.PValueBonferroni = bonferroni[i]
+		sig.PValueBH = bh[i]
+		sig.SurvivesCorrection = bh[i] < stats.DefaultAlpha
+	}
+}
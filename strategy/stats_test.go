@@ -0,0 +1,93 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"trade/model"
+)
+
+func TestCalculateReturnStats(t *testing.T) {
+	stats := CalculateReturnStats([]float64{2, -1, 4, 0, -3})
+
+	checks := []struct {
+		name      string
+		got, want float64
+	}{
+		{"MeanReturn", stats.MeanReturn, 0.4},
+		{"MedianReturn", stats.MedianReturn, 0},
+		{"StdDev", stats.StdDev, math.Sqrt(7.3)},
+		{"AvgWin", stats.AvgWin, 3},
+		{"AvgLoss", stats.AvgLoss, -2},
+		{"ProfitFactor", stats.ProfitFactor, 1.5},
+		{"Expectancy", stats.Expectancy, 0.4},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > 1e-9 {
+			t.Errorf("%s 期望 %.4f，实际 %.4f", c.name, c.want, c.got)
+		}
+	}
+
+	// 没有下跌样本时盈亏比为0
+	if stats := CalculateReturnStats([]float64{1, 2}); stats.ProfitFactor != 0 || stats.AvgLoss != 0 {
+		t.Errorf("无下跌样本统计错误: %+v", stats)
+	}
+	if stats := CalculateReturnStats(nil); stats.MeanReturn != 0 || stats.StdDev != 0 {
+		t.Errorf("空样本应返回零值: %+v", stats)
+	}
+}
+
+func TestCorrectFamily(t *testing.T) {
+	family := []*model.Significance{{PValue: 0.01}, {PValue: 0.04}, {PValue: 0.5}}
+	correctFamily(family)
+
+	wantBH := []float64{0.03, 0.06, 0.5}
+	wantBonferroni := []float64{0.03, 0.12, 1}
+	for i, sig := range family {
+		if math.Abs(sig.PValueBH-wantBH[i]) > 1e-12 || math.Abs(sig.PValueBonferroni-wantBonferroni[i]) > 1e-12 {
+			t.Errorf("分组%d校正错误: %+v", i, sig)
+		}
+	}
+
+	// 原始p值0.04单独看显著，校正后不再显著
+	if !family[0].SurvivesCorrection || family[1].SurvivesCorrection || family[2].SurvivesCorrection {
+		t.Errorf("校正后只有第一个分组应显著: %+v %+v %+v", family[0], family[1], family[2])
+	}
+}
+
+func TestBestWorstOnlyCorrected(t *testing.T) {
+	sig := func(survives bool) model.Significance {
+		return model.Significance{Baseline: 50, SurvivesCorrection: survives}
+	}
+	hours := []*HourStats{
+		{Hour: 1, TotalCount: 100, UpRate: 80, Significance: sig(false)}, // 上涨率最高但未通过校正
+		{Hour: 2, TotalCount: 100, UpRate: 65, Significance: sig(true)},
+		{Hour: 3, TotalCount: 100, UpRate: 60, Significance: sig(true)},
+		{Hour: 4, TotalCount: 3, UpRate: 90, Significance: sig(true)}, // 样本不足
+		{Hour: 5, TotalCount: 100, UpRate: 20, Significance: sig(false)},
+	}
+	best, worst := BestWorstHour(hours, 5)
+	if best == nil || best.Hour != 2 {
+		t.Errorf("最佳时段应为校正后显著的 2 点，实际 %+v", best)
+	}
+	// 显著的时段都高于基准，没有最差时段
+	if worst != nil {
+		t.Errorf("没有显著低于基准的时段时最差应为空，实际 %+v", worst)
+	}
+
+	months := []*DayStats{
+		{Month: 1, UpRate: 30, Significance: sig(true)},
+		{Month: 2, UpRate: 70, Significance: sig(false)},
+	}
+	if best, worst := BestWorstMonth(months); best != nil || worst == nil || worst.Month != 1 {
+		t.Errorf("最佳/最差月份错误: best=%+v worst=%+v", best, worst)
+	}
+
+	weekdays := []*WeekdayStats{
+		{Weekday: 1, TotalCount: 10, UpRate: 70, Significance: sig(false)},
+		{Weekday: 2, TotalCount: 10, UpRate: 30, Significance: sig(false)},
+	}
+	if best, worst := BestWorstWeekday(weekdays, 5); best != nil || worst != nil {
+		t.Errorf("没有通过校正的星期时应返回空: best=%+v worst=%+v", best, worst)
+	}
+}
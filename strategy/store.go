@@ -0,0 +1,126 @@
+package strategy
+
+import (
+	"fmt"
+
+	"trade/model"
+	"trade/store"
+)
+
+// SaveStrategy1Analysis 保存策略一结果到数据库
+func SaveStrategy1Analysis(analysis *Strategy1Analysis) {
+	currentStats := analysis.Current
+
+	// 找出校正后显著的最佳和最差月份，没有时为0
+	var bestMonth, worstMonth int
+	var bestUpRate, worstUpRate float64
+	best, worst := BestWorstMonth(analysis.AllMonths)
+	if best != nil {
+		bestMonth = best.Month
+		bestUpRate = best.UpRate
+	}
+	if worst != nil {
+		worstMonth = worst.Month
+		worstUpRate = worst.UpRate
+	}
+
+	// 创建或更新策略一结果
+	result := &model.Strategy1Result{
+		Symbol:       analysis.Symbol,
+		Interval:     analysis.Interval,
+		AnalyzeDay:   currentStats.Day,
+		Month:        analysis.Month,
+		Day:          analysis.Day,
+		TotalCount:   currentStats.TotalCount,
+		UpCount:      currentStats.UpCount,
+		DownCount:    currentStats.DownCount,
+		FlatCount:    currentStats.FlatCount,
+		UpRate:       currentStats.UpRate,
+		ReturnStats:  currentStats.ReturnStats,
+		Significance: currentStats.Significance,
+		BestMonth:    bestMonth,
+		BestUpRate:   bestUpRate,
+		WorstMonth:   worstMonth,
+		WorstUpRate:  worstUpRate,
+	}
+
+	// 详细记录
+	details := make([]model.Strategy1DetailRecord, 0, len(analysis.AllYears))
+	for _, record := range analysis.AllYears {
+		details = append(details, model.Strategy1DetailRecord{
+			Year:       record.Year,
+			OpenPrice:  record.OpenPrice,
+			ClosePrice: record.ClosePrice,
+			PriceDiff:  record.PriceDiff,
+			IsUp:       record.IsUp,
+			CloseTime:  record.CloseTime,
+		})
+	}
+
+	if err := store.Default().SaveStrategy1Result(result, details); err != nil {
+		fmt.Printf("⚠️ 保存策略一结果失败: %v\n", err)
+	}
+}
+
+// SaveStrategy2Analysis 保存策略二结果到数据库
+func SaveStrategy2Analysis(analysis *Strategy2Analysis) {
+	for _, hourStat := range analysis.AllHours {
+		// 创建或更新策略二结果
+		result := &model.Strategy2Result{
+			Symbol:       analysis.Symbol,
+			Interval:     analysis.Interval,
+			Hour:         hourStat.Hour,
+			TotalCount:   hourStat.TotalCount,
+			UpCount:      hourStat.UpCount,
+			DownCount:    hourStat.DownCount,
+			FlatCount:    hourStat.FlatCount,
+			UpRate:       hourStat.UpRate,
+			ReturnStats:  hourStat.ReturnStats,
+			Significance: hourStat.Significance,
+		}
+
+		// 详细记录
+		details := make([]model.Strategy2DetailRecord, 0, len(hourStat.Records))
+		for _, record := range hourStat.Records {
+			details = append(details, model.Strategy2DetailRecord{
+				Date:       record.Year,
+				OpenPrice:  record.OpenPrice,
+				ClosePrice: record.ClosePrice,
+				PriceDiff:  record.PriceDiff,
+				IsUp:       record.IsUp,
+				CloseTime:  record.CloseTime,
+			})
+		}
+
+		if err := store.Default().SaveStrategy2Result(result, details); err != nil {
+			fmt.Printf("⚠️ 保存策略二结果失败: %v\n", err)
+		}
+	}
+}
+
+// SaveStrategy3Analysis 保存策略三结果到数据库（整天和星期×小时统计）
+func SaveStrategy3Analysis(analysis *Strategy3Analysis) {
+	allStats := make([]*WeekdayStats, 0, len(analysis.AllWeekdays)+len(analysis.WeekdayHours))
+	allStats = append(allStats, analysis.AllWeekdays...)
+	allStats = append(allStats, analysis.WeekdayHours...)
+
+	for _, stat := range allStats {
+		result := &model.Strategy3Result{
+			Symbol:       analysis.Symbol,
+			Interval:     analysis.Interval,
+			Weekday:      stat.Weekday,
+			Hour:         stat.Hour,
+			TotalCount:   stat.TotalCount,
+			UpCount:      stat.UpCount,
+			DownCount:    stat.DownCount,
+			FlatCount:    stat.FlatCount,
+			UpRate:       stat.UpRate,
+			ReturnStats:  stat.ReturnStats,
+			Significance: stat.Significance,
+		}
+
+		if err := store.Default().SaveStrategy3Result(result); err != nil {
+			fmt.Printf("⚠️ 保存策略三结果失败: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"trade/model"
+)
+
+var (
+	// ErrNoData 没有可分析的历史数据
+	ErrNoData = errors.New("没有找到历史数据")
+	// ErrInvalidParam 策略自定义参数不合法
+	ErrInvalidParam = errors.New("参数错误")
+)
+
+// Param 策略参数说明
+type Param struct {
+	Name        string `json:"name"`        // 参数名
+	Type        string `json:"type"`        // 参数类型
+	Required    bool   `json:"required"`    // 是否必填
+	Default     string `json:"default"`     // 默认值说明
+	Description string `json:"description"` // 参数描述
+}
+
+// Input 策略分析输入
+type Input struct {
+	Symbol   string            // 交易对
+	Interval string            // K线周期
+	Time     time.Time         // 分析目标时间(策略按需取日期或小时)
+	AsOf     time.Time         // 数据截止时点，非零时只使用在该时刻之前已收盘的K线
+	Params   map[string]string // 其他策略自定义参数
+}
+
+// Result 策略分析结果
+type Result struct {
+	Strategy string      `json:"strategy"`        // 策略名称
+	Symbol   string      `json:"symbol"`          // 交易对
+	Interval string      `json:"interval"`        // K线周期
+	Time     time.Time   `json:"time"`            // 分析目标时间
+	AsOf     *time.Time  `json:"as_of,omitempty"` // 数据截止时点，为空表示使用全部K线
+	Data     interface{} `json:"data"`            // 策略自定义的结构化结果
+}
+
+// Strategy 策略接口
+type Strategy interface {
+	// Name 策略唯一标识，对应接口中的 strategy_type
+	Name() string
+	// Description 策略描述
+	Description() string
+	// Parameters 策略支持的参数
+	Parameters() []Param
+	// Analyze 分析单个交易对+时间周期
+	Analyze(ctx context.Context, input *Input) (*Result, error)
+	// Run 按配置文件批量运行（定时任务和命令行使用）
+	// asOf 为零值时按当前时间分析并使用全部K线，否则按 asOf 时点分析，只使用之前已收盘的K线
+	Run(config *model.Config, asOf time.Time)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Strategy)
+)
+
+// Register 注册策略，名称重复时 panic
+func Register(s Strategy) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[s.Name()]; exists {
+		panic(fmt.Sprintf("策略 %s 重复注册", s.Name()))
+	}
+	registry[s.Name()] = s
+}
+
+// Get 按名称查找策略
+func Get(name string) (Strategy, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	s, ok := registry[name]
+	return s, ok
+}
+
+// List 返回所有已注册策略（按名称排序）
+func List() []Strategy {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	list := make([]Strategy, 0, len(registry))
+	for _, s := range registry {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name() < list[j].Name()
+	})
+	return list
+}
+
+// Names 返回所有已注册策略名称（按名称排序）
+func Names() []string {
+	list := List()
+	names := make([]string, 0, len(list))
+	for _, s := range list {
+		names = append(names, s.Name())
+	}
+	return names
+}
+
+// OnDemand 计算量大、只按需运行的策略实现此接口，RunAll 批量运行时跳过
+// 仍可通过接口或 once 模式的 -strategy 参数单独运行
+type OnDemand interface {
+	OnDemand() bool
+}
+
+// RunAll 按配置文件依次运行所有已注册策略（按需运行的策略除外），asOf 含义同 Strategy.Run
+func RunAll(config *model.Config, asOf time.Time) {
+	for _, s := range List() {
+		if onDemand, ok := s.(OnDemand); ok && onDemand.OnDemand() {
+			continue
+		}
+		fmt.Printf("\n========== 开始运行策略: %s ==========\n", s.Name())
+		s.Run(config, asOf)
+	}
+}
+
+// analysisTime 批量运行时的分析时间：asOf 为零值时取当前时间
+func analysisTime(asOf time.Time) time.Time {
+	if asOf.IsZero() {
+		return time.Now()
+	}
+	return asOf
+}
+
+// resultAsOf 返回结果中的数据截止时点，零值返回 nil
+func resultAsOf(asOf time.Time) *time.Time {
+	if asOf.IsZero() {
+		return nil
+	}
+	return &asOf
+}
@@ -1,20 +1,132 @@
 package strategy
 
 import (
+	"context"
+	"errors"
+	"os"
 	"testing"
+	"time"
 
 	"trade/db"
+	"trade/model"
+	"trade/store"
 	"trade/utils"
 )
 
-func TestStrategy1(t *testing.T) {
-	db.InitPostgreSql()
-	config, err := utils.LoadConfig("config.json")
+// testDatabaseEnv 指定配置文件路径的环境变量，设置后才运行连接真实数据库的测试
+const testDatabaseEnv = "TRADE_TEST_CONFIG"
+
+// useDatabase 按 TRADE_TEST_CONFIG 指定的配置文件连接数据库，未设置时跳过测试
+// 数据库连接失败会直接退出测试进程，所以默认不运行
+func useDatabase(t *testing.T) *model.Config {
+	t.Helper()
+	path := os.Getenv(testDatabaseEnv)
+	if path == "" || testing.Short() {
+		t.Skipf("需要连接数据库，设置 %s=<配置文件路径> 后运行", testDatabaseEnv)
+	}
+	config, err := utils.LoadConfig(path)
 	if err != nil {
 		t.Fatalf("加载配置文件失败: %v", err)
 	}
-	if config == nil || len(config.Symbols) == 0 {
+	db.InitPostgreSql(&config.Database)
+	return config
+}
+
+func TestStrategy1(t *testing.T) {
+	config := useDatabase(t)
+	if len(config.Symbols) == 0 {
 		t.Fatalf("配置文件为空")
 	}
-	Strategy1(config)
+	Strategy1(config, time.Time{})
+}
+
+// useMemoryStore 使用内存存储并写入测试K线，测试结束后恢复默认存储
+func useMemoryStore(t *testing.T, klines ...model.Kline) *store.MemoryStore {
+	t.Helper()
+	memory := store.NewMemoryStore()
+	for i := range klines {
+		klines[i].FillTimeFields()
+	}
+	if _, err := memory.UpsertKlines(klines); err != nil {
+		t.Fatalf("写入测试K线失败: %v", err)
+	}
+	store.SetDefault(memory)
+	t.Cleanup(func() { store.SetDefault(nil) })
+	return memory
+}
+
+// testKline 构建测试K线，收盘时间为下一根K线开盘前1毫秒
+func testKline(symbol, interval string, openTime time.Time, open, close float64) model.Kline {
+	return model.Kline{
+		Symbol:    symbol,
+		Interval:  interval,
+		OpenTime:  openTime,
+		CloseTime: openTime.Add(utils.IntervalDuration(interval) - time.Millisecond),
+		Open:      open,
+		Close:     close,
+	}
+}
+
+func TestAnalyzeDayOfYearOffline(t *testing.T) {
+	day := func(year int, month time.Month) time.Time {
+		return time.Date(year, month, 30, 0, 0, 0, 0, time.UTC)
+	}
+	memory := useMemoryStore(t,
+		testKline("BTCUSDT", "1d", day(2022, time.October), 100, 110),
+		testKline("BTCUSDT", "1d", day(2021, time.October), 100, 90),
+		testKline("BTCUSDT", "1d", day(2023, time.October), 100, 120),
+		testKline("BTCUSDT", "1d", day(2024, time.October), 100, 100),
+		testKline("BTCUSDT", "1d", day(2023, time.November), 100, 90),
+		testKline("ETHUSDT", "1d", day(2023, time.October), 100, 90),
+	)
+
+	analysis := AnalyzeDayOfYear("BTCUSDT", "1d", 10, 30, time.Time{})
+	current := analysis.Current
+	if current.TotalCount != 4 || current.UpCount != 2 || current.DownCount != 1 || current.FlatCount != 1 {
+		t.Fatalf("当前日期统计错误: %+v", current)
+	}
+	if current.UpRate != 50 {
+		t.Errorf("上涨概率期望 50，实际 %.2f", current.UpRate)
+	}
+
+	years := make([]string, 0, len(analysis.AllYears))
+	for _, record := range analysis.AllYears {
+		years = append(years, record.Year)
+	}
+	if len(years) != 4 || years[0] != "2021" || years[3] != "2024" {
+		t.Errorf("跨年记录应按年份排序: %v", years)
+	}
+
+	if len(analysis.AllMonths) != 2 {
+		t.Fatalf("跨月统计期望 2 个月份，实际 %d", len(analysis.AllMonths))
+	}
+	// 样本太少，没有月份通过多重比较校正，不给出最佳/最差月份
+	if best, worst := BestWorstMonth(analysis.AllMonths); best != nil || worst != nil {
+		t.Errorf("未通过校正的月份不应参与排名: best=%+v worst=%+v", best, worst)
+	}
+
+	// 保存后可以从结果存储中读回
+	SaveStrategy1Analysis(analysis)
+	results, _ := memory.ListStrategy1Results("BTCUSDT", "1d")
+	if len(results) != 1 || results[0].AnalyzeDay != "10-30" || results[0].BestMonth != 0 {
+		t.Fatalf("保存结果错误: %+v", results)
+	}
+	details, _ := memory.ListStrategy1Details(results[0].ID)
+	if len(details) != 4 {
+		t.Errorf("详细记录期望 4 条，实际 %d", len(details))
+	}
+}
+
+func TestStrategy1AnalyzeNoData(t *testing.T) {
+	useMemoryStore(t)
+
+	s, _ := Get("strategy_1")
+	_, err := s.Analyze(context.Background(), &Input{
+		Symbol:   "BTCUSDT",
+		Interval: "1d",
+		Time:     time.Date(2024, 10, 30, 0, 0, 0, 0, time.UTC),
+	})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("没有数据时应返回 ErrNoData，实际 %v", err)
+	}
 }
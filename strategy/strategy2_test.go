@@ -2,31 +2,24 @@ package strategy
 
 import (
 	"testing"
+	"time"
 
-	"trade/db"
 	"trade/model"
-	"trade/utils"
 )
 
 // TestStrategy2 测试小时级别策略
 func TestStrategy2(t *testing.T) {
-	// 初始化数据库
-	db.InitPostgreSql()
-
-	// 加载配置文件
-	config, err := utils.LoadConfig("../config.json")
-	if err != nil {
-		t.Fatalf("加载配置文件失败: %v", err)
-	}
+	// 加载配置文件并初始化数据库
+	config := useDatabase(t)
 
 	// 运行小时级别策略
-	Strategy2(config)
+	Strategy2(config, time.Time{})
 }
 
 // TestStrategy2SingleSymbol 测试单个交易对的小时级别分析
 func TestStrategy2SingleSymbol(t *testing.T) {
 	// 初始化数据库
-	db.InitPostgreSql()
+	useDatabase(t)
 
 	// 创建测试配置
 	config := &model.Config{
@@ -39,5 +32,37 @@ func TestStrategy2SingleSymbol(t *testing.T) {
 	}
 
 	// 运行小时级别策略
-	Strategy2(config)
+	Strategy2(config, time.Time{})
+}
+
+func TestAnalyzeHourOfDayOffline(t *testing.T) {
+	hour := func(day, h int) time.Time {
+		return time.Date(2024, 10, day, h, 0, 0, 0, time.UTC)
+	}
+	useMemoryStore(t,
+		testKline("BTCUSDT", "1h", hour(1, 8), 100, 110),
+		testKline("BTCUSDT", "1h", hour(2, 8), 100, 105),
+		testKline("BTCUSDT", "1h", hour(3, 8), 100, 95),
+		testKline("BTCUSDT", "1h", hour(1, 9), 100, 90),
+		testKline("BTCUSDT", "1h", hour(2, 9), 100, 90),
+		testKline("BTCUSDT", "4h", hour(1, 8), 100, 90),
+	)
+
+	analysis := AnalyzeHourOfDay("BTCUSDT", "1h", 8, time.Time{})
+	if analysis.Current.TotalCount != 3 || analysis.Current.UpCount != 2 || analysis.Current.DownCount != 1 {
+		t.Fatalf("当前小时统计错误: %+v", analysis.Current)
+	}
+	if len(analysis.AllHours) != 2 || analysis.AllHours[0].Hour != 8 || analysis.AllHours[1].Hour != 9 {
+		t.Fatalf("24小时统计应只包含有数据的小时并按小时排序: %+v", analysis.AllHours)
+	}
+
+	// 样本太少，没有小时通过多重比较校正，不给出最佳/最差时段
+	if best, worst := BestWorstHour(analysis.AllHours, 3); best != nil || worst != nil {
+		t.Errorf("未通过校正的时段不应参与排名: best=%+v worst=%+v", best, worst)
+	}
+
+	// 没有数据的小时返回空统计
+	if empty := AnalyzeHourOfDay("BTCUSDT", "1h", 10, time.Time{}); empty.Current.TotalCount != 0 {
+		t.Errorf("无数据小时应返回空统计: %+v", empty.Current)
+	}
 }
@@ -0,0 +1,87 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeDayOfWeekOffline(t *testing.T) {
+	// 2024-10-07 是周一
+	at := func(day, h int) time.Time {
+		return time.Date(2024, 10, day, h, 0, 0, 0, time.UTC)
+	}
+	memory := useMemoryStore(t,
+		testKline("BTCUSDT", "1h", at(7, 8), 100, 110),
+		testKline("BTCUSDT", "1h", at(14, 8), 100, 95),
+		testKline("BTCUSDT", "1h", at(7, 9), 100, 102),
+		testKline("BTCUSDT", "1h", at(8, 8), 100, 90),
+		testKline("BTCUSDT", "1d", at(7, 0), 100, 90),
+	)
+
+	analysis := AnalyzeDayOfWeek("BTCUSDT", "1h", 1, WholeDay, time.Time{})
+	current := analysis.Current
+	if current.TotalCount != 3 || current.UpCount != 2 || current.DownCount != 1 {
+		t.Fatalf("周一统计错误: %+v", current)
+	}
+	if want := (10.0 - 5.0 + 2.0) / 3; math.Abs(current.MeanReturn-want) > 1e-9 {
+		t.Errorf("平均涨跌幅期望 %.4f，实际 %.4f", want, current.MeanReturn)
+	}
+	if len(analysis.AllWeekdays) != 2 || analysis.AllWeekdays[0].Weekday != 1 || analysis.AllWeekdays[1].Weekday != 2 {
+		t.Fatalf("星期统计应只包含有数据的星期并按星期排序: %+v", analysis.AllWeekdays)
+	}
+	if len(analysis.WeekdayHours) != 3 {
+		t.Fatalf("星期×小时统计期望 3 组，实际 %d", len(analysis.WeekdayHours))
+	}
+
+	// 星期×小时
+	analysis = AnalyzeDayOfWeek("BTCUSDT", "1h", 1, 8, time.Time{})
+	if analysis.Current.TotalCount != 2 || analysis.Current.Hour != 8 {
+		t.Errorf("周一08:00统计错误: %+v", analysis.Current)
+	}
+
+	// 日线不做小时细分
+	if daily := AnalyzeDayOfWeek("BTCUSDT", "1d", 1, WholeDay, time.Time{}); len(daily.WeekdayHours) != 0 || daily.Current.TotalCount != 1 {
+		t.Errorf("日线统计错误: %+v", daily)
+	}
+
+	// 保存后按 symbol+interval+weekday+hour 覆盖
+	SaveStrategy3Analysis(analysis)
+	SaveStrategy3Analysis(analysis)
+	monday := 1
+	results, _ := memory.ListStrategy3Results("BTCUSDT", "1h", &monday)
+	if len(results) != 3 || results[0].Hour != WholeDay {
+		t.Errorf("周一结果应包含整天和两个小时: %+v", results)
+	}
+}
+
+func TestStrategy3AnalyzeParams(t *testing.T) {
+	useMemoryStore(t, testKline("BTCUSDT", "1h", time.Date(2024, 10, 7, 8, 0, 0, 0, time.UTC), 100, 110))
+	s, _ := Get("strategy_3")
+
+	input := &Input{
+		Symbol:   "BTCUSDT",
+		Interval: "1h",
+		Time:     time.Date(2024, 10, 9, 8, 0, 0, 0, time.UTC), // 周三
+		Params:   map[string]string{"weekday": "1", "hour": "8"},
+	}
+	result, err := s.Analyze(context.Background(), input)
+	if err != nil {
+		t.Fatalf("分析失败: %v", err)
+	}
+	if analysis := result.Data.(*Strategy3Analysis); analysis.Weekday != 1 || analysis.Hour != 8 {
+		t.Errorf("应使用 weekday/hour 参数: %+v", analysis)
+	}
+
+	input.Params = map[string]string{"weekday": "7"}
+	if _, err := s.Analyze(context.Background(), input); !errors.Is(err, ErrInvalidParam) {
+		t.Errorf("weekday 越界应返回 ErrInvalidParam，实际 %v", err)
+	}
+
+	input.Params = nil
+	if _, err := s.Analyze(context.Background(), input); !errors.Is(err, ErrNoData) {
+		t.Errorf("周三无数据应返回 ErrNoData，实际 %v", err)
+	}
+}
@@ -1,374 +1,224 @@
 package strategy
 
 import (
+	"context"
 	"fmt"
-	"sort"
 	"time"
 
-	"trade/db"
 	"trade/model"
+	"trade/store"
 )
 
 // HourStats 每个小时的统计数据
 type HourStats struct {
-	Hour       int           // 小时 (0-23)
-	TotalCount int           // 总样本数
-	UpCount    int           // 上涨次数
-	DownCount  int           // 下跌次数
-	FlatCount  int           // 平盘次数
-	UpRate     float64       // 上涨概率
-	Records    []KlineRecord // K线记录
+	Hour       int     // 小时 (0-23)
+	TotalCount int     // 总样本数
+	UpCount    int     // 上涨次数
+	DownCount  int     // 下跌次数
+	FlatCount  int     // 平盘次数
+	UpRate     float64 // 上涨概率
+
+	// 涨跌幅统计
+	model.ReturnStats
+	// 相对基准上涨率的显著性
+	model.Significance
+
+	Records []KlineRecord // K线记录
 }
 
-// Strategy2 小时级别分析策略
-func Strategy2(config *model.Config) {
-	if config == nil || len(config.Symbols) == 0 {
-		fmt.Println("⚠️  配置文件为空，无法执行策略分析")
-		return
-	}
-
-	// 获取当前时间
-	now := time.Now()
-	currentHour := now.Hour()
-
-	fmt.Printf("\n")
-	fmt.Printf("╔════════════════════════════════════════════════════════════════╗\n")
-	fmt.Printf("║          策略二：小时级别涨跌分析（日内时段对比）              ║\n")
-	fmt.Printf("╚════════════════════════════════════════════════════════════════╝\n")
-	fmt.Printf("当前时间: %02d:00\n", currentHour)
-	fmt.Printf("将分析 %d 个交易对的小时级别数据\n\n", len(config.Symbols))
+// Strategy2Analysis 策略二分析结果
+type Strategy2Analysis struct {
+	Symbol   string       // 交易对
+	Interval string       // 时间周期
+	Hour     int          // 分析小时
+	Current  *HourStats   // 当前小时统计
+	AllHours []*HourStats // 24小时统计（按小时排序，只包含有数据的小时）
+}
 
-	// 遍历配置文件中的所有交易对
-	for i, symbolConfig := range config.Symbols {
-		fmt.Printf("\n")
-		fmt.Printf("═══════════════════════════════════════════════════════════════\n")
-		fmt.Printf("  交易对 [%d/%d]: %s\n", i+1, len(config.Symbols), symbolConfig.Symbol)
-		fmt.Printf("═══════════════════════════════════════════════════════════════\n")
+// hourlyIntervals 策略二批量运行时分析的小时级别周期
+var hourlyIntervals = []string{"8h", "4h", "2h", "1h"}
 
-		// 只分析小时级别的时间周期
-		hourlyIntervals := []string{"8h", "4h", "2h", "1h"}
-		for _, interval := range symbolConfig.Intervals {
-			// 检查是否是小时级别
-			isHourly := false
-			for _, hourInterval := range hourlyIntervals {
-				if interval == hourInterval {
-					isHourly = true
-					break
-				}
-			}
+// strategy2 小时级别涨跌分析策略
+type strategy2 struct{}
 
-			if isHourly {
-				analyzeHourlyPattern(symbolConfig.Symbol, interval, currentHour)
-			}
-		}
-	}
+func init() {
+	Register(&strategy2{})
+}
 
-	fmt.Printf("\n")
-	fmt.Printf("╔════════════════════════════════════════════════════════════════╗\n")
-	fmt.Printf("║                    小时级别分析完成                             ║\n")
-	fmt.Printf("╚════════════════════════════════════════════════════════════════╝\n")
+func (s *strategy2) Name() string {
+	return "strategy_2"
 }
 
-// analyzeHourlyPattern 分析单个交易对的小时规律
-func analyzeHourlyPattern(symbol, interval string, currentHour int) {
-	fmt.Printf("\n【时间周期: %s】\n", interval)
+func (s *strategy2) Description() string {
+	return "小时级别涨跌分析：统计特定小时的历史涨跌表现，并做24小时对比"
+}
 
-	// 1. 分析当前小时的历史表现
-	currentHourStats := analyzeSpecificHour(symbol, interval, currentHour)
+func (s *strategy2) Parameters() []Param {
+	return []Param{
+		{Name: "hour", Type: "int", Default: "当前小时", Description: "分析小时，0-23"},
+	}
+}
 
-	// 2. 分析24小时的整体表现
-	allHourStats := analyzeAll24Hours(symbol, interval)
+func (s *strategy2) Analyze(ctx context.Context, input *Input) (*Result, error) {
+	analysis := AnalyzeHourOfDay(input.Symbol, input.Interval, input.Time.Hour(), input.AsOf)
+	if analysis.Current.TotalCount == 0 {
+		return nil, fmt.Errorf("%w: %s %02d:00", ErrNoData, input.Symbol, analysis.Hour)
+	}
 
-	// 3. 保存结果到数据库
-	saveStrategy2Result(symbol, interval, allHourStats)
+	return &Result{
+		Strategy: s.Name(),
+		Symbol:   input.Symbol,
+		Interval: input.Interval,
+		Time:     input.Time,
+		AsOf:     resultAsOf(input.AsOf),
+		Data:     analysis,
+	}, nil
+}
 
-	// 4. 输出分析结果
-	printHourlyAnalysis(currentHourStats, allHourStats, currentHour, interval)
+func (s *strategy2) Run(config *model.Config, asOf time.Time) {
+	Strategy2(config, asOf)
 }
 
-// analyzeSpecificHour 分析特定小时的统计数据
-func analyzeSpecificHour(symbol, interval string, hour int) *HourStats {
-	hourStr := fmt.Sprintf("%d", hour)
+// Strategy2 小时级别分析策略，保存结果并输出到控制台
+// asOf 为零值时分析当前小时并使用全部K线，否则分析 asOf 所在小时且只使用之前已收盘的K线
+func Strategy2(config *model.Config, asOf time.Time) {
+	if config == nil || len(config.Symbols) == 0 {
+		fmt.Println("⚠️  配置文件为空，无法执行策略分析")
+		return
+	}
 
-	var klines []model.Kline
-	err := db.Pog.Where("symbol = ? AND interval = ? AND hour = ?", symbol, interval, hourStr).
-		Order("open_time ASC").
-		Find(&klines).Error
+	// 获取当前时间
+	now := analysisTime(asOf)
+	currentHour := now.Hour()
 
-	if err != nil || len(klines) == 0 {
-		return &HourStats{
-			Hour:       hour,
-			TotalCount: 0,
-			Records:    []KlineRecord{},
-		}
-	}
+	printStrategy2Header(currentHour, len(config.Symbols))
 
-	return calculateHourStats(hour, klines)
-}
+	// 遍历配置文件中的所有交易对
+	for i, symbolConfig := range config.Symbols {
+		printSymbolHeader(i, len(config.Symbols), symbolConfig.Symbol)
 
-// analyzeAll24Hours 分析所有24小时的数据
-func analyzeAll24Hours(symbol, interval string) []*HourStats {
-	stats := make([]*HourStats, 0, 24)
+		// 只分析小时级别的时间周期
+		for _, interval := range symbolConfig.Intervals {
+			if !isHourlyInterval(interval) {
+				continue
+			}
 
-	for hour := 0; hour < 24; hour++ {
-		stat := analyzeSpecificHour(symbol, interval, hour)
-		if stat.TotalCount > 0 {
-			stats = append(stats, stat)
+			analysis := AnalyzeHourOfDay(symbolConfig.Symbol, interval, currentHour, asOf)
+			SaveStrategy2Analysis(analysis)
+			PrintStrategy2Analysis(analysis)
 		}
 	}
 
-	return stats
+	printStrategy2Footer()
 }
 
-// calculateHourStats 计算小时统计数据
-func calculateHourStats(hour int, klines []model.Kline) *HourStats {
-	stats := &HourStats{
-		Hour:       hour,
-		TotalCount: len(klines),
-		Records:    make([]KlineRecord, 0, len(klines)),
-	}
+// AnalyzeHourOfDay 分析单个交易对+时间周期在指定小时的历史表现
+// asOf 非零时只使用在该时刻之前已收盘的K线
+func AnalyzeHourOfDay(symbol, interval string, hour int, asOf time.Time) *Strategy2Analysis {
+	allHours := analyzeAll24Hours(symbol, interval, asOf)
 
-	for _, kline := range klines {
-		priceDiff := kline.Close - kline.Open
-		isUp := kline.Close > kline.Open
-
-		// 记录每条K线数据
-		record := KlineRecord{
-			Year:       kline.Date,
-			OpenPrice:  kline.Open,
-			ClosePrice: kline.Close,
-			PriceDiff:  priceDiff,
-			IsUp:       isUp,
-			CloseTime:  kline.CloseTime,
-		}
-		stats.Records = append(stats.Records, record)
-
-		// 统计涨跌次数
-		if kline.Close > kline.Open {
-			stats.UpCount++
-		} else if kline.Close < kline.Open {
-			stats.DownCount++
-		} else {
-			stats.FlatCount++
-		}
+	// 以全部K线的上涨率为基准计算显著性
+	// 24小时一起做多重比较校正
+	baseline := baselineUpRate(symbol, interval, asOf)
+	family := make([]*model.Significance, 0, len(allHours))
+	for _, stat := range allHours {
+		stat.Significance = significanceOf(stat.UpCount, stat.TotalCount, baseline)
+		family = append(family, &stat.Significance)
 	}
+	correctFamily(family)
 
-	// 计算上涨概率
-	if stats.TotalCount > 0 {
-		stats.UpRate = float64(stats.UpCount) / float64(stats.TotalCount) * 100
+	// 当前小时从24小时统计中取，没有数据时返回空统计
+	current := &HourStats{
+		Hour:       hour,
+		TotalCount: 0,
+		Records:    []KlineRecord{},
 	}
-
-	return stats
-}
-
-// printHourlyAnalysis 打印小时级别分析结果
-func printHourlyAnalysis(currentHourStats *HourStats, allHourStats []*HourStats, currentHour int, interval string) {
-	// 1. 打印当前小时的历史表现
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
-	fmt.Printf("🕐 当前时段 %02d:00 的历史表现\n", currentHour)
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
-
-	if currentHourStats.TotalCount == 0 {
-		fmt.Printf("⚠️  没有找到 %02d:00 的历史数据\n\n", currentHour)
-	} else {
-		fmt.Printf("样本数量: %d 条\n", currentHourStats.TotalCount)
-		if currentHourStats.TotalCount < 10 {
-			fmt.Printf("⚠️  样本量较少（少于10条），统计结果可能不可靠\n")
+	for _, stat := range allHours {
+		if stat.Hour == hour {
+			current = stat
+			break
 		}
-		fmt.Printf("\n基础统计：\n")
-		fmt.Printf("  上涨次数: %d (%.2f%%)\n", currentHourStats.UpCount, currentHourStats.UpRate)
-		fmt.Printf("  下跌次数: %d (%.2f%%)\n", currentHourStats.DownCount,
-			float64(currentHourStats.DownCount)/float64(currentHourStats.TotalCount)*100)
-		fmt.Printf("  平盘次数: %d (%.2f%%)\n\n", currentHourStats.FlatCount,
-			float64(currentHourStats.FlatCount)/float64(currentHourStats.TotalCount)*100)
 	}
 
-	// 2. 打印24小时对比分析
-	if len(allHourStats) > 0 {
-		print24HourComparison(allHourStats, currentHour, interval)
+	return &Strategy2Analysis{
+		Symbol:   symbol,
+		Interval: interval,
+		Hour:     hour,
+		Current:  current,
+		AllHours: allHours,
 	}
-
-	// 3. 打印最佳和最差时段
-	printBestAndWorstHours(allHourStats, currentHour)
 }
 
-// print24HourComparison 打印24小时对比
-func print24HourComparison(allStats []*HourStats, currentHour int, interval string) {
-	fmt.Printf("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
-	fmt.Printf("📊 24小时涨跌概率分布 (%s周期)\n", interval)
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
-
-	// 按小时排序
-	sort.Slice(allStats, func(i, j int) bool {
-		return allStats[i].Hour < allStats[j].Hour
-	})
-
-	fmt.Printf("%-6s %-10s %-12s %-8s %s\n", "时段", "样本数", "上涨率", "涨/跌", "图表")
-	fmt.Printf("%-6s %-10s %-12s %-8s %s\n", "────", "────────", "──────────", "──────", "────────────────────")
-
+// BestWorstHour 返回样本数不少于 minSamples 且通过多重比较校正的时段中，上涨率显著高于基准的最佳小时和显著低于基准的最差小时，没有时返回 nil
+func BestWorstHour(allStats []*HourStats, minSamples int) (best, worst *HourStats) {
 	for _, stat := range allStats {
-		marker := "  "
-		if stat.Hour == currentHour {
-			marker = "👉"
-		}
-
-		// 生成可视化柱状图
-		barLength := int(stat.UpRate / 5) // 每5%一个字符
-		bar := ""
-		for i := 0; i < barLength; i++ {
-			bar += "█"
+		if stat.TotalCount < minSamples {
+			continue
 		}
-
-		fmt.Printf("%s%02d:00 %-10d %6.2f%%    %3d/%-3d %s\n",
-			marker,
-			stat.Hour,
-			stat.TotalCount,
-			stat.UpRate,
-			stat.UpCount,
-			stat.DownCount,
-			bar,
-		)
-	}
-}
-
-// printBestAndWorstHours 打印最佳和最差时段
-func printBestAndWorstHours(allStats []*HourStats, currentHour int) {
-	if len(allStats) == 0 {
-		return
-	}
-
-	fmt.Printf("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
-	fmt.Printf("💡 时段对比摘要\n")
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
-
-	// 找出上涨率最高和最低的时段（样本数>=5）
-	var best, worst *HourStats
-	for _, stat := range allStats {
-		if stat.TotalCount < 5 {
+		if !stat.SurvivesCorrection {
 			continue
 		}
-		if best == nil || stat.UpRate > best.UpRate {
+		if stat.UpRate > stat.Baseline && (best == nil || stat.UpRate > best.UpRate) {
 			best = stat
 		}
-		if worst == nil || stat.UpRate < worst.UpRate {
+		if stat.UpRate < stat.Baseline && (worst == nil || stat.UpRate < worst.UpRate) {
 			worst = stat
 		}
 	}
+	return best, worst
+}
 
-	if best != nil && worst != nil {
-		fmt.Printf("📈 最佳时段: %02d:00 - 上涨率 %.2f%% (%d涨/%d跌, 样本%d条)\n",
-			best.Hour, best.UpRate, best.UpCount, best.DownCount, best.TotalCount)
-		fmt.Printf("📉 最差时段: %02d:00 - 上涨率 %.2f%% (%d涨/%d跌, 样本%d条)\n\n",
-			worst.Hour, worst.UpRate, worst.UpCount, worst.DownCount, worst.TotalCount)
-	}
-
-	// 找出当前时段并显示信息
-	var currentStat *HourStats
-	for _, stat := range allStats {
-		if stat.Hour == currentHour {
-			currentStat = stat
-			break
+// isHourlyInterval 检查是否是小时级别周期
+func isHourlyInterval(interval string) bool {
+	for _, hourInterval := range hourlyIntervals {
+		if interval == hourInterval {
+			return true
 		}
 	}
+	return false
+}
 
-	if currentStat != nil && currentStat.TotalCount >= 5 {
-		// 计算排名（按上涨率）
-		rank := 1
-		validCount := 0
-		for _, stat := range allStats {
-			if stat.TotalCount >= 5 {
-				validCount++
-				if stat.UpRate > currentStat.UpRate {
-					rank++
-				}
-			}
-		}
-
-		fmt.Printf("🎯 当前时段 (%02d:00): 上涨率 %.2f%%, 排名 %d/%d\n",
-			currentHour, currentStat.UpRate, rank, validCount)
+// analyzeSpecificHour 分析特定小时的统计数据
+func analyzeSpecificHour(symbol, interval string, hour int, asOf time.Time) *HourStats {
+	hourStr := fmt.Sprintf("%d", hour)
 
-		if rank <= validCount/3 {
-			fmt.Printf("✅ 当前时段表现优秀，历史上涨概率较高\n")
-		} else if rank >= validCount*2/3 {
-			fmt.Printf("⚠️  当前时段表现较差，建议谨慎操作\n")
-		} else {
-			fmt.Printf("ℹ️  当前时段表现中等\n")
+	klines, err := store.Default().FindKlines(store.KlineQuery{Symbol: symbol, Interval: interval, Hour: hourStr, ClosedBefore: asOf})
+	if err != nil || len(klines) == 0 {
+		return &HourStats{
+			Hour:       hour,
+			TotalCount: 0,
+			Records:    []KlineRecord{},
 		}
 	}
 
-	// 时段建议
-	fmt.Printf("\n💡 交易时段建议：\n")
-
-	// 找出高胜率时段（上涨率>60%且样本>=10）
-	highWinRate := make([]*HourStats, 0)
-	for _, stat := range allStats {
-		if stat.TotalCount >= 10 && stat.UpRate >= 60 {
-			highWinRate = append(highWinRate, stat)
-		}
-	}
+	return calculateHourStats(hour, klines)
+}
 
-	if len(highWinRate) > 0 {
-		sort.Slice(highWinRate, func(i, j int) bool {
-			return highWinRate[i].UpRate > highWinRate[j].UpRate
-		})
+// analyzeAll24Hours 分析所有24小时的数据
+func analyzeAll24Hours(symbol, interval string, asOf time.Time) []*HourStats {
+	stats := make([]*HourStats, 0, 24)
 
-		fmt.Printf("  高胜率时段（上涨率≥60%%）:\n")
-		for i, stat := range highWinRate {
-			if i >= 3 { // 最多显示3个
-				break
-			}
-			fmt.Printf("    • %02d:00 (%.2f%%, 样本%d条)\n", stat.Hour, stat.UpRate, stat.TotalCount)
+	for hour := 0; hour < 24; hour++ {
+		stat := analyzeSpecificHour(symbol, interval, hour, asOf)
+		if stat.TotalCount > 0 {
+			stats = append(stats, stat)
 		}
-	} else {
-		fmt.Printf("  暂无高胜率时段（上涨率≥60%%且样本≥10）\n")
 	}
 
-	fmt.Printf("\n⚠️  风险提示：历史数据不代表未来表现，请结合实时行情和其他技术指标综合判断！\n")
-	fmt.Printf("════════════════════════════════════════════════════════════════\n\n")
+	return stats
 }
 
-// saveStrategy2Result 保存策略二结果到数据库
-func saveStrategy2Result(symbol, interval string, allHourStats []*HourStats) {
-	for _, hourStat := range allHourStats {
-		// 创建或更新策略二结果
-		result := &model.Strategy2Result{
-			Symbol:     symbol,
-			Interval:   interval,
-			Hour:       hourStat.Hour,
-			TotalCount: hourStat.TotalCount,
-			UpCount:    hourStat.UpCount,
-			DownCount:  hourStat.DownCount,
-			FlatCount:  hourStat.FlatCount,
-			UpRate:     hourStat.UpRate,
-		}
-
-		// 使用upsert保存结果
-		err := db.Pog.Where("symbol = ? AND interval = ? AND hour = ?", symbol, interval, hourStat.Hour).
-			Assign(result).
-			FirstOrCreate(result).Error
-
-		if err != nil {
-			fmt.Printf("⚠️ 保存策略二结果失败: %v\n", err)
-			continue
-		}
-
-		// 删除旧的详细记录
-		db.Pog.Where("result_id = ?", result.ID).Delete(&model.Strategy2DetailRecord{})
-
-		// 保存详细记录
-		for _, record := range hourStat.Records {
-			detailRecord := &model.Strategy2DetailRecord{
-				ResultID:   result.ID,
-				Date:       record.Year,
-				OpenPrice:  record.OpenPrice,
-				ClosePrice: record.ClosePrice,
-				PriceDiff:  record.PriceDiff,
-				IsUp:       record.IsUp,
-				CloseTime:  record.CloseTime,
-			}
-			if err := db.Pog.Create(detailRecord).Error; err != nil {
-				fmt.Printf("⚠️ 保存详细记录失败: %v\n", err)
-			}
-		}
+// calculateHourStats 计算小时统计数据
+func calculateHourStats(hour int, klines []model.Kline) *HourStats {
+	counts := countKlines(klines)
+	return &HourStats{
+		Hour:        hour,
+		TotalCount:  counts.TotalCount,
+		UpCount:     counts.UpCount,
+		DownCount:   counts.DownCount,
+		FlatCount:   counts.FlatCount,
+		UpRate:      counts.UpRate,
+		ReturnStats: counts.Returns,
+		Records:     counts.Records,
 	}
 }
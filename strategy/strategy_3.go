@@ -0,0 +1,278 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"trade/model"
+	"trade/store"
+)
+
+// WholeDay 星期统计中表示整天汇总的小时值
+const WholeDay = -1
+
+// weekdayNames 星期中文名称（0=周日）
+var weekdayNames = []string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"}
+
+// WeekdayStats 星期(或星期×小时)的统计数据
+type WeekdayStats struct {
+	Weekday    int     `json:"weekday"`     // 星期 (0=周日 ... 6=周六)
+	Hour       int     `json:"hour"`        // 小时 (0-23)，-1 表示整天
+	TotalCount int     `json:"total_count"` // 总样本数
+	UpCount    int     `json:"up_count"`    // 上涨次数
+	DownCount  int     `json:"down_count"`  // 下跌次数
+	FlatCount  int     `json:"flat_count"`  // 平盘次数
+	UpRate     float64 `json:"up_rate"`     // 上涨概率
+
+	// 涨跌幅统计
+	model.ReturnStats
+	// 相对基准上涨率的显著性
+	model.Significance
+}
+
+// Strategy3Analysis 策略三分析结果
+type Strategy3Analysis struct {
+	Symbol       string          `json:"symbol"`        // 交易对
+	Interval     string          `json:"interval"`      // 时间周期
+	Weekday      int             `json:"weekday"`       // 分析星期
+	Hour         int             `json:"hour"`          // 分析小时，-1 表示整天
+	Current      *WeekdayStats   `json:"current"`       // 当前星期(×小时)统计
+	AllWeekdays  []*WeekdayStats `json:"all_weekdays"`  // 7天统计（按星期排序，只包含有数据的星期）
+	WeekdayHours []*WeekdayStats `json:"weekday_hours"` // 星期×小时统计（仅日内周期，按星期、小时排序）
+}
+
+// strategy3 星期效应分析策略
+type strategy3 struct{}
+
+func init() {
+	Register(&strategy3{})
+}
+
+func (s *strategy3) Name() string {
+	return "strategy_3"
+}
+
+func (s *strategy3) Description() string {
+	return "星期效应分析：统计每个星期(日内周期再细分到星期×小时)的上涨概率、涨跌幅分布和样本数"
+}
+
+func (s *strategy3) Parameters() []Param {
+	return []Param{
+		{Name: "date", Type: "string", Default: "今天", Description: "分析日期，取其星期，格式：2024-10-30"},
+		{Name: "weekday", Type: "int", Default: "date 对应的星期", Description: "分析星期，0-6（0=周日），优先于 date"},
+		{Name: "hour", Type: "int", Default: "整天", Description: "分析小时，0-23，仅日内周期(分钟/小时级)生效"},
+	}
+}
+
+func (s *strategy3) Analyze(ctx context.Context, input *Input) (*Result, error) {
+	weekday := int(input.Time.Weekday())
+	if value, ok := input.Params["weekday"]; ok && value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 || n > 6 {
+			return nil, fmt.Errorf("%w: weekday参数必须在0-6之间", ErrInvalidParam)
+		}
+		weekday = n
+	}
+
+	// 只有显式指定 hour 时才细分到星期×小时
+	hour := WholeDay
+	if value, ok := input.Params["hour"]; ok && value != "" && isIntradayInterval(input.Interval) {
+		hour = input.Time.Hour()
+	}
+
+	analysis := AnalyzeDayOfWeek(input.Symbol, input.Interval, weekday, hour, input.AsOf)
+	if analysis.Current.TotalCount == 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoData, input.Symbol, weekdayLabel(weekday, hour))
+	}
+
+	return &Result{
+		Strategy: s.Name(),
+		Symbol:   input.Symbol,
+		Interval: input.Interval,
+		Time:     input.Time,
+		AsOf:     resultAsOf(input.AsOf),
+		Data:     analysis,
+	}, nil
+}
+
+func (s *strategy3) Run(config *model.Config, asOf time.Time) {
+	Strategy3(config, asOf)
+}
+
+// Strategy3 星期效应分析策略，保存结果并输出到控制台
+// asOf 为零值时分析当前星期并使用全部K线，否则分析 asOf 所在星期且只使用之前已收盘的K线
+func Strategy3(config *model.Config, asOf time.Time) {
+	if config == nil || len(config.Symbols) == 0 {
+		fmt.Println("⚠️  配置文件为空，无法执行策略分析")
+		return
+	}
+
+	// 获取当前星期和小时
+	now := analysisTime(asOf)
+	currentWeekday := int(now.Weekday())
+
+	printStrategy3Header(currentWeekday, len(config.Symbols))
+
+	// 遍历配置文件中的所有交易对
+	for i, symbolConfig := range config.Symbols {
+		printSymbolHeader(i, len(config.Symbols), symbolConfig.Symbol)
+
+		for _, interval := range symbolConfig.Intervals {
+			hour := WholeDay
+			if isIntradayInterval(interval) {
+				hour = now.Hour()
+			}
+
+			analysis := AnalyzeDayOfWeek(symbolConfig.Symbol, interval, currentWeekday, hour, asOf)
+			SaveStrategy3Analysis(analysis)
+			PrintStrategy3Analysis(analysis)
+		}
+	}
+
+	printStrategy3Footer()
+}
+
+// AnalyzeDayOfWeek 分析单个交易对+时间周期的星期效应
+// hour 为 -1 时当前统计取整天，否则取星期×小时（仅日内周期有数据）；asOf 非零时只使用在该时刻之前已收盘的K线
+func AnalyzeDayOfWeek(symbol, interval string, weekday, hour int, asOf time.Time) *Strategy3Analysis {
+	analysis := &Strategy3Analysis{
+		Symbol:       symbol,
+		Interval:     interval,
+		Weekday:      weekday,
+		Hour:         hour,
+		Current:      &WeekdayStats{Weekday: weekday, Hour: hour},
+		AllWeekdays:  []*WeekdayStats{},
+		WeekdayHours: []*WeekdayStats{},
+	}
+
+	klines, err := store.Default().FindKlines(store.KlineQuery{Symbol: symbol, Interval: interval, ClosedBefore: asOf})
+	if err != nil || len(klines) == 0 {
+		return analysis
+	}
+
+	// 按星期分组，日内周期再按星期×小时分组
+	byWeekday := make(map[int][]model.Kline)
+	byWeekdayHour := make(map[[2]int][]model.Kline)
+	intraday := isIntradayInterval(interval)
+	for _, kline := range klines {
+		day := klineWeekday(kline)
+		byWeekday[day] = append(byWeekday[day], kline)
+		if intraday {
+			key := [2]int{day, kline.OpenTime.UTC().Hour()}
+			byWeekdayHour[key] = append(byWeekdayHour[key], kline)
+		}
+	}
+
+	// 以全部K线的上涨率为基准计算显著性
+	baseline := countKlines(klines).UpRate
+	for day := 0; day < 7; day++ {
+		if group, ok := byWeekday[day]; ok {
+			analysis.AllWeekdays = append(analysis.AllWeekdays, calculateWeekdayStats(day, WholeDay, group, baseline))
+		}
+	}
+	for key, group := range byWeekdayHour {
+		analysis.WeekdayHours = append(analysis.WeekdayHours, calculateWeekdayStats(key[0], key[1], group, baseline))
+	}
+	sort.Slice(analysis.WeekdayHours, func(i, j int) bool {
+		a, b := analysis.WeekdayHours[i], analysis.WeekdayHours[j]
+		if a.Weekday != b.Weekday {
+			return a.Weekday < b.Weekday
+		}
+		return a.Hour < b.Hour
+	})
+
+	// 7天、星期×小时分别作为一组做多重比较校正
+	correctFamily(weekdaySignificances(analysis.AllWeekdays))
+	correctFamily(weekdaySignificances(analysis.WeekdayHours))
+
+	// 当前统计从分组结果中取，没有数据时保留空统计
+	candidates := analysis.AllWeekdays
+	if hour != WholeDay {
+		candidates = analysis.WeekdayHours
+	}
+	for _, stat := range candidates {
+		if stat.Weekday == weekday && stat.Hour == hour {
+			analysis.Current = stat
+			break
+		}
+	}
+
+	return analysis
+}
+
+// BestWorstWeekday 返回样本数不少于 minSamples 且通过多重比较校正的统计中，上涨率显著高于基准的最佳一组和显著低于基准的最差一组，没有时返回 nil
+func BestWorstWeekday(allStats []*WeekdayStats, minSamples int) (best, worst *WeekdayStats) {
+	for _, stat := range allStats {
+		if stat.TotalCount < minSamples {
+			continue
+		}
+		if !stat.SurvivesCorrection {
+			continue
+		}
+		if stat.UpRate > stat.Baseline && (best == nil || stat.UpRate > best.UpRate) {
+			best = stat
+		}
+		if stat.UpRate < stat.Baseline && (worst == nil || stat.UpRate < worst.UpRate) {
+			worst = stat
+		}
+	}
+	return best, worst
+}
+
+// WeekdayName 返回星期的中文名称（0=周日）
+func WeekdayName(weekday int) string {
+	if weekday < 0 || weekday >= len(weekdayNames) {
+		return fmt.Sprintf("星期%d", weekday)
+	}
+	return weekdayNames[weekday]
+}
+
+// weekdayLabel 返回星期(×小时)的显示文本，例如 "周一" 或 "周一 08:00"
+func weekdayLabel(weekday, hour int) string {
+	if hour == WholeDay {
+		return WeekdayName(weekday)
+	}
+	return fmt.Sprintf("%s %02d:00", WeekdayName(weekday), hour)
+}
+
+// isIntradayInterval 检查是否是日内周期（分钟/小时级别）
+func isIntradayInterval(interval string) bool {
+	return strings.HasSuffix(interval, "m") || strings.HasSuffix(interval, "h")
+}
+
+// klineWeekday 返回K线的星期（0=周日），优先使用入库时写入的 Week 字段(1=周日 ... 7=周六)
+func klineWeekday(kline model.Kline) int {
+	if week, err := strconv.Atoi(kline.Week); err == nil && week >= 1 && week <= 7 {
+		return week - 1
+	}
+	return int(kline.OpenTime.UTC().Weekday())
+}
+
+// weekdaySignificances 返回一组统计的显著性指针，用于多重比较校正
+func weekdaySignificances(allStats []*WeekdayStats) []*model.Significance {
+	family := make([]*model.Significance, 0, len(allStats))
+	for _, stat := range allStats {
+		family = append(family, &stat.Significance)
+	}
+	return family
+}
+
+// calculateWeekdayStats 计算星期(×小时)统计数据，baseline 为显著性检验的基准上涨率(%)
+func calculateWeekdayStats(weekday, hour int, klines []model.Kline, baseline float64) *WeekdayStats {
+	counts := countKlines(klines)
+	return &WeekdayStats{
+		Weekday:      weekday,
+		Hour:         hour,
+		TotalCount:   counts.TotalCount,
+		UpCount:      counts.UpCount,
+		DownCount:    counts.DownCount,
+		FlatCount:    counts.FlatCount,
+		UpRate:       counts.UpRate,
+		ReturnStats:  counts.Returns,
+		Significance: significanceOf(counts.UpCount, counts.TotalCount, baseline),
+	}
+}
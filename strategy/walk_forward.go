@@ -0,0 +1,403 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"trade/model"
+	"trade/stats"
+	"trade/store"
+)
+
+// DefaultWalkForwardMinSamples 样本外预测时分组在训练集中的最少样本数
+const DefaultWalkForwardMinSamples = 3
+
+// 样本外验证结论
+const (
+	WalkForwardPersistent = "persistent" // 优势持续存在
+	WalkForwardDecaying   = "decaying"   // 早期有优势，近年衰减
+	WalkForwardNoEdge     = "no_edge"    // 样本外没有优势
+)
+
+// WalkForwardYear 某一年的样本外预测结果
+type WalkForwardYear struct {
+	Year            int     `json:"year"`               // 预测年份
+	Predictions     int     `json:"predictions"`        // 发出方向预测的K线数
+	Hits            int     `json:"hits"`               // 预测方向正确的K线数
+	HitRate         float64 `json:"hit_rate"`           // 样本外命中率(%)
+	Skipped         int     `json:"skipped"`            // 训练样本不足或信号中性而跳过的K线数
+	LongCount       int     `json:"long_count"`         // 预测上涨次数
+	ShortCount      int     `json:"short_count"`        // 预测下跌次数
+	MeanReturn      float64 `json:"mean_return"`        // 按预测方向持有每根K线的平均收益(%)
+	TotalReturn     float64 `json:"total_return"`       // 按预测方向持有的收益合计(%)，不复利
+	PValue          float64 `json:"p_value"`            // 命中率相对50%的二项检验p值
+	InSampleHitRate float64 `json:"in_sample_hit_rate"` // 使用全部年份(含当年)统计时的命中率(%)，用于对比样本内偏差
+}
+
+// WalkForwardSummary 跨年汇总
+type WalkForwardSummary struct {
+	Predictions       int     `json:"predictions"`          // 预测总数
+	Hits              int     `json:"hits"`                 // 命中总数
+	HitRate           float64 `json:"hit_rate"`             // 样本外命中率(%)
+	PValue            float64 `json:"p_value"`              // 命中率相对50%的二项检验p值
+	MeanReturn        float64 `json:"mean_return"`          // 样本外平均收益(%)
+	InSampleHitRate   float64 `json:"in_sample_hit_rate"`   // 样本内命中率(%)
+	FirstHalfHitRate  float64 `json:"first_half_hit_rate"`  // 前一半年份的样本外命中率(%)
+	SecondHalfHitRate float64 `json:"second_half_hit_rate"` // 后一半年份的样本外命中率(%)
+	Slope             float64 `json:"slope"`                // 每年命中率的线性趋势(百分点/年)，负数表示衰减
+	Verdict           string  `json:"verdict"`              // 结论: persistent / decaying / no_edge
+	Conclusion        string  `json:"conclusion"`           // 结论说明
+}
+
+// WalkForwardAnalysis 季节性优势的样本外(walk-forward)验证结果
+type WalkForwardAnalysis struct {
+	Symbol     string             `json:"symbol"`      // 交易对
+	Interval   string             `json:"interval"`    // 时间周期
+	Base       string             `json:"base"`        // 被验证的策略: strategy_1 / strategy_2
+	MinSamples int                `json:"min_samples"` // 训练集最少样本数
+	Years      []*WalkForwardYear `json:"years"`       // 每年的样本外结果（按年份排序）
+	Summary    WalkForwardSummary `json:"summary"`     // 汇总
+}
+
+// walkForward 样本外验证策略
+type walkForward struct{}
+
+func init() {
+	Register(&walkForward{})
+}
+
+func (s *walkForward) Name() string {
+	return "walk_forward"
+}
+
+func (s *walkForward) Description() string {
+	return "样本外验证：对每一年只用之前年份的策略一/策略二统计预测当年每天/每小时的方向，统计命中率和收益，判断优势是否持续"
+}
+
+func (s *walkForward) Parameters() []Param {
+	return []Param{
+		{Name: "base", Type: "string", Default: "日内周期为 strategy_2，其余为 strategy_1", Description: "被验证的策略：strategy_1(历史同期) 或 strategy_2(小时)"},
+		{Name: "min_samples", Type: "int", Default: strconv.Itoa(DefaultWalkForwardMinSamples), Description: "分组在训练集中的最少样本数，不足时不预测"},
+	}
+}
+
+func (s *walkForward) Analyze(ctx context.Context, input *Input) (*Result, error) {
+	base := defaultWalkForwardBase(input.Interval)
+	if value, ok := input.Params["base"]; ok && value != "" {
+		if value != "strategy_1" && value != "strategy_2" {
+			return nil, fmt.Errorf("%w: base只支持strategy_1或strategy_2", ErrInvalidParam)
+		}
+		base = value
+	}
+
+	minSamples := DefaultWalkForwardMinSamples
+	if value, ok := input.Params["min_samples"]; ok && value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 2 {
+			return nil, fmt.Errorf("%w: min_samples必须是不小于2的整数", ErrInvalidParam)
+		}
+		minSamples = n
+	}
+
+	analysis := WalkForward(input.Symbol, input.Interval, base, minSamples, input.AsOf)
+	if len(analysis.Years) == 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoData, input.Symbol, input.Interval)
+	}
+
+	return &Result{
+		Strategy: s.Name(),
+		Symbol:   input.Symbol,
+		Interval: input.Interval,
+		Time:     input.Time,
+		AsOf:     resultAsOf(input.AsOf),
+		Data:     analysis,
+	}, nil
+}
+
+// OnDemand 每个交易对、周期都要逐年重算全部历史，不随定时任务和单次运行批量执行
+func (s *walkForward) OnDemand() bool {
+	return true
+}
+
+func (s *walkForward) Run(config *model.Config, asOf time.Time) {
+	if config == nil || len(config.Symbols) == 0 {
+		fmt.Println("⚠️  配置文件为空，无法执行策略分析")
+		return
+	}
+
+	printWalkForwardHeader(len(config.Symbols))
+	for i, symbolConfig := range config.Symbols {
+		printSymbolHeader(i, len(config.Symbols), symbolConfig.Symbol)
+		for _, interval := range symbolConfig.Intervals {
+			base := defaultWalkForwardBase(interval)
+			PrintWalkForwardAnalysis(WalkForward(symbolConfig.Symbol, interval, base, DefaultWalkForwardMinSamples, asOf))
+		}
+	}
+	printWalkForwardFooter()
+}
+
+// defaultWalkForwardBase 日内周期验证小时效应，其余验证历史同期效应
+func defaultWalkForwardBase(interval string) string {
+	if isIntradayInterval(interval) {
+		return "strategy_2"
+	}
+	return "strategy_1"
+}
+
+// newWalkForwardModel 被验证策略的内存信号模型，与策略的分组和交易信号规则一致
+func newWalkForwardModel(base, interval string) *SignalModel {
+	s, _ := Get(base)
+	m, err := signalModelOf(s, interval)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// predictDirection 用模型预测K线的方向：1 看涨，-1 看跌，0 不预测
+// 分组样本数不足 minSamples 时不预测，其余与交易信号(TradeSignal)同一条规则
+func predictDirection(m *SignalModel, openTime time.Time, minSamples int) int {
+	if m.Samples(openTime) < minSamples {
+		return 0
+	}
+	signal, _ := m.Signal(openTime)
+	switch signal {
+	case SignalBullish:
+		return 1
+	case SignalBearish:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// klineReturnPct 单根K线涨跌幅(%)，开盘价为0时返回 false
+func klineReturnPct(kline model.Kline) (float64, bool) {
+	if kline.Open == 0 {
+		return 0, false
+	}
+	return (kline.Close - kline.Open) / kline.Open * 100, true
+}
+
+// WalkForward 逐年做样本外验证：预测第 Y 年时只使用 Y 年之前的K线统计
+// asOf 非零时只使用在该时刻之前已收盘的K线，最后一年只验证到 asOf 为止
+func WalkForward(symbol, interval, base string, minSamples int, asOf time.Time) *WalkForwardAnalysis {
+	analysis := &WalkForwardAnalysis{
+		Symbol:     symbol,
+		Interval:   interval,
+		Base:       base,
+		MinSamples: minSamples,
+		Years:      []*WalkForwardYear{},
+	}
+
+	klines, err := store.Default().FindKlines(store.KlineQuery{Symbol: symbol, Interval: interval, ClosedBefore: asOf})
+	if err != nil || len(klines) == 0 {
+		analysis.Summary = summarizeWalkForward(analysis.Years, 0)
+		return analysis
+	}
+
+	// 按年份分组，同时用全部K线统计样本内的预测
+	byYear := make(map[int][]model.Kline)
+	inSample := newWalkForwardModel(base, interval)
+	for _, kline := range klines {
+		year := kline.OpenTime.UTC().Year()
+		byYear[year] = append(byYear[year], kline)
+		inSample.Observe(kline)
+	}
+	years := make([]int, 0, len(byYear))
+	for year := range byYear {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+
+	// 训练集只包含已经完整结束的年份，每预测完一年再把当年并入训练集
+	train := newWalkForwardModel(base, interval)
+	var inSampleHits, inSampleTotal int
+	for i, year := range years {
+		if i > 0 {
+			stat, hits, predictions := scoreWalkForwardYear(year, byYear[year], train, inSample, minSamples)
+			analysis.Years = append(analysis.Years, stat)
+			inSampleHits += hits
+			inSampleTotal += predictions
+		}
+		for _, kline := range byYear[year] {
+			train.Observe(kline)
+		}
+	}
+
+	analysis.Summary = summarizeWalkForward(analysis.Years, percent(inSampleHits, inSampleTotal))
+	return analysis
+}
+
+// scoreWalkForwardYear 用训练集预测一年内的每根K线，同时返回样本内预测的命中数和预测数
+func scoreWalkForwardYear(year int, klines []model.Kline, train, inSample *SignalModel, minSamples int) (*WalkForwardYear, int, int) {
+	stat := &WalkForwardYear{Year: year}
+	var inSampleHits, inSamplePredictions int
+
+	for _, kline := range klines {
+		r, ok := klineReturnPct(kline)
+		if !ok {
+			stat.Skipped++
+			continue
+		}
+		if direction := predictDirection(inSample, kline.OpenTime, minSamples); direction != 0 {
+			inSamplePredictions++
+			if float64(direction)*r > 0 {
+				inSampleHits++
+			}
+		}
+
+		direction := predictDirection(train, kline.OpenTime, minSamples)
+		if direction == 0 {
+			stat.Skipped++
+			continue
+		}
+
+		stat.Predictions++
+		if direction > 0 {
+			stat.LongCount++
+		} else {
+			stat.ShortCount++
+		}
+		// 平盘不算命中
+		if float64(direction)*r > 0 {
+			stat.Hits++
+		}
+		stat.TotalReturn += float64(direction) * r
+	}
+
+	stat.HitRate = percent(stat.Hits, stat.Predictions)
+	stat.InSampleHitRate = percent(inSampleHits, inSamplePredictions)
+	if stat.Predictions > 0 {
+		stat.MeanReturn = stat.TotalReturn / float64(stat.Predictions)
+	}
+	stat.PValue = stats.BinomialPValue(stat.Hits, stat.Predictions, 0.5)
+	return stat, inSampleHits, inSamplePredictions
+}
+
+// summarizeWalkForward 汇总各年结果并判断优势是持续、衰减还是不存在
+func summarizeWalkForward(years []*WalkForwardYear, inSampleHitRate float64) WalkForwardSummary {
+	summary := WalkForwardSummary{InSampleHitRate: inSampleHitRate}
+
+	// 只统计有预测的年份
+	scored := make([]*WalkForwardYear, 0, len(years))
+	var totalReturn float64
+	for _, year := range years {
+		if year.Predictions == 0 {
+			continue
+		}
+		scored = append(scored, year)
+		summary.Predictions += year.Predictions
+		summary.Hits += year.Hits
+		totalReturn += year.TotalReturn
+	}
+	if summary.Predictions == 0 {
+		summary.PValue = 1
+		summary.Verdict = WalkForwardNoEdge
+		summary.Conclusion = "历史年份不足，无法做样本外预测"
+		return summary
+	}
+
+	summary.HitRate = percent(summary.Hits, summary.Predictions)
+	summary.PValue = stats.BinomialPValue(summary.Hits, summary.Predictions, 0.5)
+	summary.MeanReturn = totalReturn / float64(summary.Predictions)
+	summary.Slope = hitRateSlope(scored)
+
+	// 前后两半年份分别汇总
+	half := len(scored) / 2
+	firstHits, firstTotal := sumHits(scored[:half])
+	secondHits, secondTotal := sumHits(scored[half:])
+	summary.FirstHalfHitRate = percent(firstHits, firstTotal)
+	summary.SecondHalfHitRate = percent(secondHits, secondTotal)
+
+	overall := significantEdge(summary.Hits, summary.Predictions)
+	first := significantEdge(firstHits, firstTotal)
+	second := significantEdge(secondHits, secondTotal)
+	switch {
+	case second || overall && summary.SecondHalfHitRate >= summary.FirstHalfHitRate:
+		summary.Verdict = WalkForwardPersistent
+		summary.Conclusion = fmt.Sprintf("样本外命中率 %.2f%% 显著高于50%%，近年仍然有效", summary.HitRate)
+	case first || overall:
+		summary.Verdict = WalkForwardDecaying
+		summary.Conclusion = fmt.Sprintf("早期样本外命中率 %.2f%%，近年降至 %.2f%%，优势在衰减",
+			summary.FirstHalfHitRate, summary.SecondHalfHitRate)
+	default:
+		summary.Verdict = WalkForwardNoEdge
+		summary.Conclusion = fmt.Sprintf("样本外命中率 %.2f%% 与50%%无显著差异(p=%.4f)，样本内的优势可能来自过拟合",
+			summary.HitRate, summary.PValue)
+	}
+	return summary
+}
+
+// significantEdge 命中率是否显著高于50%
+func significantEdge(hits, total int) bool {
+	if total == 0 || hits*2 <= total {
+		return false
+	}
+	return stats.BinomialPValue(hits, total, 0.5) < stats.DefaultAlpha
+}
+
+// sumHits 汇总多个年份的命中数和预测数
+func sumHits(years []*WalkForwardYear) (hits, total int) {
+	for _, year := range years {
+		hits += year.Hits
+		total += year.Predictions
+	}
+	return hits, total
+}
+
+// hitRateSlope 每年命中率对年份的最小二乘斜率(百分点/年)
+func hitRateSlope(years []*WalkForwardYear) float64 {
+	n := float64(len(years))
+	if n < 2 {
+		return 0
+	}
+	var sumX, sumY float64
+	for _, year := range years {
+		sumX += float64(year.Year)
+		sumY += year.HitRate
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var cov, varX float64
+	for _, year := range years {
+		dx := float64(year.Year) - meanX
+		cov += dx * (year.HitRate - meanY)
+		varX += dx * dx
+	}
+	if varX == 0 {
+		return 0
+	}
+	return cov / varX
+}
+
+// percent 返回 part/total 的百分比，total 为0时返回0
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// walkForwardBaseName 被验证策略的中文名称
+func walkForwardBaseName(base string) string {
+	if base == "strategy_2" {
+		return "策略二(小时)"
+	}
+	return "策略一(历史同期)"
+}
+
+// walkForwardVerdictText 结论的中文名称
+func walkForwardVerdictText(verdict string) string {
+	switch verdict {
+	case WalkForwardPersistent:
+		return "✅ 持续有效"
+	case WalkForwardDecaying:
+		return "📉 优势衰减"
+	default:
+		return "❌ 无样本外优势"
+	}
+}
@@ -0,0 +1,123 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"trade/model"
+)
+
+// seasonalKlines 生成每年1月前10天 08:00 和 09:00 的小时K线，up 为 true 时 08:00 上涨、09:00 下跌，否则相反
+func seasonalKlines(year int, up bool) []model.Kline {
+	klines := make([]model.Kline, 0, 20)
+	for d := 1; d <= 10; d++ {
+		move := 1 + float64(d%3) // 涨跌幅 1%~3%，保证标准差不为0
+		if !up {
+			move = -move
+		}
+		klines = append(klines,
+			testKline("BTCUSDT", "1h", time.Date(year, 1, d, 8, 0, 0, 0, time.UTC), 100, 100+move),
+			testKline("BTCUSDT", "1h", time.Date(year, 1, d, 9, 0, 0, 0, time.UTC), 100, 100-move),
+		)
+	}
+	return klines
+}
+
+func TestWalkForwardPersistent(t *testing.T) {
+	var klines []model.Kline
+	for year := 2020; year <= 2023; year++ {
+		klines = append(klines, seasonalKlines(year, true)...)
+	}
+	useMemoryStore(t, klines...)
+
+	analysis := WalkForward("BTCUSDT", "1h", "strategy_2", DefaultWalkForwardMinSamples, time.Time{})
+	// 第一年没有训练数据，不参与预测
+	if len(analysis.Years) != 3 || analysis.Years[0].Year != 2021 {
+		t.Fatalf("应从第二年开始预测: %+v", analysis.Years)
+	}
+	for _, year := range analysis.Years {
+		if year.Predictions != 20 || year.Hits != 20 || year.LongCount != 10 || year.ShortCount != 10 {
+			t.Errorf("%d 年预测结果错误: %+v", year.Year, year)
+		}
+	}
+
+	summary := analysis.Summary
+	if summary.HitRate != 100 || summary.InSampleHitRate != 100 || summary.MeanReturn <= 0 {
+		t.Errorf("汇总错误: %+v", summary)
+	}
+	if summary.Verdict != WalkForwardPersistent {
+		t.Errorf("期望 persistent，实际 %s (%s)", summary.Verdict, summary.Conclusion)
+	}
+}
+
+func TestWalkForwardDecaying(t *testing.T) {
+	// 前两年 08:00 上涨，后两年规律反转
+	var klines []model.Kline
+	klines = append(klines, seasonalKlines(2020, true)...)
+	klines = append(klines, seasonalKlines(2021, true)...)
+	klines = append(klines, seasonalKlines(2022, false)...)
+	klines = append(klines, seasonalKlines(2023, false)...)
+	useMemoryStore(t, klines...)
+
+	analysis := WalkForward("BTCUSDT", "1h", "strategy_2", DefaultWalkForwardMinSamples, time.Time{})
+	if analysis.Years[0].HitRate != 100 || analysis.Years[1].HitRate != 0 {
+		t.Fatalf("2021 年应全部命中、2022 年应全部失误: %+v %+v", analysis.Years[0], analysis.Years[1])
+	}
+	if summary := analysis.Summary; summary.Verdict != WalkForwardDecaying || summary.Slope >= 0 {
+		t.Errorf("期望 decaying 且趋势为负，实际 %+v", summary)
+	}
+}
+
+func TestWalkForwardAnalyzeParams(t *testing.T) {
+	useMemoryStore(t, seasonalKlines(2020, true)...)
+	s, _ := Get("walk_forward")
+
+	input := &Input{Symbol: "BTCUSDT", Interval: "1h", Time: time.Now(), Params: map[string]string{"base": "strategy_3"}}
+	if _, err := s.Analyze(context.Background(), input); !errors.Is(err, ErrInvalidParam) {
+		t.Errorf("base 不支持时应返回 ErrInvalidParam，实际 %v", err)
+	}
+
+	// 只有一年数据，无法做样本外预测
+	input.Params = map[string]string{"base": "strategy_2"}
+	if _, err := s.Analyze(context.Background(), input); !errors.Is(err, ErrNoData) {
+		t.Errorf("只有一年数据应返回 ErrNoData，实际 %v", err)
+	}
+}
+
+func TestWalkForwardPredictionsCorrection(t *testing.T) {
+	m := newWalkForwardModel("strategy_2", "1h")
+	for _, kline := range seasonalKlines(2020, true) {
+		m.Observe(kline)
+	}
+	// 10:00 期望收益超过一个标准误，但3个样本在校正后不显著
+	for d, move := range []float64{1, 2, 1.5} {
+		m.Observe(testKline("BTCUSDT", "1h", time.Date(2020, 2, d+1, 10, 0, 0, 0, time.UTC), 100, 100+move))
+	}
+	at := func(hour int) time.Time { return time.Date(2021, 1, 1, hour, 0, 0, 0, time.UTC) }
+	if ExpectancySignal(CalculateReturnStats([]float64{1, 2, 1.5}), 3) != SignalBullish {
+		t.Fatal("测试数据应满足期望收益规则")
+	}
+
+	if predictDirection(m, at(8), DefaultWalkForwardMinSamples) != 1 || predictDirection(m, at(9), DefaultWalkForwardMinSamples) != -1 {
+		t.Error("显著分组应给出方向")
+	}
+	if direction := predictDirection(m, at(10), DefaultWalkForwardMinSamples); direction != 0 {
+		t.Errorf("未通过多重比较校正的分组不应预测，实际 %d", direction)
+	}
+}
+
+func TestWalkForwardOnDemand(t *testing.T) {
+	s, _ := Get("walk_forward")
+	if onDemand, ok := s.(OnDemand); !ok || !onDemand.OnDemand() {
+		t.Error("walk_forward 应按需运行，不参与 RunAll")
+	}
+	for _, name := range []string{"strategy_1", "strategy_2", "strategy_3"} {
+		if s, _ := Get(name); s != nil {
+			if _, ok := s.(OnDemand); ok {
+				t.Errorf("%s 应参与 RunAll", name)
+			}
+		}
+	}
+}
@@ -2,14 +2,20 @@ package main
 
 import (
 	"fmt"
+	"log"
 
 	"trade/db"
 	"trade/model"
+	"trade/utils"
 )
 
 func main() {
 	// 初始化数据库
-	db.InitPostgreSql()
+	config, err := utils.LoadConfig("../config.json")
+	if err != nil {
+		log.Fatalf("加载配置文件失败: %v", err)
+	}
+	db.InitPostgreSql(&config.Database)
 
 	fmt.Println("========== 数据库诊断 ==========\n")
 
@@ -4,8 +4,13 @@ import (
 	"testing"
 
 	"trade/db"
+	"trade/utils"
 )
 
 func TestDb(t *testing.T) {
-	db.InitPostgreSql()
+	config, err := utils.LoadConfig("../config.json")
+	if err != nil {
+		t.Fatalf("加载配置文件失败: %v", err)
+	}
+	db.InitPostgreSql(&config.Database)
 }
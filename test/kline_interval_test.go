@@ -6,12 +6,17 @@ import (
 
 	"trade/db"
 	"trade/model"
+	"trade/utils"
 )
 
 // TestKlineIntervals 测试不同时间周期的K线数据
 func TestKlineIntervals(t *testing.T) {
 	// 初始化数据库
-	db.InitPostgreSql()
+	config, err := utils.LoadConfig("../config.json")
+	if err != nil {
+		t.Fatalf("加载配置文件失败: %v", err)
+	}
+	db.InitPostgreSql(&config.Database)
 
 	intervals := []string{"1d", "8h", "4h", "2h", "1h"}
 	symbols := []string{"BTCUSDT", "ETHUSDT"}
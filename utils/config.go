@@ -4,11 +4,34 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"trade/model"
+
+	"github.com/robfig/cron/v3"
 )
 
+// 配置文件路径的环境变量，未设置时使用 config.json
+const configPathEnv = "TRADE_CONFIG"
+
+// supportedIntervals 币安合约支持的K线周期
+var supportedIntervals = []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d", "3d", "1w", "1M"}
+
+// cronParser 带秒的cron表达式解析器（与调度器使用的格式一致）
+var cronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// DefaultConfigPath 返回默认配置文件路径（环境变量 TRADE_CONFIG 优先）
+func DefaultConfigPath() string {
+	if path := os.Getenv(configPathEnv); path != "" {
+		return path
+	}
+	return "config.json"
+}
+
 // LoadConfig 从文件加载配置
+// 加载顺序：默认值 -> 配置文件 -> 环境变量覆盖 -> 校验
 func LoadConfig(configPath string) (*model.Config, error) {
 	// 读取配置文件
 	data, err := os.ReadFile(configPath)
@@ -16,11 +39,443 @@ func LoadConfig(configPath string) (*model.Config, error) {
 		return nil, fmt.Errorf("读取配置文件失败: %v", err)
 	}
 
-	// 解析 JSON
-	var config model.Config
-	if err := json.Unmarshal(data, &config); err != nil {
+	// 解析 JSON（未出现的字段保留默认值）
+	config := defaultConfig()
+	if err := json.Unmarshal(data, config); err != nil {
 		return nil, fmt.Errorf("解析配置文件失败: %v", err)
 	}
 
-	return &config, nil
+	if err := applyEnvOverrides(config); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateConfig(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// defaultConfig 返回带默认值的配置
+func defaultConfig() *model.Config {
+	return &model.Config{
+		Database: model.DatabaseConfig{
+			Port:            5432,
+			SSLMode:         "disable",
+			TimeZone:        "UTC",
+			MaxIdleConns:    10,
+			MaxOpenConns:    100,
+			ConnMaxLifetime: "1h",
+		},
+		API:       model.ServerConfig{Port: 8080},
+		Web:       model.ServerConfig{Port: 8080},
+		Scheduler: model.SchedulerConfig{DailySpec: "0 0 0 * * *"},
+		// 默认每笔 5U 保证金、10 倍杠杆
+		Trading: model.TradingConfig{
+			Strategies:     []string{"strategy_1", "strategy_2"},
+			Margin:         5,
+			Leverage:       10,
+			FeeRate:        0.0004,
+			SlippageRate:   0.0005,
+			FundingRate:    0.0001,
+			InitialBalance: 1000,
+			ExecutionMode:  model.ExecutionModeDryRun,
+			Interval:       "1h",
+			MarginType:     model.MarginTypeIsolated,
+		},
+		Risk: model.RiskConfig{
+			MaxTotalExposure:     500,
+			MaxDailyLoss:         50,
+			MaxConsecutiveLosses: 5,
+		},
+	}
+}
+
+// applyEnvOverrides 使用环境变量覆盖配置文件中的值
+func applyEnvOverrides(config *model.Config) error {
+	stringEnvs := map[string]*string{
+		"TRADE_DB_HOST":              &config.Database.Host,
+		"TRADE_DB_USER":              &config.Database.User,
+		"TRADE_DB_PASSWORD":          &config.Database.Password,
+		"TRADE_DB_NAME":              &config.Database.DBName,
+		"TRADE_DB_SSLMODE":           &config.Database.SSLMode,
+		"TRADE_DB_TIMEZONE":          &config.Database.TimeZone,
+		"TRADE_BINANCE_API_KEY":      &config.Binance.APIKey,
+		"TRADE_BINANCE_SECRET_KEY":   &config.Binance.SecretKey,
+		"TRADE_BINANCE_DATA_URL":     &config.Binance.DataURL,
+		"TRADE_BINANCE_STREAM_URL":   &config.Binance.StreamURL,
+		"TRADE_API_HOST":             &config.API.Host,
+		"TRADE_WEB_HOST":             &config.Web.Host,
+		"TRADE_SCHEDULER_DAILY_SPEC": &config.Scheduler.DailySpec,
+		"TRADE_EXECUTION_MODE":       &config.Trading.ExecutionMode,
+	}
+	for name, target := range stringEnvs {
+		if value, ok := os.LookupEnv(name); ok {
+			*target = value
+		}
+	}
+
+	intEnvs := map[string]*int{
+		"TRADE_DB_PORT":  &config.Database.Port,
+		"TRADE_API_PORT": &config.API.Port,
+		"TRADE_WEB_PORT": &config.Web.Port,
+	}
+	for name, target := range intEnvs {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("环境变量 %s 不是有效的整数: %q", name, value)
+		}
+		*target = n
+	}
+
+	return nil
+}
+
+// ValidateConfig 校验配置，返回包含全部问题的错误
+func ValidateConfig(config *model.Config) error {
+	var problems []string
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	// 数据库
+	if config.Database.Host == "" {
+		addProblem("database.host 不能为空（或设置环境变量 TRADE_DB_HOST）")
+	}
+	if config.Database.User == "" {
+		addProblem("database.user 不能为空（或设置环境变量 TRADE_DB_USER）")
+	}
+	if config.Database.DBName == "" {
+		addProblem("database.dbname 不能为空（或设置环境变量 TRADE_DB_NAME）")
+	}
+	if !isValidPort(config.Database.Port) {
+		addProblem("database.port 必须在 1-65535 之间，当前为 %d", config.Database.Port)
+	}
+	if config.Database.MaxIdleConns < 0 || config.Database.MaxOpenConns < 0 {
+		addProblem("database.max_idle_conns / max_open_conns 不能为负数")
+	}
+	if config.Database.ConnMaxLifetime != "" {
+		if _, err := time.ParseDuration(config.Database.ConnMaxLifetime); err != nil {
+			addProblem("database.conn_max_lifetime 格式错误: %q（示例：1h、30m）", config.Database.ConnMaxLifetime)
+		}
+	}
+
+	// 币安：密钥需要成对出现
+	if (config.Binance.APIKey == "") != (config.Binance.SecretKey == "") {
+		addProblem("binance.api_key 和 binance.secret_key 需要同时设置")
+	}
+
+	// HTTP服务
+	if !isValidPort(config.API.Port) {
+		addProblem("api.port 必须在 1-65535 之间，当前为 %d", config.API.Port)
+	}
+	if !isValidPort(config.Web.Port) {
+		addProblem("web.port 必须在 1-65535 之间，当前为 %d", config.Web.Port)
+	}
+
+	// 定时任务
+	if _, err := ParseCronSpec(config.Scheduler.DailySpec); err != nil {
+		addProblem("scheduler.daily_spec 格式错误: %q（%v）", config.Scheduler.DailySpec, err)
+	}
+
+	// 自动交易
+	if config.Trading.Margin <= 0 {
+		addProblem("trading.margin 必须大于0，当前为 %v", config.Trading.Margin)
+	}
+	if config.Trading.FeeRate < 0 || config.Trading.SlippageRate < 0 {
+		addProblem("trading.fee_rate / slippage_rate 不能为负数")
+	}
+	if config.Trading.InitialBalance <= 0 {
+		addProblem("trading.initial_balance 必须大于0，当前为 %v", config.Trading.InitialBalance)
+	}
+	switch config.Trading.ExecutionMode {
+	case model.ExecutionModeDryRun:
+	case model.ExecutionModeTestnet, model.ExecutionModeLive:
+		if config.Binance.APIKey == "" || config.Binance.SecretKey == "" {
+			addProblem("trading.execution_mode 为 %s 时必须设置 binance.api_key 和 binance.secret_key", config.Trading.ExecutionMode)
+		}
+	default:
+		addProblem("trading.execution_mode 只支持 dry_run、testnet 或 live，当前为 %q", config.Trading.ExecutionMode)
+	}
+	if !IsSupportedInterval(config.Trading.Interval) {
+		addProblem("trading.interval 不支持 %q，可选：%s", config.Trading.Interval, strings.Join(supportedIntervals, ","))
+	}
+	if len(config.Trading.Strategies) == 0 {
+		addProblem("trading.strategies 不能为空")
+	}
+	for _, problem := range symbolTradingProblems("trading", config.Trading.ForSymbol(model.SymbolConfig{}), config.Trading.InitialBalance) {
+		addProblem("%s", problem)
+	}
+
+	// 风控
+	if config.Risk.MaxTotalExposure < 0 || config.Risk.MaxDailyLoss < 0 || config.Risk.MaxConsecutiveLosses < 0 {
+		addProblem("risk.max_total_exposure / max_daily_loss / max_consecutive_losses 不能为负数")
+	}
+	if notional := config.Trading.ForSymbol(model.SymbolConfig{}).Notional; config.Risk.MaxTotalExposure > 0 && config.Risk.MaxTotalExposure < notional {
+		addProblem("risk.max_total_exposure(%v) 小于每笔开仓名义价值(%v)，无法开仓", config.Risk.MaxTotalExposure, notional)
+	}
+
+	// 交易对
+	if len(config.Symbols) == 0 {
+		addProblem("symbols 不能为空")
+	}
+	seen := make(map[string]bool)
+	for i, symbolConfig := range config.Symbols {
+		if symbolConfig.Symbol == "" {
+			addProblem("symbols[%d].symbol 不能为空", i)
+		} else if seen[symbolConfig.Symbol] {
+			addProblem("symbols[%d].symbol 重复: %s", i, symbolConfig.Symbol)
+		}
+		seen[symbolConfig.Symbol] = true
+
+		if len(symbolConfig.Intervals) == 0 {
+			addProblem("symbols[%d].intervals 不能为空", i)
+		}
+		for _, interval := range symbolConfig.Intervals {
+			if !IsSupportedInterval(interval) {
+				addProblem("symbols[%d].intervals 包含不支持的周期 %q，可选：%s", i, interval, strings.Join(supportedIntervals, ","))
+			}
+		}
+
+		if resample := symbolConfig.Resample; resample != nil {
+			if !containsString(symbolConfig.Intervals, resample.Base) {
+				addProblem("symbols[%d].resample.base %q 必须同时在 intervals 中", i, resample.Base)
+			}
+			for _, label := range resample.Intervals {
+				parsed, err := ParseResampleLabel(resample.Base, label)
+				if err != nil {
+					addProblem("symbols[%d].resample.intervals: %v", i, err)
+				} else if containsString(symbolConfig.Intervals, parsed.String()) {
+					addProblem("symbols[%d].resample.intervals 中的 %s 已在 intervals 中从交易所拉取", i, label)
+				}
+			}
+		}
+
+		// 交易参数：未设置的字段继承 trading，合并后再检查一致性（全部继承时已在 trading 中检查）
+		if hasTradingOverrides(symbolConfig) {
+			field := fmt.Sprintf("symbols[%d]", i)
+			trading := config.Trading.ForSymbol(symbolConfig)
+			for _, problem := range symbolTradingProblems(field, trading, config.Trading.InitialBalance) {
+				addProblem("%s", problem)
+			}
+			if config.Risk.MaxTotalExposure > 0 && config.Risk.MaxTotalExposure < trading.Notional {
+				addProblem("%s.notional(%v) 大于 risk.max_total_exposure(%v)，无法开仓", field, trading.Notional, config.Risk.MaxTotalExposure)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("配置校验失败:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// symbolTradingProblems 检查合并后的交易参数，field 为错误信息中的字段前缀
+func symbolTradingProblems(field string, t model.SymbolTrading, initialBalance float64) []string {
+	var problems []string
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, field+"."+fmt.Sprintf(format, args...))
+	}
+
+	if t.Leverage < 1 || t.Leverage > 125 {
+		addProblem("leverage 必须在 1-125 之间，当前为 %d", t.Leverage)
+		return problems
+	}
+	if t.MarginType != model.MarginTypeIsolated && t.MarginType != model.MarginTypeCrossed {
+		addProblem("margin_type 只支持 ISOLATED 或 CROSSED，当前为 %q", t.MarginType)
+	}
+	if t.Notional <= 0 {
+		addProblem("notional 必须大于0，当前为 %v", t.Notional)
+	}
+	if t.MaxPosition > 0 && t.Notional > t.MaxPosition {
+		addProblem("notional(%v) 不能大于 max_position(%v)", t.Notional, t.MaxPosition)
+	}
+	if initialBalance > 0 && t.Margin() > initialBalance {
+		addProblem("notional/leverage 的保证金(%.2f) 不能大于 trading.initial_balance(%v)", t.Margin(), initialBalance)
+	}
+
+	// 价格反向波动 100%/杠杆 时保证金亏完，止损必须在此之前触发
+	if liquidationPct := 100 / float64(t.Leverage); t.StopLossPct >= liquidationPct {
+		addProblem("stop_loss_pct(%v) 必须小于 100/leverage(%.4g)，否则先触发强平", t.StopLossPct, liquidationPct)
+	}
+	if t.MaxPosition < 0 || t.StopLossPct < 0 || t.TakeProfitPct < 0 {
+		addProblem("max_position / stop_loss_pct / take_profit_pct 不能为负数")
+	}
+
+	seen := make(map[string]bool, len(t.Strategies))
+	for _, name := range t.Strategies {
+		if name == "" {
+			addProblem("strategies 不能包含空名称")
+		} else if seen[name] {
+			addProblem("strategies 重复: %s", name)
+		}
+		seen[name] = true
+	}
+	return problems
+}
+
+// hasTradingOverrides 交易对是否设置了任一交易参数
+func hasTradingOverrides(s model.SymbolConfig) bool {
+	return s.Leverage != 0 || s.MarginType != "" || s.Notional != 0 || len(s.Strategies) > 0 ||
+		s.MaxPosition != 0 || s.StopLossPct != 0 || s.TakeProfitPct != 0
+}
+
+// ParseCronSpec 解析带秒的cron表达式
+func ParseCronSpec(spec string) (cron.Schedule, error) {
+	return cronParser.Parse(spec)
+}
+
+func isValidPort(port int) bool {
+	return port > 0 && port <= 65535
+}
+
+// IsSupportedInterval 判断是否为币安支持的K线周期
+func IsSupportedInterval(interval string) bool {
+	for _, supported := range supportedIntervals {
+		if interval == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// IntervalDuration 根据K线周期字符串返回对应的时长（1M 按30天计算）
+func IntervalDuration(interval string) time.Duration {
+	switch interval {
+	case "1m":
+		return time.Minute
+	case "3m":
+		return 3 * time.Minute
+	case "5m":
+		return 5 * time.Minute
+	case "15m":
+		return 15 * time.Minute
+	case "30m":
+		return 30 * time.Minute
+	case "1h":
+		return time.Hour
+	case "2h":
+		return 2 * time.Hour
+	case "4h":
+		return 4 * time.Hour
+	case "6h":
+		return 6 * time.Hour
+	case "8h":
+		return 8 * time.Hour
+	case "12h":
+		return 12 * time.Hour
+	case "1d":
+		return 24 * time.Hour
+	case "3d":
+		return 3 * 24 * time.Hour
+	case "1w":
+		return 7 * 24 * time.Hour
+	case "1M":
+		return 30 * 24 * time.Hour
+	default:
+		return time.Hour // 默认1小时
+	}
+}
+
+// NextOpenTime 开盘时间为 openTime 的K线结束时刻，即下一根K线的开盘时间（1M 按自然月）
+// 入库的收盘时间截断到秒（xx:59:59），不能用收盘时间推算下一根的开盘时间
+func NextOpenTime(openTime time.Time, interval string) time.Time {
+	if interval == "1M" {
+		return openTime.AddDate(0, 1, 0)
+	}
+	return openTime.Add(IntervalDuration(interval))
+}
+
+// resampleBases 可作为重采样基础周期的K线周期（能整除一天）
+var resampleBases = []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h"}
+
+// ResampleLabel 重采样周期标签 <周期>[@<时区>][+<偏移>] 的解析结果
+// 如 4h、1d@America/New_York（按纽约当地零点切日）、1d+8h（每天 08:00 UTC 开盘）
+type ResampleLabel struct {
+	Target   string         // 目标周期
+	Location *time.Location // 日/周边界所在时区，UTC 时为 nil
+	Offset   time.Duration  // 相对日/周边界的偏移
+}
+
+// String 返回规范化的标签，作为合成K线的 interval 入库
+func (l ResampleLabel) String() string {
+	label := l.Target
+	if l.Location != nil {
+		label += "@" + l.Location.String()
+	}
+	if l.Offset > 0 {
+		offset := l.Offset.String() // 如 8h0m0s、30m0s，去掉多余的 0m0s
+		if strings.HasSuffix(offset, "m0s") {
+			offset = strings.TrimSuffix(offset, "0s")
+		}
+		if strings.HasSuffix(offset, "h0m") {
+			offset = strings.TrimSuffix(offset, "0m")
+		}
+		label += "+" + offset
+	}
+	return label
+}
+
+// ParseResampleLabel 解析重采样周期标签，并检查能否由 base 周期K线合成
+func ParseResampleLabel(base, label string) (ResampleLabel, error) {
+	var result ResampleLabel
+	if !containsString(resampleBases, base) {
+		return result, fmt.Errorf("基础周期 %q 不支持，可选：%s", base, strings.Join(resampleBases, ","))
+	}
+	baseDuration := IntervalDuration(base)
+
+	rest := label
+	if i := strings.Index(rest, "+"); i >= 0 {
+		offset, err := time.ParseDuration(rest[i+1:])
+		if err != nil || offset <= 0 {
+			return result, fmt.Errorf("%s 的偏移 %q 格式错误，应为 8h、30m 等正时长", label, rest[i+1:])
+		}
+		result.Offset = offset
+		rest = rest[:i]
+	}
+	if i := strings.Index(rest, "@"); i >= 0 {
+		location, err := time.LoadLocation(rest[i+1:])
+		if err != nil {
+			return result, fmt.Errorf("%s 的时区 %q 无法识别: %v", label, rest[i+1:], err)
+		}
+		if location != time.UTC {
+			result.Location = location
+		}
+		rest = rest[:i]
+	}
+	result.Target = rest
+
+	if result.Target != "1d" && result.Target != "1w" && !containsString(resampleBases, result.Target) {
+		return result, fmt.Errorf("%s 的目标周期 %q 不支持，可选：%s,1d,1w", label, result.Target, strings.Join(resampleBases, ","))
+	}
+	period := IntervalDuration(result.Target)
+	if period <= baseDuration || period%baseDuration != 0 {
+		return result, fmt.Errorf("%s 不是基础周期 %s 的整数倍", label, base)
+	}
+	if result.Offset >= period || result.Offset%baseDuration != 0 {
+		return result, fmt.Errorf("%s 的偏移必须小于周期且是基础周期 %s 的整数倍", label, base)
+	}
+	if result.Location != nil {
+		// 冬令时和夏令时的UTC偏移都必须落在基础K线的边界上
+		for _, month := range []time.Month{time.January, time.July} {
+			_, zoneOffset := time.Date(2024, month, 1, 0, 0, 0, 0, result.Location).Zone()
+			if time.Duration(zoneOffset)*time.Second%baseDuration != 0 {
+				return result, fmt.Errorf("%s 的时区偏移不是基础周期 %s 的整数倍", label, base)
+			}
+		}
+	}
+	return result, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,197 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfig 写入临时配置文件并返回路径
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+	return path
+}
+
+const minimalConfig = `{
+  "database": {"host": "localhost", "user": "trade", "dbname": "trade"},
+  "symbols": [{"symbol": "BTCUSDT", "intervals": ["1d", "1h"]}]
+}`
+
+func TestLoadConfigDefaults(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, minimalConfig))
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	if config.Database.Port != 5432 || config.Database.SSLMode != "disable" {
+		t.Errorf("数据库默认值错误: %+v", config.Database)
+	}
+	if config.API.Port != 8080 || config.Web.Port != 8080 {
+		t.Errorf("端口默认值错误: api=%d web=%d", config.API.Port, config.Web.Port)
+	}
+	if config.Scheduler.DailySpec != "0 0 0 * * *" {
+		t.Errorf("定时任务默认值错误: %q", config.Scheduler.DailySpec)
+	}
+	if config.Trading.Margin != 5 || config.Trading.Leverage != 10 || len(config.Trading.Strategies) == 0 {
+		t.Errorf("自动交易默认值错误: %+v", config.Trading)
+	}
+	if config.Risk.MaxDailyLoss != 50 || config.Risk.MaxConsecutiveLosses != 5 {
+		t.Errorf("风控默认值错误: %+v", config.Risk)
+	}
+	if dsn := config.Database.DSN(); strings.Contains(dsn, "password=") {
+		t.Errorf("未设置密码时 DSN 不应包含 password: %s", dsn)
+	}
+}
+
+func TestLoadConfigEnvOverrides(t *testing.T) {
+	t.Setenv("TRADE_DB_HOST", "db.internal")
+	t.Setenv("TRADE_DB_PASSWORD", "secret")
+	t.Setenv("TRADE_API_PORT", "9090")
+	t.Setenv("TRADE_BINANCE_API_KEY", "key")
+	t.Setenv("TRADE_BINANCE_SECRET_KEY", "secret-key")
+
+	config, err := LoadConfig(writeConfig(t, minimalConfig))
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	if config.Database.Host != "db.internal" || config.Database.Password != "secret" {
+		t.Errorf("数据库环境变量未生效: %+v", config.Database)
+	}
+	if config.API.Port != 9090 {
+		t.Errorf("TRADE_API_PORT 未生效: %d", config.API.Port)
+	}
+	if config.Binance.APIKey != "key" || config.Binance.SecretKey != "secret-key" {
+		t.Errorf("币安密钥环境变量未生效: %+v", config.Binance)
+	}
+
+	t.Setenv("TRADE_WEB_PORT", "abc")
+	if _, err := LoadConfig(writeConfig(t, minimalConfig)); err == nil || !strings.Contains(err.Error(), "TRADE_WEB_PORT") {
+		t.Errorf("非法整数环境变量应报错，实际: %v", err)
+	}
+}
+
+func TestLoadConfigValidation(t *testing.T) {
+	_, err := LoadConfig(writeConfig(t, `{
+  "database": {"host": "", "user": "trade", "dbname": "trade"},
+  "api": {"port": 70000},
+  "binance": {"api_key": "only-key"},
+  "scheduler": {"daily_spec": "every day"},
+  "trading": {"leverage": 200},
+  "risk": {"max_daily_loss": -1},
+  "symbols": [{"symbol": "BTCUSDT", "intervals": ["7m"]}]
+}`))
+	if err == nil {
+		t.Fatal("非法配置应校验失败")
+	}
+
+	// 所有问题一次性报告
+	for _, field := range []string{"database.host", "api.port", "binance.api_key", "scheduler.daily_spec", "trading.leverage", "risk.max_total_exposure", "symbols[0].intervals"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("错误信息应包含 %s: %v", field, err)
+		}
+	}
+}
+
+func TestLoadConfigSymbolTrading(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, `{
+  "database": {"host": "localhost", "user": "trade", "dbname": "trade"},
+  "trading": {"stop_loss_pct": 2},
+  "symbols": [
+    {"symbol": "BTCUSDT", "intervals": ["1h"]},
+    {"symbol": "ETHUSDT", "intervals": ["1h"], "leverage": 20, "margin_type": "CROSSED", "strategies": ["strategy_2"], "max_position": 200, "take_profit_pct": 3}
+  ]
+}`))
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	btc := config.SymbolTrading("BTCUSDT")
+	if btc.Leverage != 10 || btc.Notional != 50 || btc.Margin() != 5 || btc.MarginType != "ISOLATED" || btc.StopLossPct != 2 {
+		t.Errorf("BTCUSDT 应使用全局默认 5U×10倍: %+v", btc)
+	}
+	// 未设置 notional 时按全局保证金 × 交易对杠杆
+	eth := config.SymbolTrading("ETHUSDT")
+	if eth.Leverage != 20 || eth.Notional != 100 || eth.MarginType != "CROSSED" || len(eth.Strategies) != 1 ||
+		eth.MaxPosition != 200 || eth.StopLossPct != 2 || eth.TakeProfitPct != 3 {
+		t.Errorf("ETHUSDT 交易参数错误: %+v", eth)
+	}
+	if price := eth.StopLossPrice(100, -1); price != 102 {
+		t.Errorf("空头止损价应为 102，实际 %v", price)
+	}
+}
+
+func TestLoadConfigSymbolTradingValidation(t *testing.T) {
+	_, err := LoadConfig(writeConfig(t, `{
+  "database": {"host": "localhost", "user": "trade", "dbname": "trade"},
+  "trading": {"stop_loss_pct": 15},
+  "symbols": [
+    {"symbol": "BTCUSDT", "intervals": ["1h"], "notional": 300, "max_position": 100},
+    {"symbol": "ETHUSDT", "intervals": ["1h"], "margin_type": "PORTFOLIO", "strategies": ["strategy_1", "strategy_1"]},
+    {"symbol": "SOLUSDT", "intervals": ["1h"], "leverage": 1, "notional": 5000}
+  ]
+}`))
+	if err == nil {
+		t.Fatal("不一致的交易参数应校验失败")
+	}
+
+	for _, field := range []string{
+		"trading.stop_loss_pct",
+		"symbols[0].notional(300) 不能大于 max_position",
+		"symbols[1].margin_type",
+		"symbols[1].strategies 重复",
+		"symbols[2].notional/leverage",
+	} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("错误信息应包含 %s: %v", field, err)
+		}
+	}
+}
+
+func TestParseResampleLabel(t *testing.T) {
+	cases := map[string]string{
+		"4h":                     "4h",
+		"1d@UTC":                 "1d",
+		"1d+8h":                  "1d+8h",
+		"4h+30m":                 "4h+30m",
+		"1d@America/New_York":    "1d@America/New_York",
+		"1w@Asia/Shanghai+9h30m": "1w@Asia/Shanghai+9h30m",
+	}
+	for label, want := range cases {
+		parsed, err := ParseResampleLabel("1m", label)
+		if err != nil || parsed.String() != want {
+			t.Errorf("ParseResampleLabel(%q) = %q, %v，期望 %q", label, parsed.String(), err, want)
+		}
+	}
+
+	// 目标周期不是基础周期的整数倍、偏移超出周期、时区偏移不在基础K线边界上
+	// 1d 不能作为基础周期
+	for _, c := range [][2]string{{"1h", "30m"}, {"2h", "1d+1h"}, {"4h", "1d+24h"}, {"1h", "1d@Asia/Kolkata"}, {"1m", "1M"}, {"1d", "1w"}} {
+		if _, err := ParseResampleLabel(c[0], c[1]); err == nil {
+			t.Errorf("ParseResampleLabel(%q, %q) 应返回错误", c[0], c[1])
+		}
+	}
+}
+
+func TestLoadConfigResampleValidation(t *testing.T) {
+	_, err := LoadConfig(writeConfig(t, `{
+  "database": {"host": "localhost", "user": "trade", "dbname": "trade"},
+  "symbols": [
+    {"symbol": "BTCUSDT", "intervals": ["1d", "1h"], "resample": {"base": "1h", "intervals": ["4h", "1d", "3h"]}},
+    {"symbol": "ETHUSDT", "intervals": ["1d"], "resample": {"base": "1h", "intervals": ["4h"]}}
+  ]
+}`))
+	if err == nil {
+		t.Fatal("非法重采样配置应校验失败")
+	}
+	for _, want := range []string{"1d 已在 intervals 中", "\"3h\" 不支持", "symbols[1].resample.base"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("错误信息应包含 %s: %v", want, err)
+		}
+	}
+}
@@ -3,8 +3,12 @@ package utils
 import (
 	"fmt"
 	"strconv"
+	"time"
 )
 
+// asOfLayouts ParseAsOf 支持的时间格式，没有时区的按 UTC 解析
+var asOfLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
 func StringToFloat64(s string) float64 {
 	f, err := strconv.ParseFloat(s, 64)
 	if err != nil {
@@ -13,3 +17,17 @@ func StringToFloat64(s string) float64 {
 	}
 	return f
 }
+
+// ParseAsOf 解析时点(as-of)参数，空字符串返回零值表示不限制
+// 只有日期时取当天 00:00 UTC，即只使用前一天及更早已收盘的K线
+func ParseAsOf(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range asOfLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无法解析时间 %q，应为 2024-10-30、2024-10-30 08:00:00 或 RFC3339 格式", value)
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAsOf(t *testing.T) {
+	cases := map[string]time.Time{
+		"":                          {},
+		"2024-10-30":                time.Date(2024, 10, 30, 0, 0, 0, 0, time.UTC),
+		"2024-10-30 08:00:00":       time.Date(2024, 10, 30, 8, 0, 0, 0, time.UTC),
+		"2024-10-30T16:00:00+08:00": time.Date(2024, 10, 30, 8, 0, 0, 0, time.UTC),
+	}
+	for value, want := range cases {
+		got, err := ParseAsOf(value)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseAsOf(%q) = %v, %v，期望 %v", value, got, err, want)
+		}
+	}
+	if _, err := ParseAsOf("30/10/2024"); err == nil {
+		t.Error("格式错误应返回错误")
+	}
+}
@@ -2,13 +2,12 @@ package web
 
 import (
 	"encoding/json"
-	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 
-	"trade/db"
 	"trade/model"
+	"trade/store"
 )
 
 // Strategy1Response 策略一API响应
@@ -33,14 +32,19 @@ type Strategy2ResultWithDetails struct {
 	Details []model.Strategy2DetailRecord `json:"details"`
 }
 
-// StartServer 启动Web服务器
-func StartServer(port int) {
+// Strategy3Response 策略三API响应
+type Strategy3Response struct {
+	Results []model.Strategy3Result `json:"results"`
+}
+
+// StartServer 启动Web服务器，addr 为监听地址，例如 :8080
+func StartServer(addr string) {
 	// 注册路由
 	http.HandleFunc("/", indexHandler)
 	http.HandleFunc("/api/strategy1", getStrategy1Results)
 	http.HandleFunc("/api/strategy2", getStrategy2Results)
+	http.HandleFunc("/api/strategy3", getStrategy3Results)
 
-	addr := fmt.Sprintf(":%d", port)
 	log.Fatal(http.ListenAndServe(addr, nil))
 }
 
@@ -59,18 +63,9 @@ func getStrategy1Results(w http.ResponseWriter, r *http.Request) {
 	symbol := r.URL.Query().Get("symbol")
 	interval := r.URL.Query().Get("interval")
 
-	// 构建查询
-	query := db.Pog.Model(&model.Strategy1Result{})
-	if symbol != "" {
-		query = query.Where("symbol = ?", symbol)
-	}
-	if interval != "" {
-		query = query.Where("interval = ?", interval)
-	}
-
 	// 查询结果
-	var results []model.Strategy1Result
-	if err := query.Order("created_at DESC").Find(&results).Error; err != nil {
+	results, err := store.Default().ListStrategy1Results(symbol, interval)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -78,8 +73,7 @@ func getStrategy1Results(w http.ResponseWriter, r *http.Request) {
 	// 获取详细记录
 	var response Strategy1Response
 	for _, result := range results {
-		var details []model.Strategy1DetailRecord
-		db.Pog.Where("result_id = ?", result.ID).Order("year ASC").Find(&details)
+		details, _ := store.Default().ListStrategy1Details(result.ID)
 
 		response.Results = append(response.Results, Strategy1ResultWithDetails{
 			Strategy1Result: result,
@@ -101,24 +95,17 @@ func getStrategy2Results(w http.ResponseWriter, r *http.Request) {
 	interval := r.URL.Query().Get("interval")
 	hourStr := r.URL.Query().Get("hour")
 
-	// 构建查询
-	query := db.Pog.Model(&model.Strategy2Result{})
-	if symbol != "" {
-		query = query.Where("symbol = ?", symbol)
-	}
-	if interval != "" {
-		query = query.Where("interval = ?", interval)
-	}
+	var hourFilter *int
 	if hourStr != "" {
 		hour, err := strconv.Atoi(hourStr)
 		if err == nil {
-			query = query.Where("hour = ?", hour)
+			hourFilter = &hour
 		}
 	}
 
 	// 查询结果
-	var results []model.Strategy2Result
-	if err := query.Order("hour ASC").Find(&results).Error; err != nil {
+	results, err := store.Default().ListStrategy2Results(symbol, interval, hourFilter)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -126,8 +113,7 @@ func getStrategy2Results(w http.ResponseWriter, r *http.Request) {
 	// 获取详细记录
 	var response Strategy2Response
 	for _, result := range results {
-		var details []model.Strategy2DetailRecord
-		db.Pog.Where("result_id = ?", result.ID).Order("date ASC").Find(&details)
+		details, _ := store.Default().ListStrategy2Details(result.ID)
 
 		response.Results = append(response.Results, Strategy2ResultWithDetails{
 			Strategy2Result: result,
@@ -137,3 +123,32 @@ func getStrategy2Results(w http.ResponseWriter, r *http.Request) {
 
 	json.NewEncoder(w).Encode(response)
 }
+
+// getStrategy3Results 获取策略三结果
+func getStrategy3Results(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json")
+
+	// 获取查询参数
+	symbol := r.URL.Query().Get("symbol")
+	interval := r.URL.Query().Get("interval")
+	weekdayStr := r.URL.Query().Get("weekday")
+
+	var weekdayFilter *int
+	if weekdayStr != "" {
+		weekday, err := strconv.Atoi(weekdayStr)
+		if err == nil {
+			weekdayFilter = &weekday
+		}
+	}
+
+	// 查询结果
+	results, err := store.Default().ListStrategy3Results(symbol, interval, weekdayFilter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(Strategy3Response{Results: results})
+}